@@ -107,6 +107,12 @@ func newRunCmd() *cobra.Command {
 		"Master hostname that containers started by this agent will connect to")
 	cmd.Flags().IntVar(&opts.ContainerMasterPort, "container-master-port", 0,
 		"Master port that containers started by this agent will connect to")
+	cmd.Flags().IntVar(&opts.ImageCacheMaxSizeGB, "image-cache-max-size-gb", 0,
+		"Disk budget for cached images, in GB; least recently used images are removed past it")
+	cmd.Flags().Float64Var(&opts.CPUPerSlot, "cpu-per-slot", 0,
+		"CPUs a container gets for each of its slots; 0 leaves them unlimited")
+	cmd.Flags().Float64Var(&opts.MemoryPerSlotGB, "memory-per-slot-gb", 0,
+		"Memory a container gets for each of its slots, in GB; 0 leaves it unlimited")
 
 	// Device flags.
 	cmd.Flags().StringVar(&opts.SlotType, "slot-type", "auto", "slot type to expose")
@@ -116,6 +122,8 @@ func newRunCmd() *cobra.Command {
 		"visible-gpus",
 		defaultVisibleGPUs,
 		"GPUs to expose as slots")
+	cmd.Flags().IntVar(&opts.DeviceHealthCheckPeriod, "device-health-check-period", 60,
+		"Time between device health checks, in seconds; 0 disables them")
 
 	// Security flags.
 	cmd.Flags().BoolVar(
@@ -150,6 +158,8 @@ func newRunCmd() *cobra.Command {
 	cmd.Flags().StringVar(&opts.BindIP, "bind-ip", "0.0.0.0",
 		"IP address to listen on for API requests")
 	cmd.Flags().IntVar(&opts.BindPort, "bind-port", 9090, "Port to listen on for API requests")
+	cmd.Flags().StringVar(&opts.APIToken, "api-token", "",
+		"Bearer token required by the agent status endpoints; they are disabled without one")
 
 	// Proxy flags.
 	cmd.Flags().StringVar(&opts.HTTPProxy, "http-proxy", "",
@@ -169,6 +179,14 @@ func newRunCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&opts.ContainerRuntime, "container-runtime",
 		options.DockerContainerRuntime, "The container runtime to use")
+	cmd.Flags().StringVar(&opts.ContainerdOptions.Address, "containerd-address",
+		"/run/containerd/containerd.sock", "Path of the containerd socket")
+	cmd.Flags().StringVar(&opts.ContainerdOptions.Namespace, "containerd-namespace",
+		"determined", "containerd namespace for the agent's images and containers")
+	cmd.Flags().StringVar(&opts.ContainerdOptions.CNIConfDir, "containerd-cni-conf-dir",
+		"/etc/cni/net.d", "Directory of CNI network configuration for containerd containers")
+	cmd.Flags().StringSliceVar(&opts.ContainerdOptions.CNIBinDirs, "containerd-cni-bin-dirs",
+		[]string{"/opt/cni/bin"}, "Directories of CNI plugins for containerd containers")
 
 	return cmd
 }
@@ -18,7 +18,9 @@ import (
 	"github.com/determined-ai/determined/agent/internal/container"
 	"github.com/determined-ai/determined/agent/internal/containers"
 	"github.com/determined-ai/determined/agent/internal/detect"
+	"github.com/determined-ai/determined/agent/internal/imagecache"
 	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/agent/pkg/containerd"
 	"github.com/determined-ai/determined/agent/pkg/docker"
 	"github.com/determined-ai/determined/agent/pkg/events"
 	"github.com/determined-ai/determined/agent/pkg/podman"
@@ -35,6 +37,8 @@ const (
 	wsSecureScheme   = "wss"
 	eventChanSize    = 64 // same size as the websocket outbox
 	logSourceAgent   = "agent"
+
+	imageCacheSyncPeriod = 5 * time.Minute
 )
 
 // MasterWebsocket is the type for a websocket which communicates with the master.
@@ -47,15 +51,20 @@ type MasterWebsocket = ws.WebSocket[*aproto.AgentMessage, *aproto.MasterMessage]
 type Agent struct {
 	version string
 	opts    options.Options
+	status  *agentStatus
 	log     *logrus.Entry
 	wg      errgroupx.Group
 }
 
 // NewAgent constructs and runs a new agent according to the provided configuration.
-func NewAgent(parent context.Context, version string, opts options.Options) *Agent {
+// The agent keeps status up to date for the agent API.
+func NewAgent(
+	parent context.Context, version string, opts options.Options, status *agentStatus,
+) *Agent {
 	a := &Agent{
 		version: version,
 		opts:    opts,
+		status:  status,
 		log:     logrus.WithField("component", "agent"),
 		wg:      errgroupx.WithContext(parent),
 	}
@@ -89,6 +98,7 @@ func (a *Agent) run(ctx context.Context) error {
 	if err != nil {
 		return masterConnectionError{cause: fmt.Errorf("initial connection to master failed: %w", err)}
 	}
+	a.status.connectionChanged(connectionEventConnected, nil)
 	defer func() {
 		a.log.Trace("cleaning up socket")
 		if cErr := socket.Close(); err != nil {
@@ -134,6 +144,17 @@ func (a *Agent) run(ctx context.Context) error {
 			}
 		}()
 		cruntime = acl
+	case options.ContainerdContainerRuntime:
+		acl, cErr := containerd.New(a.opts)
+		if cErr != nil {
+			return fmt.Errorf("failed to build containerd client: %w", cErr)
+		}
+		defer func() {
+			if cErr := acl.Close(); cErr != nil {
+				a.log.WithError(cErr).Error("failed to close containerd client")
+			}
+		}()
+		cruntime = acl
 	case options.ApptainerContainerRuntime:
 		fallthrough
 	case options.SingularityContainerRuntime:
@@ -179,16 +200,44 @@ func (a *Agent) run(ctx context.Context) error {
 		return fmt.Errorf("failed to reattach containers: %w", err)
 	}
 
+	a.status.started(manager, devices)
+
 	a.log.Trace("writing agent started message")
 	select {
 	case socket.Outbox <- &aproto.MasterMessage{AgentStarted: &aproto.AgentStarted{
 		Version:              a.version,
 		Devices:              devices,
 		ContainersReattached: reattached,
+		SlotResources:        a.opts.SlotResources(),
 	}}:
 	case <-ctx.Done():
 		return ctx.Err()
 	}
+	if a.status.isDraining() {
+		select {
+		case socket.Outbox <- &aproto.MasterMessage{AgentDrained: &aproto.AgentDrained{}}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	healthC := make(chan []aproto.DeviceHealth)
+	if period := time.Duration(a.opts.DeviceHealthCheckPeriod) * time.Second; period > 0 {
+		a.log.Trace("watching device health")
+		healthCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go detect.WatchHealth(healthCtx, devices, period, healthC)
+	}
+	var health *aproto.DeviceHealthChanged
+
+	a.log.Trace("managing image cache")
+	images := imagecache.New(a.opts, cruntime)
+	images.SetPrePull(mopts.PrePullImages)
+	cachedImagesC := make(chan []string)
+	cacheCtx, cancelCache := context.WithCancel(ctx)
+	defer cancelCache()
+	go images.Run(cacheCtx, imageCacheSyncPeriod, cachedImagesC)
+	var cachedImages *aproto.CachedImagesChanged
 
 	a.log.Trace("watching for ws requests and system events")
 	inbox := socket.Inbox
@@ -203,6 +252,7 @@ func (a *Agent) run(ctx context.Context) error {
 
 			switch {
 			case msg.StartContainer != nil:
+				images.Touch(msg.StartContainer.Spec.RunSpec.ContainerConfig.Image)
 				if err := manager.StartContainer(ctx, *msg.StartContainer); err != nil {
 					a.log.WithError(err).Error("could not start container")
 				}
@@ -221,12 +271,37 @@ func (a *Agent) run(ctx context.Context) error {
 				return nil
 			}
 
+		case devices := <-healthC:
+			health = &aproto.DeviceHealthChanged{Devices: devices}
+			select {
+			case socket.Outbox <- &aproto.MasterMessage{DeviceHealthChanged: health}:
+			case <-ctx.Done():
+				return nil
+			}
+
+		case refs := <-cachedImagesC:
+			cachedImages = &aproto.CachedImagesChanged{Images: refs}
+			select {
+			case socket.Outbox <- &aproto.MasterMessage{CachedImagesChanged: cachedImages}:
+			case <-ctx.Done():
+				return nil
+			}
+
+		case <-a.status.drainNotify:
+			a.log.Info("agent was drained locally, refusing new containers")
+			select {
+			case socket.Outbox <- &aproto.MasterMessage{AgentDrained: &aproto.AgentDrained{}}:
+			case <-ctx.Done():
+				return nil
+			}
+
 		case <-socket.Done:
 			if err := socket.Error(); err != nil {
 				a.log.WithError(err).Error("socket disconnected")
 			} else {
 				a.log.Trace("socket disconnected")
 			}
+			a.status.connectionChanged(connectionEventDisconnected, socket.Error())
 
 			newSocket, newMopts, err := a.reconnectFlow(ctx, manager, devices, outbox)
 			if err != nil {
@@ -235,6 +310,30 @@ func (a *Agent) run(ctx context.Context) error {
 			socket = newSocket
 			inbox = socket.Inbox
 			mopts = *newMopts
+			images.SetPrePull(mopts.PrePullImages)
+
+			// The master may have restarted and forgotten which devices are unhealthy.
+			if health != nil {
+				select {
+				case socket.Outbox <- &aproto.MasterMessage{DeviceHealthChanged: health}:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+			if cachedImages != nil {
+				select {
+				case socket.Outbox <- &aproto.MasterMessage{CachedImagesChanged: cachedImages}:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+			if a.status.isDraining() {
+				select {
+				case socket.Outbox <- &aproto.MasterMessage{AgentDrained: &aproto.AgentDrained{}}:
+				case <-ctx.Done():
+					return nil
+				}
+			}
 
 		case <-ctx.Done():
 			a.log.Trace("context canceled")
@@ -376,6 +475,7 @@ func (a *Agent) reconnectFlow(
 		Version:              a.version,
 		Devices:              devices,
 		ContainersReattached: reattached,
+		SlotResources:        a.opts.SlotResources(),
 	}}:
 	case <-ctx.Done():
 		return nil, nil, ctx.Err()
@@ -424,9 +524,14 @@ func (a *Agent) reconnectFlow(
 
 func (a *Agent) reconnect(ctx context.Context) (*MasterWebsocket, error) {
 	for i := 1; ; i++ {
-		switch ws, err := a.connect(ctx, true); {
-		case err == nil:
+		ws, err := a.connect(ctx, true)
+		if err == nil {
+			a.status.connectionChanged(connectionEventConnected, nil)
 			return ws, nil
+		}
+		a.status.connectionChanged(connectionEventReconnectFailed, err)
+
+		switch {
 		case errors.Is(err, aproto.ErrAgentMustReconnect):
 			a.log.Warn("received ErrAgentMustReconnect, exiting")
 			return nil, err
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"net/http/pprof"
@@ -11,6 +12,8 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 
 	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
 	"github.com/determined-ai/determined/master/pkg/logger"
 )
 
@@ -20,9 +23,17 @@ type agentAPIServer struct {
 
 	// Internal state.
 	server *echo.Echo
+	status *agentStatus
 }
 
-func newAgentAPIServer(opts options.Options) *agentAPIServer {
+// drainResponse is the response to a drain request. Containers are the containers that are still
+// running; the host is safe to reboot once there are none.
+type drainResponse struct {
+	Draining   bool               `json:"draining"`
+	Containers []cproto.Container `json:"containers"`
+}
+
+func newAgentAPIServer(opts options.Options, status *agentStatus) *agentAPIServer {
 	server := echo.New()
 	server.Logger = logger.New()
 	server.HidePort = true
@@ -37,10 +48,56 @@ func newAgentAPIServer(opts options.Options) *agentAPIServer {
 	server.Any("/debug/pprof/symbol", echo.WrapHandler(http.HandlerFunc(pprof.Symbol)))
 	server.Any("/debug/pprof/trace", echo.WrapHandler(http.HandlerFunc(pprof.Trace)))
 
-	return &agentAPIServer{
+	a := &agentAPIServer{
 		opts:   opts,
 		server: server,
+		status: status,
+	}
+
+	if opts.APIToken == "" {
+		logrus.Warn("api_token is not set, so the agent status endpoints are disabled")
+		return a
+	}
+	api := server.Group("/api/v1", middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+		Validator: func(key string, _ echo.Context) (bool, error) {
+			return subtle.ConstantTimeCompare([]byte(key), []byte(opts.APIToken)) == 1, nil
+		},
+	}))
+	api.GET("/containers", a.getContainers)
+	api.GET("/devices", a.getDevices)
+	api.GET("/master", a.getMaster)
+	api.POST("/drain", a.postDrain)
+	return a
+}
+
+func (a *agentAPIServer) getContainers(c echo.Context) error {
+	containers := a.status.containers()
+	if containers == nil {
+		containers = []cproto.Container{}
+	}
+	return c.JSON(http.StatusOK, containers)
+}
+
+func (a *agentAPIServer) getDevices(c echo.Context) error {
+	devices := a.status.detectedDevices()
+	if devices == nil {
+		devices = []device.Device{}
+	}
+	return c.JSON(http.StatusOK, devices)
+}
+
+func (a *agentAPIServer) getMaster(c echo.Context) error {
+	address := fmt.Sprintf("%s:%d", a.opts.MasterHost, a.opts.MasterPort)
+	return c.JSON(http.StatusOK, a.status.connection(address))
+}
+
+func (a *agentAPIServer) postDrain(c echo.Context) error {
+	a.status.drain()
+	containers := a.status.containers()
+	if containers == nil {
+		containers = []cproto.Container{}
 	}
+	return c.JSON(http.StatusOK, drainResponse{Draining: true, Containers: containers})
 }
 
 func (a *agentAPIServer) serve() error {
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/agent/internal/container"
+	"github.com/determined-ai/determined/agent/internal/containers"
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/agent/pkg/events"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+const testAPIToken = "s3cret"
+
+func request(api *agentAPIServer, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	api.server.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAgentAPIAuthentication(t *testing.T) {
+	status := newAgentStatus()
+
+	api := newAgentAPIServer(options.Options{}, status)
+	require.Equal(t, http.StatusNotFound, request(api, http.MethodGet, "/api/v1/devices", "").Code)
+
+	api = newAgentAPIServer(options.Options{APIToken: testAPIToken}, status)
+	require.Equal(t, http.StatusBadRequest, request(api, http.MethodGet, "/api/v1/devices", "").Code)
+	require.Equal(t, http.StatusUnauthorized, request(api, http.MethodGet, "/api/v1/devices", "nope").Code)
+	require.Equal(t, http.StatusOK, request(api, http.MethodGet, "/api/v1/devices", testAPIToken).Code)
+}
+
+func TestAgentAPIStatus(t *testing.T) {
+	status := newAgentStatus()
+	api := newAgentAPIServer(options.Options{
+		MasterHost: "master.example.com", MasterPort: 8080, APIToken: testAPIToken,
+	}, status)
+
+	// Before the agent starts, there is nothing to report.
+	rec := request(api, http.MethodGet, "/api/v1/containers", testAPIToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, "[]", rec.Body.String())
+
+	devices := []device.Device{{ID: 0, Brand: "Tesla", UUID: "GPU-1", Type: device.CUDA}}
+	manager, err := containers.New(options.Options{}, aproto.MasterSetAgentOptions{}, devices, nil,
+		events.NilPublisher[container.Event]{})
+	require.NoError(t, err)
+	status.started(manager, devices)
+	status.connectionChanged(connectionEventConnected, nil)
+	status.connectionChanged(connectionEventDisconnected, errors.New("connection reset"))
+	status.connectionChanged(connectionEventReconnectFailed, errors.New("connection refused"))
+
+	rec = request(api, http.MethodGet, "/api/v1/devices", testAPIToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var gotDevices []device.Device
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &gotDevices))
+	require.Equal(t, devices, gotDevices)
+
+	rec = request(api, http.MethodGet, "/api/v1/master", testAPIToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var conn masterConnection
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &conn))
+	require.Equal(t, "master.example.com:8080", conn.Address)
+	require.False(t, conn.Connected)
+	require.Len(t, conn.History, 3)
+	require.Equal(t, connectionEventReconnectFailed, conn.History[2].Event)
+	require.Equal(t, "connection refused", conn.History[2].Error)
+}
+
+func TestAgentAPIDrain(t *testing.T) {
+	status := newAgentStatus()
+	api := newAgentAPIServer(options.Options{APIToken: testAPIToken}, status)
+
+	var refused []*aproto.ContainerStateChanged
+	manager, err := containers.New(options.Options{}, aproto.MasterSetAgentOptions{}, nil, nil,
+		events.FuncPublisher[container.Event](func(ctx context.Context, e container.Event) error {
+			refused = append(refused, e.StateChange)
+			return nil
+		}))
+	require.NoError(t, err)
+	status.started(manager, nil)
+
+	rec := request(api, http.MethodPost, "/api/v1/drain", testAPIToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"draining": true, "containers": []}`, rec.Body.String())
+	require.True(t, status.isDraining())
+
+	select {
+	case <-status.drainNotify:
+	default:
+		t.Fatal("agent was not notified of the drain")
+	}
+
+	id := cproto.NewID()
+	err = manager.StartContainer(context.Background(), aproto.StartContainer{
+		Container: cproto.Container{ID: id, State: cproto.Assigned},
+	})
+	require.ErrorContains(t, err, "agent is draining")
+	require.Len(t, refused, 1)
+	require.Equal(t, id, refused[0].Container.ID)
+	require.Equal(t, cproto.Terminated, refused[0].Container.State)
+	require.Equal(t, container.ErrDraining, refused[0].ContainerStopped.Failure)
+	require.Empty(t, manager.Containers())
+}
@@ -44,3 +44,10 @@ type ContainerRuntime interface {
 
 	ListRunningContainers(ctx context.Context, fs filters.Args) (map[cproto.ID]types.Container, error)
 }
+
+// ImageStore is implemented by container runtimes that can list and remove the images they store.
+type ImageStore interface {
+	ListImages(ctx context.Context) ([]docker.Image, error)
+
+	RemoveImage(ctx context.Context, img docker.Image) error
+}
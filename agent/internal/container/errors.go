@@ -17,3 +17,9 @@ var ErrKilledBeforeRun = aproto.NewContainerFailure(
 	aproto.ContainerAborted,
 	errors.New("killed before run"),
 )
+
+// ErrDraining indicates a container was refused because the agent is draining.
+var ErrDraining = aproto.NewContainerFailure(
+	aproto.ContainerAborted,
+	errors.New("agent is draining"),
+)
@@ -4,6 +4,7 @@ import (
 	"container/ring"
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"syscall"
 
@@ -46,6 +47,7 @@ type Manager struct {
 	// Internal state. Access should be protected.
 	containers  map[cproto.ID]*container.Container
 	recentExits *ring.Ring
+	draining    bool
 	wg          waitgroupx.Group
 	mu          sync.RWMutex
 }
@@ -206,6 +208,21 @@ func (m *Manager) StartContainer(ctx context.Context, req aproto.StartContainer)
 	req.Spec = spec
 
 	m.mu.Lock()
+	if m.draining {
+		m.mu.Unlock()
+		stop := &aproto.ContainerStateChanged{
+			Container: cproto.Container{
+				ID:      req.Container.ID,
+				State:   cproto.Terminated,
+				Devices: req.Container.Devices,
+			},
+			ContainerStopped: &aproto.ContainerStopped{Failure: container.ErrDraining},
+		}
+		if err := m.pub.Publish(ctx, container.Event{StateChange: stop}); err != nil {
+			return fmt.Errorf("failed to refuse container %s: %w", req.Container.ID, err)
+		}
+		return fmt.Errorf("refused container %s: agent is draining", req.Container.ID)
+	}
 	if m.containers[req.Container.ID] != nil {
 		m.mu.Unlock()
 		return fmt.Errorf("container already created: %s", req.Container.ID)
@@ -270,6 +287,26 @@ func (m *Manager) Close() {
 	m.wg.Wait()
 }
 
+// Drain makes the manager refuse to start new containers. Containers that are already managed keep
+// running.
+func (m *Manager) Drain() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.draining = true
+}
+
+// Containers returns the containers being managed, ordered by ID.
+func (m *Manager) Containers() []cproto.Container {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]cproto.Container, 0, len(m.containers))
+	for _, c := range m.containers {
+		result = append(result, c.Summary())
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
 // NumContainers returns the number of containers being managed.
 func (m *Manager) NumContainers() int {
 	m.mu.RLock()
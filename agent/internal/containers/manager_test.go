@@ -3,9 +3,11 @@ package containers
 import (
 	"testing"
 
+	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/stretchr/testify/require"
 
 	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/master/pkg/aproto"
 )
 
 func TestAddProxyInfo(t *testing.T) {
@@ -78,3 +80,43 @@ func TestAddProxyInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestLimitSlotResources(t *testing.T) {
+	perSlot := aproto.SlotResources{CPU: 1.5, MemoryBytes: 1 << 30}
+	tests := []struct {
+		name    string
+		slots   int
+		perSlot aproto.SlotResources
+		set     dcontainer.Resources
+		want    dcontainer.Resources
+	}{
+		{
+			name:    "per slot",
+			slots:   2,
+			perSlot: perSlot,
+			want:    dcontainer.Resources{NanoCPUs: 3e9, Memory: 2 << 30},
+		},
+		{
+			name:    "set by task",
+			slots:   2,
+			perSlot: perSlot,
+			set:     dcontainer.Resources{NanoCPUs: 1e9},
+			want:    dcontainer.Resources{NanoCPUs: 1e9, Memory: 2 << 30},
+		},
+		{
+			name:    "no slots",
+			perSlot: perSlot,
+		},
+		{
+			name:  "unlimited",
+			slots: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.set
+			limitSlotResources(&r, tt.slots, tt.perSlot)
+			require.Equal(t, tt.want, r)
+		})
+	}
+}
@@ -54,11 +54,27 @@ func overwriteSpec(
 		}
 	}
 
+	limitSlotResources(&spec.RunSpec.HostConfig.Resources, len(cont.Devices), opts.SlotResources())
+
 	spec.RunSpec.HostConfig.LogConfig = dcontainer.LogConfig{}
 
 	return spec, nil
 }
 
+// limitSlotResources limits the CPUs and memory of a container to the agent's share for each of
+// its slots, unless the task already set them. Containers without slots are left unlimited.
+func limitSlotResources(r *dcontainer.Resources, slots int, perSlot aproto.SlotResources) {
+	if slots == 0 {
+		return
+	}
+	if r.NanoCPUs == 0 && perSlot.CPU > 0 {
+		r.NanoCPUs = int64(perSlot.CPU * float64(slots) * 1e9)
+	}
+	if r.Memory == 0 && perSlot.MemoryBytes > 0 {
+		r.Memory = perSlot.MemoryBytes * int64(slots)
+	}
+}
+
 func addProxyInfo(env []string, opts options.Options) []string {
 	addVars := map[string]string{
 		"HTTP_PROXY":  opts.HTTPProxy,
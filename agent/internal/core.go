@@ -27,12 +27,13 @@ func Run(parent context.Context, version string, opts options.Options) error {
 	log.Infof("agent configuration: %s", printableConfig)
 
 	wg := errgroupx.WithContext(ctx)
+	status := newAgentStatus()
 
 	log.Trace("starting main agent process")
 	wg.Go(func(ctx context.Context) error {
 		defer wg.Cancel()
 
-		err := NewAgent(ctx, version, opts).Wait()
+		err := NewAgent(ctx, version, opts, status).Wait()
 		if _, ok := err.(masterConnectionError); ok {
 			onConnectionLost(ctx, opts)
 		}
@@ -44,7 +45,7 @@ func Run(parent context.Context, version string, opts options.Options) error {
 		wg.Go(func(ctx context.Context) error {
 			defer wg.Cancel()
 
-			api := newAgentAPIServer(opts)
+			api := newAgentAPIServer(opts, status)
 			wg.Go(func(ctx context.Context) error {
 				<-ctx.Done()
 				return api.close()
@@ -0,0 +1,255 @@
+package detect
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+var (
+	queryCudaHealthArgs = []string{
+		"nvidia-smi", "--query-gpu=uuid,pci.bus_id,ecc.errors.uncorrected.volatile.total",
+		"--format=csv,noheader",
+	}
+	queryRocmHealthArgs = []string{"rocm-smi", "--showuniqueid", "--json"}
+	kernelLogArgs       = []string{"dmesg"}
+
+	listedUUIDRegExp = regexp.MustCompile(`\(UUID: (\S+)\)`)
+	xidRegExp        = regexp.MustCompile(`NVRM: Xid \(PCI:([0-9a-fA-F:.]+)\): (\d+)`)
+
+	// criticalXIDs are the Xid errors after which a GPU is not expected to work correctly until it
+	// is reset. Other Xids are usually caused by the application and don't affect later tasks.
+	criticalXIDs = map[int]string{
+		48:  "double bit ECC error",
+		64:  "ECC row remapping failure",
+		74:  "NVLink error",
+		79:  "GPU has fallen off the bus",
+		92:  "high single-bit ECC error rate",
+		95:  "uncontained ECC error",
+		119: "GSP RPC timeout",
+		120: "GSP error",
+	}
+)
+
+// WatchHealth checks the health of the devices every period and sends the result to out whenever
+// it differs from the previous one. Devices are assumed healthy until the first check. It returns
+// when the context is canceled.
+func WatchHealth(
+	ctx context.Context, devices []device.Device, period time.Duration,
+	out chan<- []aproto.DeviceHealth,
+) {
+	var last []aproto.DeviceHealth
+	for _, d := range devices {
+		last = append(last, aproto.DeviceHealth{ID: d.ID, Healthy: true})
+	}
+
+	t := time.NewTicker(period)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+
+		health, err := CheckHealth(devices)
+		if err != nil {
+			log.WithError(err).Warn("failed to check device health")
+			continue
+		}
+		if slices.Equal(health, last) {
+			continue
+		}
+		last = health
+
+		select {
+		case out <- health:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// CheckHealth checks the health of the detected devices. NVIDIA GPUs are unhealthy if they are no
+// longer listed by nvidia-smi, report uncorrectable ECC errors or have logged a critical Xid
+// error to the kernel log. AMD GPUs are unhealthy if they are no longer listed by rocm-smi. Other
+// devices are always healthy.
+func CheckHealth(devices []device.Device) ([]aproto.DeviceHealth, error) {
+	var cuda, rocm bool
+	for _, d := range devices {
+		cuda = cuda || d.Type == device.CUDA
+		rocm = rocm || d.Type == device.ROCM
+	}
+
+	var listOut, queryOut, kernelLog, rocmOut []byte
+	var err error
+	if cuda {
+		// nvidia-smi exits with an error but still lists the remaining devices when one of them
+		// can't be reached, which is exactly the case this has to catch.
+		if listOut, err = healthCommandOutput(detectCudaDevices); err != nil && len(listOut) == 0 {
+			return nil, err
+		}
+		if queryOut, err = healthCommandOutput(queryCudaHealthArgs); err != nil {
+			log.WithError(err).Warn("not checking ECC errors, failed to query nvidia-smi")
+			queryOut = nil
+		}
+		// Reading the kernel log may not be permitted; Xids are then not checked.
+		if kernelLog, err = healthCommandOutput(kernelLogArgs); err != nil {
+			log.WithError(err).Debug("not checking Xid errors, failed to read kernel log")
+			kernelLog = nil
+		}
+	}
+	if rocm {
+		if rocmOut, err = healthCommandOutput(queryRocmHealthArgs); err != nil && len(rocmOut) == 0 {
+			return nil, err
+		}
+	}
+
+	var cudaHealth map[string]string
+	if cuda {
+		cudaHealth, err = cudaUnhealthyReasons(devices, listOut, queryOut, kernelLog)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var rocmHealth map[string]string
+	if rocm {
+		if rocmHealth, err = rocmUnhealthyReasons(devices, rocmOut); err != nil {
+			return nil, err
+		}
+	}
+
+	health := make([]aproto.DeviceHealth, 0, len(devices))
+	for _, d := range devices {
+		var reason string
+		switch d.Type {
+		case device.CUDA:
+			reason = cudaHealth[d.UUID]
+		case device.ROCM:
+			reason = rocmHealth[d.UUID]
+		}
+		health = append(health, aproto.DeviceHealth{ID: d.ID, Healthy: reason == "", Reason: reason})
+	}
+	return health, nil
+}
+
+// healthCommandOutput runs a command and returns its standard output, even if it fails.
+func healthCommandOutput(args []string) ([]byte, error) {
+	// #nosec G204
+	out, err := exec.Command(args[0], args[1:]...).Output()
+	if err != nil {
+		return out, errors.Wrapf(err, "error while executing %s", strings.Join(args, " "))
+	}
+	return out, nil
+}
+
+// cudaUnhealthyReasons returns the reason each unhealthy NVIDIA device is unhealthy, by UUID,
+// from the output of `nvidia-smi -L`, of the health query and of the kernel log. Only whole GPUs
+// are checked for ECC and Xid errors, since MIG instances aren't reported by the query.
+func cudaUnhealthyReasons(
+	devices []device.Device, listOut, queryOut, kernelLog []byte,
+) (map[string]string, error) {
+	listed := make(map[string]bool)
+	for _, m := range listedUUIDRegExp.FindAllSubmatch(listOut, -1) {
+		listed[string(m[1])] = true
+	}
+	reasons := missingReasons(devices, device.CUDA, listed, "nvidia-smi")
+
+	xids := parseXIDs(kernelLog)
+	r := csv.NewReader(strings.NewReader(string(queryOut)))
+	for {
+		record, err := r.Read()
+		switch {
+		case err == io.EOF:
+			return reasons, nil
+		case err != nil:
+			return nil, errors.Wrap(err, "error parsing output of nvidia-smi as CSV")
+		case len(record) != 3:
+			return nil, errors.New(
+				"error parsing output of nvidia-smi; GPU record should have exactly 3 fields")
+		}
+
+		uuid := strings.TrimSpace(record[0])
+		busID := normalizeBusID(record[1])
+		ecc := strings.TrimSpace(record[2])
+
+		// ECC is reported as "[N/A]" when it is disabled or not supported.
+		if errs, err := strconv.Atoi(ecc); err == nil && errs > 0 {
+			reasons[uuid] = fmt.Sprintf("%d uncorrectable ECC errors", errs)
+		} else if xid, ok := xids[busID]; ok {
+			reasons[uuid] = fmt.Sprintf("Xid %d: %s", xid, criticalXIDs[xid])
+		}
+	}
+}
+
+// rocmUnhealthyReasons returns the reason each unhealthy AMD device is unhealthy, by UUID, from
+// the output of rocm-smi.
+func rocmUnhealthyReasons(devices []device.Device, out []byte) (map[string]string, error) {
+	rocmDevices, err := parseRocmSmi(out)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing output of rocm-smi")
+	}
+	listed := make(map[string]bool)
+	for _, d := range rocmDevices {
+		listed[d.UUID] = true
+	}
+	return missingReasons(devices, device.ROCM, listed, "rocm-smi"), nil
+}
+
+func missingReasons(
+	devices []device.Device, typ device.Type, listed map[string]bool, tool string,
+) map[string]string {
+	reasons := make(map[string]string)
+	for _, d := range devices {
+		if d.Type == typ && !listed[d.UUID] {
+			reasons[d.UUID] = "device is no longer listed by " + tool
+		}
+	}
+	return reasons
+}
+
+// parseXIDs returns the first critical Xid error logged for each GPU in the kernel log, by
+// normalized PCI bus ID. Since the kernel log covers the whole uptime of the host, a GPU with a
+// critical Xid stays unhealthy until the host is rebooted.
+func parseXIDs(kernelLog []byte) map[string]int {
+	xids := make(map[string]int)
+	for _, m := range xidRegExp.FindAllSubmatch(kernelLog, -1) {
+		xid, err := strconv.Atoi(string(m[2]))
+		if err != nil {
+			continue
+		}
+		busID := normalizeBusID(string(m[1]))
+		if _, ok := criticalXIDs[xid]; !ok {
+			continue
+		}
+		if _, ok := xids[busID]; !ok {
+			xids[busID] = xid
+		}
+	}
+	return xids
+}
+
+// normalizeBusID reduces the PCI bus IDs reported by nvidia-smi ("00000000:3B:00.0") and the
+// kernel log ("0000:3b:00") to a common form ("3b:00").
+func normalizeBusID(busID string) string {
+	busID = strings.ToLower(strings.TrimSpace(busID))
+	busID, _, _ = strings.Cut(busID, ".")
+	parts := strings.Split(busID, ":")
+	if len(parts) > 2 {
+		parts = parts[len(parts)-2:]
+	}
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,83 @@
+package detect
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+const testNvidiaSmiList = `GPU 0: NVIDIA A100-SXM4-40GB (UUID: GPU-1b6a0c3e-6f4a-4d0b-9b8e-2c1f3a4b5c6d)
+GPU 1: NVIDIA A100-SXM4-40GB (UUID: GPU-2c7b1d4f-7a5b-4e1c-8c9f-3d2a4b5c6d7e)
+GPU 3: NVIDIA A100-SXM4-40GB (UUID: GPU-4e9d3f6b-9c7d-4a3e-8e1b-5f4c6d7e8f9a)
+Unable to determine the device handle for GPU 0000:C7:00.0: Unknown Error
+`
+
+const testNvidiaSmiHealth = `GPU-1b6a0c3e-6f4a-4d0b-9b8e-2c1f3a4b5c6d, 00000000:07:00.0, 0
+GPU-2c7b1d4f-7a5b-4e1c-8c9f-3d2a4b5c6d7e, 00000000:0F:00.0, 3
+GPU-4e9d3f6b-9c7d-4a3e-8e1b-5f4c6d7e8f9a, 00000000:87:00.0, [N/A]
+`
+
+const testKernelLog = `[  412.120934] NVRM: Xid (PCI:0000:07:00): 13, pid=2213, Graphics SM Warp Exception
+[ 9814.533271] NVRM: Xid (PCI:0000:87:00): 79, pid=0, GPU has fallen off the bus.
+[ 9814.533310] NVRM: GPU 0000:87:00.0: GPU has fallen off the bus.
+`
+
+func testCudaDevices() []device.Device {
+	uuids := []string{
+		"GPU-1b6a0c3e-6f4a-4d0b-9b8e-2c1f3a4b5c6d",
+		"GPU-2c7b1d4f-7a5b-4e1c-8c9f-3d2a4b5c6d7e",
+		"GPU-3d8c2e5a-8b6c-4f2d-9d0a-4e3b5c6d7e8f",
+		"GPU-4e9d3f6b-9c7d-4a3e-8e1b-5f4c6d7e8f9a",
+	}
+	devices := make([]device.Device, 0, len(uuids))
+	for i, uuid := range uuids {
+		devices = append(devices, device.Device{
+			ID: device.ID(i), Brand: "NVIDIA A100-SXM4-40GB", UUID: uuid, Type: device.CUDA,
+		})
+	}
+	return devices
+}
+
+func TestCudaUnhealthyReasons(t *testing.T) {
+	devices := testCudaDevices()
+	reasons, err := cudaUnhealthyReasons(
+		devices, []byte(testNvidiaSmiList), []byte(testNvidiaSmiHealth), []byte(testKernelLog),
+	)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, reasons, map[string]string{
+		devices[1].UUID: "3 uncorrectable ECC errors",
+		devices[2].UUID: "device is no longer listed by nvidia-smi",
+		devices[3].UUID: "Xid 79: GPU has fallen off the bus",
+	})
+
+	// Without access to the kernel log or the health query, only missing devices are reported.
+	reasons, err = cudaUnhealthyReasons(devices, []byte(testNvidiaSmiList), nil, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, reasons, map[string]string{
+		devices[2].UUID: "device is no longer listed by nvidia-smi",
+	})
+
+	_, err = cudaUnhealthyReasons(devices, []byte(testNvidiaSmiList), []byte("GPU-1, 0\n"), nil)
+	assert.ErrorContains(t, err, "exactly 3 fields")
+}
+
+func TestRocmUnhealthyReasons(t *testing.T) {
+	devices := []device.Device{
+		{ID: 0, UUID: "0x7ef570db1b346018", Type: device.ROCM},
+		{ID: 1, UUID: "0x6be2ee3b2b314cfc", Type: device.ROCM},
+		{ID: 4, UUID: "0x1d2e3f4a5b6c7d8e", Type: device.ROCM},
+	}
+	reasons, err := rocmUnhealthyReasons(devices, []byte(testRocmSmiData))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, reasons, map[string]string{
+		"0x1d2e3f4a5b6c7d8e": "device is no longer listed by rocm-smi",
+	})
+}
+
+func TestNormalizeBusID(t *testing.T) {
+	assert.Equal(t, normalizeBusID("00000000:3B:00.0"), "3b:00")
+	assert.Equal(t, normalizeBusID("0000:3b:00"), "3b:00")
+	assert.Equal(t, normalizeBusID(" 3b:00.0"), "3b:00")
+}
@@ -0,0 +1,225 @@
+package imagecache
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/agent/internal/container"
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/agent/pkg/docker"
+	"github.com/determined-ai/determined/agent/pkg/events"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+)
+
+// recentUseGracePeriod is how long an image is kept after a container was started with it, which
+// covers the time between the container starting and it being listed as running.
+const recentUseGracePeriod = 30 * time.Minute
+
+// Cache manages the images stored by the container runtime. It pulls the images that the master
+// asks for ahead of time, removes the least recently used images once they take more than the
+// disk budget and reports the images it holds.
+type Cache struct {
+	log     *logrus.Entry
+	agentID string
+	maxSize int64
+	runtime container.ContainerRuntime
+	// store is nil if the runtime can't list and remove images, in which case images are only
+	// pulled.
+	store container.ImageStore
+
+	mu       sync.Mutex
+	prePull  []string
+	lastUsed map[string]time.Time
+	wake     chan struct{}
+}
+
+// New returns a cache of the images of the runtime.
+func New(opts options.Options, runtime container.ContainerRuntime) *Cache {
+	c := &Cache{
+		log:      logrus.WithField("component", "image-cache"),
+		agentID:  opts.AgentID,
+		maxSize:  int64(opts.ImageCacheMaxSizeGB) << 30,
+		runtime:  runtime,
+		lastUsed: make(map[string]time.Time),
+		wake:     make(chan struct{}, 1),
+	}
+	if store, ok := runtime.(container.ImageStore); ok {
+		c.store = store
+	} else {
+		c.log.Infof("%T can't list images, so images won't be evicted or reported", runtime)
+	}
+	return c
+}
+
+// SetPrePull sets the images to pull ahead of time and keep.
+func (c *Cache) SetPrePull(images []string) {
+	c.mu.Lock()
+	c.prePull = slices.Clone(images)
+	c.mu.Unlock()
+
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Touch records that a container is being started with the image.
+func (c *Cache) Touch(image string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastUsed[aproto.CanonicalImage(image)] = time.Now()
+}
+
+// Run syncs the cache immediately, whenever the pre-pull images change and every period after
+// that, and sends the canonical references of the cached images to out whenever they change. It
+// returns when the context is canceled.
+func (c *Cache) Run(ctx context.Context, period time.Duration, out chan<- []string) {
+	var last []string
+	reported := false
+	t := time.NewTicker(period)
+	defer t.Stop()
+	for {
+		images, err := c.sync(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			c.log.WithError(err).Warn("failed to sync image cache")
+		case c.store != nil && (!reported || !slices.Equal(images, last)):
+			last, reported = images, true
+			select {
+			case out <- images:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-t.C:
+		case <-c.wake:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sync pulls the missing pre-pull images, evicts images past the disk budget and returns the
+// sorted references of the remaining images.
+func (c *Cache) sync(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	prePull := slices.Clone(c.prePull)
+	c.mu.Unlock()
+
+	for _, image := range prePull {
+		err := c.runtime.PullImage(ctx, docker.PullImage{Name: image}, events.NilPublisher[docker.Event]{})
+		if err != nil {
+			c.log.WithError(err).Warnf("failed to pre-pull image %s", image)
+		}
+	}
+	if c.store == nil {
+		return nil, nil
+	}
+
+	images, err := c.store.ListImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.maxSize > 0 {
+		pinned, err := c.pinned(ctx, prePull)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		evicted := evictions(images, pinned, c.lastUsed, c.maxSize)
+		c.mu.Unlock()
+
+		for _, img := range evicted {
+			c.log.Infof("evicting image %s (%v, %d bytes)", img.ID, img.Refs, img.Size)
+			if err := c.store.RemoveImage(ctx, img); err != nil {
+				c.log.WithError(err).Warnf("failed to evict image %s", img.ID)
+				continue
+			}
+			images = slices.DeleteFunc(images, func(i docker.Image) bool { return i.ID == img.ID })
+		}
+	}
+
+	refs := []string{}
+	for _, img := range images {
+		refs = append(refs, img.Refs...)
+	}
+	sort.Strings(refs)
+	return slices.Compact(refs), nil
+}
+
+// pinned returns the references of the images that must not be evicted: the pre-pull images, the
+// images of running containers and recently used images.
+func (c *Cache) pinned(ctx context.Context, prePull []string) (map[string]bool, error) {
+	pinned := make(map[string]bool)
+	for _, image := range prePull {
+		pinned[aproto.CanonicalImage(image)] = true
+	}
+
+	running, err := c.runtime.ListRunningContainers(ctx, docker.LabelFilter(docker.AgentLabel, c.agentID))
+	if err != nil {
+		return nil, err
+	}
+	for _, cont := range running {
+		pinned[aproto.CanonicalImage(cont.Image)] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for image, t := range c.lastUsed {
+		if time.Since(t) < recentUseGracePeriod {
+			pinned[image] = true
+		}
+	}
+	return pinned, nil
+}
+
+// evictions returns the least recently used images to remove to bring the total size of the images
+// within maxSize. Images with a pinned reference are never removed. An image that was never used
+// counts as last used when it was created.
+func evictions(
+	images []docker.Image, pinned map[string]bool, lastUsed map[string]time.Time, maxSize int64,
+) []docker.Image {
+	var total int64
+	var candidates []docker.Image
+	used := make(map[string]time.Time)
+	for _, img := range images {
+		total += img.Size
+
+		used[img.ID] = img.Created
+		isPinned := false
+		for _, ref := range img.Refs {
+			isPinned = isPinned || pinned[ref]
+			if t, ok := lastUsed[ref]; ok && t.After(used[img.ID]) {
+				used[img.ID] = t
+			}
+		}
+		if !isPinned {
+			candidates = append(candidates, img)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return used[candidates[i].ID].Before(used[candidates[j].ID])
+	})
+
+	var evicted []docker.Image
+	for _, img := range candidates {
+		if total <= maxSize {
+			break
+		}
+		evicted = append(evicted, img)
+		total -= img.Size
+	}
+	return evicted
+}
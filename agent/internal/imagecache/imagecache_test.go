@@ -0,0 +1,39 @@
+package imagecache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/agent/pkg/docker"
+)
+
+func TestEvictions(t *testing.T) {
+	now := time.Now()
+	images := []docker.Image{
+		{ID: "old", Refs: []string{"docker.io/library/old:1"}, Size: 4, Created: now.Add(-3 * time.Hour)},
+		{ID: "used", Refs: []string{"docker.io/library/used:1"}, Size: 4, Created: now.Add(-4 * time.Hour)},
+		{ID: "new", Refs: []string{"docker.io/library/new:1"}, Size: 4, Created: now.Add(-time.Hour)},
+		{
+			ID:      "pinned",
+			Refs:    []string{"docker.io/library/pinned:1", "docker.io/library/pinned:latest"},
+			Size:    4,
+			Created: now.Add(-5 * time.Hour),
+		},
+	}
+	pinned := map[string]bool{"docker.io/library/pinned:latest": true}
+	lastUsed := map[string]time.Time{"docker.io/library/used:1": now.Add(-2 * time.Hour)}
+
+	ids := func(images []docker.Image) (ids []string) {
+		for _, img := range images {
+			ids = append(ids, img.ID)
+		}
+		return ids
+	}
+
+	require.Empty(t, evictions(images, pinned, lastUsed, 16))
+	require.Equal(t, []string{"old"}, ids(evictions(images, pinned, lastUsed, 15)))
+	require.Equal(t, []string{"old", "used"}, ids(evictions(images, pinned, lastUsed, 8)))
+	require.Equal(t, []string{"old", "used", "new"}, ids(evictions(images, pinned, lastUsed, 0)))
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"reflect"
 
+	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/check"
 
 	"github.com/pkg/errors"
@@ -29,6 +30,9 @@ type Options struct {
 	APIEnabled bool   `json:"api_enabled"`
 	BindIP     string `json:"bind_ip"`
 	BindPort   int    `json:"bind_port"`
+	// APIToken is the bearer token that requests to the agent status endpoints must present. The
+	// endpoints are disabled without one.
+	APIToken string `json:"api_token"`
 
 	VisibleGPUs string `json:"visible_gpus"`
 
@@ -53,12 +57,26 @@ type Options struct {
 	// master config.
 	AgentReconnectBackoff int `json:"agent_reconnect_backoff"`
 
+	// DeviceHealthCheckPeriod is the time between device health checks, in seconds. Zero disables
+	// health checks.
+	DeviceHealthCheckPeriod int `json:"device_health_check_period"`
+
+	// ImageCacheMaxSizeGB is the disk budget for cached images, in GB. Once images take more, the
+	// least recently used ones are removed. Zero disables eviction.
+	ImageCacheMaxSizeGB int `json:"image_cache_max_size_gb"`
+
+	// CPUPerSlot and MemoryPerSlotGB limit the CPUs and the memory, in GB, that a container gets
+	// for each of its slots, unless its task overrides them. Zero leaves the resource unlimited.
+	CPUPerSlot      float64 `json:"cpu_per_slot"`
+	MemoryPerSlotGB float64 `json:"memory_per_slot_gb"`
+
 	Hooks HooksOptions `json:"hooks"`
 
 	ContainerRuntime   string             `json:"container_runtime"`
 	ImageRoot          string             `json:"image_root"`
 	SingularityOptions SingularityOptions `json:"singularity_options"`
 	PodmanOptions      PodmanOptions      `json:"podman_options"`
+	ContainerdOptions  ContainerdOptions  `json:"containerd_options"`
 
 	Debug bool `json:"debug"`
 }
@@ -86,6 +104,16 @@ func (o Options) Validate() []error {
 		o.validateTLS(),
 		check.In(o.SlotType, []string{"gpu", "cuda", "rocm", "cpu", "auto", "none"}),
 		check.NotEmpty(o.MasterHost, "master host must be provided"),
+		check.GreaterThanOrEqualTo(o.CPUPerSlot, 0.0, "cpu_per_slot must be >= 0"),
+		check.GreaterThanOrEqualTo(o.MemoryPerSlotGB, 0.0, "memory_per_slot_gb must be >= 0"),
+	}
+}
+
+// SlotResources returns the CPU and memory a container gets for each of its slots by default.
+func (o Options) SlotResources() aproto.SlotResources {
+	return aproto.SlotResources{
+		CPU:         o.CPUPerSlot,
+		MemoryBytes: int64(o.MemoryPerSlotGB * (1 << 30)),
 	}
 }
 
@@ -104,6 +132,9 @@ func (o Options) validateTLS() error {
 
 // Printable returns a printable string.
 func (o Options) Printable() ([]byte, error) {
+	if o.APIToken != "" {
+		o.APIToken = "********"
+	}
 	optJSON, err := json.Marshal(o)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to convert config to JSON")
@@ -177,6 +208,7 @@ const (
 	SingularityContainerRuntime = "singularity"
 	DockerContainerRuntime      = "docker"
 	PodmanContainerRuntime      = "podman"
+	ContainerdContainerRuntime  = "containerd"
 )
 
 // SingularityOptions configures how we interact with Singularity.
@@ -191,3 +223,15 @@ type SingularityOptions struct {
 type PodmanOptions struct {
 	AllowNetworkCreation bool `json:"allow_network_creation"` // review
 }
+
+// ContainerdOptions configures how we interact with containerd.
+type ContainerdOptions struct {
+	// Address is the path of the containerd socket.
+	Address string `json:"address"`
+	// Namespace is the containerd namespace that images and containers are kept in.
+	Namespace string `json:"namespace"`
+	// CNIConfDir and CNIBinDirs locate the CNI network configuration and plugins used to set up
+	// networking for containers that don't use the host's network.
+	CNIConfDir string   `json:"cni_conf_dir"`
+	CNIBinDirs []string `json:"cni_bin_dirs"`
+}
@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"sync"
+	"time"
+
+	"github.com/determined-ai/determined/agent/internal/containers"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+// connectionHistorySize is the number of master connection events that are kept.
+const connectionHistorySize = 64
+
+// Master connection events.
+const (
+	connectionEventConnected       = "connected"
+	connectionEventDisconnected    = "disconnected"
+	connectionEventReconnectFailed = "reconnect_failed"
+)
+
+// connectionEvent is a change in the agent's connection to the master.
+type connectionEvent struct {
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`
+	Error string    `json:"error,omitempty"`
+}
+
+// masterConnection describes the agent's connection to the master.
+type masterConnection struct {
+	Address   string            `json:"address"`
+	Connected bool              `json:"connected"`
+	History   []connectionEvent `json:"history"`
+}
+
+// agentStatus is the state of the agent that the agent API reports. The agent updates it as it
+// runs, and the API server reads it, so it outlives master connections.
+type agentStatus struct {
+	mu          sync.RWMutex
+	manager     *containers.Manager
+	devices     []device.Device
+	connected   bool
+	history     []connectionEvent
+	draining    bool
+	drainNotify chan struct{}
+}
+
+func newAgentStatus() *agentStatus {
+	return &agentStatus{drainNotify: make(chan struct{}, 1)}
+}
+
+// started records the devices and container manager of the agent once it started.
+func (s *agentStatus) started(manager *containers.Manager, devices []device.Device) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.manager, s.devices = manager, devices
+	if s.draining {
+		manager.Drain()
+	}
+}
+
+// connectionChanged records a change in the agent's connection to the master.
+func (s *agentStatus) connectionChanged(event string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connected = event == connectionEventConnected
+
+	e := connectionEvent{Time: time.Now().UTC(), Event: event}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	s.history = append(s.history, e)
+	if len(s.history) > connectionHistorySize {
+		s.history = s.history[len(s.history)-connectionHistorySize:]
+	}
+}
+
+// drain makes the agent refuse new containers and notifies the agent that it must tell the master.
+func (s *agentStatus) drain() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.draining {
+		return
+	}
+	s.draining = true
+	if s.manager != nil {
+		s.manager.Drain()
+	}
+	select {
+	case s.drainNotify <- struct{}{}:
+	default:
+	}
+}
+
+// isDraining returns whether the agent was drained locally.
+func (s *agentStatus) isDraining() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.draining
+}
+
+// containers returns the containers managed by the agent, or nil if it hasn't started yet.
+func (s *agentStatus) containers() []cproto.Container {
+	s.mu.RLock()
+	manager := s.manager
+	s.mu.RUnlock()
+	if manager == nil {
+		return nil
+	}
+	return manager.Containers()
+}
+
+// detectedDevices returns the devices detected by the agent, or nil if it hasn't started yet.
+func (s *agentStatus) detectedDevices() []device.Device {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.devices
+}
+
+// connection returns the state and history of the agent's connection to the master.
+func (s *agentStatus) connection(address string) masterConnection {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return masterConnection{
+		Address:   address,
+		Connected: s.connected,
+		History:   append([]connectionEvent{}, s.history...),
+	}
+}
@@ -0,0 +1,473 @@
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"syscall"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/cio"
+	"github.com/containerd/containerd/containers"
+	"github.com/containerd/containerd/contrib/nvidia"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/oci"
+	dockerremote "github.com/containerd/containerd/remotes/docker"
+	cni "github.com/containerd/go-cni"
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/agent/pkg/docker"
+)
+
+// cfsPeriod is the CFS period, in microseconds, against which CPU limits are set as quotas, the
+// same as docker uses.
+const cfsPeriod = 100000
+
+// containerdBackend implements backend with the containerd client and CNI.
+type containerdBackend struct {
+	cl *containerd.Client
+	// network is the CNI network of containers that don't use host networking. It is unusable,
+	// and networkErr says why, if there is no CNI configuration.
+	network    cni.CNI
+	networkErr error
+}
+
+func newContainerdBackend(opts options.ContainerdOptions) (*containerdBackend, error) {
+	cl, err := containerd.New(opts.Address, containerd.WithDefaultNamespace(opts.Namespace))
+	if err != nil {
+		return nil, fmt.Errorf("connecting to containerd at %s: %w", opts.Address, err)
+	}
+
+	b := &containerdBackend{cl: cl}
+	b.network, b.networkErr = cni.New(
+		cni.WithPluginDir(opts.CNIBinDirs),
+		cni.WithPluginConfDir(opts.CNIConfDir),
+		cni.WithMinNetworkCount(2),
+	)
+	if b.networkErr == nil {
+		b.networkErr = b.network.Load(cni.WithLoNetwork, cni.WithDefaultConf)
+	}
+	if b.networkErr != nil {
+		b.networkErr = fmt.Errorf(
+			"bridge networking requires a CNI configuration in %s: %w", opts.CNIConfDir, b.networkErr)
+	}
+	return b, nil
+}
+
+// Close closes the connection to containerd.
+func (b *containerdBackend) Close() error {
+	return b.cl.Close()
+}
+
+func (b *containerdBackend) ImageExists(ctx context.Context, ref string) (bool, error) {
+	switch _, err := b.cl.GetImage(ctx, ref); {
+	case errdefs.IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	default:
+		return true, nil
+	}
+}
+
+func (b *containerdBackend) Pull(ctx context.Context, ref string, auth *types.AuthConfig) error {
+	resolver := dockerremote.NewResolver(dockerremote.ResolverOptions{
+		Hosts: dockerremote.ConfigureDefaultRegistries(dockerremote.WithAuthorizer(
+			dockerremote.NewDockerAuthorizer(dockerremote.WithAuthCreds(credentials(auth))),
+		)),
+	})
+	_, err := b.cl.Pull(ctx, ref, containerd.WithPullUnpack, containerd.WithResolver(resolver))
+	return err
+}
+
+func (b *containerdBackend) Create(ctx context.Context, spec containerSpec) error {
+	image, err := b.cl.GetImage(ctx, spec.Image)
+	if err != nil {
+		return fmt.Errorf("getting image %s: %w", spec.Image, err)
+	}
+
+	var opts []oci.SpecOpts
+	if len(spec.Entrypoint) > 0 {
+		args := append(append([]string{}, spec.Entrypoint...), spec.Cmd...)
+		opts = append(opts, oci.WithImageConfig(image), oci.WithProcessArgs(args...))
+	} else {
+		opts = append(opts, oci.WithImageConfigArgs(image, spec.Cmd))
+	}
+	opts = append(opts, specOpts(spec)...)
+
+	_, err = b.cl.NewContainer(ctx, spec.ID,
+		containerd.WithImage(image),
+		containerd.WithNewSnapshot(spec.ID, image),
+		containerd.WithNewSpec(opts...),
+		containerd.WithContainerLabels(spec.Labels),
+	)
+	return err
+}
+
+func (b *containerdBackend) Start(
+	ctx, waitCtx context.Context, id, logPath string,
+) (_ *taskState, _ <-chan exitStatus, err error) {
+	cont, err := b.cl.LoadContainer(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	labels, err := cont.Labels(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	ports, err := parsePorts(labels[portsLabel])
+	if err != nil {
+		return nil, nil, err
+	}
+	bridge := labels[networkModeLabel] != hostNetworking
+	if bridge && b.networkErr != nil {
+		return nil, nil, b.networkErr
+	}
+
+	task, err := cont.NewTask(ctx, cio.LogFile(logPath))
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating task: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if _, dErr := task.Delete(context.Background(), containerd.WithProcessKill); dErr != nil {
+				err = fmt.Errorf("%w (and cleaning up task: %s)", err, dErr)
+			}
+		}
+	}()
+
+	// Wait before start to not miss immediate exits.
+	exits, err := task.Wait(waitCtx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("waiting on task: %w", err)
+	}
+
+	if bridge {
+		result, err := b.network.Setup(
+			ctx, id, fmt.Sprintf("/proc/%d/ns/net", task.Pid()),
+			cni.WithCapabilityPortMap(portMappings(ports)),
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("setting up network: %w", err)
+		}
+		for name, iface := range result.Interfaces {
+			if strings.HasPrefix(name, "eth") && len(iface.IPConfigs) > 0 {
+				labels[ipAddressLabel] = iface.IPConfigs[0].IP.String()
+				break
+			}
+		}
+		if labels, err = cont.SetLabels(ctx, labels); err != nil {
+			return nil, nil, fmt.Errorf("recording container address: %w", err)
+		}
+	}
+
+	if err := task.Start(ctx); err != nil {
+		return nil, nil, fmt.Errorf("starting task: %w", err)
+	}
+
+	info, err := cont.Info(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &taskState{
+		ID:      id,
+		Image:   info.Image,
+		Labels:  labels,
+		Created: info.CreatedAt,
+		Pid:     task.Pid(),
+		Running: true,
+	}, translateExits(exits), nil
+}
+
+func (b *containerdBackend) Attach(
+	ctx, waitCtx context.Context, id string,
+) (*taskState, <-chan exitStatus, error) {
+	cont, err := b.cl.LoadContainer(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	state, task, err := b.state(ctx, cont)
+	switch {
+	case err != nil:
+		return nil, nil, err
+	case task == nil:
+		return nil, nil, errdefs.ErrNotFound
+	case !state.Running:
+		return state, nil, nil
+	}
+
+	exits, err := task.Wait(waitCtx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("waiting on task: %w", err)
+	}
+	// The task may have exited before we started waiting on it.
+	status, err := task.Status(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if status.Status == containerd.Stopped {
+		state.Running = false
+		state.ExitCode = status.ExitStatus
+		return state, nil, nil
+	}
+	return state, translateExits(exits), nil
+}
+
+func (b *containerdBackend) Kill(ctx context.Context, id string, sig syscall.Signal) error {
+	cont, err := b.cl.LoadContainer(ctx, id)
+	if err != nil {
+		return err
+	}
+	task, err := cont.Task(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return task.Kill(ctx, sig)
+}
+
+func (b *containerdBackend) Delete(ctx context.Context, id string) error {
+	cont, err := b.cl.LoadContainer(ctx, id)
+	if err != nil {
+		return err
+	}
+	labels, err := cont.Labels(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch task, err := cont.Task(ctx, nil); {
+	case errdefs.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		if _, err := task.Delete(ctx, containerd.WithProcessKill); err != nil {
+			return fmt.Errorf("deleting task: %w", err)
+		}
+	}
+
+	if labels[networkModeLabel] != hostNetworking && b.networkErr == nil {
+		ports, err := parsePorts(labels[portsLabel])
+		if err != nil {
+			return err
+		}
+		// The network namespace is gone with the task; plugins release what they allocated for
+		// the container by its ID.
+		if err := b.network.Remove(
+			ctx, id, "", cni.WithCapabilityPortMap(portMappings(ports)),
+		); err != nil {
+			return fmt.Errorf("tearing down network: %w", err)
+		}
+	}
+
+	return cont.Delete(ctx, containerd.WithSnapshotCleanup)
+}
+
+func (b *containerdBackend) List(
+	ctx context.Context, labels map[string]string,
+) ([]taskState, error) {
+	var fs []string
+	for k, v := range labels {
+		fs = append(fs, fmt.Sprintf("labels.%q==%q", k, v))
+	}
+	// Filters in separate strings are alternatives; comma-separated ones must all match.
+	conts, err := b.cl.Containers(ctx, strings.Join(fs, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	var states []taskState
+	for _, cont := range conts {
+		state, _, err := b.state(ctx, cont)
+		if errdefs.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		states = append(states, *state)
+	}
+	return states, nil
+}
+
+func (b *containerdBackend) Images(ctx context.Context) ([]docker.Image, error) {
+	imgs, err := b.cl.ListImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]docker.Image, 0, len(imgs))
+	for _, img := range imgs {
+		size, err := img.Size(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("getting size of image %s: %w", img.Name(), err)
+		}
+		result = append(result, docker.Image{
+			ID:      img.Name(),
+			Refs:    []string{img.Name()},
+			Size:    size,
+			Created: img.Metadata().CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (b *containerdBackend) RemoveImage(ctx context.Context, name string) error {
+	return b.cl.ImageService().Delete(ctx, name, images.SynchronousDelete())
+}
+
+// state returns the state of a container and its task, which is nil if it was never started.
+func (b *containerdBackend) state(
+	ctx context.Context, cont containerd.Container,
+) (*taskState, containerd.Task, error) {
+	info, err := cont.Info(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	state := &taskState{
+		ID:      cont.ID(),
+		Image:   info.Image,
+		Labels:  info.Labels,
+		Created: info.CreatedAt,
+	}
+
+	task, err := cont.Task(ctx, nil)
+	if errdefs.IsNotFound(err) {
+		return state, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	status, err := task.Status(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	state.Pid = task.Pid()
+	state.Running = status.Status != containerd.Stopped
+	state.ExitCode = status.ExitStatus
+	return state, task, nil
+}
+
+// specOpts converts everything in a container spec but its image and command to OCI spec options.
+func specOpts(spec containerSpec) []oci.SpecOpts {
+	opts := []oci.SpecOpts{oci.WithEnv(spec.Env), oci.WithHostResolvconf}
+	if spec.WorkingDir != "" {
+		opts = append(opts, oci.WithProcessCwd(spec.WorkingDir))
+	}
+	if spec.User != "" {
+		opts = append(opts, oci.WithUser(spec.User))
+	}
+	if len(spec.GroupAdd) > 0 {
+		opts = append(opts, oci.WithAppendAdditionalGroups(spec.GroupAdd...))
+	}
+	opts = append(opts,
+		oci.WithMounts(spec.Mounts),
+		oci.WithAddedCapabilities(ociCapabilities(spec.CapAdd)),
+		oci.WithDroppedCapabilities(ociCapabilities(spec.CapDrop)),
+	)
+	if spec.HostNetwork {
+		opts = append(opts, oci.WithHostNamespace(specs.NetworkNamespace), oci.WithHostHostsFile)
+	}
+	if spec.ShmSize > 0 {
+		opts = append(opts, oci.WithDevShmSize(spec.ShmSize/1024))
+	}
+	if spec.NanoCPUs > 0 {
+		opts = append(opts, oci.WithCPUCFS(spec.NanoCPUs*cfsPeriod/1e9, cfsPeriod))
+	}
+	if spec.Memory > 0 {
+		opts = append(opts, oci.WithMemoryLimit(uint64(spec.Memory)))
+	}
+	if len(spec.GPUs) > 0 {
+		opts = append(opts, nvidia.WithGPUs(
+			nvidia.WithDeviceUUIDs(spec.GPUs...), nvidia.WithAllCapabilities))
+	}
+	for _, d := range spec.Devices {
+		opts = append(opts, withDevice(d))
+	}
+	return opts
+}
+
+func translateExits(in <-chan containerd.ExitStatus) <-chan exitStatus {
+	out := make(chan exitStatus, 1)
+	go func() {
+		defer close(out)
+		if s, ok := <-in; ok {
+			out <- exitStatus{Code: s.ExitCode(), Err: s.Error()}
+		}
+	}()
+	return out
+}
+
+func portMappings(ports map[int]int) []cni.PortMapping {
+	var mappings []cni.PortMapping
+	for containerPort, hostPort := range ports {
+		mappings = append(mappings, cni.PortMapping{
+			HostPort:      int32(hostPort),
+			ContainerPort: int32(containerPort),
+			Protocol:      "tcp",
+		})
+	}
+	return mappings
+}
+
+// withDevice makes a host device available in the container, possibly at a different path.
+func withDevice(d dcontainer.DeviceMapping) oci.SpecOpts {
+	return func(ctx context.Context, cl oci.Client, c *containers.Container, s *oci.Spec) error {
+		perms := d.CgroupPermissions
+		if perms == "" {
+			perms = "rwm"
+		}
+		if err := oci.WithLinuxDevice(d.PathOnHost, perms)(ctx, cl, c, s); err != nil {
+			return fmt.Errorf("adding device %s: %w", d.PathOnHost, err)
+		}
+		if d.PathInContainer != "" {
+			s.Linux.Devices[len(s.Linux.Devices)-1].Path = d.PathInContainer
+		}
+		return nil
+	}
+}
+
+// ociCapabilities converts capabilities from Docker's form ("SYS_ADMIN") to the OCI form
+// ("CAP_SYS_ADMIN").
+func ociCapabilities(caps []string) []string {
+	result := make([]string, 0, len(caps))
+	for _, c := range caps {
+		c = strings.ToUpper(c)
+		if !strings.HasPrefix(c, "CAP_") {
+			c = "CAP_" + c
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// credentials returns the registry credentials callback for a registry_auth setting. Credentials
+// are only sent to the registry they are for; without a server address, they are sent to any.
+func credentials(auth *types.AuthConfig) func(host string) (string, string, error) {
+	return func(host string) (string, string, error) {
+		if auth == nil || !registryMatches(auth.ServerAddress, host) {
+			return "", "", nil
+		}
+		if auth.IdentityToken != "" {
+			return "", auth.IdentityToken, nil
+		}
+		return auth.Username, auth.Password, nil
+	}
+}
+
+func registryMatches(serverAddress, host string) bool {
+	if serverAddress == "" {
+		return true
+	}
+	normalize := func(addr string) string {
+		addr = strings.TrimPrefix(strings.TrimPrefix(addr, "https://"), "http://")
+		addr, _, _ = strings.Cut(addr, "/")
+		switch addr {
+		case "index.docker.io", "registry-1.docker.io":
+			return "docker.io"
+		}
+		return addr
+	}
+	return normalize(serverAddress) == normalize(host)
+}
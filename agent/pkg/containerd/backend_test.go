@@ -0,0 +1,92 @@
+package containerd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containerd/containerd/containers"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/oci"
+	dcontainer "github.com/docker/docker/api/types/container"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/require"
+)
+
+func generateSpec(t *testing.T, spec containerSpec) *oci.Spec {
+	ctx := namespaces.WithNamespace(context.Background(), "test")
+	s, err := oci.GenerateSpec(ctx, nil, &containers.Container{ID: spec.ID}, specOpts(spec)...)
+	require.NoError(t, err)
+	return s
+}
+
+func findMount(s *oci.Spec, destination string) *specs.Mount {
+	for i := range s.Mounts {
+		if s.Mounts[i].Destination == destination {
+			return &s.Mounts[i]
+		}
+	}
+	return nil
+}
+
+func hasNamespace(s *oci.Spec, typ specs.LinuxNamespaceType) bool {
+	for _, ns := range s.Linux.Namespaces {
+		if ns.Type == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSpecOpts(t *testing.T) {
+	s := generateSpec(t, containerSpec{
+		ID:         "test",
+		Env:        []string{"FOO=bar"},
+		WorkingDir: "/run/determined/workdir",
+		User:       "1000:1001",
+		Mounts: []specs.Mount{{
+			Destination: "/data", Source: "/mnt/data", Type: "bind", Options: []string{"rbind", "ro"},
+		}},
+		Devices:  []dcontainer.DeviceMapping{{PathOnHost: "/dev/null", PathInContainer: "/dev/fake"}},
+		CapAdd:   []string{"sys_admin"},
+		CapDrop:  []string{"NET_RAW"},
+		ShmSize:  4 << 20,
+		NanoCPUs: 1.5e9,
+		Memory:   1 << 30,
+	})
+
+	require.Contains(t, s.Process.Env, "FOO=bar")
+	require.Equal(t, "/run/determined/workdir", s.Process.Cwd)
+	require.Equal(t, uint32(1000), s.Process.User.UID)
+	require.Equal(t, uint32(1001), s.Process.User.GID)
+
+	require.Equal(t, &specs.Mount{
+		Destination: "/data", Source: "/mnt/data", Type: "bind", Options: []string{"rbind", "ro"},
+	}, findMount(s, "/data"))
+	require.NotNil(t, findMount(s, "/etc/resolv.conf"))
+	require.Contains(t, findMount(s, "/dev/shm").Options, "size=4096k")
+
+	require.Contains(t, s.Process.Capabilities.Bounding, "CAP_SYS_ADMIN")
+	require.NotContains(t, s.Process.Capabilities.Bounding, "CAP_NET_RAW")
+
+	require.Equal(t, int64(150000), *s.Linux.Resources.CPU.Quota)
+	require.Equal(t, uint64(cfsPeriod), *s.Linux.Resources.CPU.Period)
+	require.Equal(t, int64(1<<30), *s.Linux.Resources.Memory.Limit)
+
+	device := s.Linux.Devices[len(s.Linux.Devices)-1]
+	require.Equal(t, "/dev/fake", device.Path)
+	require.Contains(t, s.Linux.Resources.Devices, specs.LinuxDeviceCgroup{
+		Allow: true, Type: device.Type, Major: &device.Major, Minor: &device.Minor, Access: "rwm",
+	})
+
+	require.True(t, hasNamespace(s, specs.NetworkNamespace))
+	require.Nil(t, findMount(s, "/etc/hosts"))
+}
+
+func TestSpecOptsHostNetwork(t *testing.T) {
+	s := generateSpec(t, containerSpec{ID: "test", HostNetwork: true})
+
+	require.False(t, hasNamespace(s, specs.NetworkNamespace))
+	require.NotNil(t, findMount(s, "/etc/hosts"))
+	require.Nil(t, s.Linux.Resources.CPU)
+	require.Nil(t, s.Linux.Resources.Memory)
+}
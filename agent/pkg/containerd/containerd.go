@@ -0,0 +1,599 @@
+// Package containerd implements the agent's container runtime on top of containerd, for hosts that
+// run containerd without dockerd.
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/agent/internal/container"
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/agent/pkg/cruntimes"
+	"github.com/determined-ai/determined/agent/pkg/docker"
+	"github.com/determined-ai/determined/agent/pkg/events"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+const (
+	// networkModeLabel, portsLabel and ipAddressLabel record a container's networking on the
+	// container itself, so that it can be reported and torn down after the agent restarts.
+	networkModeLabel = "ai.determined.containerd.network-mode"
+	portsLabel       = "ai.determined.containerd.ports"
+	ipAddressLabel   = "ai.determined.containerd.ip"
+	// autoRemoveLabel marks containers that are removed once they exit.
+	autoRemoveLabel = "ai.determined.containerd.auto-remove"
+
+	hostNetworking   = "host"
+	bridgeNetworking = "bridge"
+	archivesName     = "archives"
+	logFileName      = "container.log"
+)
+
+// containerSpec is everything needed to create a containerd container for a RunSpec.
+type containerSpec struct {
+	ID          string
+	Image       string
+	Entrypoint  []string
+	Cmd         []string
+	Env         []string
+	WorkingDir  string
+	User        string
+	GroupAdd    []string
+	Labels      map[string]string
+	Mounts      []specs.Mount
+	Devices     []dcontainer.DeviceMapping
+	GPUs        []string
+	CapAdd      []string
+	CapDrop     []string
+	HostNetwork bool
+	ShmSize     int64
+	NanoCPUs    int64
+	Memory      int64
+}
+
+// taskState is the state of a container and its task.
+type taskState struct {
+	ID       string
+	Image    string
+	Labels   map[string]string
+	Created  time.Time
+	Pid      uint32
+	Running  bool
+	ExitCode uint32
+}
+
+// exitStatus is how a task exited.
+type exitStatus struct {
+	Code uint32
+	Err  error
+}
+
+// backend is the part of containerd and CNI that Client needs. Errors for containers or images
+// that don't exist satisfy errdefs.IsNotFound.
+type backend interface {
+	// ImageExists returns whether the image has been pulled.
+	ImageExists(ctx context.Context, ref string) (bool, error)
+	// Pull pulls and unpacks an image, authenticating with the given credentials, if any.
+	Pull(ctx context.Context, ref string, auth *types.AuthConfig) error
+	// Create creates a container without starting it.
+	Create(ctx context.Context, spec containerSpec) error
+	// Start starts a created container, sets up its network and writes its output to logPath.
+	// The returned channel receives the exit status until waitCtx is canceled.
+	Start(
+		ctx, waitCtx context.Context, id, logPath string,
+	) (*taskState, <-chan exitStatus, error)
+	// Attach returns the state of a started container and, if it is running, a channel that
+	// receives its exit status until waitCtx is canceled.
+	Attach(ctx, waitCtx context.Context, id string) (*taskState, <-chan exitStatus, error)
+	// Kill signals the container's task.
+	Kill(ctx context.Context, id string, sig syscall.Signal) error
+	// Delete kills the container's task, if any, tears down its network and deletes it.
+	Delete(ctx context.Context, id string) error
+	// List returns the containers with all the given labels.
+	List(ctx context.Context, labels map[string]string) ([]taskState, error)
+	// Images returns the pulled images. Each image has a single reference, its name.
+	Images(ctx context.Context) ([]docker.Image, error)
+	// RemoveImage deletes an image by name and waits for its unused content to be collected.
+	RemoveImage(ctx context.Context, name string) error
+}
+
+// Client implements container.ContainerRuntime with containerd.
+type Client struct {
+	log      *logrus.Entry
+	b        backend
+	stateDir string
+}
+
+// New connects to containerd as configured and returns a client that launches and tracks
+// containers. Containers are kept in the configured containerd namespace and survive agent
+// restarts, so that they can be reattached.
+func New(opts options.Options) (*Client, error) {
+	b, err := newContainerdBackend(opts.ContainerdOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	baseTmp, err := cruntimes.BaseTempDirName(opts.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compose state directory path: %w", err)
+	}
+	return newClient(b, baseTmp+"-containerd")
+}
+
+func newClient(b backend, stateDir string) (*Client, error) {
+	if err := os.MkdirAll(stateDir, 0o700); err != nil {
+		return nil, fmt.Errorf("preparing containerd state directory: %w", err)
+	}
+	return &Client{
+		log:      logrus.WithField("component", "containerd"),
+		b:        b,
+		stateDir: stateDir,
+	}, nil
+}
+
+// Close closes the connection to containerd. Containers keep running.
+func (c *Client) Close() error {
+	if cl, ok := c.b.(interface{ Close() error }); ok {
+		return cl.Close()
+	}
+	return nil
+}
+
+// PullImage implements container.ContainerRuntime.
+func (c *Client) PullImage(
+	ctx context.Context,
+	req docker.PullImage,
+	p events.Publisher[docker.Event],
+) error {
+	ref, err := canonicalImage(req.Name)
+	if err != nil {
+		return err
+	}
+
+	switch exists, err := c.b.ImageExists(ctx, ref); {
+	case err != nil:
+		return fmt.Errorf("error checking if image exists %s: %w", ref, err)
+	case exists && !req.ForcePull:
+		return p.Publish(ctx, docker.NewLogEvent(model.LogLevelInfo, fmt.Sprintf(
+			"image already found, skipping pull phase: %s", ref,
+		)))
+	case exists:
+		if err = p.Publish(ctx, docker.NewLogEvent(model.LogLevelInfo, fmt.Sprintf(
+			"image present, but force_pull_image is set; checking for updates: %s", ref,
+		))); err != nil {
+			return err
+		}
+	default:
+		if err = p.Publish(ctx, docker.NewLogEvent(model.LogLevelInfo, fmt.Sprintf(
+			"image not found, pulling image: %s", ref,
+		))); err != nil {
+			return err
+		}
+	}
+
+	if err = p.Publish(ctx, docker.NewBeginStatsEvent(docker.ImagePullStatsKind)); err != nil {
+		return err
+	}
+	defer func() {
+		if scErr := p.Publish(ctx, docker.NewEndStatsEvent(docker.ImagePullStatsKind)); scErr != nil {
+			c.log.WithError(scErr).Warn("did not send image pull done stats")
+		}
+	}()
+
+	if err := c.b.Pull(ctx, ref, req.Registry); err != nil {
+		return fmt.Errorf("error pulling image %s: %w", ref, err)
+	}
+	return nil
+}
+
+// CreateContainer implements container.ContainerRuntime. The container's archives are written to
+// the agent's state directory and bind mounted, and host ports are picked for its exposed ports.
+func (c *Client) CreateContainer(
+	ctx context.Context,
+	id cproto.ID,
+	req cproto.RunSpec,
+	p events.Publisher[docker.Event],
+) (string, error) {
+	ref, err := canonicalImage(req.ContainerConfig.Image)
+	if err != nil {
+		return "", err
+	}
+
+	spec := containerSpec{
+		ID:         id.String(),
+		Image:      ref,
+		Entrypoint: req.ContainerConfig.Entrypoint,
+		Cmd:        req.ContainerConfig.Cmd,
+		Env:        req.ContainerConfig.Env,
+		WorkingDir: req.ContainerConfig.WorkingDir,
+		User:       req.ContainerConfig.User,
+		GroupAdd:   req.HostConfig.GroupAdd,
+		Labels:     make(map[string]string),
+		Devices:    req.HostConfig.Devices,
+		CapAdd:     req.HostConfig.CapAdd,
+		CapDrop:    req.HostConfig.CapDrop,
+		ShmSize:    req.HostConfig.ShmSize,
+		NanoCPUs:   req.HostConfig.NanoCPUs,
+		Memory:     req.HostConfig.Memory,
+	}
+	for k, v := range req.ContainerConfig.Labels {
+		spec.Labels[k] = v
+	}
+	if req.HostConfig.AutoRemove {
+		spec.Labels[autoRemoveLabel] = "true"
+	}
+	for _, dr := range req.HostConfig.DeviceRequests {
+		if dr.Driver == "nvidia" {
+			spec.GPUs = append(spec.GPUs, dr.DeviceIDs...)
+		}
+	}
+
+	ports := make(map[int]int)
+	switch mode := req.HostConfig.NetworkMode; mode {
+	case hostNetworking:
+		spec.HostNetwork = true
+		for port := range req.ContainerConfig.ExposedPorts {
+			ports[port.Int()] = port.Int()
+		}
+	case "", "default", bridgeNetworking:
+		for port := range req.ContainerConfig.ExposedPorts {
+			hostPort, err := freePort()
+			if err != nil {
+				return "", fmt.Errorf("picking a host port for %s: %w", port, err)
+			}
+			ports[port.Int()] = hostPort
+		}
+	default:
+		return "", fmt.Errorf("unsupported network mode %s", mode)
+	}
+	spec.Labels[networkModeLabel] = bridgeNetworking
+	if spec.HostNetwork {
+		spec.Labels[networkModeLabel] = hostNetworking
+	}
+	spec.Labels[portsLabel] = formatPorts(ports)
+
+	for _, m := range req.HostConfig.Mounts {
+		sm, err := toSpecMount(m)
+		if err != nil {
+			return "", err
+		}
+		spec.Mounts = append(spec.Mounts, sm)
+	}
+
+	dir := c.containerDir(spec.ID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("making state dir for container: %w", err)
+	}
+	archivesPath := filepath.Join(dir, archivesName)
+	mountPoints, err := cruntimes.ArchiveMountPoints(ctx, req, p, archivesPath, c.log)
+	if err != nil {
+		return "", fmt.Errorf("determining mount points: %w", err)
+	}
+	for _, m := range mountPoints {
+		spec.Mounts = append(spec.Mounts, specs.Mount{
+			Destination: m,
+			Source:      filepath.Join(archivesPath, m),
+			Type:        "bind",
+			Options:     []string{"rbind", "rw"},
+		})
+	}
+
+	if err := c.b.Create(ctx, spec); err != nil {
+		return "", fmt.Errorf("creating container: %w", err)
+	}
+	return spec.ID, nil
+}
+
+// RunContainer implements container.ContainerRuntime.
+// nolint: golint // Both contexts can't both be first.
+func (c *Client) RunContainer(
+	ctx context.Context,
+	waitCtx context.Context,
+	id string,
+	p events.Publisher[docker.Event],
+) (*docker.Container, error) {
+	state, exits, err := c.b.Start(ctx, waitCtx, id, filepath.Join(c.containerDir(id), logFileName))
+	switch {
+	case errdefs.IsNotFound(err):
+		return nil, container.ErrMissing
+	case err != nil:
+		return nil, fmt.Errorf("starting container: %w", err)
+	}
+	c.log.Infof("started container %s with pid %d", id, state.Pid)
+	return c.toDockerContainer(waitCtx, state, exits), nil
+}
+
+// ReattachContainer implements container.ContainerRuntime.
+func (c *Client) ReattachContainer(
+	ctx context.Context,
+	id cproto.ID,
+) (*docker.Container, *aproto.ExitCode, error) {
+	state, exits, err := c.b.Attach(ctx, ctx, id.String())
+	switch {
+	case errdefs.IsNotFound(err):
+		return nil, nil, nil
+	case err != nil:
+		return nil, nil, fmt.Errorf("while reattaching container: %w", err)
+	case !state.Running:
+		c.removeIfAutoRemove(ctx, state)
+		return nil, ptrs.Ptr(aproto.ExitCode(state.ExitCode)), nil
+	}
+	return c.toDockerContainer(ctx, state, exits), nil, nil
+}
+
+// SignalContainer implements container.ContainerRuntime.
+func (c *Client) SignalContainer(ctx context.Context, id string, sig syscall.Signal) error {
+	if err := c.b.Kill(ctx, id, sig); errdefs.IsNotFound(err) {
+		return container.ErrMissing
+	} else if err != nil {
+		return fmt.Errorf("signaling container %s with %s: %w", id, sig, err)
+	}
+	return nil
+}
+
+// RemoveContainer implements container.ContainerRuntime. Running containers are killed, whether
+// or not force is set, since containerd can't delete a container with a running task.
+func (c *Client) RemoveContainer(ctx context.Context, id string, force bool) error {
+	if err := c.b.Delete(ctx, id); errdefs.IsNotFound(err) {
+		return container.ErrMissing
+	} else if err != nil {
+		return fmt.Errorf("removing container %s: %w", id, err)
+	}
+	if err := os.RemoveAll(c.containerDir(id)); err != nil {
+		return fmt.Errorf("cleaning up state dir of container %s: %w", id, err)
+	}
+	return nil
+}
+
+// ListRunningContainers implements container.ContainerRuntime. Only label filters are supported.
+// Containers matching the filters that have exited can't be reattached and are removed.
+// ListImages lists the images pulled into containerd. Images that share content are listed
+// separately, by name.
+func (c *Client) ListImages(ctx context.Context) ([]docker.Image, error) {
+	images, err := c.b.Images(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing images: %w", err)
+	}
+	return images, nil
+}
+
+// RemoveImage removes an image from containerd.
+func (c *Client) RemoveImage(ctx context.Context, img docker.Image) error {
+	if err := c.b.RemoveImage(ctx, img.ID); err != nil {
+		return fmt.Errorf("removing image %s: %w", img.ID, err)
+	}
+	return nil
+}
+
+func (c *Client) ListRunningContainers(
+	ctx context.Context,
+	fs filters.Args,
+) (map[cproto.ID]types.Container, error) {
+	labels, err := labelFilters(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	states, err := c.b.List(ctx, labels)
+	if err != nil {
+		return nil, fmt.Errorf("listing containers: %w", err)
+	}
+
+	result := make(map[cproto.ID]types.Container, len(states))
+	for _, state := range states {
+		if !state.Running {
+			c.log.Infof("removing exited container %s", state.ID)
+			if err := c.RemoveContainer(ctx, state.ID, true); err != nil {
+				c.log.WithError(err).Warnf("failed to remove exited container %s", state.ID)
+			}
+			continue
+		}
+
+		containerID, ok := state.Labels[docker.ContainerIDLabel]
+		if !ok {
+			c.log.Warnf("container %v has agent label but no container ID", state.ID)
+			continue
+		}
+		result[cproto.ID(containerID)] = types.Container{
+			ID:      state.ID,
+			Image:   state.Image,
+			Created: state.Created.Unix(),
+			Labels:  state.Labels,
+			State:   "running",
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) containerDir(id string) string {
+	return filepath.Join(c.stateDir, id)
+}
+
+func (c *Client) removeIfAutoRemove(ctx context.Context, state *taskState) {
+	if state.Labels[autoRemoveLabel] != "true" {
+		return
+	}
+	if err := c.RemoveContainer(ctx, state.ID, true); err != nil {
+		c.log.WithError(err).Warnf("failed to remove exited container %s", state.ID)
+	}
+}
+
+// toDockerContainer describes a running container the way the Docker runtime does, which is what
+// the agent and master expect, and forwards its exit.
+func (c *Client) toDockerContainer(
+	waitCtx context.Context, state *taskState, exits <-chan exitStatus,
+) *docker.Container {
+	ports, err := parsePorts(state.Labels[portsLabel])
+	if err != nil {
+		c.log.WithError(err).Warnf("ignoring ports of container %s", state.ID)
+	}
+	exposed := make(nat.PortSet, len(ports))
+	bindings := make(nat.PortMap, len(ports))
+	for containerPort, hostPort := range ports {
+		port := nat.Port(fmt.Sprintf("%d/tcp", containerPort))
+		exposed[port] = struct{}{}
+		bindings[port] = []nat.PortBinding{{HostPort: strconv.Itoa(hostPort)}}
+	}
+
+	mode := state.Labels[networkModeLabel]
+	created := state.Created.Format(time.RFC3339Nano)
+	info := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:      state.ID,
+			Created: created,
+			State: &types.ContainerState{
+				Status:    "running",
+				Running:   true,
+				Pid:       int(state.Pid),
+				StartedAt: created,
+			},
+			Image:      state.Image,
+			HostConfig: &dcontainer.HostConfig{NetworkMode: dcontainer.NetworkMode(mode)},
+		},
+		Config: &dcontainer.Config{
+			Image:        state.Image,
+			Labels:       state.Labels,
+			ExposedPorts: exposed,
+		},
+	}
+	if mode != hostNetworking {
+		info.NetworkSettings = &types.NetworkSettings{
+			NetworkSettingsBase: types.NetworkSettingsBase{Ports: bindings},
+			Networks: map[string]*network.EndpointSettings{
+				bridgeNetworking: {IPAddress: state.Labels[ipAddressLabel]},
+			},
+		}
+	}
+
+	return &docker.Container{
+		ContainerInfo:   info,
+		ContainerWaiter: c.waitOnContainer(waitCtx, state, exits),
+	}
+}
+
+func (c *Client) waitOnContainer(
+	ctx context.Context, state *taskState, exits <-chan exitStatus,
+) docker.ContainerWaiter {
+	wchan := make(chan dcontainer.ContainerWaitOKBody, 1)
+	errchan := make(chan error, 1)
+	go func() {
+		var exit exitStatus
+		select {
+		case e, ok := <-exits:
+			if !ok {
+				return
+			}
+			exit = e
+		case <-ctx.Done():
+			c.log.Tracef("detached from container %s", state.ID)
+			return
+		}
+
+		if exit.Err != nil {
+			errchan <- exit.Err
+			return
+		}
+		c.log.Tracef("container %s exited with %d", state.ID, exit.Code)
+		wchan <- dcontainer.ContainerWaitOKBody{StatusCode: int64(exit.Code)}
+		c.removeIfAutoRemove(context.Background(), state)
+	}()
+	return docker.ContainerWaiter{Waiter: wchan, Errs: errchan}
+}
+
+// canonicalImage returns the fully qualified reference of an image, as containerd requires.
+func canonicalImage(image string) (string, error) {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("error parsing image name %s: %w", image, err)
+	}
+	return reference.TagNameOnly(ref).String(), nil
+}
+
+func toSpecMount(m mount.Mount) (specs.Mount, error) {
+	if m.Type != "" && m.Type != mount.TypeBind {
+		return specs.Mount{}, fmt.Errorf("unsupported mount type %s for %s", m.Type, m.Target)
+	}
+	opts := []string{"rbind", "rw"}
+	if m.ReadOnly {
+		opts[1] = "ro"
+	}
+	if m.BindOptions != nil && m.BindOptions.Propagation != "" {
+		opts = append(opts, string(m.BindOptions.Propagation))
+	}
+	return specs.Mount{Destination: m.Target, Source: m.Source, Type: "bind", Options: opts}, nil
+}
+
+// labelFilters returns the labels that Docker label filters ("key=value") match.
+func labelFilters(fs filters.Args) (map[string]string, error) {
+	for _, key := range fs.Keys() {
+		if key != "label" {
+			return nil, fmt.Errorf("unsupported container filter %s", key)
+		}
+	}
+	labels := make(map[string]string)
+	for _, f := range fs.Get("label") {
+		k, v, _ := strings.Cut(f, "=")
+		labels[k] = v
+	}
+	return labels, nil
+}
+
+// formatPorts encodes a mapping of container ports to host ports as a label value.
+func formatPorts(ports map[int]int) string {
+	var pairs []string
+	for containerPort, hostPort := range ports {
+		pairs = append(pairs, fmt.Sprintf("%d:%d", containerPort, hostPort))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// parsePorts decodes a mapping of container ports to host ports from a label value.
+func parsePorts(label string) (map[int]int, error) {
+	ports := make(map[int]int)
+	if label == "" {
+		return ports, nil
+	}
+	for _, pair := range strings.Split(label, ",") {
+		c, h, ok := strings.Cut(pair, ":")
+		containerPort, cErr := strconv.Atoi(c)
+		hostPort, hErr := strconv.Atoi(h)
+		if !ok || cErr != nil || hErr != nil {
+			return nil, fmt.Errorf("invalid port mapping %q", pair)
+		}
+		ports[containerPort] = hostPort
+	}
+	return ports, nil
+}
+
+// freePort returns a TCP port that is currently free on the host.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
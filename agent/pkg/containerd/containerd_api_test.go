@@ -0,0 +1,110 @@
+//go:build integration
+
+package containerd_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/agent/pkg/containerd"
+	"github.com/determined-ai/determined/agent/pkg/docker"
+	"github.com/determined-ai/determined/agent/pkg/events"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+)
+
+const (
+	testImage   = "busybox:1.36"
+	testAgentID = "containerd-integration-test"
+)
+
+// testOptions configures a client for the local containerd, at DET_CONTAINERD_ADDRESS or the
+// default socket. Tests are skipped if it doesn't exist.
+func testOptions(t *testing.T) options.Options {
+	address := os.Getenv("DET_CONTAINERD_ADDRESS")
+	if address == "" {
+		address = "/run/containerd/containerd.sock"
+	}
+	if _, err := os.Stat(address); err != nil {
+		t.Skipf("no containerd socket at %s: %s", address, err)
+	}
+	return options.Options{
+		AgentID: testAgentID,
+		ContainerdOptions: options.ContainerdOptions{
+			Address:   address,
+			Namespace: "determined-test",
+		},
+	}
+}
+
+func TestRunAndReattachContainer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opts := testOptions(t)
+
+	t.Log("building client")
+	cl, err := containerd.New(opts)
+	require.NoError(t, err)
+
+	t.Log("pulling image")
+	p := events.NilPublisher[docker.Event]{}
+	require.NoError(t, cl.PullImage(ctx, docker.PullImage{Name: testImage}, p))
+
+	t.Log("running container")
+	id := cproto.NewID()
+	runtimeID, err := cl.CreateContainer(ctx, id, cproto.RunSpec{
+		ContainerConfig: dcontainer.Config{
+			Image: testImage,
+			Cmd:   []string{"sleep", "600"},
+			Labels: map[string]string{
+				docker.ContainerIDLabel: id.String(),
+				docker.AgentLabel:       testAgentID,
+			},
+		},
+		HostConfig: dcontainer.HostConfig{NetworkMode: "host", AutoRemove: true},
+	}, p)
+	require.NoError(t, err)
+	defer func() {
+		if err := cl.RemoveContainer(context.Background(), runtimeID, true); err != nil {
+			t.Logf("removing container: %s", err)
+		}
+	}()
+	runCtx, cancelRun := context.WithCancel(ctx)
+	_, err = cl.RunContainer(runCtx, runCtx, runtimeID, p)
+	require.NoError(t, err)
+
+	t.Log("restarting client")
+	cancelRun()
+	require.NoError(t, cl.Close())
+	cl, err = containerd.New(opts)
+	require.NoError(t, err)
+	defer func() {
+		if err := cl.Close(); err != nil {
+			t.Logf("closing client: %s", err)
+		}
+	}()
+
+	t.Log("listing and reattaching container")
+	running, err := cl.ListRunningContainers(ctx, docker.LabelFilter(docker.AgentLabel, testAgentID))
+	require.NoError(t, err)
+	require.Contains(t, running, id)
+
+	dc, exitCode, err := cl.ReattachContainer(ctx, id)
+	require.NoError(t, err)
+	require.Nil(t, exitCode)
+	require.NotNil(t, dc)
+
+	t.Log("killing container")
+	require.NoError(t, cl.SignalContainer(ctx, dc.ContainerInfo.ID, syscall.SIGKILL))
+	select {
+	case exit := <-dc.ContainerWaiter.Waiter:
+		require.Equal(t, int64(128+syscall.SIGKILL), exit.StatusCode)
+	case err := <-dc.ContainerWaiter.Errs:
+		t.Fatalf("waiting on container: %s", err)
+	}
+}
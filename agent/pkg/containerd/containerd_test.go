@@ -0,0 +1,408 @@
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/agent/internal/container"
+	"github.com/determined-ai/determined/agent/pkg/docker"
+	"github.com/determined-ai/determined/agent/pkg/events"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+)
+
+type fakeContainer struct {
+	spec     containerSpec
+	started  bool
+	running  bool
+	exitCode uint32
+	exits    chan exitStatus
+}
+
+// fakeBackend is an in-memory backend whose containers run until they are killed.
+type fakeBackend struct {
+	mu         sync.Mutex
+	images     map[string]bool
+	pulls      []string
+	containers map[string]*fakeContainer
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{images: map[string]bool{}, containers: map[string]*fakeContainer{}}
+}
+
+func (f *fakeBackend) ImageExists(ctx context.Context, ref string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.images[ref], nil
+}
+
+func (f *fakeBackend) Pull(ctx context.Context, ref string, auth *types.AuthConfig) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pulls = append(f.pulls, ref)
+	f.images[ref] = true
+	return nil
+}
+
+func (f *fakeBackend) Create(ctx context.Context, spec containerSpec) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.images[spec.Image] {
+		return fmt.Errorf("image %s: %w", spec.Image, errdefs.ErrNotFound)
+	}
+	if _, ok := f.containers[spec.ID]; ok {
+		return fmt.Errorf("container %s: %w", spec.ID, errdefs.ErrAlreadyExists)
+	}
+	f.containers[spec.ID] = &fakeContainer{spec: spec}
+	return nil
+}
+
+func (f *fakeBackend) Start(
+	ctx, waitCtx context.Context, id, logPath string,
+) (*taskState, <-chan exitStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.containers[id]
+	if !ok {
+		return nil, nil, errdefs.ErrNotFound
+	}
+	if c.spec.Labels[networkModeLabel] != hostNetworking {
+		c.spec.Labels[ipAddressLabel] = "10.88.0.2"
+	}
+	c.started, c.running = true, true
+	c.exits = make(chan exitStatus, 1)
+	return f.state(c), c.exits, nil
+}
+
+func (f *fakeBackend) Attach(
+	ctx, waitCtx context.Context, id string,
+) (*taskState, <-chan exitStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.containers[id]
+	if !ok || !c.started {
+		return nil, nil, errdefs.ErrNotFound
+	}
+	if !c.running {
+		return f.state(c), nil, nil
+	}
+	return f.state(c), c.exits, nil
+}
+
+func (f *fakeBackend) Kill(ctx context.Context, id string, sig syscall.Signal) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.containers[id]
+	if !ok || !c.started {
+		return errdefs.ErrNotFound
+	}
+	if c.running {
+		f.exit(c, 128+uint32(sig))
+	}
+	return nil
+}
+
+func (f *fakeBackend) Delete(ctx context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.containers[id]
+	if !ok {
+		return errdefs.ErrNotFound
+	}
+	if c.running {
+		f.exit(c, 137)
+	}
+	delete(f.containers, id)
+	return nil
+}
+
+func (f *fakeBackend) List(ctx context.Context, labels map[string]string) ([]taskState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var states []taskState
+outer:
+	for _, c := range f.containers {
+		for k, v := range labels {
+			if c.spec.Labels[k] != v {
+				continue outer
+			}
+		}
+		if c.started {
+			states = append(states, *f.state(c))
+		}
+	}
+	return states, nil
+}
+
+func (f *fakeBackend) Images(ctx context.Context) ([]docker.Image, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var images []docker.Image
+	for ref := range f.images {
+		images = append(images, docker.Image{ID: ref, Refs: []string{ref}})
+	}
+	return images, nil
+}
+
+func (f *fakeBackend) RemoveImage(ctx context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.images[name] {
+		return errdefs.ErrNotFound
+	}
+	delete(f.images, name)
+	return nil
+}
+
+func (f *fakeBackend) exit(c *fakeContainer, code uint32) {
+	c.running, c.exitCode = false, code
+	c.exits <- exitStatus{Code: code}
+}
+
+func (f *fakeBackend) state(c *fakeContainer) *taskState {
+	labels := make(map[string]string)
+	for k, v := range c.spec.Labels {
+		labels[k] = v
+	}
+	return &taskState{
+		ID:       c.spec.ID,
+		Image:    c.spec.Image,
+		Labels:   labels,
+		Created:  time.Now(),
+		Pid:      42,
+		Running:  c.running,
+		ExitCode: c.exitCode,
+	}
+}
+
+func (f *fakeBackend) get(id string) *fakeContainer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.containers[id]
+}
+
+func testRunSpec(id cproto.ID, networkMode string) cproto.RunSpec {
+	return cproto.RunSpec{
+		ContainerConfig: dcontainer.Config{
+			Image:        "determinedai/environments:py-3.9",
+			Cmd:          []string{"python3", "-m", "determined.exec.harness"},
+			Env:          []string{"DET_TASK_ID=1"},
+			ExposedPorts: nat.PortSet{"1734/tcp": struct{}{}},
+			Labels: map[string]string{
+				docker.ContainerIDLabel: id.String(),
+				docker.AgentLabel:       "agent-1",
+			},
+		},
+		HostConfig: dcontainer.HostConfig{
+			NetworkMode: dcontainer.NetworkMode(networkMode),
+			AutoRemove:  true,
+			Mounts: []mount.Mount{{
+				Type: mount.TypeBind, Source: "/mnt/data", Target: "/data", ReadOnly: true,
+			}},
+			Resources: dcontainer.Resources{
+				DeviceRequests: []dcontainer.DeviceRequest{
+					{Driver: "nvidia", DeviceIDs: []string{"GPU-1"}},
+				},
+				NanoCPUs: 2e9,
+				Memory:   1 << 30,
+			},
+			CapAdd: []string{"SYS_ADMIN"},
+		},
+	}
+}
+
+func launch(
+	ctx context.Context, t *testing.T, c *Client, id cproto.ID, networkMode string,
+) *docker.Container {
+	p := events.NilPublisher[docker.Event]{}
+	req := testRunSpec(id, networkMode)
+	require.NoError(t, c.PullImage(ctx, docker.PullImage{Name: req.ContainerConfig.Image}, p))
+	runtimeID, err := c.CreateContainer(ctx, id, req, p)
+	require.NoError(t, err)
+	dc, err := c.RunContainer(ctx, ctx, runtimeID, p)
+	require.NoError(t, err)
+	return dc
+}
+
+func TestRunContainer(t *testing.T) {
+	ctx := context.Background()
+	b := newFakeBackend()
+	c, err := newClient(b, t.TempDir())
+	require.NoError(t, err)
+
+	id := cproto.NewID()
+	dc := launch(ctx, t, c, id, bridgeNetworking)
+	require.Equal(t, []string{"docker.io/determinedai/environments:py-3.9"}, b.pulls)
+
+	spec := b.get(id.String()).spec
+	require.Equal(t, []string{"GPU-1"}, spec.GPUs)
+	require.Equal(t, int64(2e9), spec.NanoCPUs)
+	require.Equal(t, int64(1<<30), spec.Memory)
+	require.False(t, spec.HostNetwork)
+	require.Equal(t, "true", spec.Labels[autoRemoveLabel])
+	require.Equal(t, "/data", spec.Mounts[0].Destination)
+	require.Equal(t, []string{"rbind", "ro"}, spec.Mounts[0].Options)
+
+	ports, err := parsePorts(spec.Labels[portsLabel])
+	require.NoError(t, err)
+	hostPort, ok := ports[1734]
+	require.True(t, ok)
+
+	// The master finds the container's addresses from the Docker-style description.
+	addrs := aproto.ContainerStarted{
+		ProxyAddress: "192.168.1.10", ContainerInfo: dc.ContainerInfo,
+	}.Addresses()
+	require.Equal(t, []cproto.Address{{
+		ContainerIP: "10.88.0.2", ContainerPort: 1734, HostIP: "192.168.1.10", HostPort: hostPort,
+	}}, addrs)
+
+	require.NoError(t, c.SignalContainer(ctx, dc.ContainerInfo.ID, syscall.SIGTERM))
+	exit := <-dc.ContainerWaiter.Waiter
+	require.Equal(t, int64(128+syscall.SIGTERM), exit.StatusCode)
+	require.Eventually(t, func() bool { return b.get(id.String()) == nil }, time.Second, 10*time.Millisecond,
+		"auto-removed container should be deleted once it exits")
+}
+
+func TestRunContainerHostNetworking(t *testing.T) {
+	ctx := context.Background()
+	b := newFakeBackend()
+	c, err := newClient(b, t.TempDir())
+	require.NoError(t, err)
+
+	id := cproto.NewID()
+	dc := launch(ctx, t, c, id, hostNetworking)
+	require.True(t, b.get(id.String()).spec.HostNetwork)
+	require.Equal(t, "1734:1734", b.get(id.String()).spec.Labels[portsLabel])
+
+	addrs := aproto.ContainerStarted{
+		ProxyAddress: "192.168.1.10", ContainerInfo: dc.ContainerInfo,
+	}.Addresses()
+	require.Equal(t, []cproto.Address{{
+		ContainerIP: "192.168.1.10", ContainerPort: 1734, HostIP: "192.168.1.10", HostPort: 1734,
+	}}, addrs)
+
+	_, err = c.CreateContainer(ctx, cproto.NewID(), testRunSpec(id, "overlay"),
+		events.NilPublisher[docker.Event]{})
+	require.ErrorContains(t, err, "unsupported network mode overlay")
+}
+
+func TestReattachContainer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	b := newFakeBackend()
+	c, err := newClient(b, t.TempDir())
+	require.NoError(t, err)
+
+	running, exited, other := cproto.NewID(), cproto.NewID(), cproto.NewID()
+	launch(ctx, t, c, running, bridgeNetworking)
+	launch(ctx, t, c, exited, bridgeNetworking)
+	launch(ctx, t, c, other, bridgeNetworking)
+	b.get(other.String()).spec.Labels[docker.AgentLabel] = "agent-2"
+	b.mu.Lock()
+	b.exit(b.containers[exited.String()], 3)
+	b.mu.Unlock()
+
+	// Only the running containers of this agent are listed; exited ones are removed.
+	listed, err := c.ListRunningContainers(ctx, docker.LabelFilter(docker.AgentLabel, "agent-1"))
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.Equal(t, running.String(), listed[running].ID)
+	require.Nil(t, b.get(exited.String()))
+
+	dc, exitCode, err := c.ReattachContainer(ctx, running)
+	require.NoError(t, err)
+	require.Nil(t, exitCode)
+	require.Equal(t, "10.88.0.2", dc.ContainerInfo.NetworkSettings.Networks[bridgeNetworking].IPAddress)
+
+	b.mu.Lock()
+	b.exit(b.containers[other.String()], 1)
+	b.mu.Unlock()
+	dc, exitCode, err = c.ReattachContainer(ctx, other)
+	require.NoError(t, err)
+	require.Nil(t, dc)
+	require.Equal(t, aproto.ExitCode(1), *exitCode)
+
+	dc, exitCode, err = c.ReattachContainer(ctx, cproto.NewID())
+	require.NoError(t, err)
+	require.Nil(t, dc)
+	require.Nil(t, exitCode)
+
+	require.ErrorIs(t, c.SignalContainer(ctx, "missing", syscall.SIGKILL), container.ErrMissing)
+	require.ErrorIs(t, c.RemoveContainer(ctx, "missing", true), container.ErrMissing)
+
+	_, err = c.ListRunningContainers(ctx, filters.NewArgs(filters.Arg("name", "x")))
+	require.ErrorContains(t, err, "unsupported container filter name")
+}
+
+func TestPorts(t *testing.T) {
+	ports := map[int]int{1734: 40001, 8080: 40002}
+	label := formatPorts(ports)
+	require.Equal(t, "1734:40001,8080:40002", label)
+
+	parsed, err := parsePorts(label)
+	require.NoError(t, err)
+	require.Equal(t, ports, parsed)
+
+	_, err = parsePorts("1734")
+	require.ErrorContains(t, err, "invalid port mapping")
+
+	port, err := freePort()
+	require.NoError(t, err)
+	require.NotEmpty(t, strconv.Itoa(port))
+}
+
+func TestCredentials(t *testing.T) {
+	creds := credentials(&types.AuthConfig{
+		ServerAddress: "https://index.docker.io/v1/", Username: "user", Password: "secret",
+	})
+	u, p, err := creds("registry-1.docker.io")
+	require.NoError(t, err)
+	require.Equal(t, []string{"user", "secret"}, []string{u, p})
+
+	u, p, err = creds("ghcr.io")
+	require.NoError(t, err)
+	require.Empty(t, u+p)
+
+	u, p, err = credentials(&types.AuthConfig{IdentityToken: "token"})("ghcr.io")
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "token"}, []string{u, p})
+
+	require.Equal(t, []string{"CAP_SYS_ADMIN", "CAP_NET_RAW"},
+		ociCapabilities([]string{"sys_admin", "CAP_NET_RAW"}))
+}
+
+func TestToSpecMount(t *testing.T) {
+	m, err := toSpecMount(mount.Mount{Source: "/mnt/data", Target: "/data"})
+	require.NoError(t, err)
+	require.Equal(t, specs.Mount{
+		Destination: "/data", Source: "/mnt/data", Type: "bind", Options: []string{"rbind", "rw"},
+	}, m)
+
+	m, err = toSpecMount(mount.Mount{
+		Type:        mount.TypeBind,
+		Source:      "/mnt/data",
+		Target:      "/data",
+		ReadOnly:    true,
+		BindOptions: &mount.BindOptions{Propagation: mount.PropagationRSlave},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"rbind", "ro", "rslave"}, m.Options)
+
+	_, err = toSpecMount(mount.Mount{Type: mount.TypeVolume, Source: "data", Target: "/data"})
+	require.ErrorContains(t, err, "unsupported mount type volume for /data")
+}
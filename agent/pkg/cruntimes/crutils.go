@@ -13,8 +13,10 @@ import (
 	"path/filepath"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 
+	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/sirupsen/logrus"
 
@@ -109,6 +111,18 @@ func CanonicalizeImage(image string) string {
 	return fmt.Sprintf("docker://%s", image)
 }
 
+// ResourcesToCLIArgs appends the --cpus and --memory arguments that singularity and podman take
+// to limit the CPUs and memory of a container.
+func ResourcesToCLIArgs(r dcontainer.Resources, args []string) []string {
+	if r.NanoCPUs > 0 {
+		args = append(args, "--cpus", strconv.FormatFloat(float64(r.NanoCPUs)/1e9, 'f', -1, 64))
+	}
+	if r.Memory > 0 {
+		args = append(args, "--memory", strconv.FormatInt(r.Memory, 10))
+	}
+	return args
+}
+
 // PullImage implements code sharing for singularity & podman.
 func PullImage(
 	ctx context.Context,
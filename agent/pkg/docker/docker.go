@@ -9,6 +9,7 @@ import (
 	"io"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
@@ -68,6 +69,14 @@ type (
 		ContainerInfo   types.ContainerJSON
 		ContainerWaiter ContainerWaiter
 	}
+	// Image describes an image stored by the container runtime. Refs are the canonical references
+	// of the image.
+	Image struct {
+		ID      string
+		Refs    []string
+		Size    int64
+		Created time.Time
+	}
 )
 
 // Client wraps the Docker client, augmenting it with a few higher level convenience APIs.
@@ -355,6 +364,41 @@ func (d *Client) ListRunningContainers(ctx context.Context, fs filters.Args) (
 	return result, nil
 }
 
+// ListImages lists the images stored by Docker.
+func (d *Client) ListImages(ctx context.Context) ([]Image, error) {
+	summaries, err := d.cl.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing images: %w", err)
+	}
+
+	images := make([]Image, 0, len(summaries))
+	for _, s := range summaries {
+		var refs []string
+		for _, ref := range append(s.RepoTags, s.RepoDigests...) {
+			if strings.HasPrefix(ref, "<none>") {
+				continue
+			}
+			refs = append(refs, aproto.CanonicalImage(ref))
+		}
+		images = append(images, Image{
+			ID:      s.ID,
+			Refs:    refs,
+			Size:    s.Size,
+			Created: time.Unix(s.Created, 0),
+		})
+	}
+	return images, nil
+}
+
+// RemoveImage removes an image, with all its references, from Docker.
+func (d *Client) RemoveImage(ctx context.Context, img Image) error {
+	_, err := d.cl.ImageRemove(ctx, img.ID, types.ImageRemoveOptions{Force: true, PruneChildren: true})
+	if err != nil {
+		return fmt.Errorf("removing image %s: %w", img.ID, err)
+	}
+	return nil
+}
+
 // LabelFilter is a convenience that takes a key and value and returns a docker label filter.
 func LabelFilter(key, val string) filters.Args {
 	return filters.NewArgs(filters.Arg("label", key+"="+val))
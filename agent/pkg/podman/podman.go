@@ -254,6 +254,8 @@ func (s *PodmanClient) RunContainer(
 		args = append(args, "--shm-size", fmt.Sprintf("%d", shmsize))
 	}
 
+	args = cruntimes.ResourcesToCLIArgs(req.HostConfig.Resources, args)
+
 	args = capabilitiesToPodmanArgs(req, args)
 
 	image := cruntimes.CanonicalizeImage(req.ContainerConfig.Image)
@@ -307,6 +307,8 @@ func (s *SingularityClient) RunContainer(
 		}
 	}
 
+	args = cruntimes.ResourcesToCLIArgs(req.HostConfig.Resources, args)
+
 	s.log.Tracef("Device type is %s", req.DeviceType)
 	if req.DeviceType == device.ROCM {
 		args = append(args, "--rocm")
@@ -189,6 +189,8 @@ def patch_agent(enabled: bool) -> Callable[[argparse.Namespace], None]:
                 payload = {
                     "drain": drain_mode,
                 }
+                if args.drain_timeout is not None:
+                    payload["drainTimeoutSeconds"] = args.drain_timeout
 
             api.post(args.master, path, payload)
             status = "Disabled" if not enabled else "Enabled"
@@ -269,6 +271,9 @@ args_description = [
             Arg("--drain", action="store_true",
                 help="enter drain mode, allowing the tasks currently running on "
                      "the disabled agents to finish. will also print these tasks, if any"),
+            Arg("--drain-timeout", type=int, default=None,
+                help="with --drain, preempt the tasks currently running on the disabled "
+                     "agents instead, and kill those still running after this many seconds"),
             Group(
                 Arg("--csv", action="store_true", help="print as CSV"),
                 Arg("--json", action="store_true", help="print as JSON"),
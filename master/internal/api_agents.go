@@ -66,6 +66,9 @@ func (a *apiServer) GetAgent(
 		if err := authz.ObfuscateAgent(resp.Agent); err != nil {
 			return nil, err
 		}
+		if resp.Drain != nil {
+			authz.ObfuscateAgentDrain(resp.Drain)
+		}
 	}
 	return resp, nil
 }
@@ -153,6 +156,16 @@ func (a *apiServer) DisableAgent(
 	if err := a.canUpdateAgents(ctx); err != nil {
 		return nil, err
 	}
+	if req.DrainTimeoutSeconds != nil {
+		switch {
+		case !req.Drain:
+			return nil, status.Error(codes.InvalidArgument,
+				"drain_timeout_seconds can only be set when draining")
+		case *req.DrainTimeoutSeconds <= 0:
+			return nil, status.Error(codes.InvalidArgument,
+				"drain_timeout_seconds must be positive")
+		}
+	}
 	return a.m.rm.DisableAgent(req)
 }
 
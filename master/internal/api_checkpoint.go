@@ -24,6 +24,7 @@ import (
 	modelauth "github.com/determined-ai/determined/master/internal/model"
 	"github.com/determined-ai/determined/master/internal/trials"
 	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/internal/webhooks"
 	"github.com/determined-ai/determined/master/internal/workspace"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/protoutils/protoconverter"
@@ -227,6 +228,7 @@ func (a *apiServer) PatchCheckpoints(
 			registeredCheckpointUUIDs)
 	}
 
+	var deletedCheckpoints []uuid.UUID
 	err = db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
 		var updatedCheckpointSizes []uuid.UUID
 		for i, c := range req.Checkpoints {
@@ -241,6 +243,7 @@ func (a *apiServer) PatchCheckpoints(
 
 				if len(c.Resources.Resources) == 0 { // Full delete case.
 					v2Update = v2Update.Set("state = ?", model.DeletedState)
+					deletedCheckpoints = append(deletedCheckpoints, uuids[i])
 				} else { // Partial delete case.
 					v2Update = v2Update.
 						Set("resources = ?", c.Resources.Resources).
@@ -288,6 +291,12 @@ func (a *apiServer) PatchCheckpoints(
 		return nil, fmt.Errorf("error patching checkpoints: %w", err)
 	}
 
+	for _, id := range deletedCheckpoints {
+		if err := webhooks.ReportCheckpointStateChanged(ctx, id, model.DeletedState); err != nil {
+			log.WithError(err).Error("failed to send checkpoint deleted webhook")
+		}
+	}
+
 	return &apiv1.PatchCheckpointsResponse{}, nil
 }
 
@@ -167,6 +167,13 @@ func (a *apiServer) getCommandLaunchParams(ctx context.Context, req *protoComman
 	if config.Resources.Slots == 0 {
 		taskContainerPodSpec = taskSpec.TaskContainerDefaults.CPUPodSpec
 	}
+	if config.Environment.PodSpecTemplate != "" {
+		config.Environment.PodSpec, err = applyPodSpecTemplate(ctx, config.Environment.PodSpecTemplate,
+			poolName, config.Environment.PodSpec, taskContainerPodSpec)
+		if err != nil {
+			return nil, launchWarnings, err
+		}
+	}
 	config.Environment.PodSpec = (*k8sV1.Pod)(schemas.Merge(
 		(*expconf.PodSpec)(config.Environment.PodSpec),
 		(*expconf.PodSpec)(taskContainerPodSpec),
@@ -2059,6 +2059,12 @@ func (a *apiServer) topTrials(
 		ranking = ByTrainingLength
 	case "adaptive_asha":
 		ranking = ByTrainingLength
+	case "tpe":
+		ranking = ByMetricOfInterest
+	case "pbt":
+		// PBT closes the worst trials of every round, so the trials with the best searcher metric
+		// are the ones that made it into the current population.
+		ranking = ByMetricOfInterest
 	case "single":
 		return nil, errors.New("single-trial experiments are not supported for trial sampling")
 	// EOL searcher configs:
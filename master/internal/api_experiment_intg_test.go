@@ -5,6 +5,7 @@ package internal
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -17,6 +18,8 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
 
 	"github.com/uptrace/bun"
 
@@ -1814,3 +1817,67 @@ func TestDeleteExperimentsFiltered(t *testing.T) {
 	}
 	t.Error("expected experiments to delete after 15 seconds and they did not")
 }
+
+// createTestTrialsWithLoss creates an experiment with one trial per loss, each reporting that loss
+// as its validation metric.
+func createTestTrialsWithLoss(
+	ctx context.Context, t *testing.T, api *apiServer, curUser model.User, losses ...float64,
+) (*model.Experiment, []int32) {
+	exp := createTestExpWithProjectID(t, api, curUser, 1)
+	var trialIDs []int32
+	for _, loss := range losses {
+		task := &model.Task{
+			TaskType:   model.TaskTypeTrial,
+			LogVersion: model.TaskLogVersion1,
+			StartTime:  time.Now(),
+			TaskID:     trialTaskID(exp.ID, model.NewRequestID(rand.Reader)),
+		}
+		require.NoError(t, api.m.db.AddTask(task))
+		trial := &model.Trial{
+			StartTime:    time.Now(),
+			State:        model.PausedState,
+			ExperimentID: exp.ID,
+		}
+		require.NoError(t, db.AddTrial(ctx, trial, task.TaskID))
+
+		_, err := api.ReportTrialValidationMetrics(ctx, &apiv1.ReportTrialValidationMetricsRequest{
+			ValidationMetrics: &trialv1.TrialMetrics{
+				TrialId:        int32(trial.ID),
+				StepsCompleted: 1,
+				Metrics: &commonv1.Metrics{
+					AvgMetrics: &structpb.Struct{Fields: map[string]*structpb.Value{
+						"loss": structpb.NewNumberValue(loss),
+					}},
+				},
+			},
+		})
+		require.NoError(t, err)
+		trialIDs = append(trialIDs, int32(trial.ID))
+	}
+	return exp, trialIDs
+}
+
+func TestTopTrialsTPE(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	exp, trialIDs := createTestTrialsWithLoss(ctx, t, api, curUser, 0.5, 0.1, 0.9)
+
+	searcher := expconf.LegacySearcher{Name: "tpe", Metric: "loss", SmallerIsBetter: true}
+	top, err := api.topTrials(ctx, exp.ID, 2, searcher)
+	require.NoError(t, err)
+	require.Equal(t, []int32{trialIDs[1], trialIDs[0]}, top)
+
+	searcher.SmallerIsBetter = false
+	top, err = api.topTrials(ctx, exp.ID, 2, searcher)
+	require.NoError(t, err)
+	require.Equal(t, []int32{trialIDs[2], trialIDs[0]}, top)
+}
+
+func TestTopTrialsPBT(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	exp, trialIDs := createTestTrialsWithLoss(ctx, t, api, curUser, 0.3, 0.7, 0.2, 0.4)
+
+	searcher := expconf.LegacySearcher{Name: "pbt", Metric: "loss", SmallerIsBetter: true}
+	top, err := api.topTrials(ctx, exp.ID, 3, searcher)
+	require.NoError(t, err)
+	require.Equal(t, []int32{trialIDs[2], trialIDs[0], trialIDs[3]}, top)
+}
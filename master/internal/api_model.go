@@ -21,6 +21,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	modelauth "github.com/determined-ai/determined/master/internal/model"
 	"github.com/determined-ai/determined/master/internal/trials"
+	"github.com/determined-ai/determined/master/internal/webhooks"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
 	"github.com/determined-ai/determined/proto/pkg/modelv1"
@@ -675,9 +676,21 @@ func (a *apiServer) PostModelVersion(
 		req.Notes,
 		user.User.Id,
 	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error adding model version to model %q", req.ModelName)
+	}
 
-	return respModelVersion, errors.Wrapf(err, "error adding model version to model %q",
-		req.ModelName)
+	mv := respModelVersion.ModelVersion
+	if err := webhooks.ReportModelVersionRegistered(ctx, webhooks.ModelVersionPayload{
+		ModelID:        int(modelResp.Id),
+		ModelName:      modelResp.Name,
+		Version:        int(mv.Version),
+		Name:           mv.Name,
+		CheckpointUUID: c.Uuid,
+	}); err != nil {
+		log.WithError(err).Error("failed to send model version registered webhook")
+	}
+	return respModelVersion, nil
 }
 
 func (a *apiServer) PatchModelVersion(
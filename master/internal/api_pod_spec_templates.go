@@ -0,0 +1,164 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	k8sV1 "k8s.io/api/core/v1"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/cluster"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/resourcepoolv1"
+)
+
+func (a *apiServer) GetPodSpecTemplates(
+	ctx context.Context, req *apiv1.GetPodSpecTemplatesRequest,
+) (*apiv1.GetPodSpecTemplatesResponse, error) {
+	templates, err := db.GetPodSpecTemplates(ctx, req.ResourcePool)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &apiv1.GetPodSpecTemplatesResponse{
+		Templates: make([]*resourcepoolv1.PodSpecTemplate, 0, len(templates)),
+	}
+	for i := range templates {
+		resp.Templates = append(resp.Templates, podSpecTemplateToProto(&templates[i]))
+	}
+	return resp, nil
+}
+
+func (a *apiServer) GetPodSpecTemplate(
+	ctx context.Context, req *apiv1.GetPodSpecTemplateRequest,
+) (*apiv1.GetPodSpecTemplateResponse, error) {
+	tmpl, err := db.ResolvePodSpecTemplate(ctx, req.Name, req.ResourcePool)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("pod spec template", req.Name, true)
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "getting pod spec template %q", req.Name)
+	}
+	return &apiv1.GetPodSpecTemplateResponse{Template: podSpecTemplateToProto(tmpl)}, nil
+}
+
+func (a *apiServer) PutPodSpecTemplate(
+	ctx context.Context, req *apiv1.PutPodSpecTemplateRequest,
+) (*apiv1.PutPodSpecTemplateResponse, error) {
+	if err := a.canModifyPodSpecTemplates(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if req.PodSpec == nil {
+		return nil, status.Error(codes.InvalidArgument, "pod_spec is required")
+	}
+	podSpec, err := protojson.Marshal(req.PodSpec)
+	if err != nil {
+		return nil, err
+	}
+	pod, err := model.ParsePodSpecTemplate(podSpec)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.ResourcePool != "" {
+		if err := a.m.rm.ValidateResourcePool(req.ResourcePool); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	tmpl := db.PodSpecTemplate{
+		Name:        req.Name,
+		PoolName:    req.ResourcePool,
+		PodSpec:     pod,
+		Description: req.Description,
+	}
+	if err := db.PutPodSpecTemplate(ctx, &tmpl); err != nil {
+		return nil, errors.Wrapf(err, "saving pod spec template %q", req.Name)
+	}
+	return &apiv1.PutPodSpecTemplateResponse{Template: podSpecTemplateToProto(&tmpl)}, nil
+}
+
+func (a *apiServer) DeletePodSpecTemplate(
+	ctx context.Context, req *apiv1.DeletePodSpecTemplateRequest,
+) (*apiv1.DeletePodSpecTemplateResponse, error) {
+	if err := a.canModifyPodSpecTemplates(ctx); err != nil {
+		return nil, err
+	}
+
+	err := db.DeletePodSpecTemplate(ctx, req.Name, req.ResourcePool)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("pod spec template", req.Name, true)
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "deleting pod spec template %q", req.Name)
+	}
+	return &apiv1.DeletePodSpecTemplateResponse{}, nil
+}
+
+// canModifyPodSpecTemplates checks that the current user may store and delete templates. Templates
+// end up in the pods of every task that references them, so they need the permission to change
+// the master configuration.
+func (a *apiServer) canModifyPodSpecTemplates(ctx context.Context) error {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return err
+	}
+	permErr, err := cluster.AuthZProvider.Get().CanUpdateMasterConfig(ctx, curUser)
+	if err != nil {
+		return err
+	} else if permErr != nil {
+		return status.Errorf(codes.PermissionDenied,
+			"current user %q doesn't have permissions to modify pod spec templates",
+			curUser.Username)
+	}
+	return nil
+}
+
+func podSpecTemplateToProto(t *db.PodSpecTemplate) *resourcepoolv1.PodSpecTemplate {
+	return &resourcepoolv1.PodSpecTemplate{
+		Id:           int32(t.ID),
+		Name:         t.Name,
+		ResourcePool: t.PoolName,
+		PodSpec:      protoutils.ToStruct(t.PodSpec),
+		Description:  t.Description,
+		CreatedAt:    timestamppb.New(t.CreatedAt),
+		UpdatedAt:    timestamppb.New(t.UpdatedAt),
+	}
+}
+
+// applyPodSpecTemplate merges the named template for a resource pool underneath the pod spec from
+// a task's config. Fields set in podSpec take precedence; lists are merged with Kubernetes
+// strategic merge semantics, so containers and volumes are merged by name while lists without a
+// merge key, like tolerations, are replaced. The result is validated together with the resource
+// pool's default pod spec, which is merged underneath it later, so that a bad template or an
+// incompatible override is reported at submission rather than when the pod is created.
+func applyPodSpecTemplate(
+	ctx context.Context, name, poolName string, podSpec, poolPodSpec *k8sV1.Pod,
+) (*k8sV1.Pod, error) {
+	tmpl, err := db.ResolvePodSpecTemplate(ctx, name, poolName)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"pod spec template %q not found for resource pool %q", name, poolName)
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "getting pod spec template %q", name)
+	}
+
+	merged := schemas.Merge((*expconf.PodSpec)(podSpec), (*expconf.PodSpec)(tmpl.PodSpec))
+	final := schemas.Merge(merged, (*expconf.PodSpec)(poolPodSpec))
+	if errs := model.ValidatePodSpec((*k8sV1.Pod)(final)); len(errs) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"pod spec is invalid after applying template %q: %v", name, errs)
+	}
+	return (*k8sV1.Pod)(merged), nil
+}
@@ -0,0 +1,87 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/internal/mocks"
+	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestPodSpecTemplatesAPI(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	var mockRM mocks.ResourceManager
+	api.m.rm = &mockRM
+	mockRM.On("ValidateResourcePool", testPoolName).Return(nil)
+
+	podSpec := func(selector string) *structpb.Struct {
+		s, err := structpb.NewStruct(map[string]any{
+			"spec": map[string]any{"nodeSelector": map[string]any{"pool": selector}},
+		})
+		require.NoError(t, err)
+		return s
+	}
+	name := uuid.New().String()
+	_, err := api.PutPodSpecTemplate(ctx, &apiv1.PutPodSpecTemplateRequest{
+		Name: name, PodSpec: podSpec("global"),
+	})
+	require.NoError(t, err)
+	put, err := api.PutPodSpecTemplate(ctx, &apiv1.PutPodSpecTemplateRequest{
+		Name: name, ResourcePool: testPoolName, PodSpec: podSpec("pooled"), Description: "pooled",
+	})
+	require.NoError(t, err)
+	require.NotZero(t, put.Template.Id)
+
+	got, err := api.GetPodSpecTemplate(ctx, &apiv1.GetPodSpecTemplateRequest{
+		Name: name, ResourcePool: testPoolName,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "pooled", got.Template.Description)
+	require.Equal(t, "pooled", got.Template.PodSpec.GetFields()["spec"].GetStructValue().
+		GetFields()["nodeSelector"].GetStructValue().GetFields()["pool"].GetStringValue())
+
+	listed, err := api.GetPodSpecTemplates(ctx, &apiv1.GetPodSpecTemplatesRequest{
+		ResourcePool: testPoolName,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, listed.Templates)
+
+	invalid, err := structpb.NewStruct(map[string]any{"spec": map[string]any{"bogus": true}})
+	require.NoError(t, err)
+	_, err = api.PutPodSpecTemplate(ctx, &apiv1.PutPodSpecTemplateRequest{
+		Name: name, PodSpec: invalid,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	username := uuid.New().String()
+	_, err = user.Add(ctx, &model.User{Username: username, Active: true}, nil)
+	require.NoError(t, err)
+	login, err := api.Login(ctx, &apiv1.LoginRequest{Username: username})
+	require.NoError(t, err)
+	userCtx := metadata.NewIncomingContext(context.TODO(),
+		metadata.Pairs("x-user-token", fmt.Sprintf("Bearer %s", login.Token)))
+	_, err = api.DeletePodSpecTemplate(userCtx, &apiv1.DeletePodSpecTemplateRequest{Name: name})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	for _, pool := range []string{testPoolName, ""} {
+		_, err = api.DeletePodSpecTemplate(ctx, &apiv1.DeletePodSpecTemplateRequest{
+			Name: name, ResourcePool: pool,
+		})
+		require.NoError(t, err)
+	}
+	_, err = api.GetPodSpecTemplate(ctx, &apiv1.GetPodSpecTemplateRequest{Name: name})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -19,6 +19,7 @@ import (
 	exputil "github.com/determined-ai/determined/master/internal/experiment"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/internal/project"
+	"github.com/determined-ai/determined/master/internal/webhooks"
 	"github.com/determined-ai/determined/master/pkg/mathx"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
@@ -669,6 +670,11 @@ func (a *apiServer) DeleteProject(
 		return nil, err
 	}
 
+	log.Debugf("deleting project %d webhooks", req.Id)
+	if err = webhooks.DeleteProjectWebhooks(ctx, int(req.Id)); err != nil {
+		return nil, errors.Wrapf(err, "error deleting project (%d) webhooks", req.Id)
+	}
+
 	if len(expList) == 0 {
 		err = a.m.db.QueryProto("delete_project", holder, req.Id)
 		return &apiv1.DeleteProjectResponse{Completed: (err == nil)},
@@ -2,17 +2,31 @@ package internal
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/internal/cluster"
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/master/internal/rm/agentrm"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	workspaceauth "github.com/determined-ai/determined/master/internal/workspace"
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/master/pkg/set"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/resourcepoolv1"
@@ -212,6 +226,305 @@ func (a *apiServer) ListWorkspacesBoundToRP(
 	}, nil
 }
 
+func (a *apiServer) PostSchedulingSimulation(
+	ctx context.Context, req *apiv1.PostSchedulingSimulationRequest,
+) (*apiv1.PostSchedulingSimulationResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Simulations evaluate resource pool configurations and can replay the allocations of any
+	// resource pool, so they need the permission to change the master configuration.
+	permErr, err := cluster.AuthZProvider.Get().CanUpdateMasterConfig(ctx, curUser)
+	if err != nil {
+		return nil, err
+	} else if permErr != nil {
+		return nil, permErr
+	}
+
+	scheduler := config.DefaultSchedulerConfig()
+	if req.Scheduler != nil {
+		bytes, err := protojson.Marshal(req.Scheduler)
+		if err != nil {
+			return nil, err
+		}
+		scheduler = &config.SchedulerConfig{}
+		if err := json.Unmarshal(bytes, scheduler); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "parsing scheduler: %s", err)
+		}
+	}
+	if err := check.Validate(scheduler); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if len(req.Agents) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "agents are required")
+	}
+
+	agents := make([]agentrm.SimulationAgent, 0, len(req.Agents))
+	for _, agent := range req.Agents {
+		agents = append(agents, agentrm.SimulationAgent{
+			Slots: int(agent.Slots),
+			Count: int(agent.Count),
+		})
+	}
+
+	var trace []agentrm.SimulationJob
+	for _, job := range req.Trace {
+		simulated := agentrm.SimulationJob{
+			ID:          job.Id,
+			JobID:       job.JobId,
+			SubmitTime:  job.SubmitTime.AsTime(),
+			Slots:       int(job.Slots),
+			Duration:    model.Duration(time.Duration(job.DurationSeconds) * time.Second),
+			Weight:      job.Weight,
+			Preemptible: job.Preemptible,
+			User:        job.User,
+			Workspace:   job.Workspace,
+		}
+		if job.MaxRuntimeSeconds != nil {
+			simulated.MaxRuntime = ptrs.Ptr(
+				model.Duration(time.Duration(*job.MaxRuntimeSeconds) * time.Second))
+		}
+		if job.Priority != nil {
+			simulated.Priority = ptrs.Ptr(int(*job.Priority))
+		}
+		trace = append(trace, simulated)
+	}
+
+	if req.ResourcePool != "" {
+		if len(trace) > 0 {
+			return nil, status.Error(codes.InvalidArgument,
+				"either trace or resource_pool can be given, not both")
+		}
+		start, end := req.Start.AsTime(), req.End.AsTime()
+		switch {
+		case !end.After(start):
+			return nil, status.Error(codes.InvalidArgument, "end must be after start")
+		case end.Sub(start) > agentrm.MaxSimulationTraceWindow:
+			return nil, status.Errorf(codes.InvalidArgument,
+				"the time range can be at most %s", agentrm.MaxSimulationTraceWindow)
+		}
+		trace, err = agentrm.SimulationTraceFromDB(ctx, req.ResourcePool, start, end)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	simulation, err := agentrm.Simulate(scheduler, agents, trace)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &apiv1.PostSchedulingSimulationResponse{
+		Jobs:              make([]*apiv1.SchedulingSimulationJobResult, 0, len(simulation.Jobs)),
+		Unschedulable:     simulation.Unschedulable,
+		MeanWaitSeconds:   time.Duration(simulation.MeanWait).Seconds(),
+		MedianWaitSeconds: time.Duration(simulation.MedianWait).Seconds(),
+		P95WaitSeconds:    time.Duration(simulation.P95Wait).Seconds(),
+		MaxWaitSeconds:    time.Duration(simulation.MaxWait).Seconds(),
+		MakespanSeconds:   time.Duration(simulation.Makespan).Seconds(),
+		Utilization:       simulation.Utilization,
+	}
+	for _, job := range simulation.Jobs {
+		result := &apiv1.SchedulingSimulationJobResult{
+			Id:          job.ID,
+			SubmitTime:  timestamppb.New(job.SubmitTime),
+			WaitSeconds: time.Duration(job.Wait).Seconds(),
+			Preemptions: int32(job.Preemptions),
+		}
+		if job.StartTime != nil {
+			result.StartTime = timestamppb.New(*job.StartTime)
+		}
+		if job.EndTime != nil {
+			result.EndTime = timestamppb.New(*job.EndTime)
+		}
+		resp.Jobs = append(resp.Jobs, result)
+	}
+	return resp, nil
+}
+
+func (a *apiServer) GetRPReservations(
+	ctx context.Context, req *apiv1.GetRPReservationsRequest,
+) (*apiv1.GetRPReservationsResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reservations, err := db.GetRPReservations(ctx, req.ResourcePool)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only list the reservations of the workspaces the current user can modify reservations of.
+	canModify := make(map[int]bool)
+	resp := &apiv1.GetRPReservationsResponse{
+		Reservations: []*resourcepoolv1.ResourcePoolReservation{},
+	}
+	for i := range reservations {
+		reservation := &reservations[i]
+		allowed, ok := canModify[reservation.WorkspaceID]
+		if !ok {
+			err := workspaceauth.AuthZProvider.Get().CanModifyRPReservations(
+				ctx, *curUser, int32(reservation.WorkspaceID),
+			)
+			if err != nil && !authz.IsPermissionDenied(err) {
+				return nil, err
+			}
+			allowed = err == nil
+			canModify[reservation.WorkspaceID] = allowed
+		}
+		if allowed {
+			resp.Reservations = append(resp.Reservations, rpReservationToProto(reservation))
+		}
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PostRPReservation(
+	ctx context.Context, req *apiv1.PostRPReservationRequest,
+) (*apiv1.PostRPReservationResponse, error) {
+	if req.StartTime == nil || req.EndTime == nil {
+		return nil, status.Error(codes.InvalidArgument, "start_time and end_time are required")
+	}
+	if err := a.canModifyRPReservation(ctx, int(req.WorkspaceId)); err != nil {
+		return nil, err
+	}
+
+	reservation := &db.RPReservation{
+		PoolName:    req.ResourcePool,
+		WorkspaceID: int(req.WorkspaceId),
+		Slots:       int(req.Slots),
+		StartTime:   req.StartTime.AsTime(),
+		EndTime:     req.EndTime.AsTime(),
+		Description: req.Description,
+	}
+	if err := a.validateRPReservation(ctx, reservation); err != nil {
+		return nil, err
+	}
+	if err := db.AddRPReservation(ctx, reservation); err != nil {
+		return nil, fmt.Errorf("adding reservation: %w", err)
+	}
+
+	created, err := db.GetRPReservation(ctx, reservation.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting reservation %d: %w", reservation.ID, err)
+	}
+	return &apiv1.PostRPReservationResponse{Reservation: rpReservationToProto(created)}, nil
+}
+
+func (a *apiServer) PatchRPReservation(
+	ctx context.Context, req *apiv1.PatchRPReservationRequest,
+) (*apiv1.PatchRPReservationResponse, error) {
+	reservation, err := a.getRPReservationForModification(ctx, int(req.ReservationId))
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Slots != nil {
+		reservation.Slots = int(*req.Slots)
+	}
+	if req.StartTime != nil {
+		reservation.StartTime = req.StartTime.AsTime()
+	}
+	if req.EndTime != nil {
+		reservation.EndTime = req.EndTime.AsTime()
+	}
+	if req.Description != nil {
+		reservation.Description = *req.Description
+	}
+	if err := a.validateRPReservation(ctx, reservation); err != nil {
+		return nil, err
+	}
+	if err := db.UpdateRPReservation(ctx, reservation); err != nil {
+		return nil, fmt.Errorf("updating reservation %d: %w", reservation.ID, err)
+	}
+
+	reservation, err = db.GetRPReservation(ctx, reservation.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting reservation %d: %w", req.ReservationId, err)
+	}
+	return &apiv1.PatchRPReservationResponse{Reservation: rpReservationToProto(reservation)}, nil
+}
+
+func (a *apiServer) DeleteRPReservation(
+	ctx context.Context, req *apiv1.DeleteRPReservationRequest,
+) (*apiv1.DeleteRPReservationResponse, error) {
+	reservation, err := a.getRPReservationForModification(ctx, int(req.ReservationId))
+	if err != nil {
+		return nil, err
+	}
+	if err := db.DeleteRPReservation(ctx, reservation.ID); err != nil {
+		return nil, fmt.Errorf("deleting reservation %d: %w", reservation.ID, err)
+	}
+	return &apiv1.DeleteRPReservationResponse{}, nil
+}
+
+// getRPReservationForModification loads a reservation and checks that the current user may
+// modify it.
+func (a *apiServer) getRPReservationForModification(
+	ctx context.Context, id int,
+) (*db.RPReservation, error) {
+	reservation, err := db.GetRPReservation(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.NotFoundErrs("reservation", fmt.Sprint(id), true)
+	} else if err != nil {
+		return nil, fmt.Errorf("getting reservation %d: %w", id, err)
+	}
+
+	if err := a.canModifyRPReservation(ctx, reservation.WorkspaceID); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (a *apiServer) canModifyRPReservation(ctx context.Context, workspaceID int) error {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = workspaceauth.AuthZProvider.Get().CanModifyRPReservations(
+		ctx, *curUser, int32(workspaceID),
+	)
+	if err != nil {
+		return authz.SubIfUnauthorized(err, status.Errorf(codes.PermissionDenied,
+			"current user %q doesn't have permissions to modify resource pool reservations",
+			curUser.Username))
+	}
+	return nil
+}
+
+func (a *apiServer) validateRPReservation(
+	ctx context.Context, reservation *db.RPReservation,
+) error {
+	if err := reservation.Validate(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := a.m.rm.ValidateResourcePool(reservation.PoolName); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	_, err := workspaceauth.WorkspaceByID(ctx, reservation.WorkspaceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(codes.InvalidArgument, "workspace %d not found",
+			reservation.WorkspaceID)
+	}
+	return err
+}
+
+func rpReservationToProto(r *db.RPReservation) *resourcepoolv1.ResourcePoolReservation {
+	return &resourcepoolv1.ResourcePoolReservation{
+		Id:            int32(r.ID),
+		ResourcePool:  r.PoolName,
+		WorkspaceId:   int32(r.WorkspaceID),
+		WorkspaceName: r.WorkspaceName,
+		Slots:         int32(r.Slots),
+		StartTime:     timestamppb.New(r.StartTime),
+		EndTime:       timestamppb.New(r.EndTime),
+		Description:   r.Description,
+		CreatedAt:     timestamppb.New(r.CreatedAt),
+	}
+}
+
 func (a *apiServer) checkIfPoolIsDefault(poolName string) error {
 	defaultComputePool, err := a.m.rm.GetDefaultComputeResourcePool(
 		sproto.GetDefaultComputeResourcePoolRequest{})
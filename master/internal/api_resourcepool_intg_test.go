@@ -5,9 +5,16 @@ package internal
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/uptrace/bun"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/mock"
@@ -16,6 +23,9 @@ import (
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/mocks"
 	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/master/pkg/set"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/resourcepoolv1"
@@ -367,3 +377,125 @@ func TestDeleteBindingsSucceeds(t *testing.T) {
 
 	require.True(t, mockRM.AssertExpectations(t))
 }
+
+func TestPostSchedulingSimulation(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+
+	start := time.Now().Truncate(time.Second)
+	req := &apiv1.PostSchedulingSimulationRequest{
+		Agents: []*apiv1.SchedulingSimulationAgent{{Slots: 4}},
+		Trace: []*apiv1.SchedulingSimulationJob{
+			{Id: "first", SubmitTime: timestamppb.New(start), Slots: 4, DurationSeconds: 3600},
+			{
+				Id: "second", SubmitTime: timestamppb.New(start.Add(time.Second)), Slots: 4,
+				DurationSeconds: 60,
+			},
+		},
+	}
+	resp, err := api.PostSchedulingSimulation(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Jobs, 2)
+	require.Equal(t, "second", resp.Jobs[1].Id)
+	require.Equal(t, start.Add(time.Hour), resp.Jobs[1].StartTime.AsTime())
+	require.Equal(t, 3599.0, resp.MaxWaitSeconds)
+	require.Empty(t, resp.Unschedulable)
+
+	_, err = api.PostSchedulingSimulation(ctx, &apiv1.PostSchedulingSimulationRequest{
+		Agents:       req.Agents,
+		Trace:        req.Trace,
+		ResourcePool: testPoolName,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = api.PostSchedulingSimulation(ctx, &apiv1.PostSchedulingSimulationRequest{
+		Agents:       req.Agents,
+		ResourcePool: testPoolName,
+		Start:        timestamppb.New(start.Add(-365 * 24 * time.Hour)),
+		End:          timestamppb.New(start),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	username := uuid.New().String()
+	_, err = user.Add(ctx, &model.User{Username: username, Active: true}, nil)
+	require.NoError(t, err)
+	login, err := api.Login(ctx, &apiv1.LoginRequest{Username: username})
+	require.NoError(t, err)
+	userCtx := metadata.NewIncomingContext(context.TODO(),
+		metadata.Pairs("x-user-token", fmt.Sprintf("Bearer %s", login.Token)))
+	_, err = api.PostSchedulingSimulation(userCtx, req)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestRPReservations(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	var mockRM mocks.ResourceManager
+	api.m.rm = &mockRM
+	mockRM.On("ValidateResourcePool", testPoolName).Return(nil)
+
+	defer func() { cleanupWorkspaces(ctx) }()
+	workspaceIDs := setupWorkspaces(ctx, t, api)
+
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	posted, err := api.PostRPReservation(ctx, &apiv1.PostRPReservationRequest{
+		ResourcePool: testPoolName,
+		WorkspaceId:  workspaceIDs[0],
+		Slots:        4,
+		StartTime:    timestamppb.New(start),
+		EndTime:      timestamppb.New(start.Add(time.Hour)),
+		Description:  "deadline",
+	})
+	require.NoError(t, err)
+	reservation := posted.Reservation
+	require.Equal(t, testWorkspaceName, reservation.WorkspaceName)
+	require.Equal(t, start, reservation.StartTime.AsTime())
+
+	_, err = api.PostRPReservation(ctx, &apiv1.PostRPReservationRequest{
+		ResourcePool: testPoolName,
+		WorkspaceId:  workspaceIDs[0],
+		Slots:        4,
+		StartTime:    timestamppb.New(start),
+		EndTime:      timestamppb.New(start),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	patched, err := api.PatchRPReservation(ctx, &apiv1.PatchRPReservationRequest{
+		ReservationId: reservation.Id,
+		Slots:         ptrs.Ptr(int32(2)),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), patched.Reservation.Slots)
+	require.Equal(t, "deadline", patched.Reservation.Description)
+
+	listed, err := api.GetRPReservations(ctx, &apiv1.GetRPReservationsRequest{
+		ResourcePool: testPoolName,
+	})
+	require.NoError(t, err)
+	require.Len(t, listed.Reservations, 1)
+	require.Equal(t, reservation.Id, listed.Reservations[0].Id)
+
+	username := uuid.New().String()
+	_, err = user.Add(ctx, &model.User{Username: username, Active: true}, nil)
+	require.NoError(t, err)
+	login, err := api.Login(ctx, &apiv1.LoginRequest{Username: username})
+	require.NoError(t, err)
+	userCtx := metadata.NewIncomingContext(context.TODO(),
+		metadata.Pairs("x-user-token", fmt.Sprintf("Bearer %s", login.Token)))
+	listed, err = api.GetRPReservations(userCtx, &apiv1.GetRPReservationsRequest{
+		ResourcePool: testPoolName,
+	})
+	require.NoError(t, err)
+	require.Empty(t, listed.Reservations)
+	_, err = api.DeleteRPReservation(userCtx, &apiv1.DeleteRPReservationRequest{
+		ReservationId: reservation.Id,
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = api.DeleteRPReservation(ctx, &apiv1.DeleteRPReservationRequest{
+		ReservationId: reservation.Id,
+	})
+	require.NoError(t, err)
+	_, err = api.DeleteRPReservation(ctx, &apiv1.DeleteRPReservationRequest{
+		ReservationId: reservation.Id,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
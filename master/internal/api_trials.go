@@ -25,6 +25,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/task"
 	"github.com/determined-ai/determined/master/internal/trials"
+	"github.com/determined-ai/determined/master/internal/webhooks"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/protoutils"
 	"github.com/determined-ai/determined/master/pkg/protoutils/protoconverter"
@@ -1388,6 +1389,11 @@ func (a *apiServer) ReportCheckpoint(
 	if err := db.AddCheckpointMetadata(ctx, c); err != nil {
 		return nil, err
 	}
+	if c.State == model.CompletedState {
+		if err := webhooks.ReportCheckpointStateChanged(ctx, c.UUID, c.State); err != nil {
+			log.WithError(err).Error("failed to send checkpoint completed webhook")
+		}
+	}
 	return &apiv1.ReportCheckpointResponse{}, nil
 }
 
@@ -19,6 +19,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/internal/templates"
+	"github.com/determined-ai/determined/master/internal/webhooks"
 	"github.com/determined-ai/determined/master/internal/workspace"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/schemas"
@@ -582,6 +583,12 @@ func (a *apiServer) DeleteWorkspace(
 		return nil, errors.Wrapf(err, "error deleting workspace (%d) templates", req.Id)
 	}
 
+	log.Debugf("deleting workspace %d webhooks", req.Id)
+	err = webhooks.DeleteWorkspaceWebhooks(ctx, int(req.Id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error deleting workspace (%d) webhooks", req.Id)
+	}
+
 	if len(projects) == 0 {
 		err = a.m.db.QueryProto("delete_workspace", holder, req.Id)
 		return &apiv1.DeleteWorkspaceResponse{Completed: (err == nil)},
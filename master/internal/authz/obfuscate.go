@@ -91,6 +91,13 @@ func ObfuscateAgent(agent *agentv1.Agent) error {
 	return nil
 }
 
+// ObfuscateAgentDrain obfuscates sensitive information in given AgentDrain.
+func ObfuscateAgentDrain(drain *agentv1.AgentDrain) {
+	for i := range drain.AllocationIds {
+		drain.AllocationIds[i] = hiddenString
+	}
+}
+
 // ObfuscateJob obfuscates sensitive information in given Job.
 func ObfuscateJob(job *jobv1.Job) jobv1.LimitedJob {
 	return jobv1.LimitedJob{
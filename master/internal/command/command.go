@@ -20,6 +20,8 @@ import (
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/task"
 	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/internal/webhooks"
+	"github.com/determined-ai/determined/master/internal/workspace"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/logger"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -135,6 +137,13 @@ func (c *Command) Start(ctx context.Context) error {
 		}
 	}
 
+	var workspaceName string
+	if w, err := workspace.WorkspaceByID(ctx, int(c.Metadata.WorkspaceID)); err != nil {
+		c.syslog.WithError(err).Warn("failed to look up workspace for slot quotas")
+	} else {
+		workspaceName = w.Name
+	}
+
 	var idleWatcherConfig *sproto.IdleTimeoutConfig
 	if c.Config.IdleTimeout != nil && (c.WatchProxyIdleTimeout || c.WatchRunnerIdleTimeout) {
 		idleWatcherConfig = &sproto.IdleTimeoutConfig{
@@ -154,13 +163,17 @@ func (c *Command) Start(ctx context.Context) error {
 			JobSubmissionTime:   c.registeredTime,
 			IsUserVisible:       true,
 			Name:                c.Config.Description,
+			User:                c.Base.Owner.Username,
+			Workspace:           workspaceName,
 			SlotsNeeded:         c.Config.Resources.Slots,
 			ResourcePool:        c.Config.Resources.ResourcePool,
 			FittingRequirements: sproto.FittingRequirements{SingleAgent: true},
+			Images:              sproto.ImagesByDeviceType(c.Config.Environment.Image),
 			ProxyPorts:          sproto.NewProxyPortConfig(c.GenericCommandSpec.ProxyPorts(), c.taskID),
 			IdleTimeout:         idleWatcherConfig,
 			Restore:             c.restored,
 			ProxyTLS:            c.TaskType == model.TaskTypeNotebook,
+			MaxRuntime:          (*time.Duration)(c.Config.Resources.MaxRuntime),
 		}, c.db, c.rm, c.GenericCommandSpec, c.OnExit)
 	if err != nil {
 		return err
@@ -242,6 +255,11 @@ func (c *Command) OnExit(ae *task.AllocationExited) {
 		c.syslog.WithError(err).Errorf(
 			"failure to delete user session for task: %v", c.taskID)
 	}
+	if err := webhooks.ReportTaskTerminated(
+		context.TODO(), c.taskID, c.taskType, int(c.Metadata.WorkspaceID), ae.String(),
+	); err != nil {
+		c.syslog.WithError(err).Error("failed to send task terminated webhook")
+	}
 
 	go func() {
 		time.Sleep(terminatedDuration)
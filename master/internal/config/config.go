@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"path/filepath"
 	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -76,8 +77,13 @@ type DBConfig struct {
 type WebhooksConfig struct {
 	BaseURL    string `json:"base_url"`
 	SigningKey string `json:"signing_key"`
+	// DeliveryRetention is how long delivery attempts are kept; zero disables delivery history.
+	DeliveryRetention model.Duration `json:"delivery_retention"`
 }
 
+// DefaultWebhookDeliveryRetention is how long webhook delivery attempts are kept by default.
+const DefaultWebhookDeliveryRetention = 7 * 24 * time.Hour
+
 // IntegrationsConfig stores configs related to integrations like pachyderm.
 type IntegrationsConfig struct {
 	Pachyderm PachydermConfig `json:"pachyderm"`
@@ -128,6 +134,9 @@ func DefaultConfig() *Config {
 		Cache: CacheConfig{
 			CacheDir: "/var/cache/determined",
 		},
+		Webhooks: WebhooksConfig{
+			DeliveryRetention: model.Duration(DefaultWebhookDeliveryRetention),
+		},
 		FeatureSwitches: []string{},
 		ResourceConfig:  *DefaultResourceConfig(),
 	}
@@ -216,6 +225,17 @@ func (c Config) Printable() ([]byte, error) {
 	}
 	c.ResourcePools = pools
 
+	additionalRMs := make([]*ResourceManagerWithPoolsConfig, 0, len(c.AdditionalResourceManagers))
+	for _, rm := range c.AdditionalResourceManagers {
+		printable := *rm
+		printable.ResourcePools = make([]ResourcePoolConfig, 0, len(rm.ResourcePools))
+		for _, poolConfig := range rm.ResourcePools {
+			printable.ResourcePools = append(printable.ResourcePools, poolConfig.Printable())
+		}
+		additionalRMs = append(additionalRMs, &printable)
+	}
+	c.AdditionalResourceManagers = additionalRMs
+
 	optJSON, err := json.Marshal(c)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to convert config to JSON")
@@ -294,9 +314,16 @@ func (c *Config) Resolve() error {
 
 // Deprecations describe fields which were recently or will soon be removed.
 func (c *Config) Deprecations() (errs []error) {
-	for _, rp := range c.ResourcePools {
+	for _, rm := range c.ResourceManagers() {
+		errs = append(errs, poolDeprecations(rm)...)
+	}
+	return errs
+}
+
+func poolDeprecations(rm *ResourceManagerWithPoolsConfig) (errs []error) {
+	for _, rp := range rm.ResourcePools {
 		switch {
-		case rp.AgentReattachEnabled && c.ResourceManager.KubernetesRM != nil:
+		case rp.AgentReattachEnabled && rm.ResourceManager != nil && rm.ResourceManager.KubernetesRM != nil:
 			errs = append(errs, fmt.Errorf(
 				"agent_reattach_enabled does not impact Kubernetes resources behavior; "+
 					"reattach is always enabled for Kubernetes resource pools",
@@ -438,25 +465,20 @@ func ReadRMPreemptionStatus(rpName string) bool {
 }
 
 func readRMPreemptionStatus(config *Config, rpName string) bool {
-	for _, rpConfig := range config.ResourcePools {
-		if rpConfig.PoolName != rpName {
-			continue
-		}
-		if rpConfig.Scheduler != nil {
-			return rpConfig.Scheduler.GetPreemption()
-		}
-		break
+	rm, rpConfig := config.resourcePool(rpName)
+	if rpConfig != nil && rpConfig.Scheduler != nil {
+		return rpConfig.Scheduler.GetPreemption()
 	}
 
 	// if not found, fall back to resource manager config
 	switch {
-	case config.ResourceManager.AgentRM != nil:
-		if config.ResourceManager.AgentRM.Scheduler == nil {
+	case rm.AgentRM != nil:
+		if rm.AgentRM.Scheduler == nil {
 			panic("scheduler not configured")
 		}
-		return config.ResourceManager.AgentRM.Scheduler.GetPreemption()
-	case config.ResourceManager.KubernetesRM != nil:
-		return config.ResourceManager.KubernetesRM.GetPreemption()
+		return rm.AgentRM.Scheduler.GetPreemption()
+	case rm.KubernetesRM != nil:
+		return rm.KubernetesRM.GetPreemption()
 	default:
 		panic("unexpected resource configuration")
 	}
@@ -464,7 +486,10 @@ func readRMPreemptionStatus(config *Config, rpName string) bool {
 
 // ReadPriority resolves the priority value for a job.
 func ReadPriority(rpName string, jobConf interface{}) int {
-	config := GetMasterConfig()
+	return readPriority(GetMasterConfig(), rpName, jobConf)
+}
+
+func readPriority(config *Config, rpName string, jobConf interface{}) int {
 	var prio *int
 	// look at the idividual job config
 	switch conf := jobConf.(type) {
@@ -480,10 +505,8 @@ func ReadPriority(rpName string, jobConf interface{}) int {
 	var schedulerConf *SchedulerConfig
 
 	// if not found, fall back to the resource pools config
-	for _, rpConfig := range config.ResourcePools {
-		if rpConfig.PoolName != rpName {
-			continue
-		}
+	rm, rpConfig := config.resourcePool(rpName)
+	if rpConfig != nil {
 		schedulerConf = rpConfig.Scheduler
 	}
 	prio = readPriorityFromScheduler(schedulerConf)
@@ -492,15 +515,15 @@ func ReadPriority(rpName string, jobConf interface{}) int {
 	}
 
 	// if not found, fall back to resource manager config
-	if config.ResourceManager.AgentRM != nil {
-		schedulerConf = config.ResourceManager.AgentRM.Scheduler
+	if rm.AgentRM != nil {
+		schedulerConf = rm.AgentRM.Scheduler
 		prio = readPriorityFromScheduler(schedulerConf)
 		if prio != nil {
 			return *prio
 		}
 	}
 
-	if config.ResourceManager.KubernetesRM != nil {
+	if rm.KubernetesRM != nil {
 		return KubernetesDefaultPriority
 	}
 
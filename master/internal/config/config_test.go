@@ -3,7 +3,9 @@ package config
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/config/provconfig"
 	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/config"
 	"github.com/determined-ai/determined/master/pkg/logger"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -650,3 +653,152 @@ resource_manager:
 		})
 	}
 }
+
+func TestKubernetesQueueIntegrationConfig(t *testing.T) {
+	parse := func(raw string) []error {
+		var unmarshaled Config
+		err := yaml.Unmarshal([]byte(raw), &unmarshaled, yaml.DisallowUnknownFields)
+		require.NoError(t, err)
+		return unmarshaled.ResourceManager.KubernetesRM.Validate()
+	}
+
+	var volcano KubernetesQueueConfig
+	require.NoError(t, yaml.Unmarshal([]byte("type: volcano"), &volcano))
+	require.Equal(t, "default", volcano.Queue)
+	require.NoError(t, check.Validate(volcano))
+
+	var kueue KubernetesQueueConfig
+	require.NoError(t, yaml.Unmarshal([]byte("type: kueue"), &kueue))
+	require.ErrorContains(t, check.Validate(kueue), "queue_integration.queue must be set")
+
+	var unknown KubernetesQueueConfig
+	require.NoError(t, yaml.Unmarshal([]byte("{type: yunikorn, queue: root}"), &unknown))
+	require.ErrorContains(t, check.Validate(unknown), "queue_integration.type must be")
+
+	errs := parse(`
+resource_manager:
+  type: kubernetes
+  default_scheduler: coscheduler
+  queue_integration:
+    type: kueue
+    queue: gpu
+`)
+	require.ErrorContains(t, errors.Join(errs...), "default_scheduler cannot be set")
+
+	errs = parse(`
+resource_manager:
+  type: kubernetes
+  launch_as_jobs: true
+  queue_integration:
+    type: volcano
+`)
+	require.ErrorContains(t, errors.Join(errs...), "launch_as_jobs cannot be set")
+}
+
+func TestKubernetesAdditionalClustersConfig(t *testing.T) {
+	parse := func(raw string) error {
+		var unmarshaled Config
+		err := yaml.Unmarshal([]byte(raw), &unmarshaled, yaml.DisallowUnknownFields)
+		require.NoError(t, err)
+		return check.Validate(unmarshaled.ResourceConfig)
+	}
+
+	var cluster KubernetesClusterConfig
+	require.NoError(t, yaml.Unmarshal([]byte("{name: east, kubeconfig_path: /east}"), &cluster))
+	require.Equal(t, "default", cluster.Namespace)
+	require.ErrorContains(t, check.Validate(cluster), "master_ip must be set for cluster east")
+
+	valid := `
+resource_manager:
+  type: kubernetes
+  max_slots_per_pod: 1
+  additional_clusters:
+    - name: east
+      kubeconfig_path: /etc/determined/east.kubeconfig
+      master_ip: 10.0.0.1
+      master_port: 8080
+resource_pools:
+  - pool_name: default
+  - pool_name: east
+    kubernetes_cluster: east
+`
+	require.NoError(t, parse(valid))
+
+	err := parse(strings.Replace(valid, "kubernetes_cluster: east", "kubernetes_cluster: west", 1))
+	require.ErrorContains(t, err, "resource pool east uses unknown kubernetes cluster west")
+
+	err = parse(`
+resource_manager:
+  type: kubernetes
+  max_slots_per_pod: 1
+  additional_clusters:
+    - {name: east, kubeconfig_path: /east, master_ip: 10.0.0.1, master_port: 8080}
+    - {name: east, kubeconfig_path: /east, master_ip: 10.0.0.1, master_port: 8080}
+`)
+	require.ErrorContains(t, err, "kubernetes cluster east is listed more than once")
+}
+
+func TestAdditionalResourceManagersConfig(t *testing.T) {
+	parse := func(raw string) (*Config, error) {
+		unmarshaled := DefaultConfig()
+		err := yaml.Unmarshal([]byte(raw), unmarshaled, yaml.DisallowUnknownFields)
+		require.NoError(t, err)
+		require.NoError(t, unmarshaled.Resolve())
+		return unmarshaled, check.Validate(unmarshaled.ResourceConfig)
+	}
+
+	hybrid, err := parse(`
+resource_manager:
+  type: agent
+  scheduler:
+    type: priority
+resource_pools:
+  - pool_name: training
+additional_resource_managers:
+  - resource_manager:
+      type: kubernetes
+      max_slots_per_pod: 1
+      default_scheduler: preemption
+    resource_pools:
+      - pool_name: notebooks
+`)
+	require.NoError(t, err)
+	require.Len(t, hybrid.ResourceManagers(), 2)
+	require.NotNil(t, hybrid.ResourceManagers()[1].ResourceManager.KubernetesRM)
+
+	// Scheduling settings come from the resource manager of the pool.
+	require.Equal(t, KubernetesDefaultPriority, readPriority(hybrid, "notebooks", nil))
+	require.Equal(t, DefaultSchedulingPriority, readPriority(hybrid, "training", nil))
+	require.True(t, readRMPreemptionStatus(hybrid, "notebooks"))
+
+	_, err = parse(`
+resource_pools:
+  - pool_name: default
+additional_resource_managers:
+  - resource_manager:
+      type: agent
+    resource_pools:
+      - pool_name: more-agents
+`)
+	require.ErrorContains(t, err, "additional resource manager 0 must be of type kubernetes")
+
+	_, err = parse(`
+resource_pools:
+  - pool_name: default
+additional_resource_managers:
+  - resource_manager:
+      type: kubernetes
+      max_slots_per_pod: 1
+    resource_pools:
+      - pool_name: default
+`)
+	require.ErrorContains(t, err, "duplicate name: default")
+
+	_, err = parse(`
+additional_resource_managers:
+  - resource_manager:
+      type: kubernetes
+      max_slots_per_pod: 1
+`)
+	require.ErrorContains(t, err, "additional resource manager 0 must have at least one resource pool")
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/determined-ai/determined/master/pkg"
 	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 // SpotPriceNotSetPlaceholder set placeholder.
@@ -37,6 +38,9 @@ type AWSClusterConfig struct {
 
 	InstanceType  Ec2InstanceType `json:"instance_type"`
 	InstanceSlots *int            `json:"instance_slots,omitempty"`
+	// FallbackInstanceTypes are launched, in order, when instances of the preceding instance types
+	// can't be launched or don't fit the pending tasks.
+	FallbackInstanceTypes []ec2FallbackInstanceType `json:"fallback_instance_types"`
 
 	LogGroup  string `json:"log_group"`
 	LogStream string `json:"log_stream"`
@@ -136,14 +140,12 @@ func (c *AWSClusterConfig) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, DefaultParser(c))
 }
 
-func validateInstanceTypeSlots(c AWSClusterConfig) error {
+func validateInstanceTypeSlots(instanceType Ec2InstanceType, instanceSlots *int) error {
 	// Must have an instance in ec2InstanceSlots map or InstanceSlots set
-	instanceType := c.InstanceType
 	if _, ok := ec2InstanceSlots[instanceType]; ok {
 		return nil
 	}
 
-	instanceSlots := c.InstanceSlots
 	if instanceSlots != nil {
 		if *instanceSlots < 0 {
 			return errors.Errorf("ec2 'instance_slots' must be greater than or equal to 0")
@@ -166,17 +168,32 @@ func (c AWSClusterConfig) Validate() []error {
 	if c.SpotEnabled && c.SpotMaxPrice != SpotPriceNotSetPlaceholder {
 		spotPriceIsNotValidNumberErr = validateMaxSpotPrice(c.SpotMaxPrice)
 	}
-	return []error{
+	errs := []error{
 		check.GreaterThan(len(c.SSHKeyName), 0, "ec2 key name must be non-empty"),
 		check.GreaterThanOrEqualTo(c.RootVolumeSize, 100, "ec2 root volume size must be >= 100"),
 		spotPriceIsNotValidNumberErr,
-		validateInstanceTypeSlots(c),
+		validateInstanceTypeSlots(c.InstanceType, c.InstanceSlots),
 	}
+	seen := map[Ec2InstanceType]bool{c.InstanceType: true}
+	for _, fallback := range c.FallbackInstanceTypes {
+		if seen[fallback.InstanceType] {
+			errs = append(errs, errors.Errorf(
+				"ec2 instance type %s is listed more than once", fallback.InstanceType))
+		}
+		seen[fallback.InstanceType] = true
+		errs = append(errs, validateInstanceTypeSlots(fallback.InstanceType, fallback.InstanceSlots))
+	}
+	return errs
 }
 
 // SlotsPerInstance returns the number of slots per instance.
 func (c AWSClusterConfig) SlotsPerInstance() int {
-	slots := c.InstanceType.Slots()
+	return c.SlotsPerInstanceType(c.InstanceType)
+}
+
+// SlotsPerInstanceType returns the number of slots per instance of the given instance type.
+func (c AWSClusterConfig) SlotsPerInstanceType(instanceType model.InstanceType) int {
+	slots := instanceType.Slots()
 	if slots == 0 && c.CPUSlotsAllowed {
 		slots = 1
 	}
@@ -184,6 +201,15 @@ func (c AWSClusterConfig) SlotsPerInstance() int {
 	return slots
 }
 
+// InstanceTypes returns the instance types the provisioner may launch, in order of preference.
+func (c AWSClusterConfig) InstanceTypes() []model.InstanceType {
+	instanceTypes := []model.InstanceType{c.InstanceType}
+	for _, fallback := range c.FallbackInstanceTypes {
+		instanceTypes = append(instanceTypes, fallback.InstanceType)
+	}
+	return instanceTypes
+}
+
 // SlotType returns the type of the slot.
 func (c AWSClusterConfig) SlotType() device.Type {
 	slots := c.InstanceType.Slots()
@@ -230,6 +256,11 @@ type ec2NetworkInterface struct {
 	SecurityGroupID string `json:"security_group_id"`
 }
 
+type ec2FallbackInstanceType struct {
+	InstanceType  Ec2InstanceType `json:"instance_type"`
+	InstanceSlots *int            `json:"instance_slots,omitempty"`
+}
+
 type ec2Tag struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
@@ -8,6 +8,7 @@ import (
 	"gotest.tools/assert"
 
 	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 func TestDefaultAWSClusterConfig(t *testing.T) {
@@ -96,3 +97,30 @@ func TestAWSClusterConfigMissingFields(t *testing.T) {
 	err = check.Validate(&config)
 	assert.ErrorContains(t, err, "non-empty")
 }
+
+func TestAWSClusterConfigFallbackInstanceTypes(t *testing.T) {
+	var config AWSClusterConfig
+	err := yaml.Unmarshal([]byte(`
+ssh_key_name: test-key
+instance_type: g5.xlarge
+fallback_instance_types:
+  - instance_type: g4dn.xlarge
+  - instance_type: test.fallback
+    instance_slots: 2
+`), &config, yaml.DisallowUnknownFields)
+	assert.NilError(t, err)
+	assert.NilError(t, check.Validate(&config))
+	assert.DeepEqual(t, config.InstanceTypes(), []model.InstanceType{
+		Ec2InstanceType("g5.xlarge"),
+		Ec2InstanceType("g4dn.xlarge"),
+		Ec2InstanceType("test.fallback"),
+	})
+	assert.Equal(t, config.SlotsPerInstanceType(Ec2InstanceType("test.fallback")), 2)
+
+	config.FallbackInstanceTypes = append(config.FallbackInstanceTypes,
+		ec2FallbackInstanceType{InstanceType: "g5.xlarge"},
+		ec2FallbackInstanceType{InstanceType: "test.unknown"})
+	err = check.Validate(&config)
+	assert.ErrorContains(t, err, "g5.xlarge is listed more than once")
+	assert.ErrorContains(t, err, "'instance_slots' must be specified")
+}
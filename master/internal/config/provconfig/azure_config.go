@@ -0,0 +1,220 @@
+package provconfig
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg"
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+// MaxAzureNamePrefixLen is the max length of the VM name prefix. Linux VM names are limited to 64
+// characters and are made of the prefix and a pet name.
+const MaxAzureNamePrefixLen = 30
+
+// AzureClusterConfig describes the configuration for an Azure cluster managed by Determined.
+type AzureClusterConfig struct {
+	SubscriptionID string `json:"subscription_id"`
+	ResourceGroup  string `json:"resource_group"`
+	Location       string `json:"location"`
+
+	// TenantID, ClientID and ClientSecret are the credentials of a service principal. If they
+	// are not set, the managed identity of the master's VM is used.
+	TenantID     string `json:"tenant_id"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+
+	// ManagementEndpoint and ActiveDirectoryEndpoint select the Azure cloud, e.g., a sovereign
+	// cloud.
+	ManagementEndpoint      string `json:"management_endpoint"`
+	ActiveDirectoryEndpoint string `json:"active_directory_endpoint"`
+
+	// ImageID is the resource ID of a custom or shared gallery image; otherwise Image is used.
+	ImageID string               `json:"image_id"`
+	Image   *AzureImageReference `json:"image"`
+
+	VMSize        AzureVMSize `json:"vm_size"`
+	InstanceSlots *int        `json:"instance_slots,omitempty"`
+
+	OSDiskSize int    `json:"os_disk_size"`
+	OSDiskType string `json:"os_disk_type"`
+
+	AdminUsername string `json:"admin_username"`
+	SSHPublicKey  string `json:"ssh_public_key"`
+
+	SubnetID               string `json:"subnet_id"`
+	NetworkSecurityGroupID string `json:"network_security_group_id"`
+	PublicIP               bool   `json:"public_ip"`
+
+	NamePrefix string            `json:"name_prefix"`
+	TagKey     string            `json:"tag_key"`
+	TagValue   string            `json:"tag_value"`
+	CustomTags map[string]string `json:"custom_tags"`
+
+	SpotEnabled  bool   `json:"spot"`
+	SpotMaxPrice string `json:"spot_max_price"`
+
+	CPUSlotsAllowed bool `json:"cpu_slots_allowed"`
+}
+
+// AzureImageReference describes a marketplace image.
+type AzureImageReference struct {
+	Publisher string `json:"publisher"`
+	Offer     string `json:"offer"`
+	SKU       string `json:"sku"`
+	Version   string `json:"version"`
+}
+
+// DefaultAzureClusterConfig returns the default configuration of the Azure cluster.
+func DefaultAzureClusterConfig() *AzureClusterConfig {
+	return &AzureClusterConfig{
+		ManagementEndpoint:      "https://management.azure.com",
+		ActiveDirectoryEndpoint: "https://login.microsoftonline.com",
+		VMSize:                  "Standard_NC24ads_A100_v4",
+		OSDiskSize:              200,
+		OSDiskType:              "StandardSSD_LRS",
+		AdminUsername:           "determined",
+		NamePrefix:              "det-agent-",
+		TagKey:                  "managed-by",
+		TagValue:                pkg.DeterminedIdentifier,
+		SpotEnabled:             false,
+		SpotMaxPrice:            SpotPriceNotSetPlaceholder,
+		CPUSlotsAllowed:         false,
+	}
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *AzureClusterConfig) UnmarshalJSON(data []byte) error {
+	*c = *DefaultAzureClusterConfig()
+	type DefaultParser *AzureClusterConfig
+	return json.Unmarshal(data, DefaultParser(c))
+}
+
+// Validate implements the check.Validatable interface.
+func (c AzureClusterConfig) Validate() []error {
+	var spotPriceIsNotValidNumberErr error
+	if c.SpotEnabled && c.SpotMaxPrice != SpotPriceNotSetPlaceholder {
+		spotPriceIsNotValidNumberErr = validateMaxSpotPrice(c.SpotMaxPrice)
+	}
+	var instanceSlotsErr error
+	if c.InstanceSlots == nil {
+		if _, ok := azureVMSizeSlots[c.VMSize]; !ok {
+			strs := make([]string, 0, len(azureVMSizeSlots))
+			for t := range azureVMSizeSlots {
+				strs = append(strs, t.Name())
+			}
+			sort.Strings(strs)
+			instanceSlotsErr = errors.Errorf("Either azure 'vm_size' and 'instance_slots' must be "+
+				"specified or the azure 'vm_size' must be one of sizes: %s", strings.Join(strs, ", "))
+		}
+	}
+	return []error{
+		check.NotEmpty(c.SubscriptionID, "azure subscription id must be non-empty"),
+		check.NotEmpty(c.ResourceGroup, "azure resource group must be non-empty"),
+		check.NotEmpty(c.Location, "azure location must be non-empty"),
+		check.NotEmpty(c.SubnetID, "azure subnet id must be non-empty"),
+		check.NotEmpty(c.SSHPublicKey, "azure ssh public key must be non-empty"),
+		check.NotEmpty(c.AdminUsername, "azure admin username must be non-empty"),
+		check.True((c.ImageID == "") != (c.Image == nil),
+			"must configure exactly one of azure image_id or image"),
+		check.True(
+			(c.TenantID == "" && c.ClientID == "" && c.ClientSecret == "") ||
+				(c.TenantID != "" && c.ClientID != "" && c.ClientSecret != ""),
+			"azure tenant_id, client_id and client_secret must be configured together"),
+		check.GreaterThanOrEqualTo(c.OSDiskSize, 30, "azure os disk size must be >= 30"),
+		check.LessThanOrEqualTo(len(c.NamePrefix), MaxAzureNamePrefixLen,
+			"azure name prefix must be at most 30 characters"),
+		spotPriceIsNotValidNumberErr,
+		instanceSlotsErr,
+		check.True(c.InstanceSlots == nil || *c.InstanceSlots >= 0,
+			"azure 'instance_slots' must be greater than or equal to 0"),
+	}
+}
+
+// InstanceType returns the VM size and the number of slots of a VM.
+func (c AzureClusterConfig) InstanceType() AzureInstanceType {
+	slots := azureVMSizeSlots[c.VMSize]
+	if c.InstanceSlots != nil {
+		slots = *c.InstanceSlots
+	}
+	return AzureInstanceType{VMSize: c.VMSize, InstanceSlots: slots}
+}
+
+// SlotsPerInstance returns the number of slots per instance.
+func (c AzureClusterConfig) SlotsPerInstance() int {
+	slots := c.InstanceType().Slots()
+	if slots == 0 && c.CPUSlotsAllowed {
+		slots = 1
+	}
+	return slots
+}
+
+// SlotType returns the type of the slot.
+func (c AzureClusterConfig) SlotType() device.Type {
+	switch {
+	case c.InstanceType().Slots() > 0:
+		return device.CUDA
+	case c.CPUSlotsAllowed:
+		return device.CPU
+	default:
+		return device.ZeroSlot
+	}
+}
+
+// AzureVMSize is the size of an Azure VM, e.g., Standard_NC24ads_A100_v4.
+type AzureVMSize string
+
+// Name returns the string representation of the VM size.
+func (s AzureVMSize) Name() string {
+	return string(s)
+}
+
+// AzureInstanceType is the instance type of an Azure cluster.
+type AzureInstanceType struct {
+	VMSize        AzureVMSize
+	InstanceSlots int
+}
+
+// Name implements model.InstanceType.
+func (t AzureInstanceType) Name() string {
+	return t.VMSize.Name()
+}
+
+// Slots implements model.InstanceType.
+func (t AzureInstanceType) Slots() int {
+	return t.InstanceSlots
+}
+
+// azureVMSizeSlots are the number of GPUs of common Azure GPU VM sizes. See
+// https://learn.microsoft.com/en-us/azure/virtual-machines/sizes-gpu.
+var azureVMSizeSlots = map[AzureVMSize]int{
+	"Standard_NC4as_T4_v3":      1,
+	"Standard_NC8as_T4_v3":      1,
+	"Standard_NC16as_T4_v3":     1,
+	"Standard_NC64as_T4_v3":     4,
+	"Standard_NC6s_v3":          1,
+	"Standard_NC12s_v3":         2,
+	"Standard_NC24s_v3":         4,
+	"Standard_NC24rs_v3":        4,
+	"Standard_NC24ads_A100_v4":  1,
+	"Standard_NC48ads_A100_v4":  2,
+	"Standard_NC96ads_A100_v4":  4,
+	"Standard_ND40rs_v2":        8,
+	"Standard_ND96asr_v4":       8,
+	"Standard_ND96amsr_A100_v4": 8,
+	"Standard_ND96isr_H100_v5":  8,
+	"Standard_NC40ads_H100_v5":  1,
+	"Standard_NC80adis_H100_v5": 2,
+	"Standard_NV36ads_A10_v5":   1,
+	"Standard_NV72ads_A10_v5":   2,
+	"Standard_D2s_v5":           0,
+	"Standard_D4s_v5":           0,
+	"Standard_D8s_v5":           0,
+	"Standard_D16s_v5":          0,
+	"Standard_D32s_v5":          0,
+	"Standard_D64s_v5":          0,
+}
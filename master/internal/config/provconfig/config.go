@@ -28,19 +28,23 @@ type Config struct {
 	AgentDockerRuntime     string `json:"agent_docker_runtime"`
 	AgentDockerImage       string `json:"agent_docker_image"`
 	// deprecated, no longer in use.
-	AgentFluentImage        string            `json:"agent_fluent_image"`
-	AgentReconnectAttempts  int               `json:"agent_reconnect_attempts"`
-	AgentReconnectBackoff   int               `json:"agent_reconnect_backoff"`
-	AgentConfigFileContents json.RawMessage   `json:"agent_config_file_contents"`
-	AWS                     *AWSClusterConfig `union:"type,aws" json:"-"`
-	GCP                     *GCPClusterConfig `union:"type,gcp" json:"-"`
-	HPC                     *HpcClusterConfig `union:"type,hpc" json:"-"`
-	MaxIdleAgentPeriod      model.Duration    `json:"max_idle_agent_period"`
-	MaxAgentStartingPeriod  model.Duration    `json:"max_agent_starting_period"`
-	MinInstances            int               `json:"min_instances"`
-	MaxInstances            int               `json:"max_instances"`
-	LaunchErrorTimeout      *model.Duration   `json:"launch_error_timeout"`
-	LaunchErrorRetries      int               `json:"launch_error_retries"`
+	AgentFluentImage        string                 `json:"agent_fluent_image"`
+	AgentReconnectAttempts  int                    `json:"agent_reconnect_attempts"`
+	AgentReconnectBackoff   int                    `json:"agent_reconnect_backoff"`
+	AgentConfigFileContents json.RawMessage        `json:"agent_config_file_contents"`
+	AWS                     *AWSClusterConfig      `union:"type,aws" json:"-"`
+	GCP                     *GCPClusterConfig      `union:"type,gcp" json:"-"`
+	HPC                     *HpcClusterConfig      `union:"type,hpc" json:"-"`
+	External                *ExternalClusterConfig `union:"type,external" json:"-"`
+	Azure                   *AzureClusterConfig    `union:"type,azure" json:"-"`
+	MaxIdleAgentPeriod      model.Duration         `json:"max_idle_agent_period"`
+	MaxAgentStartingPeriod  model.Duration         `json:"max_agent_starting_period"`
+	MinInstances            int                    `json:"min_instances"`
+	MaxInstances            int                    `json:"max_instances"`
+	WarmPoolInstances       int                    `json:"warm_pool_instances"`
+	ScalingSchedules        ScalingSchedules       `json:"scaling_schedules"`
+	LaunchErrorTimeout      *model.Duration        `json:"launch_error_timeout"`
+	LaunchErrorRetries      int                    `json:"launch_error_retries"`
 }
 
 // HpcClusterConfig describes the configuration for a HPC cluster managed by Determined.
@@ -96,8 +100,9 @@ func (c Config) Validate() []error {
 		masterURLErr,
 		check.NotEmpty(c.AgentDockerImage, "must configure an agent docker image"),
 		check.False(c.AWS != nil && c.GCP != nil, "must configure only one cluster"),
-		check.False(c.AWS == nil && c.GCP == nil && c.HPC == nil,
-			"must configure aws or gcp or hpc cluster"),
+		check.False(
+			c.AWS == nil && c.GCP == nil && c.Azure == nil && c.HPC == nil && c.External == nil,
+			"must configure aws or gcp or azure or hpc cluster, or an external provider"),
 		check.GreaterThan(
 			int64(c.MaxIdleAgentPeriod), int64(0), "max idle agent period must be greater than 0"),
 		check.GreaterThan(
@@ -107,6 +112,8 @@ func (c Config) Validate() []error {
 		check.GreaterThan(int64(c.MaxInstances), int64(0), "max instance must be greater than 0"),
 		check.GreaterThanOrEqualTo(int64(c.MaxInstances), int64(c.MinInstances),
 			"max instance must be greater than or equal to min instance"),
+		check.GreaterThanOrEqualTo(int64(c.WarmPoolInstances), int64(0),
+			"warm pool instances must be greater than or equal to 0"),
 	}...)
 	return errs
 }
@@ -163,6 +170,19 @@ func (c Config) Printable() Config {
 	if len(c.ContainerStartupScript) > 0 {
 		c.ContainerStartupScript = hiddenValue
 	}
+	if c.External != nil && len(c.External.Headers) > 0 {
+		external := *c.External
+		external.Headers = make(map[string]string, len(c.External.Headers))
+		for name := range c.External.Headers {
+			external.Headers[name] = hiddenValue
+		}
+		c.External = &external
+	}
+	if c.Azure != nil && len(c.Azure.ClientSecret) > 0 {
+		azure := *c.Azure
+		azure.ClientSecret = hiddenValue
+		c.Azure = &azure
+	}
 
 	return c
 }
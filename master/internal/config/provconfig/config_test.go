@@ -13,6 +13,7 @@ import (
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/master/version"
 )
 
@@ -21,7 +22,7 @@ func TestProvisionerConfigMissingFields(t *testing.T) {
 	err := json.Unmarshal([]byte(`{}`), &config)
 	assert.NilError(t, err)
 	err = check.Validate(&config)
-	assert.ErrorContains(t, err, "must configure aws or gcp or hpc cluster")
+	assert.ErrorContains(t, err, "must configure aws or gcp or azure or hpc cluster")
 	expected := Config{
 		MaxIdleAgentPeriod:     model.Duration(20 * time.Minute),
 		MaxAgentStartingPeriod: model.Duration(20 * time.Minute),
@@ -236,3 +237,141 @@ partition: tesla_queue
 
 	assert.Equal(t, unmarshaled.HPC.Partition, "tesla_queue")
 }
+
+func TestUnmarshalProvisionerConfigWithExternal(t *testing.T) {
+	configRaw := `
+master_url: http://test.master
+agent_docker_image: test_image
+
+type: external
+command: ["/opt/openstack-agents", "--cloud", "prod"]
+instance_type:
+  name: gpu.large
+  slots: 4
+`
+	unmarshaled := Config{}
+	err := yaml.Unmarshal([]byte(configRaw), &unmarshaled, yaml.DisallowUnknownFields)
+	assert.NilError(t, err)
+	err = check.Validate(&unmarshaled)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, unmarshaled.External, &ExternalClusterConfig{
+		Command:      []string{"/opt/openstack-agents", "--cloud", "prod"},
+		InstanceType: ExternalInstanceType{InstanceName: "gpu.large", InstanceSlots: 4},
+		Timeout:      model.Duration(time.Minute),
+	})
+	assert.Equal(t, unmarshaled.External.SlotsPerInstance(), 4)
+
+	unmarshaled.External.URL = "https://provisioner.example.com"
+	err = check.Validate(&unmarshaled)
+	assert.ErrorContains(t, err, "exactly one of command or url")
+}
+
+func TestUnmarshalProvisionerConfigWithAzure(t *testing.T) {
+	configRaw := `
+master_url: http://test.master
+agent_docker_image: test_image
+
+type: azure
+subscription_id: 00000000-0000-0000-0000-000000000000
+resource_group: determined
+location: eastus
+image:
+  publisher: microsoft-dsvm
+  offer: ubuntu-hpc
+  sku: "2204"
+  version: latest
+vm_size: Standard_NC24ads_A100_v4
+ssh_public_key: ssh-rsa AAAA
+subnet_id: /subscriptions/0/resourceGroups/determined/subnets/agents
+spot: true
+spot_max_price: "1.5"
+`
+	unmarshaled := Config{}
+	err := yaml.Unmarshal([]byte(configRaw), &unmarshaled, yaml.DisallowUnknownFields)
+	assert.NilError(t, err)
+	err = check.Validate(&unmarshaled)
+	assert.NilError(t, err)
+
+	azure := unmarshaled.Azure
+	assert.Equal(t, azure.ManagementEndpoint, "https://management.azure.com")
+	assert.Equal(t, azure.OSDiskSize, 200)
+	assert.Equal(t, azure.SpotMaxPrice, "1.5")
+	assert.Equal(t, azure.SlotsPerInstance(), 1)
+
+	azure.VMSize = "Standard_Unknown"
+	err = check.Validate(&unmarshaled)
+	assert.ErrorContains(t, err, "'instance_slots' must be specified")
+	azure.InstanceSlots = ptrs.Ptr(2)
+	azure.TenantID = "tenant"
+	err = check.Validate(&unmarshaled)
+	assert.ErrorContains(t, err, "must be configured together")
+	azure.ClientID, azure.ClientSecret = "client", "secret"
+	err = check.Validate(&unmarshaled)
+	assert.NilError(t, err)
+	assert.Equal(t, azure.SlotsPerInstance(), 2)
+	assert.Equal(t, unmarshaled.Printable().Azure.ClientSecret, "********")
+	assert.Equal(t, azure.ClientSecret, "secret")
+}
+
+func TestScalingSchedules(t *testing.T) {
+	configRaw := `
+master_url: http://test.master
+type: external
+command: ["/bin/true"]
+min_instances: 1
+max_instances: 20
+warm_pool_instances: 1
+scaling_schedules:
+  - name: working-hours
+    cron: "0 9 * * mon-fri"
+    duration: 10h
+    timezone: America/New_York
+    min_instances: 16
+  - name: nightly-jobs
+    cron: "0 0 * * *"
+    duration: 2h
+    warm_pool_instances: 4
+  - name: too-big
+    cron: "0 0 1 1 *"
+    duration: 24h
+    min_instances: 50
+`
+	config := Config{}
+	err := yaml.Unmarshal([]byte(configRaw), &config, yaml.DisallowUnknownFields)
+	assert.NilError(t, err)
+	assert.NilError(t, check.Validate(&config))
+
+	ny, err := time.LoadLocation("America/New_York")
+	assert.NilError(t, err)
+	// 2024-01-02 is a Tuesday.
+	for _, tc := range []struct {
+		t        time.Time
+		expected ScalingTargets
+	}{
+		{time.Date(2024, 1, 2, 8, 59, 0, 0, ny), ScalingTargets{1, 1, []string{}}},
+		{time.Date(2024, 1, 2, 9, 0, 0, 0, ny), ScalingTargets{16, 1, []string{"working-hours"}}},
+		{time.Date(2024, 1, 2, 18, 59, 0, 0, ny), ScalingTargets{16, 1, []string{"working-hours"}}},
+		// The window of the working hours ends when the nightly jobs start.
+		{time.Date(2024, 1, 2, 19, 0, 0, 0, ny), ScalingTargets{1, 4, []string{"nightly-jobs"}}},
+		{time.Date(2024, 1, 6, 12, 0, 0, 0, ny), ScalingTargets{1, 1, []string{}}},
+		{
+			time.Date(2024, 1, 2, 1, 0, 0, 0, time.UTC),
+			ScalingTargets{1, 4, []string{"nightly-jobs"}},
+		},
+		{
+			time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC),
+			ScalingTargets{20, 4, []string{"nightly-jobs", "too-big"}},
+		},
+	} {
+		assert.DeepEqual(t, config.ScalingTargetsAt(tc.t), tc.expected)
+	}
+
+	config.ScalingSchedules[0].Cron = "0 9 * *"
+	config.ScalingSchedules[1].Timezone = "Mars/Olympus_Mons"
+	config.ScalingSchedules[2].Duration = 0
+	err = check.Validate(&config)
+	assert.ErrorContains(t, err, "invalid cron expression")
+	assert.ErrorContains(t, err, "invalid timezone")
+	assert.ErrorContains(t, err, "duration must be greater than 0")
+}
@@ -0,0 +1,103 @@
+package provconfig
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// ExternalClusterConfig describes the configuration for a cluster whose instances are listed,
+// launched and terminated by an operator-supplied executable or HTTP endpoint.
+type ExternalClusterConfig struct {
+	// Command is the executable and arguments to run; the action (list, launch or terminate) is
+	// appended as the last argument, the JSON request is written to its standard input and the
+	// JSON response is read from its standard output.
+	Command []string `json:"command"`
+	// URL is the base URL of an HTTP endpoint; the JSON request of each action is POSTed to
+	// <url>/<action>.
+	URL string `json:"url"`
+	// Headers are added to each HTTP request, e.g., for authentication.
+	Headers map[string]string `json:"headers"`
+
+	InstanceType    ExternalInstanceType `json:"instance_type"`
+	CPUSlotsAllowed bool                 `json:"cpu_slots_allowed"`
+	Timeout         model.Duration       `json:"timeout"`
+}
+
+// ExternalInstanceType describes the instances launched by an external provider.
+type ExternalInstanceType struct {
+	InstanceName  string `json:"name"`
+	InstanceSlots int    `json:"slots"`
+}
+
+// Name implements model.InstanceType.
+func (t ExternalInstanceType) Name() string {
+	return t.InstanceName
+}
+
+// Slots implements model.InstanceType.
+func (t ExternalInstanceType) Slots() int {
+	return t.InstanceSlots
+}
+
+// DefaultExternalClusterConfig returns the default configuration of an external cluster.
+func DefaultExternalClusterConfig() *ExternalClusterConfig {
+	return &ExternalClusterConfig{
+		Timeout: model.Duration(time.Minute),
+	}
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *ExternalClusterConfig) UnmarshalJSON(data []byte) error {
+	*c = *DefaultExternalClusterConfig()
+	type DefaultParser *ExternalClusterConfig
+	return json.Unmarshal(data, DefaultParser(c))
+}
+
+// Validate implements the check.Validatable interface.
+func (c ExternalClusterConfig) Validate() []error {
+	var urlErr error
+	if c.URL != "" {
+		if u, err := url.Parse(c.URL); err != nil {
+			urlErr = errors.Wrap(err, "cannot parse external provider url")
+		} else {
+			urlErr = check.In(u.Scheme, []string{"http", "https"},
+				"external provider url scheme must be within [http, https]")
+		}
+	}
+	return []error{
+		check.True((len(c.Command) > 0) != (c.URL != ""),
+			"must configure exactly one of command or url for the external provider"),
+		urlErr,
+		check.GreaterThanOrEqualTo(int64(c.InstanceType.InstanceSlots), int64(0),
+			"instance type slots must be greater than or equal to 0"),
+		check.GreaterThan(int64(c.Timeout), int64(0), "timeout must be greater than 0"),
+	}
+}
+
+// SlotsPerInstance returns the number of slots per instance.
+func (c ExternalClusterConfig) SlotsPerInstance() int {
+	slots := c.InstanceType.Slots()
+	if slots == 0 && c.CPUSlotsAllowed {
+		slots = 1
+	}
+	return slots
+}
+
+// SlotType returns the type of the slot.
+func (c ExternalClusterConfig) SlotType() device.Type {
+	switch {
+	case c.InstanceType.Slots() > 0:
+		return device.CUDA
+	case c.CPUSlotsAllowed:
+		return device.CPU
+	default:
+		return device.ZeroSlot
+	}
+}
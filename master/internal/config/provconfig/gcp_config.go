@@ -45,6 +45,9 @@ type GCPClusterConfig struct {
 	ServiceAccount   gceServiceAccount   `json:"service_account"`
 
 	InstanceType gceInstanceType `json:"instance_type"`
+	// FallbackInstanceTypes are launched, in order, when instances of the preceding instance types
+	// can't be launched or don't fit the pending tasks.
+	FallbackInstanceTypes []gceInstanceType `json:"fallback_instance_types"`
 
 	OperationTimeoutPeriod model.Duration `json:"operation_timeout_period"`
 	CPUSlotsAllowed        bool           `json:"cpu_slots_allowed"`
@@ -77,9 +80,18 @@ func (c *GCPClusterConfig) UnmarshalJSON(data []byte) error {
 
 // Validate implements the check.Validatable interface.
 func (c GCPClusterConfig) Validate() []error {
-	return []error{
+	errs := []error{
 		check.GreaterThanOrEqualTo(c.BootDiskSize, 100, "gce VM boot disk size must be >= 100"),
 	}
+	seen := map[string]bool{c.InstanceType.Name(): true}
+	for _, fallback := range c.FallbackInstanceTypes {
+		if seen[fallback.Name()] {
+			errs = append(errs, errors.Errorf(
+				"gce VM instance type %s is listed more than once", fallback.Name()))
+		}
+		seen[fallback.Name()] = true
+	}
+	return errs
 }
 
 // InitDefaultValues init default values.
@@ -213,7 +225,12 @@ func (c *GCPClusterConfig) InstanceProperties() *compute.InstanceProperties {
 
 // SlotsPerInstance returns the number of slots per instance.
 func (c GCPClusterConfig) SlotsPerInstance() int {
-	slots := c.InstanceType.Slots()
+	return c.SlotsPerInstanceType(c.InstanceType)
+}
+
+// SlotsPerInstanceType returns the number of slots per instance of the given instance type.
+func (c GCPClusterConfig) SlotsPerInstanceType(instanceType model.InstanceType) int {
+	slots := instanceType.Slots()
 	if slots == 0 && c.CPUSlotsAllowed {
 		slots = 1
 	}
@@ -221,6 +238,29 @@ func (c GCPClusterConfig) SlotsPerInstance() int {
 	return slots
 }
 
+// InstanceTypes returns the instance types the provisioner may launch, in order of preference.
+func (c GCPClusterConfig) InstanceTypes() []model.InstanceType {
+	instanceTypes := []model.InstanceType{c.InstanceType}
+	for _, fallback := range c.FallbackInstanceTypes {
+		instanceTypes = append(instanceTypes, fallback)
+	}
+	return instanceTypes
+}
+
+// WithInstanceType returns a copy of the configuration that launches instances of the instance
+// type with the given name, which must be one of InstanceTypes.
+func (c GCPClusterConfig) WithInstanceType(name string) (*GCPClusterConfig, error) {
+	for _, fallback := range c.FallbackInstanceTypes {
+		if fallback.Name() == name {
+			c.InstanceType = fallback
+		}
+	}
+	if c.InstanceType.Name() != name {
+		return nil, errors.Errorf("gce VM instance type %s is not configured", name)
+	}
+	return &c, nil
+}
+
 // SlotType returns the type of the slot.
 func (c GCPClusterConfig) SlotType() device.Type {
 	slots := c.InstanceType.Slots()
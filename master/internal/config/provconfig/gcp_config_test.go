@@ -222,3 +222,24 @@ func TestGCEInstanceType(t *testing.T) {
 		})
 	}
 }
+
+func TestGCPClusterConfigFallbackInstanceTypes(t *testing.T) {
+	config := DefaultGCPClusterConfig()
+	config.FallbackInstanceTypes = []gceInstanceType{
+		{MachineType: "n1-standard-16", GPUType: "nvidia-tesla-t4", GPUNum: 2},
+	}
+	assert.NilError(t, check.Validate(config))
+	assert.DeepEqual(t, config.InstanceTypes(), []model.InstanceType{
+		config.InstanceType, config.FallbackInstanceTypes[0],
+	})
+
+	fallback, err := config.WithInstanceType("n1-standard-16-nvidia-tesla-t4-2")
+	assert.NilError(t, err)
+	assert.Equal(t, fallback.InstanceProperties().MachineType, "n1-standard-16")
+	assert.Equal(t, config.InstanceType.MachineType, "n1-standard-32")
+	_, err = config.WithInstanceType("n1-standard-8-nvidia-tesla-t4-1")
+	assert.ErrorContains(t, err, "is not configured")
+
+	config.FallbackInstanceTypes = append(config.FallbackInstanceTypes, config.InstanceType)
+	assert.ErrorContains(t, check.Validate(config), "is listed more than once")
+}
@@ -0,0 +1,101 @@
+package provconfig
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/cron"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// ScalingSchedule raises the minimum number of instances, or of idle instances kept warm, during
+// windows that start at the times of a cron expression, e.g., to keep instances ready during
+// working hours.
+type ScalingSchedule struct {
+	Name string `json:"name"`
+	// Cron is a five-field cron expression of the start of the windows, e.g., "0 9 * * mon-fri".
+	Cron     string         `json:"cron"`
+	Duration model.Duration `json:"duration"`
+	// Timezone is the IANA time zone of the cron expression; it defaults to UTC.
+	Timezone          string `json:"timezone"`
+	MinInstances      int    `json:"min_instances"`
+	WarmPoolInstances int    `json:"warm_pool_instances"`
+}
+
+// Validate implements the check.Validatable interface.
+func (s ScalingSchedule) Validate() []error {
+	var cronErr, timezoneErr error
+	if _, err := cron.Parse(s.Cron); err != nil {
+		cronErr = errors.Wrapf(err, "invalid cron expression of scaling schedule %q", s.Name)
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		timezoneErr = errors.Wrapf(err, "invalid timezone of scaling schedule %q", s.Name)
+	}
+	return []error{
+		check.NotEmpty(s.Name, "scaling schedule name must be non-empty"),
+		cronErr,
+		timezoneErr,
+		check.GreaterThan(int64(s.Duration), int64(0),
+			"scaling schedule duration must be greater than 0"),
+		check.GreaterThanOrEqualTo(s.MinInstances, 0,
+			"scaling schedule min instances must be greater than or equal to 0"),
+		check.GreaterThanOrEqualTo(s.WarmPoolInstances, 0,
+			"scaling schedule warm pool instances must be greater than or equal to 0"),
+	}
+}
+
+// ActiveAt returns whether t is within a window of the schedule.
+func (s ScalingSchedule) ActiveAt(t time.Time) bool {
+	schedule, err := cron.Parse(s.Cron)
+	if err != nil {
+		return false
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return false
+	}
+	// The window starting at the latest start time ends strictly before the start time plus the
+	// duration.
+	start, ok := schedule.Prev(t.In(loc), time.Duration(s.Duration))
+	return ok && t.Before(start.Add(time.Duration(s.Duration)))
+}
+
+// ScalingTargets are the targets the provisioner scales a resource pool to at a point in time.
+type ScalingTargets struct {
+	MinInstances      int      `json:"min_instances"`
+	WarmPoolInstances int      `json:"warm_pool_instances"`
+	ActiveSchedules   []string `json:"active_schedules"`
+}
+
+// ScalingSchedules are the scaling schedules of a resource pool.
+type ScalingSchedules []ScalingSchedule
+
+// TargetsAt returns the scaling targets at t: the largest of the given minimum and warm pool and
+// of those of the schedules active at t, capped at the maximum number of instances.
+func (ss ScalingSchedules) TargetsAt(
+	t time.Time, minInstances, warmPoolInstances, maxInstances int,
+) ScalingTargets {
+	targets := ScalingTargets{
+		MinInstances:      minInstances,
+		WarmPoolInstances: warmPoolInstances,
+		ActiveSchedules:   []string{},
+	}
+	for _, s := range ss {
+		if !s.ActiveAt(t) {
+			continue
+		}
+		targets.ActiveSchedules = append(targets.ActiveSchedules, s.Name)
+		targets.MinInstances = max(targets.MinInstances, s.MinInstances)
+		targets.WarmPoolInstances = max(targets.WarmPoolInstances, s.WarmPoolInstances)
+	}
+	targets.MinInstances = min(targets.MinInstances, maxInstances)
+	targets.WarmPoolInstances = min(targets.WarmPoolInstances, maxInstances)
+	return targets
+}
+
+// ScalingTargetsAt returns the scaling targets of the resource pool at t.
+func (c Config) ScalingTargetsAt(t time.Time) ScalingTargets {
+	return c.ScalingSchedules.TargetsAt(t, c.MinInstances, c.WarmPoolInstances, c.MaxInstances)
+}
@@ -15,6 +15,43 @@ func DefaultResourceConfig() *ResourceConfig {
 type ResourceConfig struct {
 	ResourceManager *ResourceManagerConfig `json:"resource_manager"`
 	ResourcePools   []ResourcePoolConfig   `json:"resource_pools"`
+
+	// AdditionalResourceManagers run alongside ResourceManager on the same master, each managing
+	// its own resource pools. Jobs are dispatched to the resource manager of their pool.
+	AdditionalResourceManagers []*ResourceManagerWithPoolsConfig `json:"additional_resource_managers"`
+}
+
+// ResourceManagerWithPoolsConfig is a resource manager together with the pools it manages.
+type ResourceManagerWithPoolsConfig struct {
+	ResourceManager *ResourceManagerConfig `json:"resource_manager"`
+	ResourcePools   []ResourcePoolConfig   `json:"resource_pools"`
+}
+
+// ResourceConfig returns the resource config of the resource manager on its own.
+func (r ResourceManagerWithPoolsConfig) ResourceConfig() *ResourceConfig {
+	return &ResourceConfig{ResourceManager: r.ResourceManager, ResourcePools: r.ResourcePools}
+}
+
+// ResourceManagers returns every resource manager with its pools, the primary one first.
+func (r ResourceConfig) ResourceManagers() []*ResourceManagerWithPoolsConfig {
+	primary := &ResourceManagerWithPoolsConfig{
+		ResourceManager: r.ResourceManager,
+		ResourcePools:   r.ResourcePools,
+	}
+	return append([]*ResourceManagerWithPoolsConfig{primary}, r.AdditionalResourceManagers...)
+}
+
+// resourcePool returns the config of the named pool and of the resource manager that manages
+// it. If no resource manager has the pool, it returns the primary resource manager and no pool.
+func (r ResourceConfig) resourcePool(name string) (*ResourceManagerConfig, *ResourcePoolConfig) {
+	for _, rm := range r.ResourceManagers() {
+		for i := range rm.ResourcePools {
+			if rm.ResourcePools[i].PoolName == name {
+				return rm.ResourceManager, &rm.ResourcePools[i]
+			}
+		}
+	}
+	return r.ResourceManager, nil
 }
 
 // ResolveResource resolves the config.
@@ -40,11 +77,43 @@ func (r *ResourceConfig) ResolveResource() error {
 func (r ResourceConfig) Validate() []error {
 	errs := make([]error, 0)
 	poolNames := make(map[string]bool)
-	for ix, rp := range r.ResourcePools {
-		if _, ok := poolNames[rp.PoolName]; ok {
-			errs = append(errs, errors.Errorf("%d resource pool has a duplicate name: %s", ix, rp.PoolName))
-		} else {
-			poolNames[rp.PoolName] = true
+	for _, rm := range r.ResourceManagers() {
+		for ix, rp := range rm.ResourcePools {
+			if _, ok := poolNames[rp.PoolName]; ok {
+				errs = append(errs, errors.Errorf("%d resource pool has a duplicate name: %s", ix, rp.PoolName))
+			} else {
+				poolNames[rp.PoolName] = true
+			}
+		}
+	}
+
+	for ix, rm := range r.AdditionalResourceManagers {
+		switch {
+		case rm == nil || rm.ResourceManager == nil || rm.ResourceManager.KubernetesRM == nil:
+			// The agent resource manager serves the agent endpoints of the master, so there can
+			// only be one and it must be the primary resource manager.
+			errs = append(errs, errors.Errorf(
+				"additional resource manager %d must be of type kubernetes", ix))
+		case len(rm.ResourcePools) == 0:
+			errs = append(errs, errors.Errorf(
+				"additional resource manager %d must have at least one resource pool", ix))
+		}
+	}
+
+	for _, rm := range r.ResourceManagers() {
+		if rm == nil || rm.ResourceManager == nil || rm.ResourceManager.KubernetesRM == nil {
+			continue
+		}
+		clusters := map[string]bool{"": true}
+		for _, cluster := range rm.ResourceManager.KubernetesRM.AdditionalClusters {
+			clusters[cluster.Name] = true
+		}
+		for _, rp := range rm.ResourcePools {
+			if !clusters[rp.KubernetesCluster] {
+				errs = append(errs, errors.Errorf(
+					"resource pool %s uses unknown kubernetes cluster %s",
+					rp.PoolName, rp.KubernetesCluster))
+			}
 		}
 	}
 	return errs
@@ -133,6 +133,17 @@ type KubernetesResourceManagerConfig struct {
 	DefaultAuxResourcePool     string `json:"default_aux_resource_pool"`
 	DefaultComputeResourcePool string `json:"default_compute_resource_pool"`
 	NoDefaultResourcePools     bool   `json:"no_default_resource_pools"`
+
+	// QueueIntegration, if set, hands the admission of workloads to a cluster-wide queue.
+	QueueIntegration *KubernetesQueueConfig `json:"queue_integration,omitempty"`
+	// LaunchAsJobs launches the pods of each allocation as an indexed batch Job owned by a
+	// headless Service of the allocation, so Kubernetes garbage collects the pods and gives them
+	// stable DNS names.
+	LaunchAsJobs bool `json:"launch_as_jobs"`
+
+	// AdditionalClusters are Kubernetes clusters, besides the one the master runs in, that back
+	// resource pools which set kubernetes_cluster.
+	AdditionalClusters []KubernetesClusterConfig `json:"additional_clusters"`
 }
 
 var defaultKubernetesResourceManagerConfig = KubernetesResourceManagerConfig{
@@ -188,6 +199,109 @@ func (k KubernetesResourceManagerConfig) Validate() []error {
 	return []error{
 		checkSlotType,
 		checkCPUResource,
+		check.True(k.QueueIntegration == nil || k.DefaultScheduler == "",
+			"default_scheduler cannot be set together with queue_integration"),
+		check.True(k.QueueIntegration == nil || !k.LaunchAsJobs,
+			"launch_as_jobs cannot be set together with queue_integration"),
+		k.validateAdditionalClusters(),
+	}
+}
+
+func (k KubernetesResourceManagerConfig) validateAdditionalClusters() error {
+	names := make(map[string]bool)
+	for _, cluster := range k.AdditionalClusters {
+		if names[cluster.Name] {
+			return errors.Errorf("kubernetes cluster %s is listed more than once", cluster.Name)
+		}
+		names[cluster.Name] = true
+	}
+	return nil
+}
+
+// ClusterNamespace returns the default namespace of the named cluster, where the empty name is
+// the cluster the master runs in.
+func (k KubernetesResourceManagerConfig) ClusterNamespace(cluster string) string {
+	for _, c := range k.AdditionalClusters {
+		if c.Name == cluster {
+			return c.Namespace
+		}
+	}
+	return k.Namespace
+}
+
+// KubernetesClusterConfig configures a Kubernetes cluster, other than the one the master runs in,
+// that tasks can be launched into.
+type KubernetesClusterConfig struct {
+	Name string `json:"name"`
+	// KubeconfigPath is the path of the kubeconfig file used to reach the cluster.
+	KubeconfigPath string `json:"kubeconfig_path"`
+	Namespace      string `json:"namespace"`
+	// MasterIP and MasterPort are the address that tasks in the cluster reach the master at.
+	MasterIP   string `json:"master_ip"`
+	MasterPort int32  `json:"master_port"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *KubernetesClusterConfig) UnmarshalJSON(data []byte) error {
+	type DefaultParser *KubernetesClusterConfig
+	if err := json.Unmarshal(data, DefaultParser(c)); err != nil {
+		return err
+	}
+	if c.Namespace == "" {
+		c.Namespace = "default"
+	}
+	return nil
+}
+
+// Validate implements the check.Validatable interface.
+func (c KubernetesClusterConfig) Validate() []error {
+	return []error{
+		check.True(c.Name != "", "additional_clusters.name must be set"),
+		check.True(c.KubeconfigPath != "",
+			"additional_clusters.kubeconfig_path must be set for cluster %s", c.Name),
+		check.True(c.MasterIP != "",
+			"additional_clusters.master_ip must be set for cluster %s", c.Name),
+		check.True(c.MasterPort > 0,
+			"additional_clusters.master_port must be > 0 for cluster %s", c.Name),
+	}
+}
+
+// Queue integrations supported by the Kubernetes resource manager.
+const (
+	KueueQueueIntegration   = "kueue"
+	VolcanoQueueIntegration = "volcano"
+)
+
+// defaultVolcanoQueue is the queue Volcano creates on installation.
+const defaultVolcanoQueue = "default"
+
+// KubernetesQueueConfig configures submitting the pods of each allocation as a Kueue Workload or
+// a Volcano PodGroup, so that the cluster-wide queue decides when they are admitted.
+type KubernetesQueueConfig struct {
+	Type string `json:"type"`
+	// Queue is the Kueue LocalQueue or the Volcano Queue workloads are submitted to. Resource
+	// pools may override it with kubernetes_queue.
+	Queue string `json:"queue"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (k *KubernetesQueueConfig) UnmarshalJSON(data []byte) error {
+	type DefaultParser *KubernetesQueueConfig
+	if err := json.Unmarshal(data, DefaultParser(k)); err != nil {
+		return err
+	}
+	if k.Type == VolcanoQueueIntegration && k.Queue == "" {
+		k.Queue = defaultVolcanoQueue
+	}
+	return nil
+}
+
+// Validate implements the check.Validatable interface.
+func (k KubernetesQueueConfig) Validate() []error {
+	return []error{
+		check.In(k.Type, []string{KueueQueueIntegration, VolcanoQueueIntegration},
+			"queue_integration.type must be kueue or volcano"),
+		check.True(k.Queue != "", "queue_integration.queue must be set"),
 	}
 }
 
@@ -32,10 +32,20 @@ type ResourcePoolConfig struct {
 	// AgentReconnectWait define the time master will wait for agent
 	// before abandoning it.
 	AgentReconnectWait model.Duration `json:"agent_reconnect_wait"`
+	// Quotas limit the slots each user and workspace can use at once in the pool.
+	Quotas *SlotQuotaConfig `json:"quotas,omitempty"`
+	// PrePullImages are pulled by agents of the pool when they connect, ahead of tasks that use
+	// them.
+	PrePullImages []string `json:"prepull_images,omitempty"`
 
 	// If empty, will behave as if the value is resource_manager.namespace,
 	// which in most cases will be the namespace the helm deployment is in.
 	KubernetesNamespace string `json:"kubernetes_namespace"`
+	// If empty, workloads are submitted to resource_manager.queue_integration.queue.
+	KubernetesQueue string `json:"kubernetes_queue"`
+	// If empty, tasks are launched into the cluster the master runs in. Otherwise, it names one
+	// of resource_manager.additional_clusters.
+	KubernetesCluster string `json:"kubernetes_cluster"`
 
 	// Deprecated: Use MaxAuxContainersPerAgent instead.
 	MaxCPUContainersPerAgent int `json:"max_cpu_containers_per_agent,omitempty"`
@@ -67,6 +77,55 @@ func (r ResourcePoolConfig) Validate() []error {
 	}
 }
 
+// SlotQuotaConfig configures the maximum number of slots that the tasks of a single user or a
+// single workspace can use at once in a resource pool. Entries in Users and Workspaces override
+// the defaults for the named user or workspace.
+type SlotQuotaConfig struct {
+	MaxSlotsPerUser      *int           `json:"max_slots_per_user"`
+	MaxSlotsPerWorkspace *int           `json:"max_slots_per_workspace"`
+	Users                map[string]int `json:"users"`
+	Workspaces           map[string]int `json:"workspaces"`
+}
+
+// Validate implements the check.Validatable interface.
+func (q SlotQuotaConfig) Validate() []error {
+	errs := []error{
+		check.True(q.MaxSlotsPerUser == nil || *q.MaxSlotsPerUser >= 0,
+			"max_slots_per_user must be >= 0"),
+		check.True(q.MaxSlotsPerWorkspace == nil || *q.MaxSlotsPerWorkspace >= 0,
+			"max_slots_per_workspace must be >= 0"),
+	}
+	for name, slots := range q.Users {
+		errs = append(errs, check.True(slots >= 0, "quota of user %s must be >= 0", name))
+	}
+	for name, slots := range q.Workspaces {
+		errs = append(errs, check.True(slots >= 0, "quota of workspace %s must be >= 0", name))
+	}
+	return errs
+}
+
+// UserQuota returns the slot quota of the user, or nil if the user has none.
+func (q *SlotQuotaConfig) UserQuota(user string) *int {
+	if q == nil {
+		return nil
+	}
+	if slots, ok := q.Users[user]; ok {
+		return &slots
+	}
+	return q.MaxSlotsPerUser
+}
+
+// WorkspaceQuota returns the slot quota of the workspace, or nil if the workspace has none.
+func (q *SlotQuotaConfig) WorkspaceQuota(workspace string) *int {
+	if q == nil {
+		return nil
+	}
+	if slots, ok := q.Workspaces[workspace]; ok {
+		return &slots
+	}
+	return q.MaxSlotsPerWorkspace
+}
+
 // Printable returns a printable object.
 func (r ResourcePoolConfig) Printable() ResourcePoolConfig {
 	if r.Provider != nil {
@@ -19,6 +19,13 @@ const (
 	// RoundRobinScheduling schedules tasks based on the order in which they arrive.
 	RoundRobinScheduling = "round_robin"
 
+	// NoBackfill disables backfilling of lower-priority tasks past a blocked higher-priority task
+	// unless they can be preempted.
+	NoBackfill = "none"
+	// EasyBackfill lets lower-priority tasks start ahead of a blocked task as long as they do not
+	// delay its reservation.
+	EasyBackfill = "easy"
+
 	best             = "best"
 	worst            = "worst"
 	defaultFitPolicy = best
@@ -65,6 +72,9 @@ func (s *SchedulerConfig) UnmarshalJSON(data []byte) error {
 		defaultPriority := DefaultSchedulingPriority
 		s.Priority.DefaultPriority = &defaultPriority
 	}
+	if s.Priority != nil && s.Priority.Backfill == "" {
+		s.Priority.Backfill = NoBackfill
+	}
 	if s.FittingPolicy == "" {
 		s.FittingPolicy = best
 	}
@@ -114,8 +124,9 @@ type FairShareSchedulerConfig struct{}
 
 // PrioritySchedulerConfig holds the configurations for the priority scheduler.
 type PrioritySchedulerConfig struct {
-	Preemption      bool `json:"preemption"`
-	DefaultPriority *int `json:"default_priority"`
+	Preemption      bool   `json:"preemption"`
+	DefaultPriority *int   `json:"default_priority"`
+	Backfill        string `json:"backfill"`
 }
 
 // RoundRobinSchedulerConfig holds the configurations for the round robing scheduler.
@@ -123,5 +134,11 @@ type RoundRobinSchedulerConfig struct{}
 
 // Validate implements the check.Validatable interface.
 func (p PrioritySchedulerConfig) Validate() []error {
-	return model.ValidatePrioritySetting(p.DefaultPriority)
+	errs := model.ValidatePrioritySetting(p.DefaultPriority)
+	if p.Backfill != "" {
+		errs = append(errs, check.Contains(
+			p.Backfill, []interface{}{NoBackfill, EasyBackfill}, "invalid backfill mode",
+		))
+	}
+	return errs
 }
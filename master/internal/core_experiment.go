@@ -13,6 +13,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
+	k8sV1 "k8s.io/api/core/v1"
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/authz"
@@ -310,6 +311,18 @@ func (m *Master) parseCreateExperiment(req *apiv1.CreateExperimentRequest, owner
 	if err != nil {
 		return nil, config, nil, nil, errors.Wrapf(err, "error getting TaskContainerDefaults")
 	}
+	if name := defaulted.Environment().PodSpecTemplate(); name != nil {
+		poolPodSpec := taskContainerDefaults.CPUPodSpec
+		if resources.SlotsPerTrial() > 0 {
+			poolPodSpec = taskContainerDefaults.GPUPodSpec
+		}
+		podSpec, err := applyPodSpecTemplate(
+			ctx, *name, poolName, (*k8sV1.Pod)(config.RawEnvironment.RawPodSpec), poolPodSpec)
+		if err != nil {
+			return nil, config, nil, nil, err
+		}
+		config.RawEnvironment.RawPodSpec = (*expconf.PodSpec)(podSpec)
+	}
 	taskSpec := *m.taskSpec
 	taskSpec.TaskContainerDefaults = taskContainerDefaults
 	taskSpec.TaskContainerDefaults.MergeIntoExpConfig(&config)
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	k8sV1 "k8s.io/api/core/v1"
+)
+
+// PodSpecTemplate is a struct reflecting the db table pod_spec_templates. It is a named pod spec
+// that experiment and command configs reference through environment.pod_spec_template. A template
+// with an empty pool name applies to every resource pool that doesn't define its own template of
+// the same name.
+type PodSpecTemplate struct {
+	bun.BaseModel `bun:"table:pod_spec_templates,alias:t"`
+
+	ID          int        `bun:"id,pk,autoincrement" json:"id"`
+	Name        string     `bun:"name" json:"name"`
+	PoolName    string     `bun:"pool_name" json:"resource_pool"`
+	PodSpec     *k8sV1.Pod `bun:"pod_spec,type:jsonb" json:"pod_spec"`
+	Description string     `bun:"description" json:"description"`
+
+	CreatedAt time.Time `bun:"created_at,nullzero,default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,default:current_timestamp" json:"updated_at"`
+}
+
+// PutPodSpecTemplate creates the template or replaces the pod spec and description of the
+// existing template with the same name and pool.
+func PutPodSpecTemplate(ctx context.Context, t *PodSpecTemplate) error {
+	_, err := Bun().NewInsert().Model(t).
+		ExcludeColumn("id", "created_at", "updated_at").
+		On("CONFLICT (name, pool_name) DO UPDATE").
+		Set("pod_spec = EXCLUDED.pod_spec").
+		Set("description = EXCLUDED.description").
+		Set("updated_at = now()").
+		Returning("id, created_at, updated_at").
+		Exec(ctx)
+	return err
+}
+
+// GetPodSpecTemplates returns the templates defined for a resource pool, or all templates if
+// poolName is empty, ordered by name.
+func GetPodSpecTemplates(ctx context.Context, poolName string) ([]PodSpecTemplate, error) {
+	templates := []PodSpecTemplate{}
+	q := Bun().NewSelect().Model(&templates).Order("name", "pool_name")
+	if poolName != "" {
+		q = q.Where("pool_name = ?", poolName)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// ResolvePodSpecTemplate returns the template with the given name that applies to a resource
+// pool, preferring one defined for the pool over one defined for all pools.
+func ResolvePodSpecTemplate(ctx context.Context, name, poolName string) (*PodSpecTemplate, error) {
+	var t PodSpecTemplate
+	err := Bun().NewSelect().Model(&t).
+		Where("name = ?", name).
+		Where("pool_name IN (?, '')", poolName).
+		OrderExpr("pool_name = '' ASC").
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// DeletePodSpecTemplate deletes the template with the given name and pool.
+func DeletePodSpecTemplate(ctx context.Context, name, poolName string) error {
+	res, err := Bun().NewDelete().Model((*PodSpecTemplate)(nil)).
+		Where("name = ?", name).
+		Where("pool_name = ?", poolName).
+		Exec(ctx)
+	return MustHaveAffectedRows(res, err)
+}
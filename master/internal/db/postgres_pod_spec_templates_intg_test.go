@@ -0,0 +1,66 @@
+//go:build integration
+// +build integration
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	k8sV1 "k8s.io/api/core/v1"
+
+	"github.com/determined-ai/determined/master/pkg/etc"
+)
+
+func TestPodSpecTemplates(t *testing.T) {
+	ctx := context.Background()
+
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+	pgDB, cleanup := MustResolveNewPostgresDatabase(t)
+	defer cleanup()
+	MustMigrateTestPostgres(t, pgDB, MigrationsFromDB)
+
+	podWithSelector := func(selector string) *k8sV1.Pod {
+		return &k8sV1.Pod{Spec: k8sV1.PodSpec{NodeSelector: map[string]string{"pool": selector}}}
+	}
+	global := PodSpecTemplate{Name: "gpu-nodes", PodSpec: podWithSelector("global")}
+	pooled := PodSpecTemplate{
+		Name: "gpu-nodes", PoolName: testPoolName, PodSpec: podWithSelector("pooled"),
+	}
+	for _, tmpl := range []*PodSpecTemplate{&global, &pooled} {
+		require.NoError(t, PutPodSpecTemplate(ctx, tmpl))
+		require.NotZero(t, tmpl.ID)
+	}
+
+	got, err := ResolvePodSpecTemplate(ctx, "gpu-nodes", testPoolName)
+	require.NoError(t, err)
+	require.Equal(t, "pooled", got.PodSpec.Spec.NodeSelector["pool"])
+
+	got, err = ResolvePodSpecTemplate(ctx, "gpu-nodes", testPool2Name)
+	require.NoError(t, err)
+	require.Equal(t, "global", got.PodSpec.Spec.NodeSelector["pool"])
+
+	_, err = ResolvePodSpecTemplate(ctx, "missing", testPoolName)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	pooled.PodSpec = podWithSelector("updated")
+	pooled.Description = "updated"
+	require.NoError(t, PutPodSpecTemplate(ctx, &pooled))
+	inPool, err := GetPodSpecTemplates(ctx, testPoolName)
+	require.NoError(t, err)
+	require.Len(t, inPool, 1)
+	require.Equal(t, "updated", inPool[0].Description)
+	require.Equal(t, "updated", inPool[0].PodSpec.Spec.NodeSelector["pool"])
+
+	all, err := GetPodSpecTemplates(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	require.NoError(t, DeletePodSpecTemplate(ctx, "gpu-nodes", testPoolName))
+	require.ErrorIs(t, DeletePodSpecTemplate(ctx, "gpu-nodes", testPoolName), ErrNotFound)
+	got, err = ResolvePodSpecTemplate(ctx, "gpu-nodes", testPoolName)
+	require.NoError(t, err)
+	require.Equal(t, "global", got.PodSpec.Spec.NodeSelector["pool"])
+	require.NoError(t, DeletePodSpecTemplate(ctx, "gpu-nodes", ""))
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+// RPReservation is a struct reflecting the db table rp_reservations. It reserves a number of
+// slots in a resource pool for the tasks of a workspace during a time window.
+type RPReservation struct {
+	bun.BaseModel `bun:"table:rp_reservations,alias:r"`
+
+	ID            int       `bun:"id,pk,autoincrement" json:"id"`
+	PoolName      string    `bun:"pool_name" json:"resource_pool"`
+	WorkspaceID   int       `bun:"workspace_id" json:"workspace_id"`
+	WorkspaceName string    `bun:"workspace_name,scanonly" json:"workspace_name"`
+	Slots         int       `bun:"slots" json:"slots"`
+	StartTime     time.Time `bun:"start_time" json:"start_time"`
+	EndTime       time.Time `bun:"end_time" json:"end_time"`
+	Description   string    `bun:"description" json:"description"`
+
+	CreatedAt time.Time `bun:"created_at,nullzero,default:current_timestamp" json:"created_at"`
+}
+
+// Validate checks the fields of the reservation that don't depend on other tables.
+func (r *RPReservation) Validate() error {
+	switch {
+	case r.PoolName == "":
+		return errors.New("resource pool is required")
+	case r.Slots <= 0:
+		return errors.New("slots must be greater than 0")
+	case !r.EndTime.After(r.StartTime):
+		return errors.New("end time must be after start time")
+	}
+	return nil
+}
+
+// Active returns whether the reservation's window contains the given time.
+func (r *RPReservation) Active(now time.Time) bool {
+	return !now.Before(r.StartTime) && now.Before(r.EndTime)
+}
+
+func rpReservationsQuery(dest interface{}) *bun.SelectQuery {
+	return Bun().NewSelect().
+		Model(dest).
+		ColumnExpr("r.*").
+		ColumnExpr("w.name AS workspace_name").
+		Join("JOIN workspaces w ON w.id = r.workspace_id")
+}
+
+// AddRPReservation inserts a new reservation and sets its ID.
+func AddRPReservation(ctx context.Context, r *RPReservation) error {
+	_, err := Bun().NewInsert().Model(r).Returning("id, created_at").Exec(ctx)
+	return err
+}
+
+// GetRPReservation returns the reservation with the given ID.
+func GetRPReservation(ctx context.Context, id int) (*RPReservation, error) {
+	var r RPReservation
+	if err := rpReservationsQuery(&r).Where("r.id = ?", id).Scan(ctx); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetRPReservations returns the reservations of a resource pool, or of all resource pools if
+// poolName is empty, ordered by start time.
+func GetRPReservations(ctx context.Context, poolName string) ([]RPReservation, error) {
+	reservations := []RPReservation{}
+	q := rpReservationsQuery(&reservations).Order("r.start_time", "r.id")
+	if poolName != "" {
+		q = q.Where("r.pool_name = ?", poolName)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// GetUnexpiredRPReservations returns the reservations of a resource pool whose window has not
+// ended by the given time.
+func GetUnexpiredRPReservations(
+	ctx context.Context, poolName string, now time.Time,
+) ([]RPReservation, error) {
+	reservations := []RPReservation{}
+	err := rpReservationsQuery(&reservations).
+		Where("r.pool_name = ?", poolName).
+		Where("r.end_time > ?", now).
+		Order("r.start_time", "r.id").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// UpdateRPReservation updates the slots, window and description of a reservation.
+func UpdateRPReservation(ctx context.Context, r *RPReservation) error {
+	_, err := Bun().NewUpdate().Model(r).
+		Column("slots", "start_time", "end_time", "description").
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+// DeleteRPReservation deletes the reservation with the given ID.
+func DeleteRPReservation(ctx context.Context, id int) error {
+	_, err := Bun().NewDelete().Model((*RPReservation)(nil)).Where("id = ?", id).Exec(ctx)
+	return err
+}
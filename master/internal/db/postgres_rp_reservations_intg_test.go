@@ -0,0 +1,86 @@
+//go:build integration
+// +build integration
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/etc"
+)
+
+func TestRPReservations(t *testing.T) {
+	ctx := context.Background()
+
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+	pgDB, cleanup := MustResolveNewPostgresDatabase(t)
+	defer cleanup()
+	MustMigrateTestPostgres(t, pgDB, MigrationsFromDB)
+
+	user := RequireMockUser(t, pgDB)
+	workspaceIDs, err := MockWorkspaces([]string{"reserved"}, user.ID)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, CleanupMockWorkspace(workspaceIDs))
+	}()
+
+	now := time.Now().Truncate(time.Second)
+	past := RPReservation{
+		PoolName:    testPoolName,
+		WorkspaceID: int(workspaceIDs[0]),
+		Slots:       2,
+		StartTime:   now.Add(-2 * time.Hour),
+		EndTime:     now.Add(-time.Hour),
+	}
+	current := RPReservation{
+		PoolName:    testPoolName,
+		WorkspaceID: int(workspaceIDs[0]),
+		Slots:       4,
+		StartTime:   now.Add(-time.Hour),
+		EndTime:     now.Add(time.Hour),
+		Description: "training run",
+	}
+	other := RPReservation{
+		PoolName:    testPool2Name,
+		WorkspaceID: int(workspaceIDs[0]),
+		Slots:       1,
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+	}
+	for _, r := range []*RPReservation{&past, &current, &other} {
+		require.NoError(t, AddRPReservation(ctx, r))
+		require.NotZero(t, r.ID)
+	}
+
+	got, err := GetRPReservation(ctx, current.ID)
+	require.NoError(t, err)
+	require.Equal(t, "reserved", got.WorkspaceName)
+	require.Equal(t, 4, got.Slots)
+	require.True(t, got.Active(now))
+
+	all, err := GetRPReservations(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	inPool, err := GetRPReservations(ctx, testPoolName)
+	require.NoError(t, err)
+	require.Len(t, inPool, 2)
+
+	unexpired, err := GetUnexpiredRPReservations(ctx, testPoolName, now)
+	require.NoError(t, err)
+	require.Len(t, unexpired, 1)
+	require.Equal(t, current.ID, unexpired[0].ID)
+
+	current.Slots = 8
+	require.NoError(t, UpdateRPReservation(ctx, &current))
+	got, err = GetRPReservation(ctx, current.ID)
+	require.NoError(t, err)
+	require.Equal(t, 8, got.Slots)
+
+	require.NoError(t, DeleteRPReservation(ctx, current.ID))
+	_, err = GetRPReservation(ctx, current.ID)
+	require.Error(t, err)
+}
@@ -5,6 +5,7 @@ import (
 	"net"
 	"os"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -14,6 +15,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/exp/maps"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
@@ -80,10 +82,21 @@ type (
 
 		// opts are additional agent options the master sends to the agent.
 		opts *aproto.MasterSetAgentOptions
+		// drain is the state of the last drain with a timeout, until the agent is enabled.
+		drain *agentDrain
 
 		agentState *agentState
 	}
 
+	// agentDrain tracks a drain with a timeout.
+	agentDrain struct {
+		start            time.Time
+		deadline         time.Time
+		end              *time.Time
+		deadlineExceeded bool
+		timer            *time.Timer
+	}
+
 	// patchAllSlotsState updates the state of all slots.
 	patchAllSlotsState struct {
 		enabled *bool
@@ -459,7 +472,7 @@ func (a *agent) GetAgent(msg *apiv1.GetAgentRequest) *apiv1.GetAgentResponse {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	return &apiv1.GetAgentResponse{Agent: a.summarize().ToProto()}
+	return &apiv1.GetAgentResponse{Agent: a.summarize().ToProto(), Drain: a.drainProto()}
 }
 
 func (a *agent) GetSlots(msg *apiv1.GetSlotsRequest) *apiv1.GetSlotsResponse {
@@ -487,6 +500,7 @@ func (a *agent) EnableAgent(msg *apiv1.EnableAgentRequest) (*apiv1.EnableAgentRe
 	}
 
 	a.agentState.enable()
+	a.stopDrain()
 	a.agentState.patchAllSlotsState(patchAllSlotsState{
 		enabled: &a.agentState.enabled,
 		drain:   &a.agentState.draining,
@@ -507,6 +521,14 @@ func (a *agent) DisableAgent(msg *apiv1.DisableAgentRequest) (*apiv1.DisableAgen
 		return nil, errors.New("can't disable agent: agent not started")
 	}
 
+	if msg.Drain && msg.DrainTimeoutSeconds != nil {
+		a.drainWithTimeout(time.Duration(*msg.DrainTimeoutSeconds) * time.Second)
+		return &apiv1.DisableAgentResponse{
+			Agent: a.summarize().ToProto(),
+			Drain: a.drainProto(),
+		}, nil
+	}
+
 	// Mark current agent as disabled with RP.
 	a.agentState.disable(msg.Drain)
 	// Update individual slot state.
@@ -527,6 +549,108 @@ func (a *agent) DisableAgent(msg *apiv1.DisableAgentRequest) (*apiv1.DisableAgen
 	return &apiv1.DisableAgentResponse{Agent: a.summarize().ToProto()}, nil
 }
 
+// drainWithTimeout drains the agent and preempts all the allocations on it, so that they
+// checkpoint and get rescheduled elsewhere. The allocations that are still running at the deadline
+// are killed. Draining an agent that is already draining leaves the drain in progress as it is.
+func (a *agent) drainWithTimeout(timeout time.Duration) {
+	if a.drain != nil && a.drain.end == nil {
+		return
+	}
+
+	a.agentState.disable(true)
+	a.agentState.patchAllSlotsState(patchAllSlotsState{
+		enabled: &a.agentState.enabled,
+		drain:   &a.agentState.draining,
+	})
+
+	now := time.Now().UTC()
+	a.syslog.Infof("draining agent, preempting its allocations with a deadline of %s", timeout)
+	a.drain = &agentDrain{start: now, deadline: now.Add(timeout)}
+	for _, aID := range a.drainingAllocations() {
+		rmevents.Publish(aID, &sproto.ReleaseResources{
+			Reason:          "agent draining",
+			ForcePreemption: true,
+		})
+	}
+	drain := a.drain
+	drain.timer = time.AfterFunc(timeout, func() { a.handleDrainDeadline(drain) })
+	a.checkDrainCompleted()
+	a.notifyListeners()
+}
+
+func (a *agent) handleDrainDeadline(drain *agentDrain) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.drain != drain || drain.end != nil {
+		return
+	}
+
+	allocations := a.drainingAllocations()
+	a.syslog.Warnf("agent drain deadline exceeded, killing %d allocations", len(allocations))
+	drain.deadlineExceeded = true
+	for _, aID := range allocations {
+		rmevents.Publish(aID, &sproto.ReleaseResources{
+			Reason:    "agent drain deadline exceeded",
+			ForceKill: true,
+		})
+	}
+}
+
+// checkDrainCompleted completes the drain once no allocation is left on the agent.
+func (a *agent) checkDrainCompleted() {
+	if a.drain == nil || a.drain.end != nil || len(a.agentState.containerAllocation) > 0 {
+		return
+	}
+
+	end := time.Now().UTC()
+	a.syslog.Infof("agent drain completed in %s", end.Sub(a.drain.start))
+	a.drain.end = &end
+	a.drain.timer.Stop()
+}
+
+// stopDrain forgets the drain, if any, and cancels its deadline.
+func (a *agent) stopDrain() {
+	if a.drain == nil {
+		return
+	}
+	if a.drain.timer != nil {
+		a.drain.timer.Stop()
+	}
+	a.drain = nil
+}
+
+// drainingAllocations returns the allocations on the agent, in order.
+func (a *agent) drainingAllocations() []model.AllocationID {
+	allocations := make([]model.AllocationID, 0, len(a.agentState.containerAllocation))
+	for _, aID := range a.agentState.containerAllocation {
+		allocations = append(allocations, aID)
+	}
+	slices.Sort(allocations)
+	return slices.Compact(allocations)
+}
+
+// drainProto returns the progress of the drain, if any.
+func (a *agent) drainProto() *agentv1.AgentDrain {
+	if a.drain == nil {
+		return nil
+	}
+	allocations := a.drainingAllocations()
+	drain := &agentv1.AgentDrain{
+		StartTime:        timestamppb.New(a.drain.start),
+		Deadline:         timestamppb.New(a.drain.deadline),
+		AllocationIds:    make([]string, 0, len(allocations)),
+		DeadlineExceeded: a.drain.deadlineExceeded,
+	}
+	for _, aID := range allocations {
+		drain.AllocationIds = append(drain.AllocationIds, string(aID))
+	}
+	if a.drain.end != nil {
+		drain.EndTime = timestamppb.New(*a.drain.end)
+	}
+	return drain
+}
+
 func (a *agent) PatchSlotState(msg patchSlotState) (*model.SlotSummary, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -593,6 +717,7 @@ func (a *agent) HandleIncomingWebsocketMessage(msg *aproto.MasterMessage) {
 	case msg.AgentStarted != nil:
 		a.syslog.Infof("agent connected ip: %v resource pool: %s slots: %d",
 			a.address, a.resourcePoolName, len(msg.AgentStarted.Devices))
+		a.agentState.slotResources = msg.AgentStarted.SlotResources
 
 		if a.started {
 			err := a.agentState.checkAgentStartedDevicesMatch(msg.AgentStarted)
@@ -652,6 +777,35 @@ func (a *agent) HandleIncomingWebsocketMessage(msg *aproto.MasterMessage) {
 			}
 		}
 
+	case msg.DeviceHealthChanged != nil:
+		if !a.started {
+			a.syslog.Warn("received DeviceHealthChanged before the agent started")
+			return
+		}
+		a.agentState.deviceHealthChanged(*msg.DeviceHealthChanged)
+		a.notifyListeners()
+	case msg.CachedImagesChanged != nil:
+		if !a.started {
+			a.syslog.Warn("received CachedImagesChanged before the agent started")
+			return
+		}
+		a.agentState.cachedImagesChanged(*msg.CachedImagesChanged)
+	case msg.AgentDrained != nil:
+		if !a.started {
+			a.syslog.Warn("received AgentDrained before the agent started")
+			return
+		}
+		if !a.agentState.enabled {
+			// Already disabled or draining.
+			return
+		}
+		a.syslog.Info("agent was drained locally")
+		a.agentState.disable(true)
+		a.agentState.patchAllSlotsState(patchAllSlotsState{
+			enabled: &a.agentState.enabled,
+			drain:   &a.agentState.draining,
+		})
+		a.notifyListeners()
 	default:
 		check.Panic(errors.Errorf("error parsing incoming message"))
 	}
@@ -703,6 +857,7 @@ func (a *agent) containerStateChanged(sc aproto.ContainerStateChanged) {
 
 	rmevents.Publish(aID, sproto.FromContainerStateChanged(sc))
 	a.agentState.containerStateChanged(sc)
+	a.checkDrainCompleted()
 }
 
 func (a *agent) Summarize() model.AgentSummary {
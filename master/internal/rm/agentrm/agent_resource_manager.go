@@ -353,6 +353,7 @@ func (a *ResourceManager) GetResourcePools(
 		}
 
 		summary.Stats = jobStats
+		summary.SlotQuotas = jobStats.SlotQuotas
 		summaries = append(summaries, summary)
 	}
 	return &apiv1.GetResourcePoolsResponse{ResourcePools: summaries}, nil
@@ -707,6 +708,19 @@ func (a *ResourceManager) createResourcePoolSummary(
 				accelerator = pool.Provider.GCP.Accelerator()
 			}
 		}
+		if pool.Provider.Azure != nil {
+			preemptible = pool.Provider.Azure.SpotEnabled
+			location = pool.Provider.Azure.Location
+			imageID = pool.Provider.Azure.ImageID
+			instanceType = pool.Provider.Azure.VMSize.Name()
+			slotsPerAgent = pool.Provider.Azure.SlotsPerInstance()
+			slotType = pool.Provider.Azure.SlotType()
+		}
+		if pool.Provider.External != nil {
+			instanceType = pool.Provider.External.InstanceType.Name()
+			slotsPerAgent = pool.Provider.External.SlotsPerInstance()
+			slotType = pool.Provider.External.SlotType()
+		}
 	}
 
 	var schedulerType resourcepoolv1.SchedulerType
@@ -748,6 +762,17 @@ func (a *ResourceManager) createResourcePoolSummary(
 	}
 	if pool.Provider != nil {
 		resp.MinAgents = int32(pool.Provider.MinInstances)
+		resp.WarmPoolAgents = int32(pool.Provider.WarmPoolInstances)
+		for _, s := range pool.Provider.ScalingSchedules {
+			resp.ScalingSchedules = append(resp.ScalingSchedules, &resourcepoolv1.ScalingSchedule{
+				Name:           s.Name,
+				Cron:           s.Cron,
+				Duration:       float32(time.Duration(s.Duration).Seconds()),
+				Timezone:       s.Timezone,
+				MinAgents:      int32(s.MinInstances),
+				WarmPoolAgents: int32(s.WarmPoolInstances),
+			})
+		}
 		resp.MaxAgents = int32(pool.Provider.MaxInstances)
 		resp.MasterUrl = pool.Provider.MasterURL
 		resp.MasterCertName = pool.Provider.MasterCertName
@@ -847,6 +872,7 @@ func (a *ResourceManager) createResourcePoolSummary(
 	if pool.Provider == nil && resp.NumAgents > 0 {
 		resp.SlotType = resourceSummary.slotType.Proto()
 	}
+	rp.summarizeScaling(resp)
 
 	return resp, nil
 }
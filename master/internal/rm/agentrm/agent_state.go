@@ -36,6 +36,10 @@ type slot struct {
 	device      device.Device
 	enabled     slotEnabled
 	containerID *cproto.ID
+	// unhealthyReason is set while the agent reports the device as unhealthy, and healthDisabled
+	// while the slot is disabled because of it, so that it can be enabled again on recovery.
+	unhealthyReason string
+	healthDisabled  bool
 }
 
 // agentState holds the scheduler state for an agent. The implementation of agent-related operations
@@ -56,6 +60,11 @@ type agentState struct {
 	slotStates          map[device.ID]*slot
 	containerAllocation map[cproto.ID]model.AllocationID
 	containerState      map[cproto.ID]*cproto.Container
+
+	// cachedImages are the canonical references of the images the agent has cached locally.
+	cachedImages map[string]bool
+	// slotResources are the CPUs and memory the agent gives containers for each slot by default.
+	slotResources aproto.SlotResources
 }
 
 // newAgentState returns a new agent empty agent state backed by the handler.
@@ -207,6 +216,8 @@ func (a *agentState) deepCopy() *agentState {
 		// TODO(ilia): Deepcopy of `slotStates` may be necessary one day.
 		slotStates:       a.slotStates,
 		resourcePoolName: a.resourcePoolName,
+		cachedImages:     maps.Clone(a.cachedImages),
+		slotResources:    a.slotResources,
 	}
 
 	return copiedAgent
@@ -384,6 +395,10 @@ func (a *agentState) getSlotSummary(deviceID device.ID) model.SlotSummary {
 		Enabled:   s.enabled.enabled(),
 		Container: container,
 		Draining:  s.enabled.draining,
+
+		UnhealthyReason: s.unhealthyReason,
+		CPU:             a.slotResources.CPU,
+		MemoryBytes:     a.slotResources.MemoryBytes,
 	}
 }
 
@@ -409,8 +424,12 @@ func (a *agentState) updateSlotDeviceView(deviceID device.ID) {
 		// On `PostStop`, draining will be already set to false, and we'll kill the container
 		// whether we have the device or not.
 		if !s.enabled.draining && s.containerID != nil {
+			reason := "slot disabled"
+			if s.healthDisabled {
+				reason = "slot disabled: " + s.unhealthyReason
+			}
 			rmevents.Publish(a.containerAllocation[*s.containerID], &sproto.ReleaseResources{
-				Reason:    "slot disabled",
+				Reason:    reason,
 				ForceKill: true,
 			})
 		}
@@ -422,6 +441,7 @@ func (a *agentState) patchSlotStateInner(
 ) model.SlotSummary {
 	if msg.enabled != nil {
 		slotState.enabled.userEnabled = *msg.enabled
+		slotState.healthDisabled = false
 	}
 	if msg.drain != nil {
 		slotState.enabled.draining = *msg.drain
@@ -431,6 +451,52 @@ func (a *agentState) patchSlotStateInner(
 	return a.getSlotSummary(slotState.device.ID)
 }
 
+// deviceHealthChanged disables the slots of devices that the agent reports as unhealthy, the same
+// way DisableSlot does, and enables them again once the devices recover. Slots that were disabled
+// or enabled by a user while unhealthy are left alone.
+func (a *agentState) deviceHealthChanged(msg aproto.DeviceHealthChanged) {
+	for _, h := range msg.Devices {
+		s, ok := a.slotStates[h.ID]
+		if !ok {
+			a.syslog.Warnf("bad deviceHealthChanged on device: %d (%s)", h.ID, a.string())
+			continue
+		}
+
+		switch {
+		case !h.Healthy && s.unhealthyReason == "":
+			a.syslog.Warnf("device %s is unhealthy: %s", s.device.String(), h.Reason)
+			s.unhealthyReason = h.Reason
+			if s.enabled.userEnabled {
+				enabled, drain := false, false
+				a.patchSlotStateInner(patchSlotState{id: h.ID, enabled: &enabled, drain: &drain}, s)
+				s.healthDisabled = true
+			}
+		case !h.Healthy:
+			s.unhealthyReason = h.Reason
+		case h.Healthy && s.unhealthyReason != "":
+			a.syslog.Infof("device %s is healthy again", s.device.String())
+			s.unhealthyReason = ""
+			if s.healthDisabled {
+				enabled := true
+				a.patchSlotStateInner(patchSlotState{id: h.ID, enabled: &enabled}, s)
+			}
+		}
+	}
+}
+
+// cachedImagesChanged replaces the set of images the agent has cached locally.
+func (a *agentState) cachedImagesChanged(msg aproto.CachedImagesChanged) {
+	a.cachedImages = make(map[string]bool, len(msg.Images))
+	for _, image := range msg.Images {
+		a.cachedImages[image] = true
+	}
+}
+
+// hasCachedImage returns true if the agent has the image, given as a canonical reference, cached.
+func (a *agentState) hasCachedImage(image string) bool {
+	return a.cachedImages[image]
+}
+
 func (a *agentState) patchAllSlotsState(
 	msg patchAllSlotsState,
 ) model.SlotsSummary {
@@ -0,0 +1,83 @@
+package agentrm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+func TestDeviceHealthChanged(t *testing.T) {
+	state := newAgentState("agent", 100)
+	for i := 0; i < 2; i++ {
+		d := device.Device{ID: device.ID(i), Type: device.CUDA}
+		state.slotStates[d.ID] = &slot{
+			device:  d,
+			enabled: slotEnabled{agentEnabled: true, userEnabled: true},
+		}
+		state.updateSlotDeviceView(d.ID)
+	}
+	require.Equal(t, 2, state.numSlots())
+
+	report := func(healthy bool, reason string) {
+		state.deviceHealthChanged(aproto.DeviceHealthChanged{Devices: []aproto.DeviceHealth{
+			{ID: 0, Healthy: true},
+			{ID: 1, Healthy: healthy, Reason: reason},
+		}})
+	}
+
+	report(false, "uncorrectable ECC errors: 2")
+	summary := state.getSlotSummary(1)
+	require.False(t, summary.Enabled)
+	require.Equal(t, "uncorrectable ECC errors: 2", summary.UnhealthyReason)
+	require.True(t, state.getSlotSummary(0).Enabled)
+	require.Equal(t, 1, state.numSlots())
+
+	report(true, "")
+	summary = state.getSlotSummary(1)
+	require.True(t, summary.Enabled)
+	require.Empty(t, summary.UnhealthyReason)
+	require.Equal(t, 2, state.numSlots())
+
+	// A slot that a user disabled stays disabled after the device recovers.
+	disabled := false
+	_, err := state.patchSlotState(patchSlotState{id: 1, enabled: &disabled})
+	require.NoError(t, err)
+	report(false, "device is no longer listed by nvidia-smi")
+	report(true, "")
+	require.False(t, state.getSlotSummary(1).Enabled)
+
+	// And a slot that a user enabled while unhealthy stays enabled.
+	enabled := true
+	_, err = state.patchSlotState(patchSlotState{id: 1, enabled: &enabled})
+	require.NoError(t, err)
+	report(false, "Xid 79: GPU has fallen off the bus")
+	_, err = state.patchSlotState(patchSlotState{id: 1, enabled: &enabled})
+	require.NoError(t, err)
+	report(false, "Xid 79: GPU has fallen off the bus")
+	require.True(t, state.getSlotSummary(1).Enabled)
+	require.Equal(t, "Xid 79: GPU has fallen off the bus", state.getSlotSummary(1).UnhealthyReason)
+}
+
+func TestSlotSummaryResources(t *testing.T) {
+	state := newAgentState("agent", 100)
+	state.slotResources = aproto.SlotResources{CPU: 4, MemoryBytes: 32 << 30}
+	d := device.Device{ID: 0, Type: device.CUDA}
+	state.slotStates[d.ID] = &slot{
+		device:  d,
+		enabled: slotEnabled{agentEnabled: true, userEnabled: true},
+	}
+
+	summary := state.getSlotSummary(d.ID)
+	require.Equal(t, 4.0, summary.CPU)
+	require.Equal(t, int64(32<<30), summary.MemoryBytes)
+	require.Equal(t, 4.0, summary.ToProto().GetCpuLimit())
+	require.Equal(t, int64(32<<30), summary.ToProto().GetMemoryLimit())
+
+	// Unlimited resources are left unset.
+	state.slotResources = aproto.SlotResources{}
+	require.Nil(t, state.getSlotSummary(d.ID).ToProto().CpuLimit)
+	require.Nil(t, state.getSlotSummary(d.ID).ToProto().MemoryLimit)
+}
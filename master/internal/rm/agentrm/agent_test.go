@@ -0,0 +1,104 @@
+package agentrm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/rm/rmevents"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/syncx/queue"
+	"github.com/determined-ai/determined/proto/pkg/agentv1"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func newDrainTestAgent(allocations ...model.AllocationID) *agent {
+	a := &agent{
+		id:           "agent",
+		syslog:       logrus.WithField("component", "agent"),
+		agentUpdates: queue.New[agentUpdatedEvent](),
+		started:      true,
+		agentState:   newAgentState("agent", 100),
+	}
+	for _, aID := range allocations {
+		a.agentState.containerAllocation[cproto.NewID()] = aID
+	}
+	return a
+}
+
+func drainTestAgent(
+	t *testing.T, a *agent, timeout time.Duration,
+) *agentv1.AgentDrain {
+	resp, err := a.DisableAgent(&apiv1.DisableAgentRequest{
+		AgentId:             "agent",
+		Drain:               true,
+		DrainTimeoutSeconds: ptrs.Ptr(int32(timeout.Seconds())),
+	})
+	require.NoError(t, err)
+	return resp.Drain
+}
+
+func TestDrainAgent(t *testing.T) {
+	aID := model.AllocationID(t.Name())
+	sub := rmevents.Subscribe(aID)
+	defer sub.Close()
+
+	a := newDrainTestAgent(aID)
+	require.Nil(t, a.GetAgent(&apiv1.GetAgentRequest{}).Drain)
+
+	drain := drainTestAgent(t, a, time.Hour)
+	require.Equal(t, []string{string(aID)}, drain.AllocationIds)
+	require.Nil(t, drain.EndTime)
+	require.False(t, a.agentState.enabled)
+	require.True(t, a.agentState.draining)
+
+	release, ok := sub.Get().(*sproto.ReleaseResources)
+	require.True(t, ok)
+	require.True(t, release.ForcePreemption)
+	require.False(t, release.ForceKill)
+
+	// Draining again reports the drain in progress instead of preempting again.
+	again := drainTestAgent(t, a, time.Minute)
+	require.Equal(t, drain.Deadline.AsTime(), again.Deadline.AsTime())
+	require.Zero(t, sub.Len())
+
+	a.agentState.containerAllocation = map[cproto.ID]model.AllocationID{}
+	a.checkDrainCompleted()
+	drain = a.GetAgent(&apiv1.GetAgentRequest{}).Drain
+	require.NotNil(t, drain)
+	require.Empty(t, drain.AllocationIds)
+	require.NotNil(t, drain.EndTime)
+	require.False(t, drain.DeadlineExceeded)
+}
+
+func TestDrainAgentDeadlineExceeded(t *testing.T) {
+	aID := model.AllocationID(t.Name())
+	sub := rmevents.Subscribe(aID)
+	defer sub.Close()
+
+	a := newDrainTestAgent(aID)
+	drainTestAgent(t, a, time.Second)
+
+	release, ok := sub.Get().(*sproto.ReleaseResources)
+	require.True(t, ok)
+	require.True(t, release.ForcePreemption)
+
+	release, ok = sub.Get().(*sproto.ReleaseResources)
+	require.True(t, ok)
+	require.True(t, release.ForceKill)
+
+	drain := a.GetAgent(&apiv1.GetAgentRequest{}).Drain
+	require.NotNil(t, drain)
+	require.True(t, drain.DeadlineExceeded)
+	require.Nil(t, drain.EndTime)
+
+	// Enabling the agent cancels the drain.
+	_, err := a.EnableAgent(nil)
+	require.NoError(t, err)
+	require.Nil(t, a.GetAgent(&apiv1.GetAgentRequest{}).Drain)
+}
@@ -211,6 +211,11 @@ func (a *agents) createAgent(
 	if poolConfig == nil {
 		return nil, fmt.Errorf("cannot find specified resource pool %s for agent %s", resourcePool, id)
 	}
+	if len(poolConfig.PrePullImages) > 0 {
+		agentOpts := *opts
+		agentOpts.PrePullImages = poolConfig.PrePullImages
+		opts = &agentOpts
+	}
 
 	return newAgent(
 		id,
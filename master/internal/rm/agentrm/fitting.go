@@ -8,6 +8,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/device"
 	"github.com/determined-ai/determined/master/pkg/mathx"
 )
 
@@ -21,7 +22,10 @@ type SoftConstraint func(req *sproto.AllocateRequest, agent *agentState) float64
 // fittingState is the basis for assigning a task to one or more agents for execution.
 type fittingState struct {
 	Agent *agentState
-	Score float64
+	// ImageCached is true if the agent already has the task's image, which saves pulling it. It
+	// takes precedence over the score.
+	ImageCached bool
+	Score       float64
 	// Use hash distances besides scores of fitting here in order to
 	// load balance across agents for tasks that would have no preference
 	// for which agent they go onto if the scores are tied. Use hash distance
@@ -50,6 +54,10 @@ func (c candidateList) Less(i, j int) bool {
 	a := c[i]
 	b := c[j]
 	switch {
+	case a.ImageCached && !b.ImageCached:
+		return true
+	case !a.ImageCached && b.ImageCached:
+		return false
 	case a.Score > b.Score:
 		return true
 	case a.Score < b.Score:
@@ -147,6 +155,7 @@ func findDedicatedAgentFits(
 		for _, agent := range agentsByNumSlots[n] {
 			candidates = append(candidates, &fittingState{
 				Agent:        agent,
+				ImageCached:  imageCached(req, agent),
 				Score:        fittingMethod(req, agent),
 				HashDistance: hashDistance(req, agent),
 				Slots:        n,
@@ -229,6 +238,7 @@ func findSharedAgentFit(
 
 		candidates = append(candidates, &fittingState{
 			Agent:        agent,
+			ImageCached:  imageCached(req, agent),
 			Score:        fittingMethod(req, agent),
 			HashDistance: hashDistance(req, agent),
 		})
@@ -244,6 +254,19 @@ func findSharedAgentFit(
 	return candidates[0]
 }
 
+// imageCached returns true if the agent has cached the image the task would run on it.
+func imageCached(req *sproto.AllocateRequest, agent *agentState) bool {
+	deviceType := device.CPU
+	if req.SlotsNeeded > 0 {
+		for d := range agent.Devices {
+			deviceType = d.Type
+			break
+		}
+	}
+	image, ok := req.Images[deviceType]
+	return ok && agent.hasCachedImage(image)
+}
+
 func stringHashNumber(s string) uint64 {
 	// An array must have an address (essentially, be assigned to a variable) to be sliced.
 	hash := md5.Sum([]byte(s)) // #nosec
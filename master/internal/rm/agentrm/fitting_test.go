@@ -8,6 +8,9 @@ import (
 	"gotest.tools/assert"
 
 	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
 )
 
 func TestIsViable(t *testing.T) {
@@ -502,6 +505,57 @@ func TestFindFitDisallowedNodes(t *testing.T) {
 	assert.Equal(t, fits[0].Agent, agents[0])
 }
 
+func TestFindFitCachedImage(t *testing.T) {
+	agents := []*agentState{
+		newFakeAgentState(t, "agent1", 4, 0, 100, 0),
+		newFakeAgentState(t, "agent2", 4, 2, 100, 0),
+	}
+	for _, agent := range agents {
+		devices := make(map[device.Device]*cproto.ID, len(agent.Devices))
+		for d, id := range agent.Devices {
+			d.Type = device.CUDA
+			devices[d] = id
+		}
+		agent.Devices = devices
+	}
+	agentsByHandler, _ := byID(agents...)
+	task := &sproto.AllocateRequest{
+		AllocationID: "a",
+		SlotsNeeded:  1,
+		Images: map[device.Type]string{
+			device.CPU:  "docker.io/library/cpu:latest",
+			device.CUDA: "docker.io/library/cuda:latest",
+		},
+	}
+
+	// Without cached images, best fit picks the busier agent.
+	fits := findFits(task, agentsByHandler, BestFit, false)
+	assert.Assert(t, len(fits) == 1)
+	assert.Equal(t, fits[0].Agent, agents[1])
+
+	// An agent with the image cached is preferred.
+	agents[0].cachedImagesChanged(aproto.CachedImagesChanged{
+		Images: []string{"docker.io/library/cuda:latest"},
+	})
+	fits = findFits(task, agentsByHandler, BestFit, false)
+	assert.Assert(t, len(fits) == 1)
+	assert.Equal(t, fits[0].Agent, agents[0])
+
+	// The image for the agent's device type is the one that counts.
+	agents[0].cachedImagesChanged(aproto.CachedImagesChanged{
+		Images: []string{"docker.io/library/cpu:latest"},
+	})
+	fits = findFits(task, agentsByHandler, BestFit, false)
+	assert.Assert(t, len(fits) == 1)
+	assert.Equal(t, fits[0].Agent, agents[1])
+
+	// Zero-slot tasks run the CPU image.
+	task.SlotsNeeded = 0
+	fits = findFits(task, agentsByHandler, BestFit, false)
+	assert.Assert(t, len(fits) == 1)
+	assert.Equal(t, fits[0].Agent, agents[0])
+}
+
 func byID(
 	handlers ...*agentState,
 ) (map[agentID]*agentState, []*agentState) {
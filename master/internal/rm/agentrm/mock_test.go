@@ -32,6 +32,8 @@ type MockTask struct {
 	// Any test that set this to false is half wrong. It is used as a proxy to oversubscribe agents.
 	ContainerStarted  bool
 	JobSubmissionTime time.Time
+	StartTime         time.Time
+	MaxRuntime        *time.Duration
 
 	BlockedNodes []string
 }
@@ -63,6 +65,7 @@ func MockTaskToAllocateRequest(mockTask *MockTask) *sproto.AllocateRequest {
 		Preemptible:       !mockTask.NonPreemptible,
 		JobSubmissionTime: jobSubmissionTime,
 		BlockedNodes:      mockTask.BlockedNodes,
+		MaxRuntime:        mockTask.MaxRuntime,
 	}
 	return req
 }
@@ -3,6 +3,7 @@ package agentrm
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -17,22 +18,46 @@ import (
 type priorityScheduler struct {
 	preemptionEnabled      bool
 	allowHeterogeneousFits bool
+	easyBackfill           bool
+	// clock returns the current time; it is overridden by the scheduling simulator.
+	clock func() time.Time
 }
 
 // NewPriorityScheduler creates a new scheduler that schedules tasks via priority.
-func NewPriorityScheduler(config *config.SchedulerConfig) Scheduler {
+func NewPriorityScheduler(conf *config.SchedulerConfig) Scheduler {
 	return &priorityScheduler{
-		preemptionEnabled:      config.Priority.Preemption,
-		allowHeterogeneousFits: config.AllowHeterogeneousFits,
+		preemptionEnabled:      conf.Priority.Preemption,
+		allowHeterogeneousFits: conf.AllowHeterogeneousFits,
+		easyBackfill:           conf.Priority.Backfill == config.EasyBackfill,
 	}
 }
 
+// reservation tracks the first pending task that could not be scheduled in a scheduling cycle
+// when EASY backfilling is enabled.
+type reservation struct {
+	// blocked is set once a pending task could not be scheduled.
+	blocked bool
+	// start is the time the blocked task is expected to be able to start. It is zero if it
+	// can't be determined because the tasks in the way did not declare a max runtime.
+	start time.Time
+	// agents is the expected state of the agents at start, with the blocked task placed.
+	agents map[agentID]*agentState
+}
+
 func (p priorityScheduler) Schedule(rp *resourcePool) (
 	[]*sproto.AllocateRequest,
 	[]model.AllocationID,
 ) {
+	taskList := rp.taskList
+	if rp.config.Quotas != nil || len(rp.activeReservations) > 0 {
+		// Admit pending tasks against slot quotas and reservations in priority order, so that
+		// higher-priority tasks use a user's quota or a workspace's reservation first.
+		taskList = rp.schedulableTasks(
+			tasklist.SortTasksWithPosition(rp.taskList, rp.groups, rp.queuePositions, false),
+		)
+	}
 	return p.prioritySchedule(
-		rp.taskList,
+		taskList,
 		rp.groups,
 		rp.queuePositions,
 		rp.agentStatesCache,
@@ -78,7 +103,9 @@ func (p priorityScheduler) prioritySchedule(
 // prioritySchedulerWithFilter defines the logic of each scheduling circle.
 // 1. Schedule pending tasks without preemption.
 // 2. Search if preempting any lower-priority tasks can make space.
-// 3. Back-fill lower-priority pending tasks if there are no tasks to preempt.
+// 3. Back-fill lower-priority pending tasks if there are no tasks to preempt. With EASY
+// backfilling, only tasks that don't delay the first task that could not be scheduled are
+// back-filled, unless they can be preempted.
 func (p priorityScheduler) prioritySchedulerWithFilter(
 	taskList *tasklist.TaskList,
 	groups map[model.JobID]*tasklist.Group,
@@ -105,21 +132,35 @@ func (p priorityScheduler) prioritySchedulerWithFilter(
 	// If there exist any tasks that cannot be scheduled, all the tasks of lower priorities
 	// can only be backfilled if they are preemptible.
 	backfilling := false
+	var resv reservation
 
 	for _, priority := range getOrderedPriorities(priorityToPendingTasksMap) {
 		allocationRequests := priorityToPendingTasksMap[priority]
 		log.Debugf("processing priority %d with %d pending tasks (backfilling: %v)",
 			priority, len(allocationRequests), backfilling)
 
-		successfulAllocations, unSuccessfulAllocations := p.trySchedulingPendingTasksInPriority(
-			allocationRequests,
-			localAgentsState,
-			fittingMethod,
-		)
+		var successfulAllocations, unSuccessfulAllocations []*sproto.AllocateRequest
+		if p.easyBackfill {
+			successfulAllocations, unSuccessfulAllocations = p.trySchedulingPendingTasksWithReservation(
+				taskList,
+				allocationRequests,
+				localAgentsState,
+				fittingMethod,
+				&resv,
+			)
+		} else {
+			successfulAllocations, unSuccessfulAllocations = p.trySchedulingPendingTasksInPriority(
+				allocationRequests,
+				localAgentsState,
+				fittingMethod,
+			)
+		}
 
 		// Only start tasks if there are no tasks of higher priorities to preempt.
 		if len(toRelease) == 0 {
-			if !backfilling {
+			// With EASY backfilling, tasks that were placed have already been checked against
+			// the reservation.
+			if !backfilling || p.easyBackfill {
 				for _, allocatedTask := range successfulAllocations {
 					log.Debugf("scheduled task: %s", allocatedTask.Name)
 					toAllocate = append(toAllocate, allocatedTask)
@@ -271,6 +312,119 @@ func (p priorityScheduler) trySchedulingPendingTasksInPriority(
 	return successfulAllocations, unSuccessfulAllocations
 }
 
+// trySchedulingPendingTasksWithReservation is the EASY backfilling counterpart of
+// trySchedulingPendingTasksInPriority. Once a task cannot be scheduled, resources are reserved for
+// it at the time enough running tasks are expected to finish, and later tasks are only scheduled
+// if they don't delay that reservation: they either declare a max runtime that ends before it, or
+// they fit alongside the reserved task. Tasks that would delay the reservation are still
+// scheduled if they can be preempted once the reserved task is able to run.
+func (p priorityScheduler) trySchedulingPendingTasksWithReservation(
+	taskList *tasklist.TaskList,
+	allocationRequests []*sproto.AllocateRequest,
+	agents map[agentID]*agentState,
+	fittingMethod SoftConstraint,
+	resv *reservation,
+) ([]*sproto.AllocateRequest, []*sproto.AllocateRequest) {
+	successfulAllocations := make([]*sproto.AllocateRequest, 0)
+	unSuccessfulAllocations := make([]*sproto.AllocateRequest, 0)
+	now := p.now()
+
+	for _, allocationRequest := range allocationRequests {
+		fits := findFits(allocationRequest, agents, fittingMethod, p.allowHeterogeneousFits)
+		if len(fits) == 0 {
+			unSuccessfulAllocations = append(unSuccessfulAllocations, allocationRequest)
+			if !resv.blocked {
+				resv.blocked = true
+				resv.start, resv.agents = p.reserve(taskList, allocationRequest, agents, fittingMethod)
+				log.Debugf("reserved resources for task %s at %s",
+					allocationRequest.Name, resv.start)
+			}
+			continue
+		}
+
+		if resv.blocked {
+			var shadowFits []*fittingState
+			if resv.agents != nil {
+				shadowFits = findFits(
+					allocationRequest, resv.agents, fittingMethod, p.allowHeterogeneousFits,
+				)
+			}
+			switch {
+			case allocationRequest.MaxRuntime != nil && !resv.start.IsZero() &&
+				!now.Add(*allocationRequest.MaxRuntime).After(resv.start):
+			case len(shadowFits) > 0:
+				addTaskToAgents(shadowFits)
+			case p.preemptionEnabled && allocationRequest.Preemptible:
+			default:
+				log.Debugf("not backfilling task %s as it would delay the reservation",
+					allocationRequest.Name)
+				continue
+			}
+			log.Debugf("scheduled task via backfilling: %s", allocationRequest.Name)
+			allocationRequest.State = sproto.SchedulingStateScheduledBackfilled
+		}
+
+		addTaskToAgents(fits)
+		successfulAllocations = append(successfulAllocations, allocationRequest)
+	}
+
+	return successfulAllocations, unSuccessfulAllocations
+}
+
+func (p priorityScheduler) now() time.Time {
+	if p.clock != nil {
+		return p.clock()
+	}
+	return time.Now()
+}
+
+// reserve finds the earliest time the request is expected to fit, by releasing running
+// allocations in order of their expected end time, based on their declared max runtime.
+// Allocations without a max runtime are assumed to never end. It returns the time and the
+// expected state of the agents with the request placed, or a zero time and nil if the request
+// never fits.
+func (p priorityScheduler) reserve(
+	taskList *tasklist.TaskList,
+	allocationRequest *sproto.AllocateRequest,
+	agents map[agentID]*agentState,
+	fittingMethod SoftConstraint,
+) (time.Time, map[agentID]*agentState) {
+	type ending struct {
+		at        time.Time
+		allocated *sproto.ResourcesAllocated
+	}
+	var endings []ending
+	for it := taskList.Iterator(); it.Next(); {
+		req := it.Value()
+		allocated := taskList.Allocation(req.AllocationID)
+		if allocated == nil || req.MaxRuntime == nil {
+			continue
+		}
+		endings = append(endings, ending{
+			at:        allocated.StartTime.Add(*req.MaxRuntime),
+			allocated: allocated,
+		})
+	}
+	sort.SliceStable(endings, func(i, j int) bool {
+		return endings[i].at.Before(endings[j].at)
+	})
+
+	shadowAgents := deepCopyAgents(agents)
+	for _, e := range endings {
+		removeTaskFromAgents(shadowAgents, e.allocated)
+		if fits := findFits(
+			allocationRequest,
+			shadowAgents,
+			fittingMethod,
+			p.allowHeterogeneousFits,
+		); len(fits) > 0 {
+			addTaskToAgents(fits)
+			return e.at, shadowAgents
+		}
+	}
+	return time.Time{}, nil
+}
+
 // sortTasksByPriorityAndPositionAndTimestamp sorts all pending and scheduled tasks
 // separately by priority. Within each priority, tasks are ordered
 // based on their queue position and then creation time.
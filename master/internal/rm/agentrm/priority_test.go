@@ -638,6 +638,123 @@ func TestPrioritySchedulingBackfillingZeroSlotTask(t *testing.T) {
 	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
 }
 
+func TestPrioritySchedulingEasyBackfilling(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+	now := time.Now()
+	hour := time.Hour
+	halfHour := 30 * time.Minute
+	twoHours := 2 * time.Hour
+
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 4},
+		{ID: "agent2", Slots: 4},
+	}
+	groups := []*MockGroup{
+		{ID: "group1", Priority: &lowerPriority},
+		{ID: "group2", Priority: &higherPriority},
+	}
+	tasks := []*MockTask{
+		{
+			ID:          "running task ends in an hour",
+			SlotsNeeded: 4, Group: groups[1], AllocatedAgent: agents[0], ContainerStarted: true,
+			StartTime: now.Add(-hour), MaxRuntime: &twoHours,
+		},
+		{
+			ID:          "running task ends in half an hour",
+			SlotsNeeded: 2, Group: groups[1], AllocatedAgent: agents[1], ContainerStarted: true,
+			StartTime: now, MaxRuntime: &halfHour,
+		},
+		{
+			ID:          "oversized task gets a reservation in an hour",
+			SlotsNeeded: 8, Group: groups[1],
+		},
+		{
+			ID:          "short task should be backfilled",
+			SlotsNeeded: 1, Group: groups[0], MaxRuntime: &halfHour,
+		},
+		{
+			ID:          "long task should not be backfilled",
+			SlotsNeeded: 1, Group: groups[0], MaxRuntime: &twoHours,
+		},
+		{
+			ID:          "non-preemptible task without a max runtime should not be backfilled",
+			SlotsNeeded: 1, Group: groups[0], NonPreemptible: true,
+		},
+	}
+
+	// Without EASY backfilling, nothing is backfilled since preemption is disabled.
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+	p := &priorityScheduler{}
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap,
+		make(map[model.JobID]decimal.Decimal), agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, []*MockTask{})
+	assertEqualToRelease(t, taskList, toRelease, []*MockTask{})
+
+	taskList, groupMap, agentMap = setupSchedulerStates(t, tasks, groups, agents)
+	p = &priorityScheduler{easyBackfill: true}
+	toAllocate, toRelease = p.prioritySchedule(taskList, groupMap,
+		make(map[model.JobID]decimal.Decimal), agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, []*MockTask{tasks[3]})
+	assertEqualToRelease(t, taskList, toRelease, []*MockTask{})
+	assert.Equal(t, toAllocate[0].State, sproto.SchedulingStateScheduledBackfilled)
+
+	// With preemption, preemptible tasks may still be backfilled past the reservation.
+	taskList, groupMap, agentMap = setupSchedulerStates(t, tasks, groups, agents)
+	p = &priorityScheduler{easyBackfill: true, preemptionEnabled: true}
+	toAllocate, toRelease = p.prioritySchedule(taskList, groupMap,
+		make(map[model.JobID]decimal.Decimal), agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, []*MockTask{tasks[3], tasks[4]})
+	assertEqualToRelease(t, taskList, toRelease, []*MockTask{})
+}
+
+func TestPrioritySchedulingEasyBackfillingAlongsideReservation(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+	halfHour := 30 * time.Minute
+
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 4},
+		{ID: "agent2", Slots: 4},
+		{ID: "agent3", Slots: 4},
+		{ID: "agent4", Slots: 2},
+	}
+	groups := []*MockGroup{
+		{ID: "group1", Priority: &lowerPriority},
+		{ID: "group2", Priority: &higherPriority},
+	}
+	tasks := []*MockTask{
+		{
+			ID:          "running task without a max runtime",
+			SlotsNeeded: 4, Group: groups[1], AllocatedAgent: agents[0], ContainerStarted: true,
+		},
+		{
+			ID:          "running task ends in half an hour",
+			SlotsNeeded: 2, Group: groups[1], AllocatedAgent: agents[1], ContainerStarted: true,
+			StartTime: time.Now(), MaxRuntime: &halfHour,
+		},
+		{
+			ID:          "oversized task gets a reservation in half an hour",
+			SlotsNeeded: 8, Group: groups[1],
+		},
+		{
+			ID:          "task that fits alongside the reservation should be backfilled",
+			SlotsNeeded: 2, Group: groups[0],
+		},
+		{
+			ID:          "task that would delay the reservation should not be backfilled",
+			SlotsNeeded: 4, Group: groups[0],
+		},
+	}
+
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+	p := &priorityScheduler{easyBackfill: true}
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap,
+		make(map[model.JobID]decimal.Decimal), agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, []*MockTask{tasks[3]})
+	assertEqualToRelease(t, taskList, toRelease, []*MockTask{})
+}
+
 func TestPrioritySchedulingPreemptOneByPosition(t *testing.T) {
 	priority := 42
 
@@ -42,6 +42,23 @@ type Provider interface {
 	Terminate(instanceIDs []string)
 }
 
+// InstanceTypeOption is an instance type a provider launches and the number of slots of its
+// instances.
+type InstanceTypeOption struct {
+	Name  string
+	Slots int
+}
+
+// MultiInstanceTypeProvider is implemented by providers that launch instances of more than one
+// instance type.
+type MultiInstanceTypeProvider interface {
+	Provider
+	// InstanceTypes returns the instance types the provider launches, in order of preference.
+	InstanceTypes() []InstanceTypeOption
+	// LaunchInstanceType launches instances of the instance type with the given name.
+	LaunchInstanceType(name string, instanceNum int) error
+}
+
 // MustMakeAgentSetupScript generates the agent setup script.
 func MustMakeAgentSetupScript(config AgentSetupScriptConfig) []byte {
 	templateStr := string(etc.MustStaticFile(etc.AgentSetupScriptTemplateResource))
@@ -144,6 +144,17 @@ func (c *awsCluster) SlotsPerInstance() int {
 	return c.config.SlotsPerInstance()
 }
 
+func (c *awsCluster) InstanceTypes() []agentsetup.InstanceTypeOption {
+	var options []agentsetup.InstanceTypeOption
+	for _, instanceType := range c.config.InstanceTypes() {
+		options = append(options, agentsetup.InstanceTypeOption{
+			Name:  instanceType.Name(),
+			Slots: c.config.SlotsPerInstanceType(instanceType),
+		})
+	}
+	return options
+}
+
 func (c *awsCluster) agentNameFromInstance(inst *ec2.Instance) string {
 	return *inst.InstanceId
 }
@@ -172,10 +183,23 @@ func (c *awsCluster) List() ([]*model.Instance, error) {
 }
 
 func (c *awsCluster) Launch(instanceNum int) error {
+	return c.launch(c.config.InstanceType, instanceNum)
+}
+
+func (c *awsCluster) LaunchInstanceType(name string, instanceNum int) error {
+	for _, instanceType := range c.config.InstanceTypes() {
+		if instanceType.Name() == name {
+			return c.launch(provconfig.Ec2InstanceType(name), instanceNum)
+		}
+	}
+	return errors.Errorf("EC2 instance type %s is not configured", name)
+}
+
+func (c *awsCluster) launch(instanceType provconfig.Ec2InstanceType, instanceNum int) error {
 	if c.config.SpotEnabled {
-		return c.launchSpot(instanceNum)
+		return c.launchSpot(instanceType, instanceNum)
 	}
-	return c.launchOnDemand(instanceNum)
+	return c.launchOnDemand(instanceType, instanceNum)
 }
 
 func (c *awsCluster) Terminate(instanceIDs []string) {
@@ -206,12 +230,14 @@ func (c *awsCluster) listOnDemand() ([]*model.Instance, error) {
 	return res, nil
 }
 
-func (c *awsCluster) launchOnDemand(instanceNum int) error {
+func (c *awsCluster) launchOnDemand(
+	instanceType provconfig.Ec2InstanceType, instanceNum int,
+) error {
 	if instanceNum <= 0 {
 		return nil
 	}
 
-	instances, err := c.launchInstances(instanceNum, false)
+	instances, err := c.launchInstances(instanceType, instanceNum, false)
 	switch {
 	case err != nil && strings.Contains(err.Error(), "InsufficientInstanceCapacity"):
 		c.syslog.WithError(err).Warn("cannot launch EC2 instances right now")
@@ -335,7 +361,9 @@ func (c *awsCluster) describeInstancesByID(
 	return instances, nil
 }
 
-func (c *awsCluster) launchInstances(instanceNum int, dryRun bool) (*ec2.Reservation, error) {
+func (c *awsCluster) launchInstances(
+	instanceType provconfig.Ec2InstanceType, instanceNum int, dryRun bool,
+) (*ec2.Reservation, error) {
 	input := &ec2.RunInstancesInput{
 		BlockDeviceMappings: []*ec2.BlockDeviceMapping{
 			{
@@ -350,7 +378,7 @@ func (c *awsCluster) launchInstances(instanceNum int, dryRun bool) (*ec2.Reserva
 		DryRun:                            aws.Bool(dryRun),
 		ImageId:                           aws.String(c.config.ImageID),
 		InstanceInitiatedShutdownBehavior: aws.String(ec2.ShutdownBehaviorTerminate),
-		InstanceType:                      aws.String(c.config.InstanceType.Name()),
+		InstanceType:                      aws.String(instanceType.Name()),
 		KeyName:                           aws.String(c.config.SSHKeyName),
 		MaxCount:                          aws.Int64(int64(instanceNum)),
 		MinCount:                          aws.Int64(1),
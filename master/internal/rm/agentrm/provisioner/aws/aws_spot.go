@@ -272,6 +272,7 @@ func (c *awsCluster) terminateSpot(instanceIDs []*string) {
 }
 
 func (c *awsCluster) launchSpot(
+	instanceType provconfig.Ec2InstanceType,
 	instanceNum int,
 ) error {
 	if instanceNum <= 0 {
@@ -281,7 +282,7 @@ func (c *awsCluster) launchSpot(
 	c.syslog.
 		WithField("log-type", "launchSpot.start").
 		Infof("launching %d EC2 spot requests", instanceNum)
-	resp, err := c.createSpotInstanceRequestsCorrectingForClockSkew(instanceNum, false)
+	resp, err := c.createSpotInstanceRequestsCorrectingForClockSkew(instanceType, instanceNum, false)
 	if err != nil {
 		c.syslog.WithError(err).Error("cannot launch EC2 spot requests")
 		return err
@@ -469,13 +470,14 @@ func roundDurationUp(d time.Duration) time.Duration {
 // This can happen a maximum of 5 times before exiting with an error, to ensure that this
 // function doesn't block for too long.
 func (c *awsCluster) createSpotInstanceRequestsCorrectingForClockSkew(
+	instanceType provconfig.Ec2InstanceType,
 	numInstances int,
 	dryRun bool,
 ) (resp *ec2.RequestSpotInstancesOutput, err error) {
 	maxRetries := 5
 	for numRetries := 0; numRetries <= maxRetries; numRetries++ {
 		offset := c.spot.approximateClockSkew + c.spot.launchTimeOffset
-		resp, err = c.createSpotInstanceRequest(numInstances, c.config.InstanceType, offset, dryRun)
+		resp, err = c.createSpotInstanceRequest(numInstances, instanceType, offset, dryRun)
 		if err == nil {
 			return resp, nil
 		}
@@ -0,0 +1,344 @@
+package azure
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	petname "github.com/dustinkirkland/golang-petname"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
+	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/agentsetup"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const resourcePoolTagKey = "determined-resource-pool"
+
+func init() {
+	petname.NonDeterministicMode()
+}
+
+// azureCluster wraps an Azure resource group to provide the agentsetup.Provider interface. VMs
+// are created one by one with their network interface, public IP and OS disk, which are all
+// deleted with the VM.
+type azureCluster struct {
+	config       *provconfig.AzureClusterConfig
+	resourcePool string
+	masterURL    url.URL
+	customData   string
+
+	client *armClient
+	syslog *logrus.Entry
+
+	// launches tracks the VMs that were created but may not be visible in the API yet.
+	mu       sync.Mutex
+	launches map[string]time.Time
+}
+
+// New creates a new Azure cluster.
+func New(
+	resourcePool string, config *provconfig.Config, cert *tls.Certificate,
+) (agentsetup.Provider, error) {
+	masterURL, err := url.Parse(config.MasterURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse master url")
+	}
+
+	var certBytes []byte
+	if masterURL.Scheme == agentsetup.SecureScheme && cert != nil {
+		for _, c := range cert.Certificate {
+			certBytes = append(certBytes, pem.EncodeToMemory(&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: c,
+			})...)
+		}
+	}
+
+	setupScript := agentsetup.MustMakeAgentSetupScript(agentsetup.AgentSetupScriptConfig{
+		MasterHost:             masterURL.Hostname(),
+		MasterPort:             masterURL.Port(),
+		MasterCertName:         config.MasterCertName,
+		SlotType:               config.Azure.SlotType(),
+		AgentNetwork:           config.AgentDockerNetwork,
+		AgentDockerRuntime:     config.AgentDockerRuntime,
+		AgentDockerImage:       config.AgentDockerImage,
+		AgentReconnectAttempts: config.AgentReconnectAttempts,
+		AgentReconnectBackoff:  config.AgentReconnectBackoff,
+		StartupScriptBase64: base64.StdEncoding.EncodeToString(
+			[]byte(config.StartupScript),
+		),
+		ContainerStartupScriptBase64: base64.StdEncoding.EncodeToString(
+			[]byte(config.ContainerStartupScript),
+		),
+		MasterCertBase64: base64.StdEncoding.EncodeToString(certBytes),
+		AgentID: `$(curl -s -H Metadata:true "http://169.254.169.254/metadata/instance/` +
+			`compute/name?api-version=2021-02-01&format=text")`,
+		ResourcePool: resourcePool,
+	})
+
+	return &azureCluster{
+		config:       config.Azure,
+		resourcePool: resourcePool,
+		masterURL:    *masterURL,
+		customData:   base64.StdEncoding.EncodeToString(setupScript),
+		client:       newARMClient(config.Azure),
+		syslog:       logrus.WithField("azure-cluster", resourcePool),
+		launches:     make(map[string]time.Time),
+	}, nil
+}
+
+func (c *azureCluster) InstanceType() model.InstanceType {
+	return c.config.InstanceType()
+}
+
+func (c *azureCluster) SlotsPerInstance() int {
+	return c.config.SlotsPerInstance()
+}
+
+func (c *azureCluster) vmsPath() string {
+	return fmt.Sprintf(
+		"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachines",
+		c.config.SubscriptionID, c.config.ResourceGroup,
+	)
+}
+
+func (c *azureCluster) List() ([]*model.Instance, error) {
+	ctx := context.Background()
+	var vms []virtualMachine
+	path, query := c.vmsPath(), url.Values{"$expand": {"instanceView"}}
+	for path != "" {
+		var page virtualMachineList
+		if err := c.client.do(ctx, http.MethodGet, path, query, nil, &page); err != nil {
+			return nil, errors.Wrap(err, "cannot list Azure VMs")
+		}
+		for _, vm := range page.Value {
+			if vm.Tags[c.config.TagKey] == c.config.TagValue &&
+				vm.Tags[resourcePoolTagKey] == c.resourcePool {
+				vms = append(vms, vm)
+			}
+		}
+		path, query = page.NextLink, nil
+	}
+
+	var failed []string
+	instances := make([]*model.Instance, 0, len(vms))
+	for _, vm := range vms {
+		inst := c.newInstance(vm)
+		if vm.provisioningFailed() {
+			code, message := vm.provisioningError()
+			c.syslog.
+				WithField("vm-provisioning-error-code", code).
+				WithField("vm-provisioning-error-message", message).
+				WithField("vm-spot", vm.Properties.Priority == spotPriority).
+				Errorf("Azure VM %s cannot be provisioned and may require user intervention; "+
+					"deleting it", vm.Name)
+			failed = append(failed, vm.Name)
+			inst.State = model.Terminating
+		} else if inst.State == model.Unknown {
+			c.syslog.Errorf("unknown instance state for instance %v: %v",
+				inst.ID, vm.Properties.InstanceView)
+		}
+		instances = append(instances, inst)
+	}
+	instances = append(instances, c.listPendingLaunches(vms)...)
+	c.deleteVMs(failed)
+	return instances, nil
+}
+
+func (c *azureCluster) Launch(instanceNum int) error {
+	if instanceNum <= 0 {
+		return nil
+	}
+	ctx := context.Background()
+	var launched []*model.Instance
+	var launchErr error
+	for i := 0; i < instanceNum; i++ {
+		name := c.generateVMName()
+		var vm virtualMachine
+		err := c.client.do(
+			ctx, http.MethodPut, c.vmsPath()+"/"+name, nil, c.newVirtualMachine(name), &vm,
+		)
+		if err != nil {
+			c.logLaunchError(name, err)
+			launchErr = err
+			// Errors like missing capacity or quota fail every VM of the batch the same way.
+			break
+		}
+		c.trackLaunch(name)
+		launched = append(launched, c.newInstance(vm))
+	}
+	if len(launched) > 0 {
+		c.syslog.Infof("launched %d/%d Azure VMs: %s",
+			len(launched), instanceNum, model.FmtInstances(launched))
+	}
+	return launchErr
+}
+
+func (c *azureCluster) Terminate(instanceIDs []string) {
+	if len(instanceIDs) == 0 {
+		return
+	}
+	c.deleteVMs(instanceIDs)
+}
+
+func (c *azureCluster) deleteVMs(names []string) {
+	if len(names) == 0 {
+		return
+	}
+	ctx := context.Background()
+	var deleted []*model.Instance
+	for _, name := range names {
+		c.untrackLaunch(name)
+		// Deleting a VM is a long-running operation; its progress is visible in the provisioning
+		// state of the VM, so it is not polled.
+		err := c.client.do(ctx, http.MethodDelete, c.vmsPath()+"/"+name, nil, nil, nil)
+		if err != nil && !isNotFound(err) {
+			c.syslog.WithError(err).Errorf("cannot delete Azure VM: %s", name)
+			continue
+		}
+		deleted = append(deleted, &model.Instance{ID: name})
+	}
+	c.syslog.Infof(
+		"deleting %d/%d Azure VMs: %s", len(deleted), len(names), model.FmtInstances(deleted),
+	)
+}
+
+func (c *azureCluster) generateVMName() string {
+	return c.config.NamePrefix + petname.Generate(2, "-") + "-" + uuid.New().String()[:8]
+}
+
+func (c *azureCluster) newVirtualMachine(name string) virtualMachine {
+	tags := map[string]string{}
+	for k, v := range c.config.CustomTags {
+		tags[k] = v
+	}
+	tags[c.config.TagKey] = c.config.TagValue
+	tags[resourcePoolTagKey] = c.resourcePool
+	tags["determined-master-host"] = c.masterURL.Hostname()
+	tags["determined-master-port"] = c.masterURL.Port()
+
+	image := &imageReference{ID: c.config.ImageID}
+	if c.config.Image != nil {
+		image = &imageReference{
+			Publisher: c.config.Image.Publisher,
+			Offer:     c.config.Image.Offer,
+			SKU:       c.config.Image.SKU,
+			Version:   c.config.Image.Version,
+		}
+	}
+
+	ipConfig := ipConfiguration{Name: name + "-ip"}
+	ipConfig.Properties.Primary = true
+	ipConfig.Properties.Subnet = &subResource{ID: c.config.SubnetID}
+	if c.config.PublicIP {
+		ipConfig.Properties.PublicIPConfiguration = &publicIPConfiguration{
+			Name: name + "-pip",
+		}
+		ipConfig.Properties.PublicIPConfiguration.Properties.DeleteOption = deleteOption
+	}
+	nic := nicConfiguration{Name: name + "-nic"}
+	nic.Properties.Primary = true
+	nic.Properties.DeleteOption = deleteOption
+	nic.Properties.IPConfigurations = []ipConfiguration{ipConfig}
+	if c.config.NetworkSecurityGroupID != "" {
+		nic.Properties.NetworkSecurityGroup = &subResource{ID: c.config.NetworkSecurityGroupID}
+	}
+
+	vm := virtualMachine{
+		Location: c.config.Location,
+		Tags:     tags,
+		Properties: virtualMachineProperties{
+			HardwareProfile: &hardwareProfile{VMSize: c.config.VMSize.Name()},
+			StorageProfile: &storageProfile{
+				ImageReference: image,
+				OSDisk: &osDisk{
+					CreateOption: "FromImage",
+					DiskSizeGB:   c.config.OSDiskSize,
+					ManagedDisk:  &managedDisk{StorageAccountType: c.config.OSDiskType},
+					DeleteOption: deleteOption,
+				},
+			},
+			OSProfile: &osProfile{
+				ComputerName:  name,
+				AdminUsername: c.config.AdminUsername,
+				CustomData:    c.customData,
+				LinuxConfiguration: &linuxConfiguration{
+					DisablePasswordAuthentication: true,
+					SSH: sshConfiguration{PublicKeys: []sshPublicKey{{
+						Path:    fmt.Sprintf("/home/%s/.ssh/authorized_keys", c.config.AdminUsername),
+						KeyData: c.config.SSHPublicKey,
+					}}},
+				},
+			},
+			NetworkProfile: &networkProfile{
+				NetworkAPIVersion: networkAPIVersion,
+				NICConfigurations: []nicConfiguration{nic},
+			},
+		},
+	}
+
+	if c.config.SpotEnabled {
+		vm.Properties.Priority = spotPriority
+		// Evicted spot VMs are deleted rather than deallocated, so that they don't linger
+		// in the resource group and the provisioner launches replacements for them.
+		vm.Properties.EvictionPolicy = "Delete"
+		// A max price of -1 caps the price of a spot VM at the price of a regular VM.
+		maxPrice := -1.0
+		if c.config.SpotMaxPrice != provconfig.SpotPriceNotSetPlaceholder {
+			if p, err := strconv.ParseFloat(c.config.SpotMaxPrice, 64); err == nil {
+				maxPrice = p
+			}
+		}
+		vm.Properties.BillingProfile = &billingProfile{MaxPrice: maxPrice}
+	}
+	return vm
+}
+
+// Azure error codes of VM creations that fail because of the spot price, capacity or quotas,
+// which the user may need to address by changing the configuration of the resource pool or
+// requesting quota. See
+// https://learn.microsoft.com/en-us/azure/virtual-machines/troubleshooting-allocation-failures.
+var azureErrorsRequiringIntervention = map[string]bool{
+	"AllocationFailed":                      true,
+	"OverconstrainedAllocationRequest":      true,
+	"OverconstrainedZonalAllocationRequest": true,
+	"ZonalAllocationFailed":                 true,
+	"SkuNotAvailable":                       true,
+	"OperationNotAllowed":                   true,
+	"QuotaExceeded":                         true,
+	"SpotMaxPriceIsLowerThanCurrentPrice":   true,
+}
+
+func (c *azureCluster) logLaunchError(name string, err error) {
+	log := c.syslog.WithError(err).WithField("vm-spot", c.config.SpotEnabled)
+	var armErr *armError
+	if errors.As(err, &armErr) && azureErrorsRequiringIntervention[armErr.Code] {
+		log.WithField("vm-provisioning-error-code", armErr.Code).
+			Errorf("Azure VM %s cannot be created and may require user intervention", name)
+		return
+	}
+	log.Errorf("cannot create Azure VM %s", name)
+}
+
+func (c *azureCluster) newInstance(vm virtualMachine) *model.Instance {
+	launchTime := time.Now()
+	if vm.Properties.TimeCreated != nil {
+		launchTime = *vm.Properties.TimeCreated
+	}
+	return &model.Instance{
+		ID:         vm.Name,
+		LaunchTime: launchTime,
+		AgentName:  vm.Name,
+		State:      vm.state(),
+	}
+}
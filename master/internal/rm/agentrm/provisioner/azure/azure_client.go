@@ -0,0 +1,191 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
+)
+
+const (
+	computeAPIVersion = "2024-03-01"
+	// networkAPIVersion is the API version used by Azure to create the network interfaces of a VM
+	// from its network interface configurations.
+	networkAPIVersion = "2020-11-01"
+
+	// imdsTokenURL is the endpoint of the Azure Instance Metadata Service that issues tokens for
+	// the managed identity of the VM the master runs on.
+	imdsTokenURL = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+	requestTimeout = time.Minute
+	// tokenRefreshMargin is how long before its expiry a token is refreshed.
+	tokenRefreshMargin = 5 * time.Minute
+)
+
+// armError is an error returned by the Azure Resource Manager API.
+type armError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *armError) Error() string {
+	return fmt.Sprintf("azure api error (%d %s): %s", e.StatusCode, e.Code, e.Message)
+}
+
+func isNotFound(err error) bool {
+	var armErr *armError
+	return errors.As(err, &armErr) && armErr.StatusCode == http.StatusNotFound
+}
+
+// armClient is a minimal client of the Azure Resource Manager REST API that authenticates as a
+// service principal or as the managed identity of the master's VM.
+type armClient struct {
+	config *provconfig.AzureClusterConfig
+	http   *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func newARMClient(config *provconfig.AzureClusterConfig) *armClient {
+	return &armClient{
+		config: config,
+		http:   &http.Client{Timeout: requestTimeout},
+	}
+}
+
+type tokenResponse struct {
+	AccessToken string      `json:"access_token"`
+	ExpiresIn   json.Number `json:"expires_in"`
+}
+
+func (c *armClient) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Now().Add(tokenRefreshMargin).Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	var req *http.Request
+	var err error
+	resource := strings.TrimSuffix(c.config.ManagementEndpoint, "/") + "/"
+	if c.config.ClientSecret != "" {
+		form := url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {c.config.ClientID},
+			"client_secret": {c.config.ClientSecret},
+			"scope":         {resource + ".default"},
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s/oauth2/v2.0/token",
+			strings.TrimSuffix(c.config.ActiveDirectoryEndpoint, "/"), c.config.TenantID,
+		), strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		query := url.Values{"api-version": {"2018-02-01"}, "resource": {resource}}
+		req, err = http.NewRequestWithContext(
+			ctx, http.MethodGet, imdsTokenURL+"?"+query.Encode(), nil,
+		)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Metadata", "true")
+	}
+
+	var token tokenResponse
+	if err := c.send(req, &token); err != nil {
+		return "", errors.Wrap(err, "cannot acquire azure access token")
+	}
+	expiresIn, err := token.ExpiresIn.Int64()
+	if err != nil {
+		return "", errors.Wrap(err, "cannot parse azure access token expiry")
+	}
+	c.token = token.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	return c.token, nil
+}
+
+// do calls the API on the given resource path, e.g.,
+// /subscriptions/<id>/resourceGroups/<name>/providers/Microsoft.Compute/virtualMachines, or on
+// an absolute URL returned by the API, e.g., the next link of a list.
+func (c *armClient) do(
+	ctx context.Context, method, path string, query url.Values, body, out interface{},
+) error {
+	target := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		if query == nil {
+			query = url.Values{}
+		}
+		query.Set("api-version", computeAPIVersion)
+		target = strings.TrimSuffix(c.config.ManagementEndpoint, "/") + path + "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return c.send(req, out)
+}
+
+func (c *armClient) send(req *http.Request, out interface{}) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		armErr := &armError{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(data, &errResp); err == nil && errResp.Error.Code != "" {
+			armErr.Code, armErr.Message = errResp.Error.Code, errResp.Error.Message
+		} else {
+			armErr.Code, armErr.Message = resp.Status, strings.TrimSpace(string(data))
+		}
+		return armErr
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
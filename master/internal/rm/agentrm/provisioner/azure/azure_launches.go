@@ -0,0 +1,58 @@
+package azure
+
+import (
+	"time"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// pendingLaunchTimeout is how long a created VM is reported as starting while it is not visible
+// in the list of VMs.
+const pendingLaunchTimeout = 5 * time.Minute
+
+// Like spot requests in AWS, a VM created by a successful PUT request may not be visible in the
+// list of VMs of the resource group right away. If the provisioner relied solely on the list, it
+// would think it needs to create additional VMs, leading to overprovisioning. The VMs we have
+// created are therefore tracked until they are listed and, in the meantime, reported as starting
+// instances. The tracking is dropped if a VM is still not visible after pendingLaunchTimeout, in
+// which case its creation has failed or it has been deleted.
+
+func (c *azureCluster) trackLaunch(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.launches[name] = time.Now()
+}
+
+func (c *azureCluster) untrackLaunch(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.launches, name)
+}
+
+// listPendingLaunches returns the tracked VMs that are not in the listed VMs and stops tracking
+// the VMs that are listed or that timed out.
+func (c *azureCluster) listPendingLaunches(listed []virtualMachine) []*model.Instance {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, vm := range listed {
+		delete(c.launches, vm.Name)
+	}
+
+	var pending []*model.Instance
+	for name, launchTime := range c.launches {
+		if time.Since(launchTime) > pendingLaunchTimeout {
+			c.syslog.Warnf("Azure VM %s was created %s ago but is not visible; no longer tracking it",
+				name, time.Since(launchTime).Round(time.Second))
+			delete(c.launches, name)
+			continue
+		}
+		pending = append(pending, &model.Instance{
+			ID:         name,
+			LaunchTime: launchTime,
+			AgentName:  name,
+			State:      model.Starting,
+		})
+	}
+	return pending
+}
@@ -0,0 +1,257 @@
+package azure
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const testVMsPath = "/subscriptions/sub/resourceGroups/rg/providers/" +
+	"Microsoft.Compute/virtualMachines"
+
+// fakeCompute is a fake Azure compute endpoint that keeps VMs in memory.
+type fakeCompute struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	tokens   int
+	vms      map[string]virtualMachine
+	hidden   map[string]bool
+	deleted  []string
+	createFn func(vm virtualMachine) (int, string)
+}
+
+func (f *fakeCompute) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/tenant/oauth2/v2.0/token" {
+		require.NoError(f.t, r.ParseForm())
+		require.Equal(f.t, "secret", r.PostForm.Get("client_secret"))
+		f.tokens++
+		require.NoError(f.t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "token", "expires_in": 3599,
+		}))
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	require.Equal(f.t, computeAPIVersion, r.URL.Query().Get("api-version"))
+
+	switch name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, testVMsPath), "/"); {
+	case r.Method == http.MethodGet && name == "":
+		// Serve the VMs over two pages.
+		var page virtualMachineList
+		for _, vm := range f.vms {
+			if !f.hidden[vm.Name] {
+				page.Value = append(page.Value, vm)
+			}
+		}
+		sort.Slice(page.Value, func(i, j int) bool {
+			return page.Value[i].Name < page.Value[j].Name
+		})
+		if r.URL.Query().Get("page") == "" {
+			require.Equal(f.t, "instanceView", r.URL.Query().Get("$expand"))
+			if len(page.Value) > 1 {
+				page.Value = page.Value[:1]
+				page.NextLink = "http://" + r.Host + testVMsPath + "?api-version=" +
+					computeAPIVersion + "&page=2"
+			}
+		} else {
+			page.Value = page.Value[1:]
+		}
+		require.NoError(f.t, json.NewEncoder(w).Encode(page))
+	case r.Method == http.MethodPut:
+		var vm virtualMachine
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&vm))
+		if f.createFn != nil {
+			if status, code := f.createFn(vm); status != 0 {
+				w.WriteHeader(status)
+				require.NoError(f.t, json.NewEncoder(w).Encode(map[string]interface{}{
+					"error": map[string]string{"code": code, "message": "no capacity"},
+				}))
+				return
+			}
+		}
+		created := time.Now().UTC()
+		vm.Name = name
+		vm.Properties.ProvisioningState = "Creating"
+		vm.Properties.TimeCreated = &created
+		f.vms[name] = vm
+		w.WriteHeader(http.StatusCreated)
+		require.NoError(f.t, json.NewEncoder(w).Encode(vm))
+	case r.Method == http.MethodDelete:
+		f.deleted = append(f.deleted, name)
+		if _, ok := f.vms[name]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.vms, name)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	}
+}
+
+func (f *fakeCompute) setState(name, provisioningState string, statuses ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	vm := f.vms[name]
+	vm.Properties.ProvisioningState = provisioningState
+	vm.Properties.InstanceView = &instanceView{}
+	for _, code := range statuses {
+		vm.Properties.InstanceView.Statuses = append(vm.Properties.InstanceView.Statuses,
+			instanceViewStatus{Code: code})
+	}
+	f.vms[name] = vm
+}
+
+func newTestCluster(t *testing.T) (*azureCluster, *fakeCompute, func()) {
+	fake := &fakeCompute{
+		t:      t,
+		vms:    make(map[string]virtualMachine),
+		hidden: make(map[string]bool),
+	}
+	server := httptest.NewServer(fake)
+
+	config := provconfig.DefaultAzureClusterConfig()
+	config.SubscriptionID = "sub"
+	config.ResourceGroup = "rg"
+	config.Location = "eastus"
+	config.TenantID, config.ClientID, config.ClientSecret = "tenant", "client", "secret"
+	config.ManagementEndpoint = server.URL
+	config.ActiveDirectoryEndpoint = server.URL
+	config.ImageID = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/images/det"
+	config.SSHPublicKey = "ssh-rsa AAAA"
+	config.SubnetID = "/subscriptions/sub/resourceGroups/rg/subnets/agents"
+	config.CustomTags = map[string]string{"team": "ml"}
+	config.SpotEnabled = true
+	config.SpotMaxPrice = "1.5"
+
+	return &azureCluster{
+		config:       config,
+		resourcePool: "gpu",
+		customData:   "c2NyaXB0",
+		client:       newARMClient(config),
+		syslog:       logrus.WithField("azure-cluster", "gpu"),
+		launches:     make(map[string]time.Time),
+	}, fake, server.Close
+}
+
+func TestAzureCluster(t *testing.T) {
+	c, fake, closeServer := newTestCluster(t)
+	defer closeServer()
+
+	require.NoError(t, c.Launch(2))
+	require.Len(t, fake.vms, 2)
+	require.Equal(t, 1, fake.tokens)
+	var names []string
+	for name, vm := range fake.vms {
+		names = append(names, name)
+		require.True(t, strings.HasPrefix(name, "det-agent-"))
+		require.Equal(t, "eastus", vm.Location)
+		require.Equal(t, map[string]string{
+			"team":                     "ml",
+			"managed-by":               "determined-ai-determined",
+			"determined-resource-pool": "gpu",
+			"determined-master-host":   "",
+			"determined-master-port":   "",
+		}, vm.Tags)
+		props := vm.Properties
+		require.Equal(t, "Standard_NC24ads_A100_v4", props.HardwareProfile.VMSize)
+		require.Equal(t, c.config.ImageID, props.StorageProfile.ImageReference.ID)
+		require.Equal(t, name, props.OSProfile.ComputerName)
+		require.Equal(t, "c2NyaXB0", props.OSProfile.CustomData)
+		nic := props.NetworkProfile.NICConfigurations[0]
+		require.Equal(t, c.config.SubnetID, nic.Properties.IPConfigurations[0].Properties.Subnet.ID)
+		require.Equal(t, "Spot", props.Priority)
+		require.Equal(t, "Delete", props.EvictionPolicy)
+		require.Equal(t, 1.5, props.BillingProfile.MaxPrice)
+	}
+
+	// A VM of another resource pool is ignored, and created VMs that aren't visible yet are
+	// reported as starting.
+	fake.vms["other"] = virtualMachine{Name: "other", Tags: map[string]string{
+		"managed-by": "determined-ai-determined", "determined-resource-pool": "cpu",
+	}}
+	fake.hidden[names[1]] = true
+	fake.setState(names[0], "Succeeded", "ProvisioningState/succeeded", "PowerState/running")
+	instances, err := c.List()
+	require.NoError(t, err)
+	states := make(map[string]model.InstanceState)
+	for _, inst := range instances {
+		require.Equal(t, inst.ID, inst.AgentName)
+		states[inst.ID] = inst.State
+	}
+	require.Equal(t, map[string]model.InstanceState{
+		names[0]: model.Running,
+		names[1]: model.Starting,
+	}, states)
+	require.Len(t, c.launches, 1)
+
+	// A VM whose provisioning failed is deleted.
+	delete(fake.hidden, names[1])
+	fake.setState(names[1], "Failed", "ProvisioningState/failed/AllocationFailed")
+	instances, err = c.List()
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+	require.Empty(t, c.launches)
+	require.Equal(t, []string{names[1]}, fake.deleted)
+	for _, inst := range instances {
+		if inst.ID == names[1] {
+			require.Equal(t, model.Terminating, inst.State)
+		}
+	}
+
+	// Deleting a VM that is already gone isn't an error.
+	c.Terminate([]string{names[0], "gone"})
+	require.Equal(t, []string{names[1], names[0], "gone"}, fake.deleted)
+	instances, err = c.List()
+	require.NoError(t, err)
+	require.Empty(t, instances)
+
+	fake.createFn = func(vm virtualMachine) (int, string) {
+		return http.StatusConflict, "SkuNotAvailable"
+	}
+	err = c.Launch(3)
+	require.ErrorContains(t, err, "SkuNotAvailable")
+	require.Len(t, fake.vms, 1)
+	require.Equal(t, 1, fake.tokens)
+}
+
+func TestAzureVMState(t *testing.T) {
+	for _, tc := range []struct {
+		provisioningState string
+		statuses          []string
+		expected          model.InstanceState
+	}{
+		{"Creating", nil, model.Starting},
+		{"Deleting", []string{"PowerState/running"}, model.Terminating},
+		{"Succeeded", []string{"ProvisioningState/succeeded", "PowerState/deallocated"}, model.Stopped},
+		{"Updating", []string{"PowerState/stopping"}, model.Stopping},
+		{"Succeeded", nil, model.Unknown},
+	} {
+		vm := virtualMachine{Properties: virtualMachineProperties{
+			ProvisioningState: tc.provisioningState,
+			InstanceView:      &instanceView{},
+		}}
+		for _, code := range tc.statuses {
+			vm.Properties.InstanceView.Statuses = append(vm.Properties.InstanceView.Statuses,
+				instanceViewStatus{Code: code})
+		}
+		require.Equal(t, tc.expected, vm.state(), tc)
+	}
+}
@@ -0,0 +1,187 @@
+package azure
+
+import (
+	"strings"
+	"time"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// The subset of the Microsoft.Compute/virtualMachines resource used by the provider. See
+// https://learn.microsoft.com/en-us/rest/api/compute/virtual-machines.
+
+const (
+	spotPriority = "Spot"
+	deleteOption = "Delete"
+)
+
+type virtualMachineList struct {
+	Value    []virtualMachine `json:"value"`
+	NextLink string           `json:"nextLink"`
+}
+
+type virtualMachine struct {
+	ID         string                   `json:"id,omitempty"`
+	Name       string                   `json:"name,omitempty"`
+	Location   string                   `json:"location"`
+	Tags       map[string]string        `json:"tags,omitempty"`
+	Properties virtualMachineProperties `json:"properties"`
+}
+
+type virtualMachineProperties struct {
+	HardwareProfile *hardwareProfile `json:"hardwareProfile,omitempty"`
+	StorageProfile  *storageProfile  `json:"storageProfile,omitempty"`
+	OSProfile       *osProfile       `json:"osProfile,omitempty"`
+	NetworkProfile  *networkProfile  `json:"networkProfile,omitempty"`
+
+	Priority       string          `json:"priority,omitempty"`
+	EvictionPolicy string          `json:"evictionPolicy,omitempty"`
+	BillingProfile *billingProfile `json:"billingProfile,omitempty"`
+
+	ProvisioningState string        `json:"provisioningState,omitempty"`
+	TimeCreated       *time.Time    `json:"timeCreated,omitempty"`
+	InstanceView      *instanceView `json:"instanceView,omitempty"`
+}
+
+type hardwareProfile struct {
+	VMSize string `json:"vmSize"`
+}
+
+type storageProfile struct {
+	ImageReference *imageReference `json:"imageReference,omitempty"`
+	OSDisk         *osDisk         `json:"osDisk,omitempty"`
+}
+
+type imageReference struct {
+	ID        string `json:"id,omitempty"`
+	Publisher string `json:"publisher,omitempty"`
+	Offer     string `json:"offer,omitempty"`
+	SKU       string `json:"sku,omitempty"`
+	Version   string `json:"version,omitempty"`
+}
+
+type osDisk struct {
+	CreateOption string       `json:"createOption"`
+	DiskSizeGB   int          `json:"diskSizeGB,omitempty"`
+	ManagedDisk  *managedDisk `json:"managedDisk,omitempty"`
+	DeleteOption string       `json:"deleteOption,omitempty"`
+}
+
+type managedDisk struct {
+	StorageAccountType string `json:"storageAccountType,omitempty"`
+}
+
+type osProfile struct {
+	ComputerName       string              `json:"computerName"`
+	AdminUsername      string              `json:"adminUsername"`
+	CustomData         string              `json:"customData,omitempty"`
+	LinuxConfiguration *linuxConfiguration `json:"linuxConfiguration,omitempty"`
+}
+
+type linuxConfiguration struct {
+	DisablePasswordAuthentication bool             `json:"disablePasswordAuthentication"`
+	SSH                           sshConfiguration `json:"ssh"`
+}
+
+type sshConfiguration struct {
+	PublicKeys []sshPublicKey `json:"publicKeys"`
+}
+
+type sshPublicKey struct {
+	Path    string `json:"path"`
+	KeyData string `json:"keyData"`
+}
+
+type networkProfile struct {
+	NetworkAPIVersion string             `json:"networkApiVersion"`
+	NICConfigurations []nicConfiguration `json:"networkInterfaceConfigurations"`
+}
+
+type nicConfiguration struct {
+	Name       string `json:"name"`
+	Properties struct {
+		Primary              bool              `json:"primary"`
+		DeleteOption         string            `json:"deleteOption,omitempty"`
+		NetworkSecurityGroup *subResource      `json:"networkSecurityGroup,omitempty"`
+		IPConfigurations     []ipConfiguration `json:"ipConfigurations"`
+	} `json:"properties"`
+}
+
+type ipConfiguration struct {
+	Name       string `json:"name"`
+	Properties struct {
+		Primary               bool                   `json:"primary"`
+		Subnet                *subResource           `json:"subnet,omitempty"`
+		PublicIPConfiguration *publicIPConfiguration `json:"publicIPAddressConfiguration,omitempty"`
+	} `json:"properties"`
+}
+
+type publicIPConfiguration struct {
+	Name       string `json:"name"`
+	Properties struct {
+		DeleteOption string `json:"deleteOption,omitempty"`
+	} `json:"properties"`
+}
+
+type subResource struct {
+	ID string `json:"id"`
+}
+
+type billingProfile struct {
+	MaxPrice float64 `json:"maxPrice"`
+}
+
+type instanceView struct {
+	Statuses []instanceViewStatus `json:"statuses"`
+}
+
+type instanceViewStatus struct {
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// See https://learn.microsoft.com/en-us/azure/virtual-machines/states-billing.
+var azurePowerStates = map[string]model.InstanceState{
+	"starting":     model.Starting,
+	"running":      model.Running,
+	"stopping":     model.Stopping,
+	"stopped":      model.Stopped,
+	"deallocating": model.Stopping,
+	"deallocated":  model.Stopped,
+}
+
+func (vm virtualMachine) state() model.InstanceState {
+	switch strings.ToLower(vm.Properties.ProvisioningState) {
+	case "creating":
+		return model.Starting
+	case "deleting":
+		return model.Terminating
+	}
+	if vm.Properties.InstanceView != nil {
+		for _, status := range vm.Properties.InstanceView.Statuses {
+			if power, ok := strings.CutPrefix(status.Code, "PowerState/"); ok {
+				if state, ok := azurePowerStates[power]; ok {
+					return state
+				}
+			}
+		}
+	}
+	return model.Unknown
+}
+
+func (vm virtualMachine) provisioningFailed() bool {
+	return strings.EqualFold(vm.Properties.ProvisioningState, "Failed")
+}
+
+// provisioningError returns the code and message of the failed provisioning state of the VM,
+// e.g., ProvisioningState/failed/AllocationFailed.
+func (vm virtualMachine) provisioningError() (string, string) {
+	if vm.Properties.InstanceView != nil {
+		for _, status := range vm.Properties.InstanceView.Statuses {
+			if code, ok := strings.CutPrefix(status.Code, "ProvisioningState/failed/"); ok {
+				return code, status.Message
+			}
+		}
+	}
+	return "", ""
+}
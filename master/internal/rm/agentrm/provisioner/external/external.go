@@ -0,0 +1,268 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
+	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/agentsetup"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Actions of the external provider contract.
+const (
+	actionList      = "list"
+	actionLaunch    = "launch"
+	actionTerminate = "terminate"
+)
+
+// listRequest is the request of the list action.
+type listRequest struct {
+	ResourcePool string `json:"resource_pool"`
+}
+
+// listResponse is the response of the list action.
+type listResponse struct {
+	Instances []instance `json:"instances"`
+}
+
+// instance is an instance reported by the list action. Its agent name must be the name the
+// agent on the instance connects to the master with, which is the instance's hostname for agents
+// started by the agent setup script.
+type instance struct {
+	ID         string              `json:"id"`
+	AgentName  string              `json:"agent_name"`
+	State      model.InstanceState `json:"state"`
+	LaunchTime time.Time           `json:"launch_time"`
+}
+
+// launchRequest is the request of the launch action.
+type launchRequest struct {
+	ResourcePool     string `json:"resource_pool"`
+	Count            int    `json:"count"`
+	InstanceType     string `json:"instance_type"`
+	SlotsPerInstance int    `json:"slots_per_instance"`
+	MasterURL        string `json:"master_url"`
+	// AgentSetupScript is the base64-encoded script that installs and starts the agent.
+	AgentSetupScript string `json:"agent_setup_script"`
+}
+
+// terminateRequest is the request of the terminate action.
+type terminateRequest struct {
+	ResourcePool string   `json:"resource_pool"`
+	InstanceIDs  []string `json:"instance_ids"`
+}
+
+// externalCluster launches and terminates instances by invoking an operator-supplied executable
+// or HTTP endpoint.
+type externalCluster struct {
+	config       *provconfig.ExternalClusterConfig
+	resourcePool string
+	masterURL    string
+	setupScript  string
+
+	client *http.Client
+	syslog *logrus.Entry
+}
+
+// New creates a new external cluster.
+func New(
+	resourcePool string, config *provconfig.Config, cert *tls.Certificate,
+) (agentsetup.Provider, error) {
+	masterURL, err := url.Parse(config.MasterURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse master url")
+	}
+
+	var certBytes []byte
+	if masterURL.Scheme == agentsetup.SecureScheme && cert != nil {
+		for _, c := range cert.Certificate {
+			certBytes = append(certBytes, pem.EncodeToMemory(&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: c,
+			})...)
+		}
+	}
+
+	setupScript := agentsetup.MustMakeAgentSetupScript(agentsetup.AgentSetupScriptConfig{
+		MasterHost:             masterURL.Hostname(),
+		MasterPort:             masterURL.Port(),
+		MasterCertName:         config.MasterCertName,
+		SlotType:               config.External.SlotType(),
+		AgentNetwork:           config.AgentDockerNetwork,
+		AgentDockerRuntime:     config.AgentDockerRuntime,
+		AgentDockerImage:       config.AgentDockerImage,
+		AgentReconnectAttempts: config.AgentReconnectAttempts,
+		AgentReconnectBackoff:  config.AgentReconnectBackoff,
+		StartupScriptBase64: base64.StdEncoding.EncodeToString(
+			[]byte(config.StartupScript),
+		),
+		ContainerStartupScriptBase64: base64.StdEncoding.EncodeToString(
+			[]byte(config.ContainerStartupScript),
+		),
+		MasterCertBase64: base64.StdEncoding.EncodeToString(certBytes),
+		AgentID:          "$(hostname)",
+		ResourcePool:     resourcePool,
+	})
+
+	return &externalCluster{
+		config:       config.External,
+		resourcePool: resourcePool,
+		masterURL:    config.MasterURL,
+		setupScript:  base64.StdEncoding.EncodeToString(setupScript),
+		client:       &http.Client{Timeout: time.Duration(config.External.Timeout)},
+		syslog:       logrus.WithField("external-cluster", resourcePool),
+	}, nil
+}
+
+func (c *externalCluster) InstanceType() model.InstanceType {
+	return c.config.InstanceType
+}
+
+func (c *externalCluster) SlotsPerInstance() int {
+	return c.config.SlotsPerInstance()
+}
+
+func (c *externalCluster) List() ([]*model.Instance, error) {
+	var resp listResponse
+	if err := c.call(actionList, listRequest{ResourcePool: c.resourcePool}, &resp); err != nil {
+		return nil, errors.Wrap(err, "cannot list external instances")
+	}
+
+	instances := make([]*model.Instance, 0, len(resp.Instances))
+	for _, inst := range resp.Instances {
+		state := inst.State
+		switch state {
+		case model.Starting, model.Running, model.Stopping, model.Stopped, model.Terminating:
+		default:
+			c.syslog.Errorf("unknown instance state for instance %v: %v", inst.ID, inst.State)
+			state = model.Unknown
+		}
+		instances = append(instances, &model.Instance{
+			ID:         inst.ID,
+			LaunchTime: inst.LaunchTime,
+			AgentName:  inst.AgentName,
+			State:      state,
+		})
+	}
+	return instances, nil
+}
+
+func (c *externalCluster) Launch(instanceNum int) error {
+	if instanceNum <= 0 {
+		return nil
+	}
+	err := c.call(actionLaunch, launchRequest{
+		ResourcePool:     c.resourcePool,
+		Count:            instanceNum,
+		InstanceType:     c.config.InstanceType.Name(),
+		SlotsPerInstance: c.SlotsPerInstance(),
+		MasterURL:        c.masterURL,
+		AgentSetupScript: c.setupScript,
+	}, nil)
+	if err != nil {
+		c.syslog.WithError(err).Error("cannot launch external instances")
+		return err
+	}
+	c.syslog.Infof("launched %d external instances", instanceNum)
+	return nil
+}
+
+func (c *externalCluster) Terminate(instanceIDs []string) {
+	if len(instanceIDs) == 0 {
+		return
+	}
+	err := c.call(actionTerminate, terminateRequest{
+		ResourcePool: c.resourcePool,
+		InstanceIDs:  instanceIDs,
+	}, nil)
+	if err != nil {
+		c.syslog.WithError(err).Errorf("cannot terminate external instances: %v", instanceIDs)
+		return
+	}
+	c.syslog.Infof("terminated external instances: %v", instanceIDs)
+}
+
+// call runs an action of the contract and decodes its response into resp, if it isn't nil.
+func (c *externalCluster) call(action string, req interface{}, resp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	var out []byte
+	if len(c.config.Command) > 0 {
+		out, err = c.runCommand(action, body)
+	} else {
+		out, err = c.post(action, body)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "%s action failed", action)
+	}
+
+	if resp == nil {
+		return nil
+	}
+	if err := json.Unmarshal(out, resp); err != nil {
+		return errors.Wrapf(err, "cannot decode response of %s action", action)
+	}
+	return nil
+}
+
+func (c *externalCluster) runCommand(action string, body []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.config.Timeout))
+	defer cancel()
+
+	args := append(append([]string{}, c.config.Command[1:]...), action)
+	//nolint:gosec // The command comes from the master configuration.
+	cmd := exec.CommandContext(ctx, c.config.Command[0], args...)
+	cmd.Stdin = bytes.NewReader(body)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+func (c *externalCluster) post(action string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(
+		http.MethodPost, strings.TrimSuffix(c.config.URL, "/")+"/"+action, bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range c.config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
@@ -0,0 +1,114 @@
+package external
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func newTestCluster(config *provconfig.ExternalClusterConfig) *externalCluster {
+	config.Timeout = model.Duration(10 * time.Second)
+	return &externalCluster{
+		config:       config,
+		resourcePool: "on-prem",
+		masterURL:    "http://master:8080",
+		setupScript:  "c2NyaXB0",
+		client:       &http.Client{},
+		syslog:       logrus.WithField("external-cluster", "on-prem"),
+	}
+}
+
+func TestExternalClusterHTTP(t *testing.T) {
+	launchTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	requests := make(map[string]map[string]interface{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests[r.URL.Path] = body
+		switch r.URL.Path {
+		case "/list":
+			require.NoError(t, json.NewEncoder(w).Encode(listResponse{Instances: []instance{
+				{ID: "vm-1", AgentName: "host-1", State: model.Running, LaunchTime: launchTime},
+				{ID: "vm-2", AgentName: "host-2", State: "Rebooting", LaunchTime: launchTime},
+			}}))
+		case "/terminate":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestCluster(&provconfig.ExternalClusterConfig{
+		URL:          server.URL + "/",
+		Headers:      map[string]string{"Authorization": "Bearer token"},
+		InstanceType: provconfig.ExternalInstanceType{InstanceName: "gpu.large", InstanceSlots: 4},
+	})
+
+	instances, err := c.List()
+	require.NoError(t, err)
+	require.Equal(t, []*model.Instance{
+		{ID: "vm-1", AgentName: "host-1", State: model.Running, LaunchTime: launchTime},
+		{ID: "vm-2", AgentName: "host-2", State: model.Unknown, LaunchTime: launchTime},
+	}, instances)
+	require.Equal(t, map[string]interface{}{"resource_pool": "on-prem"}, requests["/list"])
+
+	require.NoError(t, c.Launch(2))
+	require.Equal(t, map[string]interface{}{
+		"resource_pool":      "on-prem",
+		"count":              float64(2),
+		"instance_type":      "gpu.large",
+		"slots_per_instance": float64(4),
+		"master_url":         "http://master:8080",
+		"agent_setup_script": "c2NyaXB0",
+	}, requests["/launch"])
+
+	c.Terminate([]string{"vm-1"})
+	require.Equal(t, []interface{}{"vm-1"}, requests["/terminate"]["instance_ids"])
+
+	c.config.Headers = nil
+	_, err = c.List()
+	require.ErrorContains(t, err, "401")
+}
+
+func TestExternalClusterCommand(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "provider.sh")
+	// The script records each request and answers list with one instance.
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+cat > "$1/$2.json"
+case "$2" in
+list) echo '{"instances": [{"id": "vm-1", "agent_name": "host-1", "state": "Starting"}]}' ;;
+terminate) echo "no such instance" >&2; exit 3 ;;
+esac
+`), 0o700)) //nolint:gosec // The script must be executable.
+
+	c := newTestCluster(&provconfig.ExternalClusterConfig{Command: []string{script, dir}})
+
+	instances, err := c.List()
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	require.Equal(t, model.Starting, instances[0].State)
+
+	require.NoError(t, c.Launch(3))
+	launch, err := os.ReadFile(filepath.Join(dir, "launch.json")) //nolint:gosec // Test file.
+	require.NoError(t, err)
+	var req launchRequest
+	require.NoError(t, json.Unmarshal(launch, &req))
+	require.Equal(t, 3, req.Count)
+
+	err = c.call(actionTerminate, terminateRequest{InstanceIDs: []string{"vm-9"}}, nil)
+	require.ErrorContains(t, err, "no such instance")
+}
@@ -134,6 +134,17 @@ func (c *gcpCluster) SlotsPerInstance() int {
 	return c.config.SlotsPerInstance()
 }
 
+func (c *gcpCluster) InstanceTypes() []agentsetup.InstanceTypeOption {
+	var options []agentsetup.InstanceTypeOption
+	for _, instanceType := range c.config.InstanceTypes() {
+		options = append(options, agentsetup.InstanceTypeOption{
+			Name:  instanceType.Name(),
+			Slots: c.config.SlotsPerInstanceType(instanceType),
+		})
+	}
+	return options
+}
+
 func (c *gcpCluster) idFromInstance(inst *compute.Instance) string {
 	return fmt.Sprintf("%v", inst.Name)
 }
@@ -205,13 +216,27 @@ func (c *gcpCluster) List() ([]*model.Instance, error) {
 }
 
 func (c *gcpCluster) Launch(instanceNum int) error {
+	return c.launch(c.config, instanceNum)
+}
+
+func (c *gcpCluster) LaunchInstanceType(name string, instanceNum int) error {
+	config, err := c.config.WithInstanceType(name)
+	if err != nil {
+		return err
+	}
+	return c.launch(config, instanceNum)
+}
+
+// launch launches instances with the instance properties of the given configuration, which
+// differs from the configuration of the cluster only in its instance type.
+func (c *gcpCluster) launch(config *provconfig.GCPClusterConfig, instanceNum int) error {
 	if instanceNum <= 0 {
 		return nil
 	}
 	clientCtx := context.Background()
 	bulk := &compute.BulkInsertInstanceResource{
 		Count:              int64(instanceNum),
-		InstanceProperties: c.clusterInstanceProperties(),
+		InstanceProperties: c.clusterInstanceProperties(config),
 		MinCount:           1,
 		NamePattern:        c.generateInstanceNamePattern(),
 	}
@@ -228,8 +253,10 @@ func (c *gcpCluster) Launch(instanceNum int) error {
 	return nil
 }
 
-func (c *gcpCluster) clusterInstanceProperties() *compute.InstanceProperties {
-	rb := c.config.InstanceProperties()
+func (c *gcpCluster) clusterInstanceProperties(
+	config *provconfig.GCPClusterConfig,
+) *compute.InstanceProperties {
+	rb := config.InstanceProperties()
 	if rb.Labels == nil {
 		rb.Labels = make(map[string]string)
 	}
@@ -14,6 +14,8 @@ import (
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/agentsetup"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/aws"
+	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/azure"
+	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/external"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/gcp"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/scaledecider"
 	"github.com/determined-ai/determined/master/internal/sproto"
@@ -40,6 +42,8 @@ const (
 //     2.1 It terminates instances if they stay idle for more than `maxIdleAgentPeriod` time.
 //     2.2 It checks recently launched instances and avoids provisioning more than needed.
 //  3. The instance providers take actions to launch/terminate instances.
+//     3.1 Providers that launch more than one instance type launch the most preferred instance
+//     type that fits the pending tasks and fall back to the next ones when launching fails.
 //  4. The rate limiter ensures telemetry does not get sent more frequently than every 90sec.
 type Provisioner struct {
 	mu sync.Mutex
@@ -47,7 +51,10 @@ type Provisioner struct {
 	provider         agentsetup.Provider
 	scaleDecider     *scaledecider.ScaleDecider
 	telemetryLimiter *rate.Limiter
-	launchErr        *errInfo.StickyError
+	// instanceTypes are the instance types the provider launches, in order of preference, and
+	// launchErrs are the errors launching each of them.
+	instanceTypes []agentsetup.InstanceTypeOption
+	launchErrs    map[string]*errInfo.StickyError
 
 	syslog *logrus.Entry
 }
@@ -71,6 +78,16 @@ func New(
 		if cluster, err = gcp.New(resourcePool, config, cert); err != nil {
 			return nil, errors.Wrap(err, "cannot create a GCP cluster")
 		}
+	case config.Azure != nil:
+		var err error
+		if cluster, err = azure.New(resourcePool, config, cert); err != nil {
+			return nil, errors.Wrap(err, "cannot create an Azure cluster")
+		}
+	case config.External != nil:
+		var err error
+		if cluster, err = external.New(resourcePool, config, cert); err != nil {
+			return nil, errors.Wrap(err, "cannot create an external cluster")
+		}
 	}
 
 	var launchErrorTimeout time.Duration
@@ -78,6 +95,7 @@ func New(
 		launchErrorTimeout = time.Duration(*config.LaunchErrorTimeout)
 	}
 
+	instanceTypes := providerInstanceTypes(cluster)
 	return &Provisioner{
 		provider: cluster,
 		scaleDecider: scaledecider.New(
@@ -87,10 +105,15 @@ func New(
 			maxDisconnectPeriod,
 			config.MinInstances,
 			config.MaxInstances,
+			config.WarmPoolInstances,
+			config.ScalingSchedules,
 			db,
 		),
 		telemetryLimiter: rate.NewLimiter(rate.Every(telemetryCooldown), 1),
-		launchErr:        errInfo.NewStickyError(launchErrorTimeout, config.LaunchErrorRetries),
+		instanceTypes:    instanceTypes,
+		launchErrs: newLaunchErrors(
+			instanceTypes, launchErrorTimeout, config.LaunchErrorRetries,
+		),
 
 		syslog: logrus.WithField("component", "provisioner").
 			WithField("resource-pool", resourcePool),
@@ -114,6 +137,14 @@ func (p *Provisioner) UpdateScalingInfo(info *sproto.ScalingInfo) {
 	p.scaleDecider.UpdateScalingInfo(info)
 }
 
+// ScalingTargets returns the scaling targets the provisioner currently scales to.
+func (p *Provisioner) ScalingTargets() provconfig.ScalingTargets {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.scaleDecider.ScalingTargets()
+}
+
 // SlotsPerInstance returns the number of Slots per instance the provisioner launches.
 func (p *Provisioner) SlotsPerInstance() int {
 	p.mu.Lock()
@@ -135,6 +166,14 @@ func (p *Provisioner) CurrentSlotCount() (int, error) {
 	return p.provider.SlotsPerInstance() * len(nodes), nil
 }
 
+// InstanceTypes returns the instance types the provisioner launches, in order of preference.
+func (p *Provisioner) InstanceTypes() []agentsetup.InstanceTypeOption {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.instanceTypes
+}
+
 // InstanceType returns the instance type of the provider for the provisioner.
 func (p *Provisioner) InstanceType() string {
 	p.mu.Lock()
@@ -178,12 +217,8 @@ func (p *Provisioner) Provision() {
 		}
 	}
 
-	if numToLaunch := p.scaleDecider.CalculateNumInstancesToLaunch(); numToLaunch > 0 {
-		p.syslog.Infof("decided to launch %d instances (type %s)",
-			numToLaunch, p.provider.InstanceType().Name())
-		if err := p.launch(numToLaunch); err != nil {
-			p.syslog.WithError(err).Error("failure launching instances")
-		}
+	if err := p.launch(); err != nil {
+		p.syslog.WithError(err).Error("failure launching instances")
 	}
 
 	if p.telemetryLimiter.Allow() {
@@ -191,17 +226,82 @@ func (p *Provisioner) Provision() {
 	}
 }
 
-func (p *Provisioner) launch(numToLaunch int) error {
-	if err := p.launchErr.Error(); err != nil {
-		return err
+// launch launches instances of the most preferred instance type that fits all pending tasks, or,
+// if none does, of the most preferred instance type that fits some. Instance types whose launch
+// errors are sticky are skipped and, if launching fails, the next instance type is launched.
+func (p *Provisioner) launch() error {
+	var fitting, others []agentsetup.InstanceTypeOption
+	numsToLaunch := make(map[string]int, len(p.instanceTypes))
+	for _, instanceType := range p.instanceTypes {
+		numToLaunch, fitsAllTasks := p.scaleDecider.CalculateNumInstancesOfTypeToLaunch(
+			instanceType.Name)
+		if numToLaunch <= 0 {
+			continue
+		}
+		numsToLaunch[instanceType.Name] = numToLaunch
+		if fitsAllTasks {
+			fitting = append(fitting, instanceType)
+		} else {
+			others = append(others, instanceType)
+		}
+	}
+
+	var stickyErr error
+	for _, instanceType := range append(fitting, others...) {
+		launchErr := p.launchErrs[instanceType.Name]
+		if stickyErr = launchErr.Error(); stickyErr != nil {
+			continue
+		}
+		numToLaunch := numsToLaunch[instanceType.Name]
+		p.syslog.Infof("decided to launch %d instances (type %s)", numToLaunch, instanceType.Name)
+		err := p.launchInstanceType(instanceType.Name, numToLaunch)
+		if stickyErr = launchErr.SetError(err); err == nil {
+			return nil
+		}
+		p.syslog.WithError(err).Warnf("cannot launch instances (type %s)", instanceType.Name)
+	}
+	return stickyErr
+}
+
+func (p *Provisioner) launchInstanceType(name string, numToLaunch int) error {
+	if provider, ok := p.provider.(agentsetup.MultiInstanceTypeProvider); ok {
+		return provider.LaunchInstanceType(name, numToLaunch)
 	}
-	return p.launchErr.SetError(p.provider.Launch(numToLaunch))
+	return p.provider.Launch(numToLaunch)
 }
 
-// LaunchError returns the current launch error sent from the provider.
+// LaunchError returns the current launch error sent from the provider. While any instance type
+// can be launched, there is no launch error.
 func (p *Provisioner) LaunchError() error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	return p.launchErr.Error()
+	var err error
+	for _, instanceType := range p.instanceTypes {
+		if err = p.launchErrs[instanceType.Name].Error(); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// providerInstanceTypes returns the instance types the provider launches, in order of preference.
+func providerInstanceTypes(provider agentsetup.Provider) []agentsetup.InstanceTypeOption {
+	if provider, ok := provider.(agentsetup.MultiInstanceTypeProvider); ok {
+		return provider.InstanceTypes()
+	}
+	return []agentsetup.InstanceTypeOption{{
+		Name:  provider.InstanceType().Name(),
+		Slots: provider.SlotsPerInstance(),
+	}}
+}
+
+func newLaunchErrors(
+	instanceTypes []agentsetup.InstanceTypeOption, timeout time.Duration, maxRetries int,
+) map[string]*errInfo.StickyError {
+	launchErrs := make(map[string]*errInfo.StickyError, len(instanceTypes))
+	for _, instanceType := range instanceTypes {
+		launchErrs[instanceType.Name] = errInfo.NewStickyError(timeout, maxRetries)
+	}
+	return launchErrs
 }
@@ -12,9 +12,9 @@ import (
 	"gotest.tools/assert"
 
 	. "github.com/determined-ai/determined/master/internal/config/provconfig"
+	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/agentsetup"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/scaledecider"
 	"github.com/determined-ai/determined/master/internal/sproto"
-	errInfo "github.com/determined-ai/determined/master/pkg/errors"
 	"github.com/determined-ai/determined/master/pkg/model"
 )
 
@@ -46,6 +46,10 @@ type mockConfig struct {
 	initInstances       []*model.Instance
 	failProvisioning    bool
 	numPerProvision     int
+	// fallbackInstanceTypes make the mock provider launch more than one instance type, failing to
+	// launch those in failingInstanceTypes.
+	fallbackInstanceTypes []model.InstanceType
+	failingInstanceTypes  map[string]bool
 }
 
 type mockEnvironment struct {
@@ -60,8 +64,19 @@ func newMockEnvironment(t *testing.T, setup *mockConfig) (*mockEnvironment, *Pro
 	if setup.LaunchErrorTimeout != nil {
 		launchErrorTimeout = time.Duration(*setup.LaunchErrorTimeout)
 	}
+	var provider agentsetup.Provider = cluster
+	if len(setup.fallbackInstanceTypes) > 0 {
+		provider = &mockMultiProvider{
+			mockProvider: cluster,
+			instanceTypes: append(
+				[]model.InstanceType{setup.instanceType}, setup.fallbackInstanceTypes...,
+			),
+			failing: setup.failingInstanceTypes,
+		}
+	}
+	instanceTypes := providerInstanceTypes(provider)
 	p := &Provisioner{
-		provider: cluster,
+		provider: provider,
 		scaleDecider: scaledecider.New(
 			"default",
 			time.Duration(setup.MaxIdleAgentPeriod),
@@ -69,11 +84,16 @@ func newMockEnvironment(t *testing.T, setup *mockConfig) (*mockEnvironment, *Pro
 			setup.maxDisconnectPeriod,
 			setup.MinInstances,
 			setup.MaxInstances,
+			setup.WarmPoolInstances,
+			setup.ScalingSchedules,
 			nil,
 		),
 		telemetryLimiter: rate.NewLimiter(rate.Every(telemetryCooldown), 1),
-		launchErr:        errInfo.NewStickyError(launchErrorTimeout, setup.LaunchErrorRetries),
-		syslog:           logrus.WithField("test-provisioner", "default"),
+		instanceTypes:    instanceTypes,
+		launchErrs: newLaunchErrors(
+			instanceTypes, launchErrorTimeout, setup.LaunchErrorRetries,
+		),
+		syslog: logrus.WithField("test-provisioner", "default"),
 	}
 	go p.Run()
 
@@ -147,12 +167,12 @@ func (c *mockProvider) Launch(instanceNum int) error {
 	case c.numPerProvision > 0:
 		return c.launchOne(c.numPerProvision)
 	default:
-		return c.launchSuccess(instanceNum)
+		return c.launchSuccess(c.mockInstanceType, instanceNum)
 	}
 }
 
-func (c *mockProvider) launchSuccess(instanceNum int) error {
-	c.history = append(c.history, newMockFuncCall("launch", c.mockInstanceType, instanceNum))
+func (c *mockProvider) launchSuccess(instanceType model.InstanceType, instanceNum int) error {
+	c.history = append(c.history, newMockFuncCall("launch", instanceType, instanceNum))
 	for i := 0; i < instanceNum; i++ {
 		name := uuid.New().String()
 		inst := model.Instance{
@@ -193,6 +213,38 @@ func (c *mockProvider) Terminate(instanceIDs []string) {
 	}
 }
 
+// mockMultiProvider is a mockProvider that launches instances of more than one instance type.
+type mockMultiProvider struct {
+	*mockProvider
+	instanceTypes []model.InstanceType
+	failing       map[string]bool
+}
+
+func (c *mockMultiProvider) InstanceTypes() []agentsetup.InstanceTypeOption {
+	var options []agentsetup.InstanceTypeOption
+	for _, instanceType := range c.instanceTypes {
+		options = append(options, agentsetup.InstanceTypeOption{
+			Name:  instanceType.Name(),
+			Slots: instanceType.Slots(),
+		})
+	}
+	return options
+}
+
+func (c *mockMultiProvider) LaunchInstanceType(name string, instanceNum int) error {
+	for _, instanceType := range c.instanceTypes {
+		if instanceType.Name() != name {
+			continue
+		}
+		if c.failing[name] {
+			c.history = append(c.history, newMockFuncCall("launchFail", instanceType, instanceNum))
+			return c.launchFail()
+		}
+		return c.launchSuccess(instanceType, instanceNum)
+	}
+	return fmt.Errorf("unknown instance type %s", name)
+}
+
 func TestProvisionerScaleUp(t *testing.T) {
 	setup := &mockConfig{
 		maxDisconnectPeriod: 5 * time.Minute,
@@ -241,6 +293,108 @@ func TestProvisionerScaleUpNotPastMax(t *testing.T) {
 	})
 }
 
+func TestProvisionerScaleUpWarmPool(t *testing.T) {
+	setup := &mockConfig{
+		maxDisconnectPeriod: 5 * time.Minute,
+		instanceType: TestInstanceType{
+			NameString: "test.instanceType",
+			NumSlots:   4,
+		},
+		Config: &Config{
+			MaxAgentStartingPeriod: model.Duration(time.Hour),
+			MaxIdleAgentPeriod:     model.Duration(50 * time.Millisecond),
+			MaxInstances:           100,
+			WarmPoolInstances:      2,
+		},
+		initInstances: []*model.Instance{
+			{
+				ID:         "instance1",
+				LaunchTime: time.Now().Add(-time.Hour),
+				AgentName:  "agent1",
+				State:      model.Running,
+			},
+		},
+	}
+	mock, _ := newMockEnvironment(t, setup)
+
+	// One instance is launched for the pending tasks and another one to have two idle or starting
+	// instances in the warm pool. The long idle instance is kept in the warm pool.
+	mock.provisioner.UpdateScalingInfo(&sproto.ScalingInfo{
+		DesiredNewInstances: 1,
+		Agents: map[string]sproto.AgentSummary{
+			"agent1": {Name: "agent1", IsIdle: true},
+		},
+	})
+	mock.provisioner.Provision()
+	time.Sleep(100 * time.Millisecond)
+	mock.provisioner.Provision()
+	assert.DeepEqual(t, mock.cluster.history, []mockFuncCall{
+		newMockFuncCall("list"),
+		newMockFuncCall("launch", TestInstanceType{
+			NameString: "test.instanceType",
+			NumSlots:   4,
+		}, 2),
+		newMockFuncCall("list"),
+	})
+}
+
+func TestProvisionerScalingSchedule(t *testing.T) {
+	setup := &mockConfig{
+		maxDisconnectPeriod: 5 * time.Minute,
+		instanceType: TestInstanceType{
+			NameString: "test.instanceType",
+			NumSlots:   4,
+		},
+		Config: &Config{
+			MaxAgentStartingPeriod: model.Duration(time.Hour),
+			MaxIdleAgentPeriod:     model.Duration(50 * time.Millisecond),
+			MaxInstances:           100,
+			ScalingSchedules: ScalingSchedules{
+				{
+					Name:         "always",
+					Cron:         "* * * * *",
+					Duration:     model.Duration(time.Hour),
+					MinInstances: 3,
+				},
+				{
+					Name:         "never",
+					Cron:         "0 0 30 2 *",
+					Duration:     model.Duration(time.Hour),
+					MinInstances: 10,
+				},
+			},
+		},
+		initInstances: []*model.Instance{
+			{
+				ID:         "instance1",
+				LaunchTime: time.Now().Add(-time.Hour),
+				AgentName:  "agent1",
+				State:      model.Running,
+			},
+		},
+	}
+	mock, _ := newMockEnvironment(t, setup)
+
+	// Instances are launched up to the minimum of the active schedule, and the long idle instance
+	// is kept.
+	mock.provisioner.UpdateScalingInfo(&sproto.ScalingInfo{
+		Agents: map[string]sproto.AgentSummary{
+			"agent1": {Name: "agent1", IsIdle: true},
+		},
+	})
+	mock.provisioner.Provision()
+	time.Sleep(100 * time.Millisecond)
+	mock.provisioner.Provision()
+	assert.DeepEqual(t, mock.cluster.history, []mockFuncCall{
+		newMockFuncCall("list"),
+		newMockFuncCall("launch", TestInstanceType{
+			NameString: "test.instanceType",
+			NumSlots:   4,
+		}, 2),
+		newMockFuncCall("list"),
+	})
+}
+
 func TestProvisionerScaleDown(t *testing.T) {
 	setup := &mockConfig{
 		maxDisconnectPeriod: 5 * time.Minute,
@@ -470,3 +624,70 @@ func TestProvisionerLaunchOneAtATimeFail(t *testing.T) {
 	}
 	assert.Error(t, provisioner.LaunchError(), "failed to launch", "expected error")
 }
+
+func TestProvisionerFallbackInstanceTypes(t *testing.T) {
+	small := TestInstanceType{NameString: "small", NumSlots: 2}
+	medium := TestInstanceType{NameString: "medium", NumSlots: 4}
+	large := TestInstanceType{NameString: "large", NumSlots: 8}
+	timeout := model.Duration(time.Minute)
+	setup := &mockConfig{
+		maxDisconnectPeriod: 5 * time.Minute,
+		instanceType:        small,
+		Config: &Config{
+			MaxAgentStartingPeriod: model.Duration(time.Hour),
+			MaxInstances:           100,
+			LaunchErrorTimeout:     &timeout,
+		},
+		fallbackInstanceTypes: []model.InstanceType{medium, large},
+		failingInstanceTypes:  map[string]bool{"small": true},
+	}
+	mock, provisioner := newMockEnvironment(t, setup)
+
+	// The smallest instance type fits the tasks but fails to launch, so the next one is launched.
+	mock.provisioner.UpdateScalingInfo(&sproto.ScalingInfo{
+		DesiredNewInstances: 4,
+		InstanceTypeDemands: map[string]sproto.InstanceTypeDemand{
+			"small":  {DesiredNewInstances: 4, FitsAllTasks: true},
+			"medium": {DesiredNewInstances: 2, FitsAllTasks: true},
+			"large":  {DesiredNewInstances: 1, FitsAllTasks: true},
+		},
+	})
+	mock.provisioner.Provision()
+	assert.DeepEqual(t, mock.cluster.history, []mockFuncCall{
+		newMockFuncCall("list"),
+		newMockFuncCall("launchFail", small, 4),
+		newMockFuncCall("launch", medium, 2),
+	})
+	assert.NilError(t, provisioner.LaunchError())
+
+	// Only the largest instance type fits all tasks, e.g., an 8-slot task, so it is preferred.
+	mock.cluster.history = nil
+	mock.cluster.instances = map[string]*model.Instance{}
+	mock.provisioner.UpdateScalingInfo(&sproto.ScalingInfo{
+		DesiredNewInstances: 4,
+		InstanceTypeDemands: map[string]sproto.InstanceTypeDemand{
+			"small":  {DesiredNewInstances: 4},
+			"medium": {DesiredNewInstances: 2},
+			"large":  {DesiredNewInstances: 1, FitsAllTasks: true},
+		},
+	})
+	mock.provisioner.Provision()
+	assert.DeepEqual(t, mock.cluster.history, []mockFuncCall{
+		newMockFuncCall("list"),
+		newMockFuncCall("launch", large, 1),
+	})
+
+	// Instance types with sticky launch errors are skipped, and there is a launch error only once
+	// no instance type can be launched.
+	mock.cluster.history = nil
+	mock.cluster.instances = map[string]*model.Instance{}
+	setup.failingInstanceTypes["medium"] = true
+	setup.failingInstanceTypes["large"] = true
+	mock.provisioner.Provision()
+	assert.DeepEqual(t, mock.cluster.history, []mockFuncCall{
+		newMockFuncCall("list"),
+		newMockFuncCall("launchFail", large, 1),
+		newMockFuncCall("launchFail", medium, 2),
+	})
+	assert.Error(t, provisioner.LaunchError(), "failed to launch")
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/mathx"
@@ -32,11 +33,14 @@ type ScaleDecider struct {
 	maxDisconnectPeriod time.Duration
 	minInstanceNum      int
 	maxInstanceNum      int
+	warmPoolNum         int
+	scalingSchedules    provconfig.ScalingSchedules
 
 	instanceSnapshot       map[string]*model.Instance
 	connectedAgentSnapshot map[string]sproto.AgentSummary
 	idleAgentSnapshot      map[string]sproto.AgentSummary
 	desiredNewInstances    int
+	instanceTypeDemands    map[string]sproto.InstanceTypeDemand
 
 	instances        map[string]*model.Instance
 	pending          map[string]bool
@@ -58,6 +62,8 @@ func New(
 	maxDisconnectPeriod time.Duration,
 	minInstanceNum int,
 	maxInstanceNum int,
+	warmPoolNum int,
+	scalingSchedules provconfig.ScalingSchedules,
 	db db.DB,
 ) *ScaleDecider {
 	return &ScaleDecider{
@@ -66,6 +72,8 @@ func New(
 		maxDisconnectPeriod:    maxDisconnectPeriod,
 		minInstanceNum:         minInstanceNum,
 		maxInstanceNum:         maxInstanceNum,
+		warmPoolNum:            warmPoolNum,
+		scalingSchedules:       scalingSchedules,
 		instanceSnapshot:       make(map[string]*model.Instance),
 		connectedAgentSnapshot: make(map[string]sproto.AgentSummary),
 		idleAgentSnapshot:      make(map[string]sproto.AgentSummary),
@@ -88,6 +96,7 @@ func (s *ScaleDecider) UpdateScalingInfo(info *sproto.ScalingInfo) {
 	defer s.mu.Unlock()
 
 	s.desiredNewInstances = info.DesiredNewInstances
+	s.instanceTypeDemands = info.InstanceTypeDemands
 	s.idleAgentSnapshot = make(map[string]sproto.AgentSummary)
 	s.connectedAgentSnapshot = make(map[string]sproto.AgentSummary, len(info.Agents))
 	for _, agent := range info.Agents {
@@ -275,14 +284,18 @@ func (s *ScaleDecider) FindInstancesToTerminate() sproto.TerminateDecision {
 		delete(s.disconnected, id)
 	}
 
-	// Terminate instances that are idle for a long time.
+	// Terminate instances that are idle for a long time, keeping the minimum number of instances
+	// and of idle instances in the warm pool.
+	targets := s.scalingTargets()
 	for id := range s.longIdle {
-		if len(s.instances)-len(toTerminate) > s.minInstanceNum {
-			toTerminate[id] = sproto.TerminateLongIdleInstances
-			delete(s.idle, id)
-		} else {
+		if len(s.instances)-len(toTerminate) <= targets.MinInstances {
 			break
 		}
+		if _, idle := s.idle[id]; idle && len(s.idle) <= targets.WarmPoolInstances {
+			break
+		}
+		toTerminate[id] = sproto.TerminateLongIdleInstances
+		delete(s.idle, id)
 	}
 
 	// Terminate instances to keep the number of instances less than the desired size.
@@ -337,9 +350,44 @@ func (s *ScaleDecider) CalculateNumInstancesToLaunch() int {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.numInstancesToLaunch(s.desiredNewInstances)
+}
+
+// CalculateNumInstancesOfTypeToLaunch calculates the number of instances of the given instance
+// type to launch and whether instances of the instance type fit all pending tasks.
+func (s *ScaleDecider) CalculateNumInstancesOfTypeToLaunch(instanceType string) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	demand, ok := s.instanceTypeDemands[instanceType]
+	if !ok {
+		return s.numInstancesToLaunch(s.desiredNewInstances), true
+	}
+	return s.numInstancesToLaunch(demand.DesiredNewInstances), demand.FitsAllTasks
+}
+
+func (s *ScaleDecider) numInstancesToLaunch(desiredNewInstances int) int {
+	// Instances that are starting serve both the pending tasks and the warm pool.
+	targets := s.scalingTargets()
+	warmPoolShortage := mathx.Max(0, targets.WarmPoolInstances-len(s.idle))
 	return mathx.Max(0, mathx.Clamp(
-		s.minInstanceNum-len(s.instances),
-		s.desiredNewInstances-len(s.recentlyLaunched),
+		targets.MinInstances-len(s.instances),
+		desiredNewInstances+warmPoolShortage-len(s.recentlyLaunched),
 		s.maxInstanceNum-len(s.instances),
 	))
 }
+
+// ScalingTargets returns the minimum number of instances and of idle instances to keep according
+// to the scaling schedules active now.
+func (s *ScaleDecider) ScalingTargets() provconfig.ScalingTargets {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.scalingTargets()
+}
+
+func (s *ScaleDecider) scalingTargets() provconfig.ScalingTargets {
+	return s.scalingSchedules.TargetsAt(
+		time.Now(), s.minInstanceNum, s.warmPoolNum, s.maxInstanceNum,
+	)
+}
@@ -211,6 +211,16 @@ func TestFindInstancesToTerminate(t *testing.T) {
 			},
 			toTerminate: []string{"long idle"},
 		},
+		{
+			name: "keep long idle in the warm pool",
+			scaleDecider: ScaleDecider{
+				instances:      map[string]*model.Instance{"long idle": {}},
+				idle:           map[string]time.Time{"long idle": time.Now().Add(-time.Hour)},
+				longIdle:       map[string]bool{"long idle": true},
+				maxInstanceNum: 10,
+				warmPoolNum:    1,
+			},
+		},
 		{
 			name: "terminate long disconnected",
 			scaleDecider: ScaleDecider{
@@ -26,6 +26,12 @@ func Setup(
 	if config.GCP != nil {
 		syslog.Info("connecting to GCP")
 	}
+	if config.Azure != nil {
+		syslog.Info("connecting to Azure")
+	}
+	if config.External != nil {
+		syslog.Info("using an external provider")
+	}
 	provisioner, err := New(resourcePool, config, cert, db)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating provisioner")
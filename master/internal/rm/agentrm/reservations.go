@@ -0,0 +1,148 @@
+package agentrm
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/rm/tasklist"
+	"github.com/determined-ai/determined/master/internal/sproto"
+)
+
+const (
+	// reservationRefreshInterval is how often a resource pool reloads its reservations.
+	reservationRefreshInterval = 10 * time.Second
+	// reservationLoadTimeout bounds how long a resource pool waits for its reservations to load.
+	reservationLoadTimeout = 30 * time.Second
+)
+
+// refreshReservations starts reloading the pool's reservations in the background when they are
+// stale and reschedules if the set of active reservations changed, e.g., because a reservation
+// window opened or closed. It must be called with rp.mu held; the scheduler never waits on the
+// database and uses the last loaded reservations until the reload finishes.
+func (rp *resourcePool) refreshReservations() {
+	if rp.db == nil {
+		return
+	}
+
+	now := time.Now()
+	if !rp.reservationsLoading && now.Sub(rp.reservationsRefreshed) >= reservationRefreshInterval {
+		rp.reservationsLoading = true
+		go rp.loadReservations(now)
+	}
+
+	active := activeReservations(rp.reservations, now)
+	if !slices.EqualFunc(active, rp.activeReservations, func(a, b db.RPReservation) bool {
+		return a.ID == b.ID && a.Slots == b.Slots && a.WorkspaceName == b.WorkspaceName
+	}) {
+		rp.activeReservations = active
+		rp.reschedule = true
+	}
+}
+
+// loadReservations loads the pool's unexpired reservations and hands them to the scheduler.
+func (rp *resourcePool) loadReservations(now time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), reservationLoadTimeout)
+	defer cancel()
+	reservations, err := db.GetUnexpiredRPReservations(ctx, rp.config.PoolName, now)
+
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.reservationsLoading = false
+	if err != nil {
+		rp.syslog.WithError(err).Error("failed to load resource pool reservations")
+		return
+	}
+	rp.reservations = reservations
+	rp.reservationsRefreshed = now
+}
+
+func activeReservations(reservations []db.RPReservation, now time.Time) []db.RPReservation {
+	var active []db.RPReservation
+	for _, r := range reservations {
+		if r.Active(now) {
+			active = append(active, r)
+		}
+	}
+	return active
+}
+
+// schedulableTasks returns the task list without the pending tasks held back by slot quotas or
+// by reservations for other workspaces. Pending tasks are admitted in the given order, or in the
+// order of the task list if it is nil.
+func (rp *resourcePool) schedulableTasks(order []*sproto.AllocateRequest) *tasklist.TaskList {
+	return withinReservations(
+		tasklist.WithinQuotas(rp.taskList, rp.config.Quotas, order),
+		rp.activeReservations,
+		rp.agentStatesCache,
+		order,
+	)
+}
+
+// withinReservations returns the task list without the pending tasks that would use slots
+// reserved for other workspaces. Slots a reservation's workspace isn't already using are
+// subtracted from the empty slots of the agents; tasks of the workspace may use them, tasks of
+// other workspaces may only use what is left. Running tasks are never affected.
+func withinReservations(
+	taskList *tasklist.TaskList,
+	reservations []db.RPReservation,
+	agents map[agentID]*agentState,
+	order []*sproto.AllocateRequest,
+) *tasklist.TaskList {
+	if len(reservations) == 0 {
+		return taskList
+	}
+	if order == nil {
+		for it := taskList.Iterator(); it.Next(); {
+			order = append(order, it.Value())
+		}
+	}
+
+	outstanding := make(map[string]int)
+	for _, r := range reservations {
+		outstanding[r.WorkspaceName] += r.Slots
+	}
+	for it := taskList.Iterator(); it.Next(); {
+		req := it.Value()
+		if taskList.IsScheduled(req.AllocationID) && outstanding[req.Workspace] > 0 {
+			outstanding[req.Workspace] = max(0, outstanding[req.Workspace]-req.SlotsNeeded)
+		}
+	}
+	reserved := 0
+	for _, slots := range outstanding {
+		reserved += slots
+	}
+	free := 0
+	for _, agent := range agents {
+		free += agent.numEmptySlots()
+	}
+
+	admitted := make(map[*sproto.AllocateRequest]bool)
+	for _, req := range order {
+		if _, ok := taskList.TaskByID(req.AllocationID); !ok ||
+			taskList.IsScheduled(req.AllocationID) {
+			continue
+		}
+		fromReservation := min(req.SlotsNeeded, outstanding[req.Workspace])
+		if req.SlotsNeeded > 0 && req.SlotsNeeded-fromReservation > free-reserved {
+			continue
+		}
+		admitted[req] = true
+		outstanding[req.Workspace] -= fromReservation
+		reserved -= fromReservation
+		free -= req.SlotsNeeded
+	}
+
+	filtered := tasklist.New()
+	for it := taskList.Iterator(); it.Next(); {
+		req := it.Value()
+		if allocated := taskList.Allocation(req.AllocationID); allocated != nil {
+			filtered.AddTask(req)
+			filtered.AddAllocationRaw(req.AllocationID, allocated)
+		} else if admitted[req] {
+			filtered.AddTask(req)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,63 @@
+package agentrm
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/rm/tasklist"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestWithinReservations(t *testing.T) {
+	now := time.Now()
+	newTask := func(id, workspace string, slots int) *sproto.AllocateRequest {
+		now = now.Add(time.Second)
+		return &sproto.AllocateRequest{
+			AllocationID:      model.AllocationID(id),
+			JobID:             model.JobID(id),
+			JobSubmissionTime: now,
+			RequestTime:       now,
+			Workspace:         workspace,
+			SlotsNeeded:       slots,
+		}
+	}
+
+	tasks := []*sproto.AllocateRequest{
+		newTask("research-running", "research", 2),
+		newTask("prod-fits", "prod", 2),
+		newTask("prod-blocked", "prod", 2),
+		newTask("research-reserved", "research", 3),
+		newTask("research-over-reservation", "research", 2),
+		newTask("prod-zero-slot", "prod", 0),
+	}
+	taskList := tasklist.New()
+	for _, task := range tasks {
+		taskList.AddTask(task)
+	}
+	taskList.AddAllocationRaw(tasks[0].AllocationID, &sproto.ResourcesAllocated{
+		ID: tasks[0].AllocationID,
+	})
+
+	// 6 empty slots; research holds 2 of its 5 reserved slots, so 3 are reserved and only 3 are
+	// left for other workspaces.
+	agents := map[agentID]*agentState{}
+	forceAddAgent(t, agents, "agent1", 2, 0, 0)
+	forceAddAgent(t, agents, "agent2", 4, 0, 0)
+	reservations := []db.RPReservation{{ID: 1, WorkspaceName: "research", Slots: 5}}
+
+	filtered := withinReservations(taskList, reservations, agents, nil)
+	var ids []model.AllocationID
+	for it := filtered.Iterator(); it.Next(); {
+		ids = append(ids, it.Value().AllocationID)
+	}
+	assert.DeepEqual(t, ids, []model.AllocationID{
+		"research-running", "prod-fits", "research-reserved", "prod-zero-slot",
+	})
+	assert.Assert(t, filtered.IsScheduled("research-running"))
+
+	assert.Equal(t, withinReservations(taskList, nil, agents, nil), taskList)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/set"
 	"github.com/determined-ai/determined/proto/pkg/jobv1"
+	"github.com/determined-ai/determined/proto/pkg/resourcepoolv1"
 )
 
 // resourcePool manages the agent and task lifecycles.
@@ -51,6 +52,13 @@ type resourcePool struct {
 	reschedule      bool
 	rescheduleTimer *time.Timer
 
+	// reservations are the pool's unexpired reservations, reloaded periodically from the database
+	// in the background.
+	reservations          []db.RPReservation
+	reservationsRefreshed time.Time
+	reservationsLoading   bool
+	activeReservations    []db.RPReservation
+
 	// Track notifyOnStop for testing purposes.
 	saveNotifications bool
 	notifications     []<-chan struct{}
@@ -215,10 +223,13 @@ func (rp *resourcePool) restoreResources(
 		resources[cr.Summary().ResourcesID] = &cr
 	}
 
+	// The original start time isn't persisted, so a restored allocation is treated as if it just
+	// started, which only makes the backfill scheduler more conservative.
 	allocated := sproto.ResourcesAllocated{
 		ID:           req.AllocationID,
 		ResourcePool: rp.config.PoolName,
 		Resources:    resources,
+		StartTime:    time.Now(),
 		Recovered:    true,
 	}
 
@@ -369,6 +380,7 @@ func (rp *resourcePool) schedulerTick() {
 			}
 		}
 	}
+	rp.refreshReservations()
 	if rp.reschedule {
 		rp.syslog.Trace("scheduling")
 		rp.agentStatesCache = rp.agentService.list(rp.config.PoolName)
@@ -476,6 +488,7 @@ func (rp *resourcePool) allocateResources(req *sproto.AllocateRequest) bool {
 		ResourcePool:      rp.config.PoolName,
 		Resources:         sprotoResources,
 		JobSubmissionTime: req.JobSubmissionTime,
+		StartTime:         time.Now(),
 	}
 	rp.taskList.AddAllocation(req.AllocationID, &allocated)
 	rmevents.Publish(req.AllocationID, allocated.Clone())
@@ -505,15 +518,31 @@ func (rp *resourcePool) sendScalingInfo() {
 }
 
 func (rp *resourcePool) updateScalingInfo() bool {
+	// Tasks held back by slot quotas shouldn't cause the pool to scale up.
+	taskList := tasklist.WithinQuotas(rp.taskList, rp.config.Quotas, nil)
 	desiredInstanceNum := calculateDesiredNewAgentNum(
-		rp.taskList, rp.groups, rp.slotsPerInstance, rp.config.MaxAuxContainersPerAgent,
+		taskList, rp.groups, rp.slotsPerInstance, rp.config.MaxAuxContainersPerAgent,
 	)
+	var instanceTypeDemands map[string]sproto.InstanceTypeDemand
+	if rp.provisioner != nil {
+		if instanceTypes := rp.provisioner.InstanceTypes(); len(instanceTypes) > 1 {
+			instanceTypeDemands = make(map[string]sproto.InstanceTypeDemand, len(instanceTypes))
+			for _, instanceType := range instanceTypes {
+				instanceTypeDemands[instanceType.Name] = sproto.InstanceTypeDemand{
+					DesiredNewInstances: calculateDesiredNewAgentNum(
+						taskList, rp.groups, instanceType.Slots, rp.config.MaxAuxContainersPerAgent,
+					),
+					FitsAllTasks: allTasksFit(taskList, instanceType.Slots),
+				}
+			}
+		}
+	}
 	agents := make(map[string]sproto.AgentSummary)
 	for _, agentState := range rp.agentStatesCache {
 		summary := newAgentSummary(agentState)
 		agents[summary.Name] = summary
 	}
-	return rp.scalingInfo.Update(desiredInstanceNum, agents)
+	return rp.scalingInfo.Update(desiredInstanceNum, instanceTypeDemands, agents)
 }
 
 func (rp *resourcePool) refreshAgentStateCacheFor(agents []*agent) {
@@ -651,6 +680,22 @@ func (rp *resourcePool) CapacityCheck(msg sproto.CapacityCheck) (sproto.Capacity
 	case rp.config.Provider.GCP != nil:
 		totalSlots = rp.config.Provider.MaxInstances * rp.config.Provider.GCP.SlotsPerInstance()
 
+		for id, a := range rp.agentStatesCache {
+			if blockedNodeSet.Contains(string(id)) {
+				totalSlots -= len(a.slotStates)
+			}
+		}
+	case rp.config.Provider.Azure != nil:
+		totalSlots = rp.config.Provider.MaxInstances * rp.config.Provider.Azure.SlotsPerInstance()
+
+		for id, a := range rp.agentStatesCache {
+			if blockedNodeSet.Contains(string(id)) {
+				totalSlots -= len(a.slotStates)
+			}
+		}
+	case rp.config.Provider.External != nil:
+		totalSlots = rp.config.Provider.MaxInstances * rp.config.Provider.External.SlotsPerInstance()
+
 		for id, a := range rp.agentStatesCache {
 			if blockedNodeSet.Contains(string(id)) {
 				totalSlots -= len(a.slotStates)
@@ -786,7 +831,20 @@ func (rp *resourcePool) RecoverJobPosition(msg sproto.RecoverJobPosition) {
 func (rp *resourcePool) GetJobQStats(msg sproto.GetJobQStats) *jobv1.QueueStats {
 	rp.mu.Lock()
 	defer rp.mu.Unlock()
-	return tasklist.JobStats(rp.taskList)
+	stats := tasklist.JobStats(rp.taskList)
+	stats.SlotQuotas = tasklist.NewSlotQuotas(rp.config.Quotas, rp.taskList).Usage()
+	return stats
+}
+
+// summarizeScaling fills in the scaling targets the provisioner of the pool currently scales to.
+func (rp *resourcePool) summarizeScaling(summary *resourcepoolv1.ResourcePool) {
+	if rp.provisioner == nil {
+		return
+	}
+	targets := rp.provisioner.ScalingTargets()
+	summary.MinAgents = int32(targets.MinInstances)
+	summary.WarmPoolAgents = int32(targets.WarmPoolInstances)
+	summary.ActiveScalingSchedules = targets.ActiveSchedules
 }
 
 func (rp *resourcePool) GetJobQ(msg sproto.GetJobQ) map[model.JobID]*sproto.RMJobInfo {
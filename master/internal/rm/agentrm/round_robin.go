@@ -21,7 +21,7 @@ func (p *roundRobinScheduler) Schedule(rp *resourcePool) (
 	[]model.AllocationID,
 ) {
 	return roundRobinSchedule(
-		rp.taskList,
+		rp.schedulableTasks(nil),
 		rp.groups,
 		rp.agentStatesCache,
 		rp.fittingMethod,
@@ -70,3 +70,20 @@ func calculateDesiredNewAgentNum(
 	}
 	return mathx.Max(numAgentByZeroSlot, numAgentBySlot)
 }
+
+// allTasksFit returns whether every pending task fits on agents with the given number of slots,
+// either on one agent or spread evenly over several agents.
+func allTasksFit(taskList *tasklist.TaskList, slotsPerAgent int) bool {
+	for it := taskList.Iterator(); it.Next(); {
+		slotsNeeded := it.Value().SlotsNeeded
+		switch {
+		case taskList.IsScheduled(it.Value().AllocationID), slotsNeeded == 0:
+			continue
+		case slotsPerAgent == 0:
+			return false
+		case slotsNeeded > slotsPerAgent && slotsNeeded%slotsPerAgent != 0:
+			return false
+		}
+	}
+	return true
+}
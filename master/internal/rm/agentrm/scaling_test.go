@@ -139,3 +139,18 @@ func TestCalculatingDesiredInstanceNum(t *testing.T) {
 	assert.Equal(t, calculateDesiredNewAgentNum(taskList, nil, 1, 2), 9)
 	assert.Equal(t, calculateDesiredNewAgentNum(taskList, nil, 2, 2), 3)
 }
+
+func TestAllTasksFit(t *testing.T) {
+	taskList := tasklist.New()
+	forceAddTask(t, taskList, "scheduled", 1, 16)
+	forceAddTask(t, taskList, "zero-slot", 0, 0)
+	assert.Equal(t, allTasksFit(taskList, 0), true)
+
+	forceAddTask(t, taskList, "task1", 0, 2)
+	forceAddTask(t, taskList, "task2", 0, 6)
+	assert.Equal(t, allTasksFit(taskList, 0), false)
+	assert.Equal(t, allTasksFit(taskList, 1), true)
+	assert.Equal(t, allTasksFit(taskList, 4), false)
+	assert.Equal(t, allTasksFit(taskList, 6), true)
+	assert.Equal(t, allTasksFit(taskList, 8), true)
+}
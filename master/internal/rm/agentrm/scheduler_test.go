@@ -535,6 +535,7 @@ func setupSchedulerStates(
 						devices:     devices,
 					},
 				},
+				StartTime: mockTask.StartTime,
 			}
 			taskList.AddAllocation(req.AllocationID, allocated)
 		}
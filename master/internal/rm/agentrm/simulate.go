@@ -0,0 +1,405 @@
+package agentrm
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/rm/tasklist"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	// maxSimulatedSchedulingRounds bounds how many times the scheduler is run at a single point
+	// in simulated time, in case a scheduler keeps preempting and rescheduling the same tasks.
+	maxSimulatedSchedulingRounds = 100
+	// simulatedMaxAuxContainersPerAgent matches the resource pool default.
+	simulatedMaxAuxContainersPerAgent = 100
+
+	// MaxSimulationJobs bounds the number of tasks in a simulated job trace. Simulations run in
+	// the master, so this and the other bounds keep them from using too much of it.
+	MaxSimulationJobs = 10000
+	// MaxSimulationAgents bounds the number of simulated agents.
+	MaxSimulationAgents = 1000
+	// MaxSimulationSlotsPerAgent bounds the number of slots of a simulated agent.
+	MaxSimulationSlotsPerAgent = 1024
+	// MaxSimulationTraceWindow bounds the time range of allocations replayed from the database.
+	MaxSimulationTraceWindow = 90 * 24 * time.Hour
+)
+
+// SimulationJob is a task in a job trace replayed by Simulate.
+type SimulationJob struct {
+	ID string `json:"id"`
+	// JobID groups the tasks of a job, e.g., the trials of an experiment. It defaults to ID.
+	JobID      string         `json:"job_id,omitempty"`
+	SubmitTime time.Time      `json:"submit_time"`
+	Slots      int            `json:"slots"`
+	Duration   model.Duration `json:"duration"`
+	// MaxRuntime is the declared max runtime of the task. As with allocations, tasks are stopped
+	// once they exceed it: preemptible tasks are requeued and others end.
+	MaxRuntime *model.Duration `json:"max_runtime,omitempty"`
+	// Priority defaults to the default priority of the priority scheduler.
+	Priority *int `json:"priority,omitempty"`
+	// Weight is the fair share weight of the job. It defaults to 1.
+	Weight float64 `json:"weight,omitempty"`
+	// Preemptible defaults to true, as for trials.
+	Preemptible *bool  `json:"preemptible,omitempty"`
+	User        string `json:"user,omitempty"`
+	Workspace   string `json:"workspace,omitempty"`
+}
+
+// SimulationAgent describes identical agents of the simulated resource pool.
+type SimulationAgent struct {
+	Slots int `json:"slots"`
+	// Count is the number of such agents. It defaults to 1.
+	Count int `json:"count,omitempty"`
+}
+
+// SimulationJobResult is the outcome of a task in a simulation.
+type SimulationJobResult struct {
+	ID         string         `json:"id"`
+	SubmitTime time.Time      `json:"submit_time"`
+	StartTime  *time.Time     `json:"start_time"`
+	EndTime    *time.Time     `json:"end_time"`
+	Wait       model.Duration `json:"wait"`
+	// Preemptions is the number of times the task was preempted; it is assumed to resume from
+	// where it was preempted.
+	Preemptions int `json:"preemptions"`
+}
+
+// Simulation holds the results of replaying a job trace against a scheduler configuration.
+type Simulation struct {
+	Jobs []SimulationJobResult `json:"jobs"`
+	// Unschedulable lists the tasks that never started, e.g., because they need more slots than
+	// fit on the simulated agents or were queued behind such a task.
+	Unschedulable []string       `json:"unschedulable"`
+	MeanWait      model.Duration `json:"mean_wait"`
+	MedianWait    model.Duration `json:"median_wait"`
+	P95Wait       model.Duration `json:"p95_wait"`
+	MaxWait       model.Duration `json:"max_wait"`
+	// Makespan is the time from the first submission to the last completion.
+	Makespan model.Duration `json:"makespan"`
+	// Utilization is the fraction of slot time in use during the makespan.
+	Utilization float64 `json:"utilization"`
+}
+
+type simulatedTask struct {
+	req       *sproto.AllocateRequest
+	remaining time.Duration
+	started   time.Time
+	result    *SimulationJobResult
+}
+
+// end returns when the task stops running: when it finishes or, like allocations are terminated,
+// when it exceeds its max runtime.
+func (t *simulatedTask) end() time.Time {
+	if t.req.MaxRuntime != nil && *t.req.MaxRuntime < t.remaining {
+		return t.started.Add(*t.req.MaxRuntime)
+	}
+	return t.started.Add(t.remaining)
+}
+
+// Simulate replays the job trace against the scheduler configuration on the given agents, using
+// the same scheduler implementations as resource pools, and reports queue wait times and
+// utilization. Tasks run for their recorded duration once scheduled, or until they exceed their
+// max runtime.
+func Simulate(
+	conf *config.SchedulerConfig, agents []SimulationAgent, trace []SimulationJob,
+) (Simulation, error) {
+	var simulation Simulation
+	if err := validateSimulation(agents, trace); err != nil {
+		return simulation, err
+	}
+
+	trace = append([]SimulationJob(nil), trace...)
+	sort.SliceStable(trace, func(i, j int) bool {
+		return trace[i].SubmitTime.Before(trace[j].SubmitTime)
+	})
+
+	agentStates := make(map[agentID]*agentState)
+	totalSlots := 0
+	for i, a := range agents {
+		for j := 0; j < max(a.Count, 1); j++ {
+			state := newAgentState(agentID(fmt.Sprintf("agent-%d-%d", i, j)),
+				simulatedMaxAuxContainersPerAgent)
+			for k := 0; k < a.Slots; k++ {
+				state.Devices[device.Device{ID: device.ID(k)}] = nil
+			}
+			agentStates[state.id] = state
+			totalSlots += a.Slots
+		}
+	}
+
+	var now time.Time
+	rp := &resourcePool{
+		syslog:           logrus.WithField("component", "scheduling-simulator"),
+		config:           &config.ResourcePoolConfig{PoolName: "simulation", Scheduler: conf},
+		scheduler:        MakeScheduler(conf),
+		fittingMethod:    MakeFitFunction(conf.FittingPolicy),
+		agentStatesCache: agentStates,
+		taskList:         tasklist.New(),
+		groups:           make(map[model.JobID]*tasklist.Group),
+		queuePositions:   tasklist.InitializeJobSortState(false),
+	}
+	if p, ok := rp.scheduler.(*priorityScheduler); ok {
+		p.clock = func() time.Time { return now }
+	}
+
+	tasks := make(map[model.AllocationID]*simulatedTask)
+	results := make([]SimulationJobResult, len(trace))
+	var busy float64
+	release := func(task *simulatedTask) {
+		allocated := rp.taskList.Allocation(task.req.AllocationID)
+		for _, r := range allocated.Resources {
+			typed := r.(*containerResources)
+			typed.agent.deallocateContainer(typed.containerID)
+		}
+		rp.taskList.RemoveAllocation(task.req.AllocationID)
+		ran := now.Sub(task.started)
+		task.remaining -= ran
+		busy += ran.Seconds() * float64(task.req.SlotsNeeded)
+	}
+
+	next := 0
+	for next < len(trace) || rp.taskList.Len() > 0 {
+		var running []*simulatedTask
+		for it := rp.taskList.Iterator(); it.Next(); {
+			if rp.taskList.IsScheduled(it.Value().AllocationID) {
+				running = append(running, tasks[it.Value().AllocationID])
+			}
+		}
+		if len(running) == 0 && next == len(trace) {
+			// Nothing left can change the state of the pool.
+			for it := rp.taskList.Iterator(); it.Next(); {
+				simulation.Unschedulable = append(simulation.Unschedulable,
+					tasks[it.Value().AllocationID].result.ID)
+			}
+			break
+		}
+
+		// Advance to the next submission or completion.
+		var nextEvent time.Time
+		if next < len(trace) {
+			nextEvent = trace[next].SubmitTime
+		}
+		for _, task := range running {
+			if end := task.end(); nextEvent.IsZero() || end.Before(nextEvent) {
+				nextEvent = end
+			}
+		}
+		now = nextEvent
+
+		for _, task := range running {
+			if task.end().After(now) {
+				continue
+			}
+			release(task)
+			if task.remaining > 0 && task.req.Preemptible {
+				// Preemptible tasks that exceed their max runtime are requeued.
+				task.result.Preemptions++
+				continue
+			}
+			end := now
+			task.result.EndTime = &end
+			rp.taskList.RemoveTaskByID(task.req.AllocationID)
+		}
+
+		for ; next < len(trace) && !trace[next].SubmitTime.After(now); next++ {
+			task := rp.submitSimulatedJob(trace[next], &results[next])
+			tasks[task.req.AllocationID] = task
+		}
+
+		for round := 0; round < maxSimulatedSchedulingRounds; round++ {
+			toAllocate, toRelease := rp.scheduler.Schedule(rp)
+			changed := false
+			for _, id := range toRelease {
+				if task, ok := tasks[id]; ok && rp.taskList.IsScheduled(id) {
+					release(task)
+					task.result.Preemptions++
+					changed = true
+				}
+			}
+			for _, req := range toAllocate {
+				if rp.taskList.IsScheduled(req.AllocationID) || !rp.allocateSimulated(req, now) {
+					continue
+				}
+				task := tasks[req.AllocationID]
+				task.started = now
+				if task.result.StartTime == nil {
+					start := now
+					task.result.StartTime = &start
+					task.result.Wait = model.Duration(now.Sub(task.result.SubmitTime))
+				}
+				changed = true
+			}
+			if !changed {
+				break
+			}
+		}
+	}
+
+	simulation.Jobs = results
+	summarizeSimulation(&simulation, busy, totalSlots)
+	return simulation, nil
+}
+
+func validateSimulation(agents []SimulationAgent, trace []SimulationJob) error {
+	count := 0
+	for _, a := range agents {
+		if a.Slots < 0 || a.Count < 0 {
+			return errors.New("agent slots and count must not be negative")
+		}
+		if a.Slots > MaxSimulationSlotsPerAgent {
+			return errors.Errorf("agents can have at most %d slots", MaxSimulationSlotsPerAgent)
+		}
+		count += max(a.Count, 1)
+	}
+	if count > MaxSimulationAgents {
+		return errors.Errorf("at most %d agents can be simulated", MaxSimulationAgents)
+	}
+	if len(trace) > MaxSimulationJobs {
+		return errors.Errorf("at most %d tasks can be simulated", MaxSimulationJobs)
+	}
+	ids := make(map[string]bool, len(trace))
+	for _, job := range trace {
+		switch {
+		case job.ID == "":
+			return errors.New("every job in the trace needs an id")
+		case ids[job.ID]:
+			return errors.Errorf("duplicate job id %s in the trace", job.ID)
+		case job.Slots < 0:
+			return errors.Errorf("job %s needs a non-negative number of slots", job.ID)
+		case job.Duration < 0:
+			return errors.Errorf("job %s needs a non-negative duration", job.ID)
+		case job.MaxRuntime != nil && *job.MaxRuntime <= 0:
+			return errors.Errorf("job %s needs a positive max runtime", job.ID)
+		}
+		ids[job.ID] = true
+	}
+	return nil
+}
+
+// submitSimulatedJob adds the task of a job to the pool, like Allocate does.
+func (rp *resourcePool) submitSimulatedJob(
+	job SimulationJob, result *SimulationJobResult,
+) *simulatedTask {
+	jobID := model.JobID(job.JobID)
+	if jobID == "" {
+		jobID = model.JobID(job.ID)
+	}
+	req := &sproto.AllocateRequest{
+		AllocationID:      model.AllocationID(job.ID),
+		Name:              job.ID,
+		JobID:             jobID,
+		JobSubmissionTime: job.SubmitTime,
+		RequestTime:       job.SubmitTime,
+		IsUserVisible:     true,
+		SlotsNeeded:       job.Slots,
+		Preemptible:       job.Preemptible == nil || *job.Preemptible,
+		MaxRuntime:        (*time.Duration)(job.MaxRuntime),
+		User:              job.User,
+		Workspace:         job.Workspace,
+	}
+
+	group := rp.getOrCreateSimulatedGroup(jobID)
+	if job.Priority != nil {
+		group.Priority = job.Priority
+	}
+	if job.Weight > 0 {
+		group.Weight = job.Weight
+	}
+	if _, ok := rp.queuePositions[jobID]; !ok {
+		rp.queuePositions[jobID] = tasklist.InitializeQueuePosition(job.SubmitTime, false)
+	}
+	rp.taskList.AddTask(req)
+
+	*result = SimulationJobResult{ID: job.ID, SubmitTime: job.SubmitTime}
+	return &simulatedTask{req: req, remaining: time.Duration(job.Duration), result: result}
+}
+
+// getOrCreateSimulatedGroup is getOrCreateGroup without the hooks into running jobs.
+func (rp *resourcePool) getOrCreateSimulatedGroup(jobID model.JobID) *tasklist.Group {
+	if g, ok := rp.groups[jobID]; ok {
+		return g
+	}
+	g := &tasklist.Group{JobID: jobID, Weight: 1}
+	if rp.config.Scheduler.Priority != nil {
+		g.Priority = rp.config.Scheduler.Priority.DefaultPriority
+	}
+	rp.groups[jobID] = g
+	return g
+}
+
+// allocateSimulated places the task on the simulated agents, like allocateResources does.
+func (rp *resourcePool) allocateSimulated(req *sproto.AllocateRequest, now time.Time) bool {
+	fits := findFits(
+		req, rp.agentStatesCache, rp.fittingMethod, rp.config.Scheduler.AllowHeterogeneousFits,
+	)
+	if len(fits) == 0 {
+		return false
+	}
+
+	resources := sproto.ResourceList{}
+	for _, fit := range fits {
+		containerID := cproto.NewID()
+		devices, err := fit.Agent.allocateFreeDevices(fit.Slots, containerID)
+		if err != nil {
+			rp.syslog.WithError(err).Warnf("failed to allocate simulated task %s", req.Name)
+			return false
+		}
+		resources[sproto.ResourcesID(containerID)] = &containerResources{
+			req:         req,
+			agent:       fit.Agent,
+			containerID: containerID,
+			devices:     devices,
+		}
+	}
+	rp.taskList.AddAllocation(req.AllocationID, &sproto.ResourcesAllocated{
+		ID:                req.AllocationID,
+		ResourcePool:      rp.config.PoolName,
+		Resources:         resources,
+		JobSubmissionTime: req.JobSubmissionTime,
+		StartTime:         now,
+	})
+	return true
+}
+
+func summarizeSimulation(simulation *Simulation, busy float64, totalSlots int) {
+	var waits []time.Duration
+	var first, last time.Time
+	for _, job := range simulation.Jobs {
+		if first.IsZero() || job.SubmitTime.Before(first) {
+			first = job.SubmitTime
+		}
+		if job.EndTime != nil && job.EndTime.After(last) {
+			last = *job.EndTime
+		}
+		if job.StartTime != nil {
+			waits = append(waits, time.Duration(job.Wait))
+		}
+	}
+	if len(waits) > 0 {
+		sort.Slice(waits, func(i, j int) bool { return waits[i] < waits[j] })
+		var total time.Duration
+		for _, w := range waits {
+			total += w
+		}
+		simulation.MeanWait = model.Duration(total / time.Duration(len(waits)))
+		simulation.MedianWait = model.Duration(waits[len(waits)/2])
+		simulation.P95Wait = model.Duration(waits[(len(waits)*95-1)/100])
+		simulation.MaxWait = model.Duration(waits[len(waits)-1])
+	}
+	if last.After(first) {
+		simulation.Makespan = model.Duration(last.Sub(first))
+		if totalSlots > 0 {
+			simulation.Utilization = busy / (last.Sub(first).Seconds() * float64(totalSlots))
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package agentrm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestSimulate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	trace := []SimulationJob{
+		{
+			ID:         "high",
+			SubmitTime: start.Add(10 * time.Minute),
+			Slots:      4,
+			Duration:   model.Duration(30 * time.Minute),
+			Priority:   ptrs.Ptr(1),
+		},
+		{
+			ID:         "low",
+			SubmitTime: start,
+			Slots:      4,
+			Duration:   model.Duration(time.Hour),
+			Priority:   ptrs.Ptr(50),
+		},
+		{
+			ID:         "too-big",
+			SubmitTime: start,
+			Slots:      8,
+			Duration:   model.Duration(time.Hour),
+			Priority:   ptrs.Ptr(99),
+		},
+	}
+	agents := []SimulationAgent{{Slots: 4}}
+	results := func(s Simulation) map[string]SimulationJobResult {
+		byID := make(map[string]SimulationJobResult)
+		for _, job := range s.Jobs {
+			byID[job.ID] = job
+		}
+		return byID
+	}
+
+	priority := func(preemption bool) *config.SchedulerConfig {
+		return &config.SchedulerConfig{
+			Priority: &config.PrioritySchedulerConfig{
+				Preemption:      preemption,
+				DefaultPriority: ptrs.Ptr(config.DefaultSchedulingPriority),
+				Backfill:        config.NoBackfill,
+			},
+			FittingPolicy: "best",
+		}
+	}
+
+	// Without preemption, the high-priority job waits for the low-priority one to finish.
+	s, err := Simulate(priority(false), agents, trace)
+	require.NoError(t, err)
+	jobs := results(s)
+	require.Equal(t, model.Duration(50*time.Minute), jobs["high"].Wait)
+	require.Equal(t, start.Add(90*time.Minute), *jobs["high"].EndTime)
+	require.Equal(t, []string{"too-big"}, s.Unschedulable)
+	require.Nil(t, jobs["too-big"].StartTime)
+	require.Equal(t, model.Duration(90*time.Minute), s.Makespan)
+	require.InDelta(t, 1.0, s.Utilization, 1e-9)
+	require.Equal(t, model.Duration(50*time.Minute), s.MaxWait)
+
+	// With preemption, it starts immediately and the low-priority job resumes afterwards.
+	s, err = Simulate(priority(true), agents, trace)
+	require.NoError(t, err)
+	jobs = results(s)
+	require.Equal(t, model.Duration(0), jobs["high"].Wait)
+	require.Equal(t, start.Add(40*time.Minute), *jobs["high"].EndTime)
+	require.Equal(t, 1, jobs["low"].Preemptions)
+	require.Equal(t, start.Add(90*time.Minute), *jobs["low"].EndTime)
+
+	for _, conf := range []*config.SchedulerConfig{
+		config.DefaultSchedulerConfig(),
+		{RoundRobin: &config.RoundRobinSchedulerConfig{}, FittingPolicy: "best"},
+	} {
+		s, err = Simulate(conf, agents, trace)
+		require.NoError(t, err)
+		jobs = results(s)
+		require.NotNil(t, jobs["low"].EndTime)
+		require.NotNil(t, jobs["high"].EndTime)
+	}
+
+	_, err = Simulate(config.DefaultSchedulerConfig(), agents, append(trace, trace[0]))
+	require.ErrorContains(t, err, "duplicate job id")
+
+	_, err = Simulate(config.DefaultSchedulerConfig(),
+		[]SimulationAgent{{Slots: 4, Count: MaxSimulationAgents + 1}}, trace)
+	require.ErrorContains(t, err, "agents can be simulated")
+	_, err = Simulate(config.DefaultSchedulerConfig(),
+		[]SimulationAgent{{Slots: MaxSimulationSlotsPerAgent + 1}}, trace)
+	require.ErrorContains(t, err, "slots")
+	_, err = Simulate(config.DefaultSchedulerConfig(), agents,
+		make([]SimulationJob, MaxSimulationJobs+1))
+	require.ErrorContains(t, err, "tasks can be simulated")
+}
+
+func TestSimulateBackfillOverrun(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	overrun := func(preemptible bool) []SimulationJob {
+		return []SimulationJob{
+			{
+				ID:         "running",
+				SubmitTime: start,
+				Slots:      2,
+				Duration:   model.Duration(time.Hour),
+				MaxRuntime: ptrs.Ptr(model.Duration(time.Hour)),
+				Priority:   ptrs.Ptr(40),
+			},
+			{
+				ID:         "head",
+				SubmitTime: start.Add(time.Minute),
+				Slots:      4,
+				Duration:   model.Duration(30 * time.Minute),
+				Priority:   ptrs.Ptr(40),
+			},
+			{
+				ID:          "backfilled",
+				SubmitTime:  start.Add(2 * time.Minute),
+				Slots:       2,
+				Duration:    model.Duration(2 * time.Hour),
+				MaxRuntime:  ptrs.Ptr(model.Duration(30 * time.Minute)),
+				Priority:    ptrs.Ptr(50),
+				Preemptible: ptrs.Ptr(preemptible),
+			},
+		}
+	}
+	conf := &config.SchedulerConfig{
+		Priority: &config.PrioritySchedulerConfig{
+			DefaultPriority: ptrs.Ptr(config.DefaultSchedulingPriority),
+			Backfill:        config.EasyBackfill,
+		},
+		FittingPolicy: "best",
+	}
+	results := func(s Simulation) map[string]SimulationJobResult {
+		byID := make(map[string]SimulationJobResult)
+		for _, job := range s.Jobs {
+			byID[job.ID] = job
+		}
+		return byID
+	}
+
+	// The backfilled job runs past its max runtime, so it is stopped then, and the head job still
+	// starts at its reservation.
+	s, err := Simulate(conf, []SimulationAgent{{Slots: 4}}, overrun(false))
+	require.NoError(t, err)
+	jobs := results(s)
+	require.Equal(t, start.Add(2*time.Minute), *jobs["backfilled"].StartTime)
+	require.Equal(t, start.Add(32*time.Minute), *jobs["backfilled"].EndTime)
+	require.Equal(t, start.Add(time.Hour), *jobs["head"].StartTime)
+
+	// Preemptible jobs are requeued instead, and resume once the head job is done.
+	s, err = Simulate(conf, []SimulationAgent{{Slots: 4}}, overrun(true))
+	require.NoError(t, err)
+	jobs = results(s)
+	require.Equal(t, start.Add(time.Hour), *jobs["head"].StartTime)
+	require.Equal(t, 3, jobs["backfilled"].Preemptions)
+	require.Equal(t, start.Add(3*time.Hour), *jobs["backfilled"].EndTime)
+}
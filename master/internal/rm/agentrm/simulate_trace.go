@@ -0,0 +1,94 @@
+package agentrm
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+// SimulationTraceFromDB builds a job trace from the finished allocations of a resource pool that
+// were requested in the given time range. An allocation is assumed to have been requested when
+// its task started or, for later allocations of a task, when the previous one ended. Priorities,
+// max runtimes and workspaces are only known for experiments. At most one more allocation than
+// MaxSimulationJobs is loaded, so traces that are too long fail to simulate.
+func SimulationTraceFromDB(
+	ctx context.Context, resourcePool string, start, end time.Time,
+) ([]SimulationJob, error) {
+	var rows []struct {
+		AllocationID string
+		JobID        *string
+		TaskType     model.TaskType
+		Slots        int
+		SubmitTime   time.Time
+		StartTime    time.Time
+		EndTime      time.Time
+		Priority     *int
+		MaxRuntime   *string
+		Username     *string
+		Workspace    *string
+	}
+	err := db.Bun().NewRaw(`
+SELECT * FROM (
+	SELECT
+		a.allocation_id,
+		t.job_id,
+		t.task_type,
+		a.slots,
+		COALESCE(
+			LAG(a.end_time) OVER (PARTITION BY a.task_id ORDER BY a.start_time), t.start_time
+		) AS submit_time,
+		a.start_time,
+		a.end_time,
+		(e.config->'resources'->>'priority')::int AS priority,
+		e.config->'resources'->>'max_runtime' AS max_runtime,
+		u.username,
+		w.name AS workspace
+	FROM allocations a
+	JOIN tasks t ON t.task_id = a.task_id
+	LEFT JOIN jobs j ON j.job_id = t.job_id
+	LEFT JOIN users u ON u.id = j.owner_id
+	LEFT JOIN experiments e ON e.job_id = t.job_id
+	LEFT JOIN projects p ON p.id = e.project_id
+	LEFT JOIN workspaces w ON w.id = p.workspace_id
+	WHERE a.resource_pool = ? AND a.end_time IS NOT NULL
+) trace
+WHERE submit_time >= ? AND submit_time < ?
+ORDER BY submit_time, allocation_id
+LIMIT ?`, resourcePool, start, end, MaxSimulationJobs+1).Scan(ctx, &rows)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading job trace for resource pool %s", resourcePool)
+	}
+
+	trace := make([]SimulationJob, 0, len(rows))
+	for _, row := range rows {
+		job := SimulationJob{
+			ID:          row.AllocationID,
+			SubmitTime:  row.SubmitTime,
+			Slots:       row.Slots,
+			Duration:    model.Duration(row.EndTime.Sub(row.StartTime)),
+			Priority:    row.Priority,
+			Preemptible: ptrs.Ptr(row.TaskType == model.TaskTypeTrial),
+		}
+		if row.JobID != nil {
+			job.JobID = *row.JobID
+		}
+		if row.MaxRuntime != nil {
+			if d, err := time.ParseDuration(*row.MaxRuntime); err == nil {
+				job.MaxRuntime = ptrs.Ptr(model.Duration(d))
+			}
+		}
+		if row.Username != nil {
+			job.User = *row.Username
+		}
+		if row.Workspace != nil {
+			job.Workspace = *row.Workspace
+		}
+		trace = append(trace, job)
+	}
+	return trace, nil
+}
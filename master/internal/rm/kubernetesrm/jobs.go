@@ -0,0 +1,219 @@
+package kubernetesrm
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	batchV1 "k8s.io/api/batch/v1"
+	k8sV1 "k8s.io/api/core/v1"
+	k8error "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+const (
+	// jobNameLabel is set by the job controller on the pods it creates.
+	jobNameLabel = "job-name"
+	// jobCompletionIndexEnvVar holds the completion index of a pod launched as part of a job.
+	// Kubernetes only expands references to variables defined before the referencing one, and
+	// the job controller appends its own JOB_COMPLETION_INDEX after the variables of the pod.
+	jobCompletionIndexEnvVar = "DET_JOB_COMPLETION_INDEX"
+	// jobTTLSecondsAfterFinished is how long a finished job is kept before it is garbage collected,
+	// in case it outlives the master.
+	jobTTLSecondsAfterFinished = 300
+	// maxJobIndexLength is the length reserved in the name of a job for the completion index that
+	// the job controller appends to the hostnames of its pods.
+	maxJobIndexLength = 6
+	maxDNSLabelLength = 63
+	// serviceRequestTimeout bounds the requests made to create the service of an allocation.
+	serviceRequestTimeout = 30 * time.Second
+)
+
+var invalidDNSLabelChars = regexp.MustCompile(`[^a-z0-9-]`)
+
+// dnsLabel converts s into a valid DNS label of at most maxLength characters.
+func dnsLabel(s string, maxLength int) string {
+	s = invalidDNSLabelChars.ReplaceAllString(strings.ToLower(s), "-")
+	if len(s) > maxLength {
+		s = s[:maxLength]
+	}
+	return strings.TrimRight(s, "-")
+}
+
+// allocationServiceName returns the name of the headless service of an allocation.
+func allocationServiceName(allocationID model.AllocationID) string {
+	return dnsLabel("det-"+string(allocationID), maxDNSLabelLength)
+}
+
+// allocationJobName returns the name of the job that runs the pods of an allocation. It is also
+// the name of the config map the pods share.
+func allocationJobName(allocationID model.AllocationID) string {
+	return dnsLabel("det-"+string(allocationID), maxDNSLabelLength-maxJobIndexLength)
+}
+
+// jobPodName returns the name the handler of the pod with the given completion index is
+// registered under. It matches the hostname the job controller gives the pod.
+func jobPodName(allocationID model.AllocationID, index int) string {
+	return fmt.Sprintf("%s-%d", allocationJobName(allocationID), index)
+}
+
+// jobContainerID returns the ID of the container that runs as the pod with the given completion
+// index. The ranks of an allocation follow the IDs of its resources, so the pod of each rank is
+// the one whose completion index is the rank.
+func jobContainerID(base cproto.ID, index string) cproto.ID {
+	return cproto.ID(fmt.Sprintf("%s-%s", base, index))
+}
+
+// podHandlerName returns the name the handler of a pod is registered under: the name of its job
+// and its completion index for pods launched as jobs, and the name of the pod otherwise.
+func podHandlerName(pod *k8sV1.Pod) string {
+	if isJobPod(pod) {
+		return pod.Labels[jobNameLabel] + "-" + pod.Annotations[batchV1.JobCompletionIndexAnnotation]
+	}
+	return pod.Name
+}
+
+// podConfigMapName returns the name of the config map of a pod, which all the pods of a job share.
+func podConfigMapName(pod *k8sV1.Pod) string {
+	if isJobPod(pod) {
+		return pod.Labels[jobNameLabel]
+	}
+	return pod.Name
+}
+
+// isJobPod returns whether the pod was launched as a job.
+func isJobPod(pod *k8sV1.Pod) bool {
+	_, ok := pod.Labels[jobNameLabel]
+	return ok
+}
+
+// expandJobCompletionIndex replaces references to the completion index of a pod in the value of
+// one of its environment variables, as Kubernetes does when it starts the pod.
+func expandJobCompletionIndex(pod *k8sV1.Pod, value string) string {
+	if !isJobPod(pod) {
+		return value
+	}
+	return strings.ReplaceAll(value, "$("+jobCompletionIndexEnvVar+")",
+		pod.Annotations[batchV1.JobCompletionIndexAnnotation])
+}
+
+// podDNSName returns the stable DNS name of a pod that is part of a headless service, or an
+// empty string if it has none.
+func podDNSName(pod *k8sV1.Pod) string {
+	if pod.Spec.Hostname == "" || pod.Spec.Subdomain == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s.%s.%s.svc", pod.Spec.Hostname, pod.Spec.Subdomain, pod.Namespace)
+}
+
+// allocationService returns the headless service that owns the job of an allocation and
+// resolves the hostnames of its pods.
+func allocationService(namespace string, allocationID model.AllocationID) *k8sV1.Service {
+	return &k8sV1.Service{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      allocationServiceName(allocationID),
+			Namespace: namespace,
+			Labels:    map[string]string{determinedLabel: string(allocationID)},
+		},
+		Spec: k8sV1.ServiceSpec{
+			ClusterIP: k8sV1.ClusterIPNone,
+			Selector:  map[string]string{determinedLabel: string(allocationID)},
+			// Ranks look each other up during rendezvous, before they are ready.
+			PublishNotReadyAddresses: true,
+		},
+	}
+}
+
+// ensureAllocationService creates the headless service of an allocation, or returns the existing
+// one if it was already created.
+func ensureAllocationService(
+	ctx context.Context,
+	services typedV1.ServiceInterface,
+	namespace string,
+	allocationID model.AllocationID,
+) (*k8sV1.Service, error) {
+	service, err := services.Create(
+		ctx, allocationService(namespace, allocationID), metaV1.CreateOptions{})
+	if k8error.IsAlreadyExists(err) {
+		service, err = services.Get(ctx, allocationServiceName(allocationID), metaV1.GetOptions{})
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating service for allocation %s", allocationID)
+	}
+	return service, nil
+}
+
+func serviceOwnerReference(service *k8sV1.Service) metaV1.OwnerReference {
+	return metaV1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "Service",
+		Name:       service.Name,
+		UID:        service.UID,
+	}
+}
+
+// indexedJob wraps the pod of the first rank of an allocation in an indexed job that runs a pod
+// per rank, owned by the service of the allocation. Each pod runs exactly once, since Determined
+// decides whether and where tasks are restarted. The IDs of the containers are the only part of
+// the pods that differ between ranks, so they are derived from the completion index of each pod.
+func indexedJob(
+	allocationID model.AllocationID,
+	pod *k8sV1.Pod,
+	owner *k8sV1.Service,
+	containerID cproto.ID,
+	numPods int,
+) *batchV1.Job {
+	template := k8sV1.PodTemplateSpec{
+		ObjectMeta: metaV1.ObjectMeta{
+			Labels:      pod.Labels,
+			Annotations: pod.Annotations,
+		},
+		Spec: *pod.Spec.DeepCopy(),
+	}
+	template.Spec.Subdomain = owner.Name
+
+	base := containerID[:strings.LastIndex(string(containerID), "-")]
+	indexedID := string(jobContainerID(base, "$("+jobCompletionIndexEnvVar+")"))
+	for i, container := range template.Spec.Containers {
+		env := []k8sV1.EnvVar{{
+			Name: jobCompletionIndexEnvVar,
+			ValueFrom: &k8sV1.EnvVarSource{FieldRef: &k8sV1.ObjectFieldSelector{
+				FieldPath: fmt.Sprintf("metadata.annotations['%s']",
+					batchV1.JobCompletionIndexAnnotation),
+			}},
+		}}
+		for _, e := range container.Env {
+			if e.Name == "DET_CONTAINER_ID" || e.Name == "DET_RESOURCES_ID" {
+				e.Value = indexedID
+			}
+			env = append(env, e)
+		}
+		template.Spec.Containers[i].Env = env
+	}
+
+	completionMode := batchV1.IndexedCompletion
+	return &batchV1.Job{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:            allocationJobName(allocationID),
+			Namespace:       pod.Namespace,
+			Labels:          pod.Labels,
+			OwnerReferences: []metaV1.OwnerReference{serviceOwnerReference(owner)},
+		},
+		Spec: batchV1.JobSpec{
+			Parallelism:             ptrs.Ptr(int32(numPods)),
+			Completions:             ptrs.Ptr(int32(numPods)),
+			CompletionMode:          &completionMode,
+			BackoffLimit:            ptrs.Ptr(int32(0)),
+			TTLSecondsAfterFinished: ptrs.Ptr(int32(jobTTLSecondsAfterFinished)),
+			Template:                template,
+		},
+	}
+}
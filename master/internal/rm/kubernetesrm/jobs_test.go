@@ -0,0 +1,131 @@
+//nolint:exhaustruct
+package kubernetesrm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	batchV1 "k8s.io/api/batch/v1"
+	k8sV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestJobNames(t *testing.T) {
+	require.Equal(t, "det-1-2-3", allocationServiceName("1.2.3"))
+	require.Equal(t, "det-1-2-3", allocationJobName("1.2.3"))
+	require.Equal(t, "det-1-2-3-4", jobPodName("1.2.3", 4))
+	require.Equal(t, cproto.ID("abc-4"), jobContainerID("abc", "4"))
+
+	long := model.AllocationID(strings.Repeat("a", 100))
+	require.Len(t, allocationServiceName(long), maxDNSLabelLength)
+	require.Len(t, allocationJobName(long), maxDNSLabelLength-maxJobIndexLength)
+	require.LessOrEqual(t, len(jobPodName(long, 99999)), maxDNSLabelLength)
+	require.True(t, strings.HasPrefix(jobPodName(long, 12), allocationJobName(long)))
+}
+
+func TestPodHandlerName(t *testing.T) {
+	bare := &k8sV1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "exp-1-trial-1-0-1.1.1-able-cat"}}
+	require.False(t, isJobPod(bare))
+	require.Equal(t, "exp-1-trial-1-0-1.1.1-able-cat", podHandlerName(bare))
+	require.Equal(t, "exp-1-trial-1-0-1.1.1-able-cat", podConfigMapName(bare))
+	require.Equal(t, "abc-$(DET_JOB_COMPLETION_INDEX)",
+		expandJobCompletionIndex(bare, "abc-$(DET_JOB_COMPLETION_INDEX)"))
+
+	job := &k8sV1.Pod{ObjectMeta: metaV1.ObjectMeta{
+		Name:        "det-1-1-1-2-x7k2p",
+		Labels:      map[string]string{jobNameLabel: "det-1-1-1"},
+		Annotations: map[string]string{batchV1.JobCompletionIndexAnnotation: "2"},
+	}}
+	require.True(t, isJobPod(job))
+	require.Equal(t, "det-1-1-1-2", podHandlerName(job))
+	require.Equal(t, "det-1-1-1", podConfigMapName(job))
+	require.Equal(t, "abc-2", expandJobCompletionIndex(job, "abc-$(DET_JOB_COMPLETION_INDEX)"))
+}
+
+func TestIndexedJob(t *testing.T) {
+	ctx := context.Background()
+	services := fake.NewSimpleClientset().CoreV1().Services("default")
+
+	service, err := ensureAllocationService(ctx, services, "default", "1.1.1")
+	require.NoError(t, err)
+	require.Equal(t, k8sV1.ClusterIPNone, service.Spec.ClusterIP)
+	require.Equal(t, map[string]string{determinedLabel: "1.1.1"}, service.Spec.Selector)
+
+	// An existing service is reused.
+	service.UID = types.UID("uid")
+	_, err = services.Update(ctx, service, metaV1.UpdateOptions{})
+	require.NoError(t, err)
+	again, err := ensureAllocationService(ctx, services, "default", "1.1.1")
+	require.NoError(t, err)
+	require.Equal(t, types.UID("uid"), again.UID)
+
+	pod := &k8sV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      jobPodName("1.1.1", 0),
+			Namespace: "default",
+			Labels:    map[string]string{determinedLabel: "1.1.1"},
+		},
+		Spec: k8sV1.PodSpec{
+			RestartPolicy: k8sV1.RestartPolicyNever,
+			Containers: []k8sV1.Container{{
+				Name: model.DeterminedK8ContainerName,
+				Env: []k8sV1.EnvVar{
+					{Name: "DET_RESOURCES_ID", Value: "abc-def-0"},
+					{Name: "DET_TASK_ID", Value: "1"},
+					{Name: "DET_CONTAINER_ID", Value: "abc-def-0"},
+				},
+			}},
+		},
+	}
+	job := indexedJob("1.1.1", pod, again, "abc-def-0", 3)
+	require.Equal(t, "det-1-1-1", job.Name)
+	require.Equal(t, "default", job.Namespace)
+	require.Equal(t, []metaV1.OwnerReference{{
+		APIVersion: "v1", Kind: "Service", Name: "det-1-1-1", UID: "uid",
+	}}, job.OwnerReferences)
+	require.Equal(t, batchV1.IndexedCompletion, *job.Spec.CompletionMode)
+	require.Equal(t, int32(3), *job.Spec.Parallelism)
+	require.Equal(t, int32(3), *job.Spec.Completions)
+	require.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	require.Equal(t, pod.Labels, job.Spec.Template.Labels)
+	require.Equal(t, "det-1-1-1", job.Spec.Template.Spec.Subdomain)
+	require.Equal(t, k8sV1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+
+	// Each pod derives the IDs of its container from its completion index.
+	require.Equal(t, []k8sV1.EnvVar{
+		{
+			Name: "DET_JOB_COMPLETION_INDEX",
+			ValueFrom: &k8sV1.EnvVarSource{FieldRef: &k8sV1.ObjectFieldSelector{
+				FieldPath: "metadata.annotations['batch.kubernetes.io/job-completion-index']",
+			}},
+		},
+		{Name: "DET_RESOURCES_ID", Value: "abc-def-$(DET_JOB_COMPLETION_INDEX)"},
+		{Name: "DET_TASK_ID", Value: "1"},
+		{Name: "DET_CONTAINER_ID", Value: "abc-def-$(DET_JOB_COMPLETION_INDEX)"},
+	}, job.Spec.Template.Spec.Containers[0].Env)
+	require.Equal(t, "abc-def-0", pod.Spec.Containers[0].Env[0].Value)
+}
+
+func TestPodDNSName(t *testing.T) {
+	pod := &k8sV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Namespace: "default"},
+		Status:     k8sV1.PodStatus{PodIP: "10.0.0.1"},
+	}
+	require.Equal(t, "", podDNSName(pod))
+
+	pod.Spec.Hostname = "det-1-1-1-0"
+	pod.Spec.Subdomain = "det-1-1-1"
+	require.Equal(t, "det-1-1-1-0.det-1-1-1.default.svc", podDNSName(pod))
+
+	started := getResourcesStartedForPod(pod, []int{1734})
+	require.Equal(t, "10.0.0.1", started.Addresses[0].HostIP)
+	require.Equal(t, "det-1-1-1-0.det-1-1-1.default.svc", started.Addresses[0].HostName)
+	require.Equal(t, "10.0.0.1", started.Addresses[0].ContainerIP)
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"maps"
+	"slices"
 	"time"
 
 	"github.com/pkg/errors"
@@ -39,8 +40,10 @@ type ResourceManager struct {
 	poolsConfig           []config.ResourcePoolConfig
 	taskContainerDefaults *model.TaskContainerDefaultsConfig
 
-	podsService *pods
-	pools       map[string]*kubernetesResourcePool // immutable after initialization in new.
+	// clusters holds the pods service of each Kubernetes cluster, keyed by cluster name. The
+	// cluster the master runs in has the empty name.
+	clusters map[string]*pods                   // immutable after initialization in new.
+	pools    map[string]*kubernetesResourcePool // immutable after initialization in new.
 
 	masterTLSConfig model.TLSClientConfig
 	loggingConfig   model.LoggingConfig
@@ -76,7 +79,8 @@ func New(
 		poolsConfig:           rmConfigs.ResourcePools,
 		taskContainerDefaults: taskContainerDefaults,
 
-		pools: make(map[string]*kubernetesResourcePool),
+		clusters: make(map[string]*pods),
+		pools:    make(map[string]*kubernetesResourcePool),
 
 		masterTLSConfig: tlsConfig,
 		loggingConfig:   opts.LoggingOptions,
@@ -84,31 +88,32 @@ func New(
 		db: db,
 	}
 
-	poolNamespaces := make(map[string]string)
+	clusterPools := make(map[string][]config.ResourcePoolConfig)
+	poolNamespaces := make(map[string]map[string]string)
 	for i := range k.poolsConfig {
+		cluster := k.poolsConfig[i].KubernetesCluster
 		if k.poolsConfig[i].KubernetesNamespace == "" {
-			k.poolsConfig[i].KubernetesNamespace = k.config.Namespace
+			k.poolsConfig[i].KubernetesNamespace = k.config.ClusterNamespace(cluster)
 		}
 
-		poolNamespaces[k.poolsConfig[i].KubernetesNamespace] = k.poolsConfig[i].PoolName
+		if poolNamespaces[cluster] == nil {
+			poolNamespaces[cluster] = make(map[string]string)
+		}
+		poolNamespaces[cluster][k.poolsConfig[i].KubernetesNamespace] = k.poolsConfig[i].PoolName
+		clusterPools[cluster] = append(clusterPools[cluster], k.poolsConfig[i])
 	}
 
-	k.podsService = newPodsService(
-		k.config.Namespace,
-		poolNamespaces,
-		k.config.MasterServiceName,
-		k.masterTLSConfig,
-		k.loggingConfig,
-		k.config.DefaultScheduler,
-		k.config.SlotType,
-		config.PodSlotResourceRequests{CPU: k.config.SlotResourceRequests.CPU},
-		k.poolsConfig,
-		k.taskContainerDefaults,
-		k.config.CredsDir,
-		k.config.MasterIP,
-		k.config.MasterPort,
-		k.podStatusUpdateCallback,
-	)
+	// The cluster the master runs in is always watched, even if no pool uses it, as before
+	// additional clusters could be configured.
+	k.clusters[""] = k.newPodsService(config.KubernetesClusterConfig{
+		Namespace:  k.config.Namespace,
+		MasterIP:   k.config.MasterIP,
+		MasterPort: k.config.MasterPort,
+	}, poolNamespaces[""], clusterPools[""])
+	for _, cluster := range k.config.AdditionalClusters {
+		k.clusters[cluster.Name] = k.newPodsService(
+			cluster, poolNamespaces[cluster.Name], clusterPools[cluster.Name])
+	}
 
 	for _, poolConfig := range k.poolsConfig {
 		maxSlotsPerPod := 0
@@ -122,7 +127,8 @@ func New(
 		}
 
 		poolConfig := poolConfig
-		rp := newResourcePool(maxSlotsPerPod, &poolConfig, k.podsService, k.db)
+		rp := newResourcePool(
+			maxSlotsPerPod, &poolConfig, k.clusters[poolConfig.KubernetesCluster], k.db)
 		go func() {
 			t := time.NewTicker(podSubmissionInterval)
 			defer t.Stop()
@@ -135,6 +141,62 @@ func New(
 	return k
 }
 
+// newPodsService starts the pods service that launches the tasks of the given pools into a
+// cluster.
+func (k *ResourceManager) newPodsService(
+	cluster config.KubernetesClusterConfig,
+	poolNamespaces map[string]string,
+	poolsConfig []config.ResourcePoolConfig,
+) *pods {
+	if poolNamespaces == nil {
+		poolNamespaces = make(map[string]string)
+	}
+	p := newPodsService(
+		cluster.Namespace,
+		poolNamespaces,
+		k.config.MasterServiceName,
+		k.masterTLSConfig,
+		k.loggingConfig,
+		k.config.DefaultScheduler,
+		k.config.SlotType,
+		config.PodSlotResourceRequests{CPU: k.config.SlotResourceRequests.CPU},
+		poolsConfig,
+		k.taskContainerDefaults,
+		k.config.CredsDir,
+		cluster.KubeconfigPath,
+		cluster.MasterIP,
+		cluster.MasterPort,
+		k.podStatusUpdateCallback,
+		k.config.QueueIntegration,
+		k.config.LaunchAsJobs,
+	)
+	if cluster.Name != "" {
+		p.syslog = p.syslog.WithField("cluster", cluster.Name)
+	}
+	return p
+}
+
+// clusterNames returns the names of the clusters in a stable order.
+func (k *ResourceManager) clusterNames() []string {
+	names := make([]string, 0, len(k.clusters))
+	for name := range k.clusters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// clusterForAgent returns the pods service of the cluster the node belongs to, or that of the
+// cluster the master runs in if no cluster knows the node.
+func (k *ResourceManager) clusterForAgent(agentID string) *pods {
+	for _, name := range k.clusterNames() {
+		if k.clusters[name].hasNode(agentID) {
+			return k.clusters[name]
+		}
+	}
+	return k.clusters[""]
+}
+
 // Allocate implements rm.ResourceManager.
 func (k *ResourceManager) Allocate(msg sproto.AllocateRequest) (*sproto.ResourcesSubscription, error) {
 	// This code exists to handle the case where an experiment does not have
@@ -179,7 +241,11 @@ func (ResourceManager) GetAgent(*apiv1.GetAgentRequest) (*apiv1.GetAgentResponse
 
 // GetAgents implements rm.ResourceManager.
 func (k *ResourceManager) GetAgents(msg *apiv1.GetAgentsRequest) (*apiv1.GetAgentsResponse, error) {
-	return k.podsService.GetAgents(msg), nil
+	resp := &apiv1.GetAgentsResponse{}
+	for _, name := range k.clusterNames() {
+		resp.Agents = append(resp.Agents, k.clusters[name].GetAgents(msg).Agents...)
+	}
+	return resp, nil
 }
 
 // GetAllocationSummaries implements rm.ResourceManager.
@@ -287,6 +353,7 @@ func (k *ResourceManager) GetResourcePools(*apiv1.GetResourcePoolsRequest) (*api
 		}
 
 		summary.Stats = jobStats
+		summary.SlotQuotas = jobStats.SlotQuotas
 		summaries = append(summaries, summary)
 	}
 	return &apiv1.GetResourcePoolsResponse{ResourcePools: summaries}, nil
@@ -692,14 +759,18 @@ func (k *ResourceManager) getTaskContainerDefaults(
 func (k *ResourceManager) EnableAgent(
 	req *apiv1.EnableAgentRequest,
 ) (resp *apiv1.EnableAgentResponse, err error) {
-	return k.podsService.EnableAgent(req)
+	return k.clusterForAgent(req.AgentId).EnableAgent(req)
 }
 
 // DisableAgent prevents scheduling on a node and has the option to kill running jobs.
 func (k *ResourceManager) DisableAgent(
 	req *apiv1.DisableAgentRequest,
 ) (resp *apiv1.DisableAgentResponse, err error) {
-	return k.podsService.DisableAgent(req)
+	if req.DrainTimeoutSeconds != nil {
+		// Nodes are only cordoned; preempting the pods on them is not supported.
+		return nil, rmerrors.ErrNotSupported
+	}
+	return k.clusterForAgent(req.AgentId).DisableAgent(req)
 }
 
 // EnableSlot implements 'det slot enable...' functionality.
@@ -0,0 +1,60 @@
+//nolint:exhaustruct
+package kubernetesrm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	k8sV1 "k8s.io/api/core/v1"
+)
+
+func TestReadClientConfigFromKubeconfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, []byte(`
+apiVersion: v1
+kind: Config
+clusters:
+- name: east
+  cluster:
+    server: https://east.example.com:6443
+users:
+- name: det
+  user:
+    token: secret
+contexts:
+- name: east
+  context:
+    cluster: east
+    user: det
+current-context: east
+`), 0o600))
+
+	config, err := readClientConfig(path, "")
+	require.NoError(t, err)
+	require.Equal(t, "https://east.example.com:6443", config.Host)
+	require.Equal(t, "secret", config.BearerToken)
+
+	_, err = readClientConfig(filepath.Join(t.TempDir(), "missing"), "")
+	require.Error(t, err)
+}
+
+func TestClusterForAgent(t *testing.T) {
+	newCluster := func(nodes ...string) *pods {
+		p := &pods{currentNodes: make(map[string]*k8sV1.Node)}
+		for _, node := range nodes {
+			p.currentNodes[node] = &k8sV1.Node{}
+		}
+		return p
+	}
+	local, east, west := newCluster("node-a"), newCluster("node-b"), newCluster("node-c")
+	k := &ResourceManager{clusters: map[string]*pods{"": local, "east": east, "west": west}}
+
+	require.Equal(t, []string{"", "east", "west"}, k.clusterNames())
+	require.Same(t, local, k.clusterForAgent("node-a"))
+	require.Same(t, east, k.clusterForAgent("node-b"))
+	require.Same(t, west, k.clusterForAgent("node-c"))
+	// Unknown nodes are reported as missing by the cluster the master runs in.
+	require.Same(t, local, k.clusterForAgent("node-d"))
+}
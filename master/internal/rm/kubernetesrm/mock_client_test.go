@@ -12,10 +12,12 @@ import (
 	"github.com/pkg/errors"
 
 	k8sV1 "k8s.io/api/core/v1"
+	policyV1 "k8s.io/api/policy/v1"
 	"k8s.io/api/policy/v1beta1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
+	applyCoreV1 "k8s.io/client-go/applyconfigurations/core/v1"
 	"k8s.io/client-go/rest"
 )
 
@@ -97,6 +99,12 @@ func (m *mockConfigMapInterface) Patch(
 	panic("implement me")
 }
 
+func (m *mockConfigMapInterface) Apply(
+	ctx context.Context, cm *applyCoreV1.ConfigMapApplyConfiguration, opts metaV1.ApplyOptions,
+) (*k8sV1.ConfigMap, error) {
+	panic("implement me")
+}
+
 type mockPodInterface struct {
 	pods map[string]*k8sV1.Pod
 	// Simulates latency of the real k8 API server.
@@ -200,16 +208,21 @@ func (m *mockPodInterface) Patch(
 	panic("implement me")
 }
 
-func (m *mockPodInterface) GetEphemeralContainers(
-	ctx context.Context, podName string, options metaV1.GetOptions,
-) (*k8sV1.EphemeralContainers, error) {
+func (m *mockPodInterface) Apply(
+	ctx context.Context, pod *applyCoreV1.PodApplyConfiguration, opts metaV1.ApplyOptions,
+) (*k8sV1.Pod, error) {
+	panic("implement me")
+}
+
+func (m *mockPodInterface) ApplyStatus(
+	ctx context.Context, pod *applyCoreV1.PodApplyConfiguration, opts metaV1.ApplyOptions,
+) (*k8sV1.Pod, error) {
 	panic("implement me")
 }
 
 func (m *mockPodInterface) UpdateEphemeralContainers(
-	ctx context.Context, podName string, ephemeralContainers *k8sV1.EphemeralContainers,
-	opts metaV1.UpdateOptions,
-) (*k8sV1.EphemeralContainers, error) {
+	ctx context.Context, podName string, pod *k8sV1.Pod, opts metaV1.UpdateOptions,
+) (*k8sV1.Pod, error) {
 	panic("implement me")
 }
 
@@ -221,6 +234,14 @@ func (m *mockPodInterface) Evict(ctx context.Context, eviction *v1beta1.Eviction
 	panic("implement me")
 }
 
+func (m *mockPodInterface) EvictV1(ctx context.Context, eviction *policyV1.Eviction) error {
+	panic("implement me")
+}
+
+func (m *mockPodInterface) EvictV1beta1(ctx context.Context, eviction *v1beta1.Eviction) error {
+	panic("implement me")
+}
+
 func (m *mockPodInterface) GetLogs(name string, opts *k8sV1.PodLogOptions) *rest.Request {
 	return rest.NewRequestWithClient(&url.URL{}, "", rest.ClientContentConfig{},
 		&http.Client{
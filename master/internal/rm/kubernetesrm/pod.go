@@ -1,6 +1,7 @@
 package kubernetesrm
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -74,6 +75,11 @@ type pod struct {
 	configMapInterface   typedV1.ConfigMapInterface
 	resourceRequestQueue *requestQueue
 	scheduler            string
+	queue                *queueIntegration
+	queueName            string
+	launchAsJob          bool
+	serviceInterface     typedV1.ServiceInterface
+	rank                 int
 	slotType             device.Type
 	slotResourceRequests config.PodSlotResourceRequests
 
@@ -115,6 +121,10 @@ func newPod(
 	slotType device.Type,
 	slotResourceRequests config.PodSlotResourceRequests,
 	scheduler string,
+	queue *queueIntegration,
+	queueName string,
+	launchAsJob bool,
+	serviceInterface typedV1.ServiceInterface,
 ) *pod {
 	podContainer := cproto.Container{
 		ID:          cproto.ID(msg.Spec.ContainerID),
@@ -122,6 +132,11 @@ func newPod(
 		Description: msg.Spec.Description,
 	}
 	uniqueName := configureUniqueName(msg.Spec, msg.Rank)
+	configMapName := uniqueName
+	if launchAsJob {
+		uniqueName = jobPodName(msg.AllocationID, msg.Rank)
+		configMapName = allocationJobName(msg.AllocationID)
+	}
 
 	// The lifecycle of the containers specified in this map will be monitored.
 	// As soon as one or more of them exits, the pod will be terminated.
@@ -146,10 +161,15 @@ func newPod(
 		configMapInterface:   configMapInterface,
 		resourceRequestQueue: resourceRequestQueue,
 		podName:              uniqueName,
-		configMapName:        uniqueName,
+		configMapName:        configMapName,
 		container:            podContainer,
 		containerNames:       containerNames,
 		scheduler:            scheduler,
+		queue:                queue,
+		queueName:            queueName,
+		launchAsJob:          launchAsJob,
+		serviceInterface:     serviceInterface,
+		rank:                 msg.Rank,
 		slotType:             slotType,
 		slotResourceRequests: slotResourceRequests,
 		syslog: logrus.New().WithField("component", "pod").WithFields(
@@ -308,6 +328,13 @@ func (p *pod) kill() {
 	}
 
 	p.syslog.Infof("requesting to delete kubernetes resources")
+	if p.launchAsJob {
+		// The pods of a job are deleted together, so only the first request does anything. The
+		// config map is owned by the service of the allocation and is deleted with it.
+		p.resourceRequestQueue.deleteKubernetesJob(
+			p.namespace, allocationJobName(p.allocationID), p.podName)
+		return
+	}
 	p.resourceRequestQueue.deleteKubernetesResources(
 		p.namespace,
 		p.podName,
@@ -328,7 +355,12 @@ func (p *pod) getPodNodeInfo() podNodeInfo {
 }
 
 func (p *pod) startPodLogStreamer() error {
-	return startPodLogStreamer(p.podInterface, p.podName, func(log []byte) {
+	// Pods launched as jobs are named by the job controller.
+	podName := p.podName
+	if p.pod != nil {
+		podName = p.pod.Name
+	}
+	return startPodLogStreamer(p.podInterface, podName, func(log []byte) {
 		p.receiveContainerLog(sproto.ContainerLog{
 			Timestamp: time.Now().UTC(),
 			RunMessage: &aproto.RunMessage{
@@ -344,10 +376,45 @@ func (p *pod) createPodSpecAndSubmit() error {
 		return err
 	}
 
+	if p.queue != nil {
+		err := p.queue.createPodGroup(
+			context.TODO(), p.namespace, podGroupName(p.allocationID), p.queueName, p.numPods())
+		if err != nil {
+			return err
+		}
+	}
+
+	if p.launchAsJob {
+		return p.submitJob()
+	}
+
 	p.resourceRequestQueue.createKubernetesResources(p.pod, p.configMap)
 	return nil
 }
 
+// submitJob launches the pods of the allocation as an indexed job owned by the headless service
+// of the allocation. The first rank submits the job and the config map its pods share, and the
+// handlers of the other ranks wait for the pods with their completion index. The config map is
+// owned by the service too, so all of them are garbage collected together.
+func (p *pod) submitJob() error {
+	if p.rank != 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), serviceRequestTimeout)
+	defer cancel()
+	service, err := ensureAllocationService(ctx, p.serviceInterface, p.namespace, p.allocationID)
+	if err != nil {
+		return err
+	}
+
+	p.configMap.OwnerReferences = append(
+		p.configMap.OwnerReferences, serviceOwnerReference(service))
+	job := indexedJob(p.allocationID, p.pod, service, p.container.ID, p.numPods())
+	p.resourceRequestQueue.createKubernetesJob(job, p.configMap, p.podName)
+	return nil
+}
+
 func (p *pod) receiveResourceCreationFailed(msg resourceCreationFailed) {
 	p.syslog.WithError(msg.err).Error("pod handler notified that resource creation failed")
 	p.insertLog(time.Now().UTC(), msg.err.Error())
@@ -565,6 +632,7 @@ func getResourcesStartedForPod(pod *k8sV1.Pod, ports []int) sproto.ResourcesStar
 			ContainerPort: port,
 			HostIP:        pod.Status.PodIP,
 			HostPort:      port,
+			HostName:      podDNSName(pod),
 		})
 	}
 
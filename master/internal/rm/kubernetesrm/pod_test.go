@@ -55,7 +55,7 @@ func createPod(
 		model.TLSClientConfig{}, model.TLSClientConfig{},
 		model.LoggingConfig{DefaultLoggingConfig: &model.DefaultLoggingConfig{}},
 		podInterface, configMapInterface, resourceRequestQueue,
-		slotType, slotResourceRequests, "default-scheduler",
+		slotType, slotResourceRequests, "default-scheduler", nil, "", false, nil,
 	)
 
 	return newPodHandler
@@ -108,6 +108,7 @@ func createPodWithMockQueue(t *testing.T, k8sRequestQueue *requestQueue) (
 		k8sRequestQueue = startRequestQueue(
 			map[string]typedV1.PodInterface{"default": podInterface},
 			map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+			nil,
 			failures,
 		)
 	}
@@ -672,6 +673,7 @@ func TestKillTaskPod(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 	ref, _, _ := createPodWithMockQueue(t, k8sRequestQueue)
@@ -697,6 +699,7 @@ func TestResourceCreationCancelled(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -746,6 +749,7 @@ func TestResourceDeletionFailed(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,9 +22,12 @@ import (
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
 	k8sClient "k8s.io/client-go/kubernetes"
+	typedBatchV1 "k8s.io/client-go/kubernetes/typed/batch/v1"
 	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
@@ -75,6 +79,9 @@ type pods struct {
 	resourcePoolConfigs   []config.ResourcePoolConfig
 	baseContainerDefaults *model.TaskContainerDefaultsConfig
 	credsDir              string
+	kubeconfigPath        string
+	queueConfig           *config.KubernetesQueueConfig
+	launchAsJobs          bool
 
 	clientSet        *k8sClient.Clientset
 	masterIP         string
@@ -84,6 +91,7 @@ type pods struct {
 	loggingConfig    model.LoggingConfig
 
 	resourceRequestQueue         *requestQueue
+	queue                        *queueIntegration
 	podNameToPodHandler          map[string]*pod
 	podNameToResourcePool        map[string]string
 	containerIDToPodName         map[string]string
@@ -96,6 +104,8 @@ type pods struct {
 
 	podInterfaces       map[string]typedV1.PodInterface
 	configMapInterfaces map[string]typedV1.ConfigMapInterface
+	jobInterfaces       map[string]typedBatchV1.JobInterface
+	serviceInterfaces   map[string]typedV1.ServiceInterface
 
 	summarizeCacheLock sync.RWMutex
 	summarizeCache     summarizeResult
@@ -152,9 +162,12 @@ func newPodsService(
 	resourcePoolConfigs []config.ResourcePoolConfig,
 	taskContainerDefaults *model.TaskContainerDefaultsConfig,
 	credsDir string,
+	kubeconfigPath string,
 	masterIP string,
 	masterPort int32,
 	podStatusUpdateCallback podStatusUpdateCallback,
+	queueConfig *config.KubernetesQueueConfig,
+	launchAsJobs bool,
 ) *pods {
 	loggingTLSConfig := masterTLSConfig
 	if loggingConfig.ElasticLoggingConfig != nil {
@@ -181,12 +194,17 @@ func newPodsService(
 		resourcePoolConfigs:          resourcePoolConfigs,
 		baseContainerDefaults:        taskContainerDefaults,
 		credsDir:                     credsDir,
+		kubeconfigPath:               kubeconfigPath,
+		queueConfig:                  queueConfig,
+		launchAsJobs:                 launchAsJobs,
 		masterIP:                     masterIP,
 		masterPort:                   masterPort,
 		currentNodes:                 make(map[string]*k8sV1.Node),
 		nodeToSystemResourceRequests: make(map[string]int64),
 		podInterfaces:                make(map[string]typedV1.PodInterface),
 		configMapInterfaces:          make(map[string]typedV1.ConfigMapInterface),
+		jobInterfaces:                make(map[string]typedBatchV1.JobInterface),
+		serviceInterfaces:            make(map[string]typedV1.ServiceInterface),
 		syslog:                       logrus.WithField("namespace", namespace),
 		podStatusUpdateCallback:      podStatusUpdateCallback,
 	}
@@ -203,6 +221,10 @@ func newPodsService(
 
 	p.startResourceRequestQueue()
 
+	if p.queue != nil {
+		p.wg.Go(p.queue.run)
+	}
+
 	if err := p.deleteDoomedKubernetesResources(); err != nil {
 		panic(err)
 	}
@@ -295,7 +317,20 @@ func (p *pods) DisableAgent(msg *apiv1.DisableAgentRequest) (*apiv1.DisableAgent
 	return p.disableNode(msg.AgentId, msg.Drain)
 }
 
-func readClientConfig(credsDir string) (*rest.Config, error) {
+// hasNode returns whether the node is part of the cluster the service manages.
+func (p *pods) hasNode(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.currentNodes[name]
+	return ok
+}
+
+func readClientConfig(kubeconfigPath, credsDir string) (*rest.Config, error) {
+	if kubeconfigPath != "" {
+		// A cluster other than the one the master runs in, reached through its kubeconfig.
+		return clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	}
+
 	if credsDir == "" {
 		// The default in-cluster case.  Internally, k8s.io/client-go/rest is going to look for
 		// environment variables:
@@ -337,7 +372,7 @@ func readClientConfig(credsDir string) (*rest.Config, error) {
 }
 
 func (p *pods) startClientSet() error {
-	config, err := readClientConfig(p.credsDir)
+	config, err := readClientConfig(p.kubeconfigPath, p.credsDir)
 	if err != nil {
 		return errors.Wrap(err, "error building kubernetes config")
 	}
@@ -347,9 +382,21 @@ func (p *pods) startClientSet() error {
 		return errors.Wrap(err, "failed to initialize kubernetes clientSet")
 	}
 
-	for _, ns := range append(maps.Keys(p.namespaceToPoolName), p.namespace) {
+	namespaces := append(maps.Keys(p.namespaceToPoolName), p.namespace)
+	for _, ns := range namespaces {
 		p.podInterfaces[ns] = p.clientSet.CoreV1().Pods(ns)
 		p.configMapInterfaces[ns] = p.clientSet.CoreV1().ConfigMaps(ns)
+		p.jobInterfaces[ns] = p.clientSet.BatchV1().Jobs(ns)
+		p.serviceInterfaces[ns] = p.clientSet.CoreV1().Services(ns)
+	}
+
+	if p.queueConfig != nil {
+		dynamicClient, err := dynamic.NewForConfig(config)
+		if err != nil {
+			return errors.Wrap(err, "failed to initialize kubernetes dynamic client")
+		}
+		p.queue = newQueueIntegration(
+			*p.queueConfig, dynamicClient, set.FromSlice(namespaces).ToSlice())
 	}
 
 	p.syslog.Infof("kubernetes clientSet initialized")
@@ -426,14 +473,14 @@ func (p *pods) reattachAllocationPods(msg reattachAllocationPods) ([]reattachPod
 			for _, env := range container.Env {
 				switch env.Name {
 				case "DET_CONTAINER_ID":
-					if !existingConfigMaps.Contains(pod.Name) {
+					if !existingConfigMaps.Contains(podConfigMapName(&pod)) {
 						p.deleteKubernetesResources(pods, configMaps)
 						return nil, fmt.Errorf("pod missing config map %s", pod.Name)
 					}
 
 					p := pod
 					k8sPods = append(k8sPods, &p)
-					containerIDs = append(containerIDs, env.Value)
+					containerIDs = append(containerIDs, expandJobCompletionIndex(&pod, env.Value))
 
 					var podPorts []int
 					for _, p := range container.Ports {
@@ -544,11 +591,16 @@ func (p *pods) reattachPod(
 		p.slotType,
 		p.slotResourceRequests,
 		p.scheduler,
+		p.queue,
+		p.poolQueueName(resourcePool),
+		isJobPod(pod),
+		p.serviceInterfaces[pod.Namespace],
 	)
 
+	handlerName := podHandlerName(pod)
 	newPodHandler.restore = true
-	newPodHandler.podName = pod.Name
-	newPodHandler.configMapName = pod.Name
+	newPodHandler.podName = handlerName
+	newPodHandler.configMapName = podConfigMapName(pod)
 	newPodHandler.ports = ports
 
 	state, err := newPodHandler.getPodState(pod, newPodHandler.containerNames)
@@ -574,13 +626,13 @@ func (p *pods) reattachPod(
 		return reattachPodResponse{}, fmt.Errorf("reattaching pod: %w", err)
 	}
 
-	p.podNameToPodHandler[pod.Name] = newPodHandler
-	p.podNameToResourcePool[pod.Name] = resourcePool
-	p.containerIDToPodName[containerID] = pod.Name
-	p.podNameToContainerID[pod.Name] = containerID
+	p.podNameToPodHandler[handlerName] = newPodHandler
+	p.podNameToResourcePool[handlerName] = resourcePool
+	p.containerIDToPodName[containerID] = handlerName
+	p.podNameToContainerID[handlerName] = containerID
 	p.containerIDToSchedulingState[containerID] = sproto.SchedulingStateQueued
 	p.podHandlerToMetadata[newPodHandler] = podMetadata{
-		podName:     pod.Name,
+		podName:     handlerName,
 		containerID: containerID,
 	}
 
@@ -613,6 +665,11 @@ func (p *pods) deleteKubernetesResources(
 	pods *k8sV1.PodList, configMaps *k8sV1.ConfigMapList,
 ) {
 	for _, pod := range pods.Items {
+		if isJobPod(&pod) {
+			p.resourceRequestQueue.deleteKubernetesJob(
+				pod.Namespace, pod.Labels[jobNameLabel], podHandlerName(&pod))
+			continue
+		}
 		p.resourceRequestQueue.deleteKubernetesResources(pod.Namespace, pod.Name, "")
 	}
 
@@ -669,7 +726,7 @@ func (p *pods) deleteDoomedKubernetesResources() error {
 			toKillPods.Items = append(toKillPods.Items, pod)
 			continue
 		}
-		savedPodNames.Insert(pod.Name)
+		savedPodNames.Insert(podConfigMapName(&pod))
 	}
 
 	configMaps, err := p.listConfigMapsInAllNamespaces(context.TODO(), listOptions)
@@ -692,6 +749,35 @@ func (p *pods) deleteDoomedKubernetesResources() error {
 	}
 
 	p.deleteKubernetesResources(toKillPods, toKillConfigMaps)
+	return p.deleteDoomedAllocationServices(openAllocationIDs)
+}
+
+// deleteDoomedAllocationServices deletes the headless services of allocations that are no longer
+// open, which garbage collects any jobs and config maps they own.
+func (p *pods) deleteDoomedAllocationServices(openAllocationIDs set.Set[model.AllocationID]) error {
+	propagation := metaV1.DeletePropagationBackground
+	for namespace, services := range p.serviceInterfaces {
+		if _, ok := p.namespaceToPoolName[namespace]; !ok {
+			continue
+		}
+
+		list, err := services.List(context.TODO(), metaV1.ListOptions{LabelSelector: determinedLabel})
+		if err != nil {
+			return errors.Wrap(err, "error listing existing services")
+		}
+		for _, service := range list.Items {
+			if openAllocationIDs.Contains(model.AllocationID(service.Labels[determinedLabel])) {
+				continue
+			}
+			p.syslog.Warnf("deleting service '%s', did not find open allocation '%s'",
+				service.Name, service.Labels[determinedLabel])
+			err := services.Delete(context.TODO(), service.Name,
+				metaV1.DeleteOptions{PropagationPolicy: &propagation})
+			if err != nil && !k8error.IsNotFound(err) {
+				p.syslog.WithError(err).Warnf("failed to delete service %s", service.Name)
+			}
+		}
+	}
 	return nil
 }
 
@@ -777,7 +863,8 @@ func (p *pods) startPreemptionListeners() error {
 
 func (p *pods) startResourceRequestQueue() {
 	failures := make(chan resourcesRequestFailure, 16)
-	p.resourceRequestQueue = startRequestQueue(p.podInterfaces, p.configMapInterfaces, failures)
+	p.resourceRequestQueue = startRequestQueue(
+		p.podInterfaces, p.configMapInterfaces, p.jobInterfaces, failures)
 	p.wg.Go(func(ctx context.Context) {
 		for {
 			select {
@@ -835,6 +922,10 @@ func (p *pods) receiveStartTaskPod(msg StartTaskPod) error {
 		p.slotType,
 		p.slotResourceRequests,
 		p.scheduler,
+		p.queue,
+		p.poolQueueName(msg.Req.ResourcePool),
+		p.launchAsJobs,
+		p.serviceInterfaces[msg.Namespace],
 	)
 
 	if _, alreadyExists := p.podNameToPodHandler[newPodHandler.podName]; alreadyExists {
@@ -869,7 +960,7 @@ func (p *pods) podStatusCallback(event watch.Event) {
 	syslog := p.syslog.WithField("pod", pod.Name)
 	syslog.WithField("event.Type", event.Type).Debug("received pod informer event")
 
-	podHandler, ok := p.podNameToPodHandler[pod.Name]
+	podHandler, ok := p.podNameToPodHandler[podHandlerName(pod)]
 	if !ok {
 		syslog.Debug("received status update for un-registered pod")
 		return
@@ -891,7 +982,7 @@ func (p *pods) podStatusCallback(event watch.Event) {
 		}
 	}
 
-	if containerID, ok := p.podNameToContainerID[pod.Name]; ok {
+	if containerID, ok := p.podNameToContainerID[podHandlerName(pod)]; ok {
 		if state, ok := p.containerIDToSchedulingState[containerID]; ok {
 			currState := sproto.SchedulingStateQueued
 			if pod.Status.Phase == "Running" {
@@ -1036,7 +1127,7 @@ func (p *pods) releaseAllocationsOnDisabledNode(nodeName string) error {
 
 	notifiedAllocations := make(map[model.AllocationID]bool)
 	for _, pod := range pods.Items {
-		podHandler, ok := p.podNameToPodHandler[pod.Name]
+		podHandler, ok := p.podNameToPodHandler[podHandlerName(&pod)]
 		if !ok {
 			p.syslog.Warnf(
 				"during node disable couldn't find pod %s's actor to kill", pod.Name)
@@ -1093,7 +1184,7 @@ func (p *pods) eventStatusCallback(event watch.Event) {
 	})
 
 	syslog.Debugf("listener got new event: %s", newEvent.Message)
-	ref, ok := p.podNameToPodHandler[newEvent.InvolvedObject.Name]
+	ref, ok := p.podHandlerForEvent(newEvent.InvolvedObject.Name)
 	if !ok {
 		// We log at the debug level because we are unable to filter
 		// pods based on their labels the way we do with pod status updates.
@@ -1104,6 +1195,21 @@ func (p *pods) eventStatusCallback(event watch.Event) {
 	ref.podEventUpdate(newEvent)
 }
 
+// podHandlerForEvent returns the handler of the object an event is about. Events only name the
+// object, so for pods launched as jobs, the generated suffix of the pod name is trimmed to find
+// the job and completion index the handler is registered under.
+func (p *pods) podHandlerForEvent(name string) (*pod, bool) {
+	if ref, ok := p.podNameToPodHandler[name]; ok {
+		return ref, true
+	}
+	if i := strings.LastIndex(name, "-"); i > 0 {
+		if ref, ok := p.podNameToPodHandler[name[:i]]; ok && ref.launchAsJob {
+			return ref, true
+		}
+	}
+	return nil, false
+}
+
 func (p *pods) receiveResourceSummarize(msg SummarizeResources) (*PodsInfo, error) {
 	summary, err := p.summarize()
 	if err != nil {
@@ -1129,7 +1235,7 @@ func (p *pods) preemptionCallback(event watch.Event) {
 	}
 	p.syslog.Debugf("informer got new preemption event for pod %s ", pod.Name)
 
-	ref, ok := p.podNameToPodHandler[pod.Name]
+	ref, ok := p.podNameToPodHandler[podHandlerName(pod)]
 	if !ok {
 		p.syslog.Debug("received preemption command for unregistered pod")
 		return
@@ -1206,8 +1312,28 @@ func (p *pods) cleanUpPodHandler(podHandler *pod) error {
 	delete(p.containerIDToSchedulingState, podInfo.containerID)
 	delete(p.podHandlerToMetadata, podHandler)
 
+	lastPod := !p.allocationHasPods(podHandler.allocationID)
+	deletePodGroup := p.queue != nil && lastPod
+	deleteService := podHandler.launchAsJob && lastPod
+
 	// launch this work async, since we hold the lock and it does API calls.
 	p.wg.Go(func(ctx context.Context) {
+		if deletePodGroup {
+			group := podGroupName(podHandler.allocationID)
+			if err := p.queue.deletePodGroup(ctx, podHandler.namespace, group); err != nil {
+				p.syslog.WithError(err).Warnf("deletion of pod group %s failed", group)
+			}
+		}
+		if deleteService {
+			name := allocationServiceName(podHandler.allocationID)
+			propagation := metaV1.DeletePropagationBackground
+			err := p.serviceInterfaces[podHandler.namespace].Delete(
+				ctx, name, metaV1.DeleteOptions{PropagationPolicy: &propagation})
+			if err != nil && !k8error.IsNotFound(err) {
+				p.syslog.WithError(err).Warnf("deletion of service %s failed", name)
+			}
+		}
+
 		name := fmt.Sprintf("%s-priorityclass", podInfo.containerID)
 		err := p.clientSet.
 			SchedulingV1().
@@ -1221,6 +1347,30 @@ func (p *pods) cleanUpPodHandler(podHandler *pod) error {
 	return nil
 }
 
+// allocationHasPods returns whether any registered pod handler belongs to the allocation.
+func (p *pods) allocationHasPods(allocationID model.AllocationID) bool {
+	for handler := range p.podHandlerToMetadata {
+		if handler.allocationID == allocationID {
+			return true
+		}
+	}
+	return false
+}
+
+// poolQueueName returns the cluster-wide queue the pods of a resource pool are submitted to, if
+// a queue integration is configured.
+func (p *pods) poolQueueName(pool string) string {
+	if p.queue == nil {
+		return ""
+	}
+	for _, poolConfig := range p.resourcePoolConfigs {
+		if poolConfig.PoolName == pool {
+			return p.queue.queueName(poolConfig.KubernetesQueue)
+		}
+	}
+	return p.queue.queueName("")
+}
+
 func (p *pods) handleGetAgentsRequest() *apiv1.GetAgentsResponse {
 	nodeSummaries := p.summarizeClusterByNodes()
 	_, nodesToPools := p.getNodeResourcePoolMapping(nodeSummaries)
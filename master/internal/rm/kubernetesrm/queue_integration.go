@@ -0,0 +1,314 @@
+package kubernetesrm
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	k8sV1 "k8s.io/api/core/v1"
+	k8error "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	kueueQueueNameLabel               = "kueue.x-k8s.io/queue-name"
+	kueuePodGroupNameLabel            = "kueue.x-k8s.io/pod-group-name"
+	kueuePodGroupTotalCountAnnotation = "kueue.x-k8s.io/pod-group-total-count"
+
+	volcanoScheduler           = "volcano"
+	volcanoGroupNameAnnotation = "scheduling.k8s.io/group-name"
+
+	queueRefreshPeriod = 10 * time.Second
+	maxGroupNameLength = 63
+)
+
+var (
+	kueueWorkloadsResource = schema.GroupVersionResource{
+		Group: "kueue.x-k8s.io", Version: "v1beta1", Resource: "workloads",
+	}
+	volcanoPodGroupsResource = schema.GroupVersionResource{
+		Group: "scheduling.volcano.sh", Version: "v1beta1", Resource: "podgroups",
+	}
+
+	invalidGroupNameChars = regexp.MustCompile(`[^a-z0-9.-]`)
+)
+
+// queuedWorkload is the state of a workload in a cluster-wide queue.
+type queuedWorkload struct {
+	admitted bool
+	// position is the number of pending workloads ahead of this one in its queue.
+	position int
+}
+
+// queueEntry is a workload listed from the cluster, before positions are assigned.
+type queueEntry struct {
+	name     string
+	queue    string
+	priority int64
+	created  time.Time
+	// rank orders pending workloads before priority; lower ranks are admitted first.
+	rank     int
+	admitted bool
+	finished bool
+}
+
+// queueIntegration submits the pods of each allocation to Kueue or Volcano as a single
+// workload and tracks where those workloads sit in their queues, so the admission decisions
+// of the cluster-wide queue can be reflected in the job queue.
+type queueIntegration struct {
+	mu sync.RWMutex
+
+	config     config.KubernetesQueueConfig
+	client     dynamic.Interface
+	namespaces []string
+	workloads  map[string]queuedWorkload
+
+	syslog *logrus.Entry
+}
+
+func newQueueIntegration(
+	cfg config.KubernetesQueueConfig, client dynamic.Interface, namespaces []string,
+) *queueIntegration {
+	return &queueIntegration{
+		config:     cfg,
+		client:     client,
+		namespaces: namespaces,
+		workloads:  make(map[string]queuedWorkload),
+		syslog:     logrus.WithField("component", "queue-integration").WithField("type", cfg.Type),
+	}
+}
+
+// podGroupName returns the name of the Kueue pod group or Volcano PodGroup of an allocation.
+// It is usable both as an object name and as a label value.
+func podGroupName(allocationID model.AllocationID) string {
+	name := "det-" + invalidGroupNameChars.ReplaceAllString(
+		strings.ToLower(string(allocationID)), "-")
+	if len(name) > maxGroupNameLength {
+		name = name[:maxGroupNameLength]
+	}
+	return strings.TrimRight(name, ".-")
+}
+
+// queueName returns the queue workloads of a resource pool are submitted to.
+func (q *queueIntegration) queueName(poolQueue string) string {
+	if poolQueue != "" {
+		return poolQueue
+	}
+	return q.config.Queue
+}
+
+// configurePod marks the pod as one of numPods pods of the group submitted to queue.
+func (q *queueIntegration) configurePod(pod *k8sV1.Pod, group, queue string, numPods int) {
+	if pod.ObjectMeta.Labels == nil {
+		pod.ObjectMeta.Labels = make(map[string]string)
+	}
+	if pod.ObjectMeta.Annotations == nil {
+		pod.ObjectMeta.Annotations = make(map[string]string)
+	}
+
+	switch q.config.Type {
+	case config.KueueQueueIntegration:
+		pod.ObjectMeta.Labels[kueueQueueNameLabel] = queue
+		pod.ObjectMeta.Labels[kueuePodGroupNameLabel] = group
+		pod.ObjectMeta.Annotations[kueuePodGroupTotalCountAnnotation] = strconv.Itoa(numPods)
+	case config.VolcanoQueueIntegration:
+		if pod.Spec.SchedulerName == "" {
+			pod.Spec.SchedulerName = volcanoScheduler
+		}
+		pod.ObjectMeta.Annotations[volcanoGroupNameAnnotation] = group
+	}
+}
+
+// createPodGroup creates the Volcano PodGroup that gangs the pods of an allocation. Kueue
+// creates the Workload of a pod group itself, so this is a no-op for Kueue.
+func (q *queueIntegration) createPodGroup(
+	ctx context.Context, namespace, group, queue string, numPods int,
+) error {
+	if q.config.Type != config.VolcanoQueueIntegration {
+		return nil
+	}
+
+	podGroup := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": volcanoPodGroupsResource.GroupVersion().String(),
+		"kind":       "PodGroup",
+		"metadata": map[string]interface{}{
+			"name":      group,
+			"namespace": namespace,
+			"labels":    map[string]interface{}{determinedLabel: group},
+		},
+		"spec": map[string]interface{}{
+			"minMember": int64(numPods),
+			"queue":     queue,
+		},
+	}}
+	_, err := q.client.Resource(volcanoPodGroupsResource).Namespace(namespace).Create(
+		ctx, podGroup, metaV1.CreateOptions{})
+	if err != nil && !k8error.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "creating volcano pod group %s", group)
+	}
+	return nil
+}
+
+// deletePodGroup deletes the Volcano PodGroup of an allocation once its pods are gone.
+func (q *queueIntegration) deletePodGroup(ctx context.Context, namespace, group string) error {
+	if q.config.Type != config.VolcanoQueueIntegration {
+		return nil
+	}
+
+	err := q.client.Resource(volcanoPodGroupsResource).Namespace(namespace).Delete(
+		ctx, group, metaV1.DeleteOptions{})
+	if err != nil && !k8error.IsNotFound(err) {
+		return errors.Wrapf(err, "deleting volcano pod group %s", group)
+	}
+	return nil
+}
+
+// workload returns the last observed state of the workload of a pod group.
+func (q *queueIntegration) workload(group string) (queuedWorkload, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	w, ok := q.workloads[group]
+	return w, ok
+}
+
+// run periodically refreshes the state of the queues until the context is canceled.
+func (q *queueIntegration) run(ctx context.Context) {
+	t := time.NewTicker(queueRefreshPeriod)
+	defer t.Stop()
+	for {
+		if err := q.refresh(ctx); err != nil {
+			q.syslog.WithError(err).Warn("failed to refresh cluster queue state")
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refresh lists the workloads in the watched namespaces and recomputes their positions.
+func (q *queueIntegration) refresh(ctx context.Context) error {
+	resource := kueueWorkloadsResource
+	if q.config.Type == config.VolcanoQueueIntegration {
+		resource = volcanoPodGroupsResource
+	}
+
+	var entries []queueEntry
+	for _, namespace := range q.namespaces {
+		list, err := q.client.Resource(resource).Namespace(namespace).List(
+			ctx, metaV1.ListOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "listing %s in namespace %s", resource.Resource, namespace)
+		}
+		for _, item := range list.Items {
+			var entry queueEntry
+			if q.config.Type == config.VolcanoQueueIntegration {
+				entry = volcanoQueueEntry(item)
+			} else {
+				entry = kueueQueueEntry(item)
+			}
+			if !entry.finished {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	workloads := queuePositions(entries)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.workloads = workloads
+	return nil
+}
+
+// queuePositions orders the pending entries of each queue the way the cluster-wide queue
+// admits them, by rank, then by priority, then first come first served.
+func queuePositions(entries []queueEntry) map[string]queuedWorkload {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch {
+		case a.rank != b.rank:
+			return a.rank < b.rank
+		case a.priority != b.priority:
+			return a.priority > b.priority
+		default:
+			return a.created.Before(b.created)
+		}
+	})
+
+	workloads := make(map[string]queuedWorkload, len(entries))
+	pendingPerQueue := make(map[string]int)
+	for _, entry := range entries {
+		if entry.admitted {
+			workloads[entry.name] = queuedWorkload{admitted: true}
+			continue
+		}
+		workloads[entry.name] = queuedWorkload{position: pendingPerQueue[entry.queue]}
+		pendingPerQueue[entry.queue]++
+	}
+	return workloads
+}
+
+// kueueQueueEntry reads a Kueue Workload. LocalQueues are namespaced, so the queue of an entry
+// is qualified by its namespace.
+func kueueQueueEntry(item unstructured.Unstructured) queueEntry {
+	queue, _, _ := unstructured.NestedString(item.Object, "spec", "queueName")
+	priority, _, _ := unstructured.NestedInt64(item.Object, "spec", "priority")
+	entry := queueEntry{
+		name:     item.GetName(),
+		queue:    item.GetNamespace() + "/" + queue,
+		priority: priority,
+		created:  item.GetCreationTimestamp().Time,
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["status"] != string(metaV1.ConditionTrue) {
+			continue
+		}
+		switch condition["type"] {
+		case "QuotaReserved", "Admitted":
+			entry.admitted = true
+		case "Finished":
+			entry.finished = true
+		}
+	}
+	return entry
+}
+
+// volcanoQueueEntry reads a Volcano PodGroup. Volcano moves a pending PodGroup to Inqueue once
+// its queue has room for it, so those are admitted ahead of the ones still Pending.
+func volcanoQueueEntry(item unstructured.Unstructured) queueEntry {
+	queue, _, _ := unstructured.NestedString(item.Object, "spec", "queue")
+	phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+	entry := queueEntry{
+		name:    item.GetName(),
+		queue:   queue,
+		created: item.GetCreationTimestamp().Time,
+	}
+
+	switch phase {
+	case "Running":
+		entry.admitted = true
+	case "Completed":
+		entry.finished = true
+	case "Pending", "":
+		entry.rank = 1
+	}
+	return entry
+}
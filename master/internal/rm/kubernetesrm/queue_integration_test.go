@@ -0,0 +1,200 @@
+//nolint:exhaustruct
+package kubernetesrm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	k8sV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const testQueueNamespace = "default"
+
+func newFakeQueueIntegration(
+	queueType string, objects ...runtime.Object,
+) *queueIntegration {
+	client := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			kueueWorkloadsResource:   "WorkloadList",
+			volcanoPodGroupsResource: "PodGroupList",
+		},
+		objects...,
+	)
+	return newQueueIntegration(
+		config.KubernetesQueueConfig{Type: queueType, Queue: "gpu"},
+		client,
+		[]string{testQueueNamespace},
+	)
+}
+
+func kueueWorkload(
+	name, queue string, priority int64, created time.Time, conditions ...string,
+) *unstructured.Unstructured {
+	var status []interface{}
+	for _, c := range conditions {
+		status = append(status, map[string]interface{}{"type": c, "status": "True"})
+	}
+	w := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": kueueWorkloadsResource.GroupVersion().String(),
+		"kind":       "Workload",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": testQueueNamespace,
+		},
+		"spec":   map[string]interface{}{"queueName": queue, "priority": priority},
+		"status": map[string]interface{}{"conditions": status},
+	}}
+	w.SetCreationTimestamp(metaV1.NewTime(created))
+	return w
+}
+
+func volcanoPodGroup(name, queue, phase string, created time.Time) *unstructured.Unstructured {
+	pg := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": volcanoPodGroupsResource.GroupVersion().String(),
+		"kind":       "PodGroup",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": testQueueNamespace,
+		},
+		"spec":   map[string]interface{}{"queue": queue},
+		"status": map[string]interface{}{"phase": phase},
+	}}
+	pg.SetCreationTimestamp(metaV1.NewTime(created))
+	return pg
+}
+
+func TestPodGroupName(t *testing.T) {
+	require.Equal(t, "det-1.2.3", podGroupName("1.2.3"))
+	require.Equal(t, "det-abc-def.1", podGroupName("ABC_DEF.1"))
+
+	long := podGroupName(model.AllocationID(strings.Repeat("a", 100)))
+	require.Len(t, long, maxGroupNameLength)
+}
+
+func TestQueueIntegrationConfigurePod(t *testing.T) {
+	kueue := newFakeQueueIntegration(config.KueueQueueIntegration)
+	pod := &k8sV1.Pod{}
+	kueue.configurePod(pod, "det-1.1", "gpu", 4)
+	require.Equal(t, "gpu", pod.Labels[kueueQueueNameLabel])
+	require.Equal(t, "det-1.1", pod.Labels[kueuePodGroupNameLabel])
+	require.Equal(t, "4", pod.Annotations[kueuePodGroupTotalCountAnnotation])
+	require.Empty(t, pod.Spec.SchedulerName)
+
+	volcano := newFakeQueueIntegration(config.VolcanoQueueIntegration)
+	pod = &k8sV1.Pod{}
+	volcano.configurePod(pod, "det-1.1", "gpu", 4)
+	require.Equal(t, volcanoScheduler, pod.Spec.SchedulerName)
+	require.Equal(t, "det-1.1", pod.Annotations[volcanoGroupNameAnnotation])
+
+	// A scheduler set in the user's pod spec is kept.
+	pod = &k8sV1.Pod{Spec: k8sV1.PodSpec{SchedulerName: "custom"}}
+	volcano.configurePod(pod, "det-1.1", "gpu", 4)
+	require.Equal(t, "custom", pod.Spec.SchedulerName)
+}
+
+func TestKueueQueuePositions(t *testing.T) {
+	now := time.Now()
+	q := newFakeQueueIntegration(config.KueueQueueIntegration,
+		kueueWorkload("running", "gpu", 0, now.Add(-time.Hour), "QuotaReserved", "Admitted"),
+		kueueWorkload("done", "gpu", 0, now.Add(-time.Hour), "Admitted", "Finished"),
+		kueueWorkload("old", "gpu", 0, now.Add(-time.Minute)),
+		kueueWorkload("new", "gpu", 0, now),
+		kueueWorkload("urgent", "gpu", 100, now),
+		kueueWorkload("other-queue", "cpu", 0, now),
+	)
+	require.NoError(t, q.refresh(context.Background()))
+
+	expected := map[string]queuedWorkload{
+		"running":     {admitted: true},
+		"urgent":      {position: 0},
+		"old":         {position: 1},
+		"new":         {position: 2},
+		"other-queue": {position: 0},
+	}
+	for name, want := range expected {
+		got, ok := q.workload(name)
+		require.True(t, ok, name)
+		require.Equal(t, want, got, name)
+	}
+	_, ok := q.workload("done")
+	require.False(t, ok)
+}
+
+func TestVolcanoPodGroups(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	q := newFakeQueueIntegration(config.VolcanoQueueIntegration,
+		volcanoPodGroup("running", "gpu", "Running", now.Add(-time.Hour)),
+		volcanoPodGroup("pending", "gpu", "Pending", now.Add(-time.Minute)),
+		volcanoPodGroup("inqueue", "gpu", "Inqueue", now),
+	)
+
+	require.NoError(t, q.createPodGroup(ctx, testQueueNamespace, "det-1.1", "gpu", 2))
+	// Every pod of the allocation tries to create the group.
+	require.NoError(t, q.createPodGroup(ctx, testQueueNamespace, "det-1.1", "gpu", 2))
+
+	podGroups := q.client.Resource(volcanoPodGroupsResource).Namespace(testQueueNamespace)
+	created, err := podGroups.Get(ctx, "det-1.1", metaV1.GetOptions{})
+	require.NoError(t, err)
+	minMember, _, _ := unstructured.NestedInt64(created.Object, "spec", "minMember")
+	require.Equal(t, int64(2), minMember)
+
+	// The fake client does not set creation timestamps like the API server does.
+	created.SetCreationTimestamp(metaV1.NewTime(now.Add(time.Minute)))
+	_, err = podGroups.Update(ctx, created, metaV1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, q.refresh(ctx))
+	expected := map[string]queuedWorkload{
+		"running": {admitted: true},
+		"inqueue": {position: 0},
+		"pending": {position: 1},
+		"det-1.1": {position: 2},
+	}
+	for name, want := range expected {
+		got, ok := q.workload(name)
+		require.True(t, ok, name)
+		require.Equal(t, want, got, name)
+	}
+
+	require.NoError(t, q.deletePodGroup(ctx, testQueueNamespace, "det-1.1"))
+	require.NoError(t, q.deletePodGroup(ctx, testQueueNamespace, "det-1.1"))
+}
+
+func TestApplyClusterQueuePositions(t *testing.T) {
+	q := newFakeQueueIntegration(config.KueueQueueIntegration)
+	q.workloads = map[string]queuedWorkload{
+		podGroupName("a.1"): {position: 7},
+		podGroupName("b.1"): {admitted: true},
+	}
+	rp := &kubernetesResourcePool{podsService: &pods{queue: q}}
+
+	reqs := []*sproto.AllocateRequest{
+		{AllocationID: "a.1", JobID: "a"},
+		{AllocationID: "b.1", JobID: "b"},
+		{AllocationID: "c.1", JobID: "c"},
+	}
+	jobs := map[model.JobID]*sproto.RMJobInfo{
+		"a": {JobsAhead: 0, State: sproto.SchedulingStateScheduled},
+		"b": {JobsAhead: 1, State: sproto.SchedulingStateScheduled},
+		"c": {JobsAhead: 2, State: sproto.SchedulingStateQueued},
+	}
+	rp.applyClusterQueuePositions(reqs, jobs)
+
+	require.Equal(t, &sproto.RMJobInfo{JobsAhead: 7, State: sproto.SchedulingStateQueued}, jobs["a"])
+	require.Equal(t, &sproto.RMJobInfo{JobsAhead: 1, State: sproto.SchedulingStateScheduled}, jobs["b"])
+	require.Equal(t, &sproto.RMJobInfo{JobsAhead: 2, State: sproto.SchedulingStateQueued}, jobs["c"])
+}
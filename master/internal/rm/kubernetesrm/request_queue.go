@@ -5,7 +5,9 @@ import (
 	"sync"
 
 	"github.com/sirupsen/logrus"
+	batchV1 "k8s.io/api/batch/v1"
 	k8sV1 "k8s.io/api/core/v1"
+	typedBatchV1 "k8s.io/client-go/kubernetes/typed/batch/v1"
 	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
 
 	"github.com/determined-ai/determined/master/pkg/set"
@@ -20,16 +22,38 @@ const (
 type (
 	createKubernetesResources struct {
 		podSpec       *k8sV1.Pod
+		jobSpec       *batchV1.Job
 		configMapSpec *k8sV1.ConfigMap
+		// handler is the name of the pod handler that requested a job.
+		handler string
 	}
 
 	deleteKubernetesResources struct {
 		namespace     string
 		podName       string
+		jobName       string
 		configMapName string
+		// handler is the name of the pod handler that requested the deletion of a job.
+		handler string
 	}
 )
 
+// handlerName returns the name of the pod handler that requested the resources.
+func (c createKubernetesResources) handlerName() string {
+	if c.jobSpec != nil {
+		return c.handler
+	}
+	return c.podSpec.Name
+}
+
+// handlerName returns the name of the pod handler that requested the deletion.
+func (d deleteKubernetesResources) handlerName() string {
+	if d.jobName != "" {
+		return d.handler
+	}
+	return d.podName
+}
+
 // error types that are sent by requestQueue and requestProcessingWorkers as responses
 // to creation or deletion requests.
 type (
@@ -103,6 +127,7 @@ type queuedResourceRequest struct {
 type requestQueue struct {
 	podInterfaces       map[string]typedV1.PodInterface
 	configMapInterfaces map[string]typedV1.ConfigMapInterface
+	jobInterfaces       map[string]typedBatchV1.JobInterface
 	failures            chan<- resourcesRequestFailure
 
 	mu         sync.Mutex
@@ -122,11 +147,13 @@ type requestID string
 func startRequestQueue(
 	podInterfaces map[string]typedV1.PodInterface,
 	configMapInterfaces map[string]typedV1.ConfigMapInterface,
+	jobInterfaces map[string]typedBatchV1.JobInterface,
 	failures chan<- resourcesRequestFailure,
 ) *requestQueue {
 	r := &requestQueue{
 		podInterfaces:       podInterfaces,
 		configMapInterfaces: configMapInterfaces,
+		jobInterfaces:       jobInterfaces,
 		failures:            failures,
 
 		workerChan: make(chan interface{}),
@@ -148,6 +175,7 @@ func (r *requestQueue) startWorkers() {
 		startRequestProcessingWorker(
 			r.podInterfaces,
 			r.configMapInterfaces,
+			r.jobInterfaces,
 			strconv.Itoa(i),
 			r.workerChan,
 			r.workerReady,
@@ -160,6 +188,9 @@ func keyForCreate(msg createKubernetesResources) requestID {
 	if msg.podSpec != nil {
 		return requestID(msg.podSpec.Namespace + "/" + msg.podSpec.Name)
 	}
+	if msg.jobSpec != nil {
+		return requestID(msg.jobSpec.Namespace + "/" + msg.jobSpec.Name)
+	}
 	if msg.configMapSpec != nil {
 		return requestID(msg.configMapSpec.Namespace + "/" + msg.configMapSpec.Name)
 	}
@@ -170,6 +201,9 @@ func keyForDelete(msg deleteKubernetesResources) requestID {
 	if msg.podName != "" {
 		return requestID(msg.namespace + "/" + msg.podName)
 	}
+	if msg.jobName != "" {
+		return requestID(msg.namespace + "/" + msg.jobName)
+	}
 	if msg.configMapName != "" {
 		return requestID(msg.namespace + "/" + msg.configMapName)
 	}
@@ -180,10 +214,23 @@ func (r *requestQueue) createKubernetesResources(
 	podSpec *k8sV1.Pod,
 	configMapSpec *k8sV1.ConfigMap,
 ) {
+	r.create(createKubernetesResources{podSpec: podSpec, configMapSpec: configMapSpec})
+}
+
+func (r *requestQueue) createKubernetesJob(
+	jobSpec *batchV1.Job,
+	configMapSpec *k8sV1.ConfigMap,
+	handler string,
+) {
+	r.create(createKubernetesResources{
+		jobSpec: jobSpec, configMapSpec: configMapSpec, handler: handler,
+	})
+}
+
+func (r *requestQueue) create(msg createKubernetesResources) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	msg := createKubernetesResources{podSpec, configMapSpec}
 	ref := keyForCreate(msg)
 
 	if _, requestAlreadyExists := r.pendingResourceCreations[ref]; requestAlreadyExists {
@@ -206,10 +253,23 @@ func (r *requestQueue) deleteKubernetesResources(
 	podName string,
 	configMapName string,
 ) {
+	r.delete(deleteKubernetesResources{
+		namespace: namespace, podName: podName, configMapName: configMapName,
+	})
+}
+
+func (r *requestQueue) deleteKubernetesJob(
+	namespace string,
+	jobName string,
+	handler string,
+) {
+	r.delete(deleteKubernetesResources{namespace: namespace, jobName: jobName, handler: handler})
+}
+
+func (r *requestQueue) delete(msg deleteKubernetesResources) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	msg := deleteKubernetesResources{namespace, podName, configMapName}
 	ref := keyForDelete(msg)
 
 	// If the request has not been processed yet, cancel it and inform the handler.
@@ -217,7 +277,7 @@ func (r *requestQueue) deleteKubernetesResources(
 		r.pendingResourceCreations[ref].createResources = nil
 		delete(r.pendingResourceCreations, ref)
 		r.failures <- resourceCreationCancelled{
-			podName: podName,
+			podName: msg.handlerName(),
 		}
 		r.syslog.Warnf("delete issued with pending create request for %s", ref)
 		return
@@ -11,8 +11,11 @@ import (
 	"github.com/sirupsen/logrus"
 	"gotest.tools/assert"
 
+	batchV1 "k8s.io/api/batch/v1"
 	k8sV1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	typedBatchV1 "k8s.io/client-go/kubernetes/typed/batch/v1"
 	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
@@ -139,6 +142,7 @@ func TestRequestQueueCreatingManyPod(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -163,6 +167,7 @@ func TestRequestQueueCreatingAndDeletingManyPod(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -189,6 +194,7 @@ func TestRequestQueueCreatingThenDeletingManyPods(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -222,6 +228,7 @@ func TestRequestQueueCreatingAndDeletingManyPodWithDelay(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -251,6 +258,7 @@ func TestRequestQueueCreationCancelled(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -290,6 +298,7 @@ func TestRequestQueueCreationFailed(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -326,6 +335,7 @@ func TestRequestQueueDeletionFailed(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -357,3 +367,42 @@ func TestRequestQueueDeletionFailed(t *testing.T) {
 	wg.Wait()
 	assert.Equal(t, deleteFailed, true)
 }
+
+func TestRequestQueueCreatingAndDeletingJobs(t *testing.T) {
+	configMapInterface := &mockConfigMapInterface{configMaps: make(map[string]*k8sV1.ConfigMap)}
+	jobInterface := fake.NewSimpleClientset().BatchV1().Jobs("default")
+
+	failures := make(chan resourcesRequestFailure, 64)
+	k8sRequestQueue := startRequestQueue(
+		map[string]typedV1.PodInterface{},
+		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		map[string]typedBatchV1.JobInterface{"default": jobInterface},
+		failures,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runDefaultErrorHandler(ctx, failures)
+
+	names := []string{"det-1-1-1", "det-1-1-2", "det-1-1-3"}
+	for _, name := range names {
+		meta := metaV1.ObjectMeta{Name: name, Namespace: "default"}
+		k8sRequestQueue.createKubernetesJob(
+			&batchV1.Job{ObjectMeta: meta}, &k8sV1.ConfigMap{ObjectMeta: meta}, name+"-0")
+	}
+	waitForPendingRequestToFinish(k8sRequestQueue)
+
+	jobs, err := jobInterface.List(ctx, metaV1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(jobs.Items), len(names))
+	assert.Equal(t, len(configMapInterface.configMaps), len(names))
+
+	for _, name := range names {
+		k8sRequestQueue.deleteKubernetesJob("default", name, name+"-0")
+	}
+	waitForPendingRequestToFinish(k8sRequestQueue)
+
+	jobs, err = jobInterface.List(ctx, metaV1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(jobs.Items), 0)
+}
@@ -7,12 +7,14 @@ import (
 	"github.com/sirupsen/logrus"
 
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typedBatchV1 "k8s.io/client-go/kubernetes/typed/batch/v1"
 	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
 type requestProcessingWorker struct {
 	podInterfaces       map[string]typedV1.PodInterface
 	configMapInterfaces map[string]typedV1.ConfigMapInterface
+	jobInterfaces       map[string]typedBatchV1.JobInterface
 	failures            chan<- resourcesRequestFailure
 	syslog              *logrus.Entry
 }
@@ -22,6 +24,7 @@ type readyCallbackFunc func(createRef requestID)
 func startRequestProcessingWorker(
 	podInterfaces map[string]typedV1.PodInterface,
 	configMapInterfaces map[string]typedV1.ConfigMapInterface,
+	jobInterfaces map[string]typedBatchV1.JobInterface,
 	id string,
 	in <-chan interface{},
 	ready readyCallbackFunc,
@@ -31,6 +34,7 @@ func startRequestProcessingWorker(
 	r := &requestProcessingWorker{
 		podInterfaces:       podInterfaces,
 		configMapInterfaces: configMapInterfaces,
+		jobInterfaces:       jobInterfaces,
 		failures:            failures,
 		syslog:              syslog,
 	}
@@ -60,15 +64,29 @@ func (r *requestProcessingWorker) receiveCreateKubernetesResources(
 	msg createKubernetesResources,
 ) {
 	r.syslog.Debugf("creating configMap with spec %v", msg.configMapSpec)
-	configMap, err := r.configMapInterfaces[msg.podSpec.Namespace].Create(
+	configMap, err := r.configMapInterfaces[msg.configMapSpec.Namespace].Create(
 		context.TODO(), msg.configMapSpec, metaV1.CreateOptions{})
 	if err != nil {
 		r.syslog.WithError(err).Errorf("error creating configMap %s", msg.configMapSpec.Name)
-		r.failures <- resourceCreationFailed{podName: msg.podSpec.Name, err: err}
+		r.failures <- resourceCreationFailed{podName: msg.handlerName(), err: err}
 		return
 	}
 	r.syslog.Infof("created configMap %s", configMap.Name)
 
+	if msg.jobSpec != nil {
+		r.syslog.Debugf("launching job with spec %v", msg.jobSpec)
+		job, err := r.jobInterfaces[msg.jobSpec.Namespace].Create(
+			context.TODO(), msg.jobSpec, metaV1.CreateOptions{},
+		)
+		if err != nil {
+			r.syslog.WithError(err).Errorf("error creating job %s", msg.jobSpec.Name)
+			r.failures <- resourceCreationFailed{podName: msg.handlerName(), err: err}
+			return
+		}
+		r.syslog.Infof("created job %s", job.Name)
+		return
+	}
+
 	r.syslog.Debugf("launching pod with spec %v", msg.podSpec)
 	pod, err := r.podInterfaces[msg.podSpec.Namespace].Create(
 		context.TODO(), msg.podSpec, metaV1.CreateOptions{},
@@ -99,6 +117,21 @@ func (r *requestProcessingWorker) receiveDeleteKubernetesResources(
 		}
 	}
 
+	if len(msg.jobName) > 0 {
+		// Deleting the job in the background also deletes its pods.
+		propagation := metaV1.DeletePropagationBackground
+		err = r.jobInterfaces[msg.namespace].Delete(
+			context.TODO(), msg.jobName, metaV1.DeleteOptions{
+				GracePeriodSeconds: &gracePeriod,
+				PropagationPolicy:  &propagation,
+			})
+		if err != nil {
+			r.syslog.WithError(err).Errorf("failed to delete job %s", msg.jobName)
+		} else {
+			r.syslog.Infof("deleted job %s", msg.jobName)
+		}
+	}
+
 	if len(msg.configMapName) > 0 {
 		errDeletingConfigMap := r.configMapInterfaces[msg.namespace].Delete(
 			context.TODO(), msg.configMapName,
@@ -114,6 +147,6 @@ func (r *requestProcessingWorker) receiveDeleteKubernetesResources(
 	// It is possible that the creator of the message is no longer around.
 	// However this should have no impact on correctness.
 	if err != nil {
-		r.failures <- resourceDeletionFailed{podName: msg.podName, err: err}
+		r.failures <- resourceDeletionFailed{podName: msg.handlerName(), err: err}
 	}
 }
@@ -2,6 +2,7 @@ package kubernetesrm
 
 import (
 	"fmt"
+	"strconv"
 	"sync"
 
 	"github.com/google/uuid"
@@ -152,7 +153,7 @@ func (k *kubernetesResourcePool) GetJobQStats(msg sproto.GetJobQStats) *jobv1.Qu
 	defer k.mu.Unlock()
 	k.reschedule = true
 
-	return tasklist.JobStats(k.reqList)
+	return k.queueStats()
 }
 
 func (k *kubernetesResourcePool) GetJobQStatsAPI(msg *apiv1.GetJobQueueStatsRequest) *apiv1.GetJobQueueStatsResponse {
@@ -164,12 +165,18 @@ func (k *kubernetesResourcePool) GetJobQStatsAPI(msg *apiv1.GetJobQueueStatsRequ
 		Results: make([]*apiv1.RPQueueStat, 0),
 	}
 	resp.Results = append(resp.Results, &apiv1.RPQueueStat{
-		Stats:        tasklist.JobStats(k.reqList),
+		Stats:        k.queueStats(),
 		ResourcePool: k.poolConfig.PoolName,
 	})
 	return resp
 }
 
+func (k *kubernetesResourcePool) queueStats() *jobv1.QueueStats {
+	stats := tasklist.JobStats(k.reqList)
+	stats.SlotQuotas = tasklist.NewSlotQuotas(k.poolConfig.Quotas, k.reqList).Usage()
+	return stats
+}
+
 func (k *kubernetesResourcePool) SetGroupWeight(msg sproto.SetGroupWeight) error {
 	k.mu.Lock()
 	defer k.mu.Unlock()
@@ -461,9 +468,35 @@ func (k *kubernetesResourcePool) jobQInfo() map[model.JobID]*sproto.RMJobInfo {
 	reqs := tasklist.SortTasksWithPosition(k.reqList, k.groups, k.queuePositions, true)
 	jobQInfo := tasklist.ReduceToJobQInfo(reqs)
 	correctedJobQInfo := k.correctJobQInfo(reqs, jobQInfo)
+	k.applyClusterQueuePositions(reqs, correctedJobQInfo)
 	return correctedJobQInfo
 }
 
+// applyClusterQueuePositions reports jobs waiting for admission by a cluster-wide queue as
+// queued behind the workloads ahead of them in that queue.
+func (k *kubernetesResourcePool) applyClusterQueuePositions(
+	reqs []*sproto.AllocateRequest,
+	q map[model.JobID]*sproto.RMJobInfo,
+) {
+	queue := k.podsService.queue
+	if queue == nil {
+		return
+	}
+
+	for _, req := range reqs {
+		info, ok := q[req.JobID]
+		if !ok {
+			continue
+		}
+		w, ok := queue.workload(podGroupName(req.AllocationID))
+		if !ok || w.admitted {
+			continue
+		}
+		info.State = sproto.SchedulingStateQueued
+		info.JobsAhead = w.position
+	}
+}
+
 func (k *kubernetesResourcePool) receiveSetAllocationName(
 	msg sproto.SetAllocationName,
 ) {
@@ -565,12 +598,19 @@ func (k *kubernetesResourcePool) assignResources(
 func (k *kubernetesResourcePool) createResources(
 	req *sproto.AllocateRequest, slotsPerPod, numPods int,
 ) []*k8sPodResources {
+	// The pods of an allocation launched as a job tell their containers apart by their completion
+	// index, so the IDs of their containers share a prefix and end in it.
+	jobID := cproto.NewID()
 	var resources []*k8sPodResources
 	for pod := 0; pod < numPods; pod++ {
+		containerID := cproto.NewID()
+		if k.podsService.launchAsJobs {
+			containerID = jobContainerID(jobID, strconv.Itoa(pod))
+		}
 		resources = append(resources, &k8sPodResources{
 			req:             req,
 			podsService:     k.podsService,
-			containerID:     cproto.NewID(),
+			containerID:     containerID,
 			slots:           slotsPerPod,
 			group:           k.groups[req.JobID],
 			initialPosition: k.queuePositions[k.allocationIDToJobID[req.AllocationID]],
@@ -662,6 +702,7 @@ func (k *kubernetesResourcePool) getOrCreateGroup(jobID model.JobID) *tasklist.G
 }
 
 func (k *kubernetesResourcePool) schedulePendingTasks() {
+	quotas := tasklist.NewSlotQuotas(k.poolConfig.Quotas, k.reqList)
 	for it := k.reqList.Iterator(); it.Next(); {
 		req := it.Value()
 		group := k.groups[req.JobID]
@@ -675,6 +716,9 @@ func (k *kubernetesResourcePool) schedulePendingTasks() {
 					continue
 				}
 			}
+			if !quotas.Admit(req) {
+				continue
+			}
 			k.assignResources(req)
 		}
 	}
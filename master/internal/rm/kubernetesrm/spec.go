@@ -291,6 +291,23 @@ func addNodeSelectorRequirement(
 	}
 }
 
+// configureQueueIntegration submits the pod to the cluster-wide queue, if one is configured.
+// GC tasks are system tasks and bypass the queue.
+func (p *pod) configureQueueIntegration(newPod *k8sV1.Pod) {
+	if p.queue == nil || p.submissionInfo.taskSpec.Description == gcTask {
+		return
+	}
+	p.queue.configurePod(newPod, podGroupName(p.allocationID), p.queueName, p.numPods())
+}
+
+// numPods returns the number of pods the allocation of the pod is split across.
+func (p *pod) numPods() int {
+	if p.req == nil || p.slots == 0 {
+		return 1
+	}
+	return max(p.req.SlotsNeeded/p.slots, 1)
+}
+
 func (p *pod) configureCoscheduler(newPod *k8sV1.Pod, scheduler string) {
 	if newPod.Spec.SchedulerName != scheduler {
 		return
@@ -364,6 +381,7 @@ func (p *pod) configurePodSpec(
 	podSpec.ObjectMeta.Labels[determinedLabel] = p.submissionInfo.taskSpec.AllocationID
 
 	p.modifyPodSpec(podSpec, scheduler)
+	p.configureQueueIntegration(podSpec)
 
 	addNodeDisabledAffinityToPodSpec(podSpec, clusterIDNodeLabel())
 	addDisallowedNodesToPodSpec(p.req, podSpec)
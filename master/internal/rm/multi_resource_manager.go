@@ -0,0 +1,374 @@
+package rm
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/rm/rmerrors"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/command"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
+)
+
+// MultiResourceManager dispatches requests to one of several resource managers by resource pool,
+// so that agent and Kubernetes resource pools can be served by one master and one job queue.
+// Requests without a resource pool go to the primary resource manager, which owns the default
+// pools; requests about agents go to the resource manager that knows the agent.
+type MultiResourceManager struct {
+	// rms holds every resource manager, the primary one first.
+	rms []ResourceManager
+	// poolRMs maps the name of each resource pool to the resource manager that manages it.
+	poolRMs map[string]ResourceManager
+}
+
+// NewMultiResourceManager returns a resource manager that dispatches to the given resource
+// managers, the primary one first, where poolRMs maps each resource pool to its resource manager.
+func NewMultiResourceManager(
+	rms []ResourceManager, poolRMs map[string]ResourceManager,
+) *MultiResourceManager {
+	return &MultiResourceManager{rms: rms, poolRMs: poolRMs}
+}
+
+// rmForPool returns the resource manager of the named pool. Unknown pools, including the empty
+// name, go to the primary resource manager, which reports them as it would on its own.
+func (m *MultiResourceManager) rmForPool(name string) ResourceManager {
+	if rm, ok := m.poolRMs[name]; ok {
+		return rm
+	}
+	return m.rms[0]
+}
+
+// forAgent calls fn with each resource manager in turn until one of them knows the agent.
+func forAgent[T any](m *MultiResourceManager, fn func(ResourceManager) (T, error)) (T, error) {
+	var resp T
+	var err error
+	for _, rm := range m.rms {
+		resp, err = fn(rm)
+		if !isUnknownAgent(err) {
+			return resp, err
+		}
+	}
+	return resp, err
+}
+
+func isUnknownAgent(err error) bool {
+	return status.Code(err) == codes.NotFound || errors.Is(err, rmerrors.ErrNotSupported)
+}
+
+// GetAllocationSummary implements rm.ResourceManager.
+func (m *MultiResourceManager) GetAllocationSummary(
+	msg sproto.GetAllocationSummary,
+) (*sproto.AllocationSummary, error) {
+	var err error
+	for _, rm := range m.rms {
+		var summary *sproto.AllocationSummary
+		if summary, err = rm.GetAllocationSummary(msg); err == nil {
+			return summary, nil
+		}
+	}
+	return nil, err
+}
+
+// GetAllocationSummaries implements rm.ResourceManager.
+func (m *MultiResourceManager) GetAllocationSummaries(
+	msg sproto.GetAllocationSummaries,
+) (map[model.AllocationID]sproto.AllocationSummary, error) {
+	summaries := make(map[model.AllocationID]sproto.AllocationSummary)
+	for _, rm := range m.rms {
+		rmSummaries, err := rm.GetAllocationSummaries(msg)
+		if err != nil {
+			return nil, err
+		}
+		for id, summary := range rmSummaries {
+			summaries[id] = summary
+		}
+	}
+	return summaries, nil
+}
+
+// SetAllocationName implements rm.ResourceManager.
+func (m *MultiResourceManager) SetAllocationName(msg sproto.SetAllocationName) {
+	m.rmForPool(msg.ResourcePool).SetAllocationName(msg)
+}
+
+// Allocate implements rm.ResourceManager.
+func (m *MultiResourceManager) Allocate(msg sproto.AllocateRequest) (*sproto.ResourcesSubscription, error) {
+	return m.rmForPool(msg.ResourcePool).Allocate(msg)
+}
+
+// Release implements rm.ResourceManager.
+func (m *MultiResourceManager) Release(msg sproto.ResourcesReleased) {
+	m.rmForPool(msg.ResourcePool).Release(msg)
+}
+
+// ValidateCommandResources implements rm.ResourceManager.
+func (m *MultiResourceManager) ValidateCommandResources(
+	msg sproto.ValidateCommandResourcesRequest,
+) (sproto.ValidateCommandResourcesResponse, error) {
+	return m.rmForPool(msg.ResourcePool).ValidateCommandResources(msg)
+}
+
+// ValidateResources implements rm.ResourceManager.
+func (m *MultiResourceManager) ValidateResources(name string, slots int, command bool) error {
+	return m.rmForPool(name).ValidateResources(name, slots, command)
+}
+
+// DeleteJob implements rm.ResourceManager. Jobs are not tied to a resource pool, so every resource
+// manager cleans up after the job.
+func (m *MultiResourceManager) DeleteJob(msg sproto.DeleteJob) (sproto.DeleteJobResponse, error) {
+	resps := make([]sproto.DeleteJobResponse, 0, len(m.rms))
+	for _, rm := range m.rms {
+		resp, err := rm.DeleteJob(msg)
+		if err != nil {
+			return sproto.DeleteJobResponse{}, err
+		}
+		resps = append(resps, resp)
+	}
+
+	respC := make(chan error, 1)
+	go func() {
+		var errs []error
+		for _, resp := range resps {
+			errs = append(errs, <-resp.Err)
+		}
+		respC <- errors.Join(errs...)
+	}()
+	return sproto.DeleteJobResponse{Err: respC}, nil
+}
+
+// NotifyContainerRunning implements rm.ResourceManager.
+func (m *MultiResourceManager) NotifyContainerRunning(msg sproto.NotifyContainerRunning) error {
+	return m.forEachSupported(func(rm ResourceManager) error {
+		return rm.NotifyContainerRunning(msg)
+	})
+}
+
+// SetGroupMaxSlots implements rm.ResourceManager.
+func (m *MultiResourceManager) SetGroupMaxSlots(msg sproto.SetGroupMaxSlots) {
+	m.rmForPool(msg.ResourcePool).SetGroupMaxSlots(msg)
+}
+
+// SetGroupWeight implements rm.ResourceManager.
+func (m *MultiResourceManager) SetGroupWeight(msg sproto.SetGroupWeight) error {
+	return m.rmForPool(msg.ResourcePool).SetGroupWeight(msg)
+}
+
+// SetGroupPriority implements rm.ResourceManager.
+func (m *MultiResourceManager) SetGroupPriority(msg sproto.SetGroupPriority) error {
+	return m.rmForPool(msg.ResourcePool).SetGroupPriority(msg)
+}
+
+// ExternalPreemptionPending implements rm.ResourceManager.
+func (m *MultiResourceManager) ExternalPreemptionPending(msg sproto.PendingPreemption) error {
+	return m.forEachSupported(func(rm ResourceManager) error {
+		return rm.ExternalPreemptionPending(msg)
+	})
+}
+
+// forEachSupported calls fn with every resource manager, returning rmerrors.ErrNotSupported only
+// if none of them support the operation.
+func (m *MultiResourceManager) forEachSupported(fn func(ResourceManager) error) error {
+	supported := false
+	var errs []error
+	for _, rm := range m.rms {
+		err := fn(rm)
+		if errors.Is(err, rmerrors.ErrNotSupported) {
+			continue
+		}
+		supported = true
+		errs = append(errs, err)
+	}
+	if !supported {
+		return rmerrors.ErrNotSupported
+	}
+	return errors.Join(errs...)
+}
+
+// IsReattachableOnlyAfterStarted implements rm.ResourceManager. It does not know the pool of the
+// allocation, so it answers for the most restrictive resource manager.
+func (m *MultiResourceManager) IsReattachableOnlyAfterStarted() bool {
+	for _, rm := range m.rms {
+		if rm.IsReattachableOnlyAfterStarted() {
+			return true
+		}
+	}
+	return false
+}
+
+// GetResourcePools implements rm.ResourceManager.
+func (m *MultiResourceManager) GetResourcePools(
+	msg *apiv1.GetResourcePoolsRequest,
+) (*apiv1.GetResourcePoolsResponse, error) {
+	resp := &apiv1.GetResourcePoolsResponse{}
+	for _, rm := range m.rms {
+		rmResp, err := rm.GetResourcePools(msg)
+		if err != nil {
+			return nil, err
+		}
+		resp.ResourcePools = append(resp.ResourcePools, rmResp.ResourcePools...)
+	}
+	return resp, nil
+}
+
+// GetDefaultComputeResourcePool implements rm.ResourceManager.
+func (m *MultiResourceManager) GetDefaultComputeResourcePool(
+	msg sproto.GetDefaultComputeResourcePoolRequest,
+) (sproto.GetDefaultComputeResourcePoolResponse, error) {
+	return m.rms[0].GetDefaultComputeResourcePool(msg)
+}
+
+// GetDefaultAuxResourcePool implements rm.ResourceManager.
+func (m *MultiResourceManager) GetDefaultAuxResourcePool(
+	msg sproto.GetDefaultAuxResourcePoolRequest,
+) (sproto.GetDefaultAuxResourcePoolResponse, error) {
+	return m.rms[0].GetDefaultAuxResourcePool(msg)
+}
+
+// ValidateResourcePool implements rm.ResourceManager.
+func (m *MultiResourceManager) ValidateResourcePool(name string) error {
+	return m.rmForPool(name).ValidateResourcePool(name)
+}
+
+// ResolveResourcePool implements rm.ResourceManager. The default pools of a workspace may belong
+// to any resource manager, so they are looked up before dispatching.
+func (m *MultiResourceManager) ResolveResourcePool(name string, workspaceID, slots int) (string, error) {
+	if name == "" {
+		defaultComputePool, defaultAuxPool, err := db.GetDefaultPoolsForWorkspace(
+			context.TODO(), workspaceID)
+		if err != nil {
+			return "", err
+		}
+		if slots == 0 {
+			name = defaultAuxPool
+		} else {
+			name = defaultComputePool
+		}
+	}
+	return m.rmForPool(name).ResolveResourcePool(name, workspaceID, slots)
+}
+
+// ValidateResourcePoolAvailability implements rm.ResourceManager.
+func (m *MultiResourceManager) ValidateResourcePoolAvailability(
+	v *sproto.ValidateResourcePoolAvailabilityRequest,
+) ([]command.LaunchWarning, error) {
+	return m.rmForPool(v.Name).ValidateResourcePoolAvailability(v)
+}
+
+// TaskContainerDefaults implements rm.ResourceManager.
+func (m *MultiResourceManager) TaskContainerDefaults(
+	resourcePoolName string,
+	fallbackConfig model.TaskContainerDefaultsConfig,
+) (model.TaskContainerDefaultsConfig, error) {
+	return m.rmForPool(resourcePoolName).TaskContainerDefaults(resourcePoolName, fallbackConfig)
+}
+
+// GetJobQ implements rm.ResourceManager.
+func (m *MultiResourceManager) GetJobQ(msg sproto.GetJobQ) (map[model.JobID]*sproto.RMJobInfo, error) {
+	return m.rmForPool(msg.ResourcePool).GetJobQ(msg)
+}
+
+// GetJobQueueStatsRequest implements rm.ResourceManager.
+func (m *MultiResourceManager) GetJobQueueStatsRequest(
+	msg *apiv1.GetJobQueueStatsRequest,
+) (*apiv1.GetJobQueueStatsResponse, error) {
+	resp := &apiv1.GetJobQueueStatsResponse{Results: make([]*apiv1.RPQueueStat, 0)}
+	for _, rm := range m.rms {
+		rmResp, err := rm.GetJobQueueStatsRequest(msg)
+		if err != nil {
+			return nil, err
+		}
+		resp.Results = append(resp.Results, rmResp.Results...)
+	}
+	return resp, nil
+}
+
+// MoveJob implements rm.ResourceManager.
+func (m *MultiResourceManager) MoveJob(msg sproto.MoveJob) error {
+	return m.rmForPool(msg.ResourcePool).MoveJob(msg)
+}
+
+// RecoverJobPosition implements rm.ResourceManager.
+func (m *MultiResourceManager) RecoverJobPosition(msg sproto.RecoverJobPosition) {
+	m.rmForPool(msg.ResourcePool).RecoverJobPosition(msg)
+}
+
+// GetExternalJobs implements rm.ResourceManager.
+func (m *MultiResourceManager) GetExternalJobs(msg sproto.GetExternalJobs) ([]*jobv1.Job, error) {
+	return m.rmForPool(msg.ResourcePool).GetExternalJobs(msg)
+}
+
+// GetAgents implements rm.ResourceManager.
+func (m *MultiResourceManager) GetAgents(msg *apiv1.GetAgentsRequest) (*apiv1.GetAgentsResponse, error) {
+	resp := &apiv1.GetAgentsResponse{}
+	for _, rm := range m.rms {
+		rmResp, err := rm.GetAgents(msg)
+		if err != nil {
+			return nil, err
+		}
+		resp.Agents = append(resp.Agents, rmResp.Agents...)
+	}
+	return resp, nil
+}
+
+// GetAgent implements rm.ResourceManager.
+func (m *MultiResourceManager) GetAgent(msg *apiv1.GetAgentRequest) (*apiv1.GetAgentResponse, error) {
+	return forAgent(m, func(rm ResourceManager) (*apiv1.GetAgentResponse, error) {
+		return rm.GetAgent(msg)
+	})
+}
+
+// EnableAgent implements rm.ResourceManager.
+func (m *MultiResourceManager) EnableAgent(
+	msg *apiv1.EnableAgentRequest,
+) (*apiv1.EnableAgentResponse, error) {
+	return forAgent(m, func(rm ResourceManager) (*apiv1.EnableAgentResponse, error) {
+		return rm.EnableAgent(msg)
+	})
+}
+
+// DisableAgent implements rm.ResourceManager.
+func (m *MultiResourceManager) DisableAgent(
+	msg *apiv1.DisableAgentRequest,
+) (*apiv1.DisableAgentResponse, error) {
+	return forAgent(m, func(rm ResourceManager) (*apiv1.DisableAgentResponse, error) {
+		return rm.DisableAgent(msg)
+	})
+}
+
+// GetSlots implements rm.ResourceManager.
+func (m *MultiResourceManager) GetSlots(msg *apiv1.GetSlotsRequest) (*apiv1.GetSlotsResponse, error) {
+	return forAgent(m, func(rm ResourceManager) (*apiv1.GetSlotsResponse, error) {
+		return rm.GetSlots(msg)
+	})
+}
+
+// GetSlot implements rm.ResourceManager.
+func (m *MultiResourceManager) GetSlot(msg *apiv1.GetSlotRequest) (*apiv1.GetSlotResponse, error) {
+	return forAgent(m, func(rm ResourceManager) (*apiv1.GetSlotResponse, error) {
+		return rm.GetSlot(msg)
+	})
+}
+
+// EnableSlot implements rm.ResourceManager.
+func (m *MultiResourceManager) EnableSlot(msg *apiv1.EnableSlotRequest) (*apiv1.EnableSlotResponse, error) {
+	return forAgent(m, func(rm ResourceManager) (*apiv1.EnableSlotResponse, error) {
+		return rm.EnableSlot(msg)
+	})
+}
+
+// DisableSlot implements rm.ResourceManager.
+func (m *MultiResourceManager) DisableSlot(
+	msg *apiv1.DisableSlotRequest,
+) (*apiv1.DisableSlotResponse, error) {
+	return forAgent(m, func(rm ResourceManager) (*apiv1.DisableSlotResponse, error) {
+		return rm.DisableSlot(msg)
+	})
+}
+
+var _ ResourceManager = (*MultiResourceManager)(nil)
@@ -0,0 +1,132 @@
+//nolint:exhaustruct
+package rm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/rm/rmerrors"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/proto/pkg/agentv1"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+// fakeRM implements the parts of ResourceManager the tests exercise; calling anything else
+// panics on the nil embedded interface.
+type fakeRM struct {
+	ResourceManager
+
+	name       string
+	agents     []string
+	reattach   bool
+	allocated  []sproto.AllocateRequest
+	deleteErr  error
+	notifyErr  error
+	preemptErr error
+}
+
+func (f *fakeRM) Allocate(msg sproto.AllocateRequest) (*sproto.ResourcesSubscription, error) {
+	f.allocated = append(f.allocated, msg)
+	return nil, nil
+}
+
+func (f *fakeRM) GetAgents(*apiv1.GetAgentsRequest) (*apiv1.GetAgentsResponse, error) {
+	resp := &apiv1.GetAgentsResponse{}
+	for _, agent := range f.agents {
+		resp.Agents = append(resp.Agents, &agentv1.Agent{Id: agent})
+	}
+	return resp, nil
+}
+
+func (f *fakeRM) GetAgent(msg *apiv1.GetAgentRequest) (*apiv1.GetAgentResponse, error) {
+	for _, agent := range f.agents {
+		if agent == msg.AgentId {
+			return &apiv1.GetAgentResponse{Agent: &agentv1.Agent{Id: agent, Label: f.name}}, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "agent %s not found", msg.AgentId)
+}
+
+func (f *fakeRM) DeleteJob(sproto.DeleteJob) (sproto.DeleteJobResponse, error) {
+	return sproto.DeleteJobResponseOf(f.deleteErr), nil
+}
+
+func (f *fakeRM) NotifyContainerRunning(sproto.NotifyContainerRunning) error {
+	return f.notifyErr
+}
+
+func (f *fakeRM) ExternalPreemptionPending(sproto.PendingPreemption) error {
+	return f.preemptErr
+}
+
+func (f *fakeRM) IsReattachableOnlyAfterStarted() bool {
+	return f.reattach
+}
+
+func newFakeMultiRM() (*MultiResourceManager, *fakeRM, *fakeRM) {
+	agents := &fakeRM{name: "agent", agents: []string{"gpu-1", "gpu-2"}, reattach: true}
+	k8s := &fakeRM{name: "kubernetes", agents: []string{"node-1"}}
+	m := NewMultiResourceManager(
+		[]ResourceManager{agents, k8s},
+		map[string]ResourceManager{"training": agents, "notebooks": k8s},
+	)
+	return m, agents, k8s
+}
+
+func TestMultiResourceManagerDispatchesByPool(t *testing.T) {
+	m, agents, k8s := newFakeMultiRM()
+
+	for _, pool := range []string{"training", "notebooks", "", "unknown"} {
+		_, err := m.Allocate(sproto.AllocateRequest{ResourcePool: pool})
+		require.NoError(t, err)
+	}
+	// Requests without a known pool go to the primary resource manager, which owns the defaults.
+	require.Equal(t, []sproto.AllocateRequest{
+		{ResourcePool: "training"}, {ResourcePool: ""}, {ResourcePool: "unknown"},
+	}, agents.allocated)
+	require.Equal(t, []sproto.AllocateRequest{{ResourcePool: "notebooks"}}, k8s.allocated)
+}
+
+func TestMultiResourceManagerAgents(t *testing.T) {
+	m, _, _ := newFakeMultiRM()
+
+	resp, err := m.GetAgents(&apiv1.GetAgentsRequest{})
+	require.NoError(t, err)
+	var ids []string
+	for _, agent := range resp.Agents {
+		ids = append(ids, agent.Id)
+	}
+	require.Equal(t, []string{"gpu-1", "gpu-2", "node-1"}, ids)
+
+	agent, err := m.GetAgent(&apiv1.GetAgentRequest{AgentId: "gpu-2"})
+	require.NoError(t, err)
+	require.Equal(t, "agent", agent.Agent.Label)
+
+	agent, err = m.GetAgent(&apiv1.GetAgentRequest{AgentId: "node-1"})
+	require.NoError(t, err)
+	require.Equal(t, "kubernetes", agent.Agent.Label)
+
+	_, err = m.GetAgent(&apiv1.GetAgentRequest{AgentId: "missing"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestMultiResourceManagerBroadcasts(t *testing.T) {
+	m, agents, k8s := newFakeMultiRM()
+	require.True(t, m.IsReattachableOnlyAfterStarted())
+
+	k8s.deleteErr = errors.New("cleanup failed")
+	resp, err := m.DeleteJob(sproto.DeleteJob{JobID: "job"})
+	require.NoError(t, err)
+	require.ErrorContains(t, <-resp.Err, "cleanup failed")
+
+	agents.notifyErr = rmerrors.ErrNotSupported
+	k8s.notifyErr = rmerrors.ErrNotSupported
+	require.ErrorIs(t, m.NotifyContainerRunning(sproto.NotifyContainerRunning{}), rmerrors.ErrNotSupported)
+
+	agents.preemptErr = rmerrors.ErrNotSupported
+	require.NoError(t, m.ExternalPreemptionPending(sproto.PendingPreemption{}))
+}
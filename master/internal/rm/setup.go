@@ -21,6 +21,30 @@ func New(
 	taskContainerDefaults *model.TaskContainerDefaultsConfig,
 	opts *aproto.MasterSetAgentOptions,
 	cert *tls.Certificate,
+) ResourceManager {
+	if len(config.AdditionalResourceManagers) == 0 {
+		return newResourceManager(db, echo, config, taskContainerDefaults, opts, cert)
+	}
+
+	rms := make([]ResourceManager, 0, len(config.AdditionalResourceManagers)+1)
+	poolRMs := make(map[string]ResourceManager)
+	for _, rmConfig := range config.ResourceManagers() {
+		rm := newResourceManager(db, echo, rmConfig.ResourceConfig(), taskContainerDefaults, opts, cert)
+		rms = append(rms, rm)
+		for _, pool := range rmConfig.ResourcePools {
+			poolRMs[pool.PoolName] = rm
+		}
+	}
+	return NewMultiResourceManager(rms, poolRMs)
+}
+
+func newResourceManager(
+	db *db.PgDB,
+	echo *echo.Echo,
+	config *config.ResourceConfig,
+	taskContainerDefaults *model.TaskContainerDefaultsConfig,
+	opts *aproto.MasterSetAgentOptions,
+	cert *tls.Certificate,
 ) ResourceManager {
 	switch {
 	case config.ResourceManager.AgentRM != nil:
@@ -0,0 +1,147 @@
+package tasklist
+
+import (
+	"sort"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
+)
+
+// SlotQuotas tracks the slots used by the tasks of each user and workspace in a resource pool
+// against the pool's slot quotas.
+type SlotQuotas struct {
+	config     *config.SlotQuotaConfig
+	users      map[string]int
+	workspaces map[string]int
+}
+
+// NewSlotQuotas counts the slots used by the scheduled tasks in the task list. A nil config
+// means the pool has no quotas.
+func NewSlotQuotas(conf *config.SlotQuotaConfig, taskList *TaskList) *SlotQuotas {
+	q := &SlotQuotas{
+		config:     conf,
+		users:      make(map[string]int),
+		workspaces: make(map[string]int),
+	}
+	for it := taskList.Iterator(); it.Next(); {
+		req := it.Value()
+		if taskList.IsScheduled(req.AllocationID) {
+			q.add(req)
+		}
+	}
+	return q
+}
+
+// Admit returns whether the task can be scheduled without exceeding the quotas of its user or
+// workspace and, if so, counts its slots against them.
+func (q *SlotQuotas) Admit(req *sproto.AllocateRequest) bool {
+	if req.SlotsNeeded == 0 {
+		return true
+	}
+	if req.User != "" {
+		if quota := q.config.UserQuota(req.User); quota != nil &&
+			q.users[req.User]+req.SlotsNeeded > *quota {
+			return false
+		}
+	}
+	if req.Workspace != "" {
+		if quota := q.config.WorkspaceQuota(req.Workspace); quota != nil &&
+			q.workspaces[req.Workspace]+req.SlotsNeeded > *quota {
+			return false
+		}
+	}
+	q.add(req)
+	return true
+}
+
+func (q *SlotQuotas) add(req *sproto.AllocateRequest) {
+	if req.User != "" {
+		q.users[req.User] += req.SlotsNeeded
+	}
+	if req.Workspace != "" {
+		q.workspaces[req.Workspace] += req.SlotsNeeded
+	}
+}
+
+// Usage reports the slots used by every user and workspace that has a quota in the pool.
+func (q *SlotQuotas) Usage() []*jobv1.SlotQuotaUsage {
+	if q.config == nil {
+		return nil
+	}
+
+	usage := make([]*jobv1.SlotQuotaUsage, 0)
+	for _, user := range quotaNames(q.users, q.config.Users) {
+		if quota := q.config.UserQuota(user); quota != nil {
+			usage = append(usage, &jobv1.SlotQuotaUsage{
+				User:      ptrs.Ptr(user),
+				SlotsUsed: int32(q.users[user]),
+				MaxSlots:  int32(*quota),
+			})
+		}
+	}
+	for _, workspace := range quotaNames(q.workspaces, q.config.Workspaces) {
+		if quota := q.config.WorkspaceQuota(workspace); quota != nil {
+			usage = append(usage, &jobv1.SlotQuotaUsage{
+				Workspace: ptrs.Ptr(workspace),
+				SlotsUsed: int32(q.workspaces[workspace]),
+				MaxSlots:  int32(*quota),
+			})
+		}
+	}
+	return usage
+}
+
+// quotaNames returns the sorted names of everyone using slots or with an explicit quota.
+func quotaNames(used map[string]int, configured map[string]int) []string {
+	names := make([]string, 0, len(used)+len(configured))
+	for name := range used {
+		names = append(names, name)
+	}
+	for name := range configured {
+		if _, ok := used[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithinQuotas returns the task list without the pending tasks that cannot be scheduled without
+// exceeding the slot quotas. Pending tasks are admitted in the given order, or in the order of the
+// task list if it is nil. If conf is nil, the task list is returned as is.
+func WithinQuotas(
+	taskList *TaskList,
+	conf *config.SlotQuotaConfig,
+	order []*sproto.AllocateRequest,
+) *TaskList {
+	if conf == nil {
+		return taskList
+	}
+	if order == nil {
+		for it := taskList.Iterator(); it.Next(); {
+			order = append(order, it.Value())
+		}
+	}
+
+	quotas := NewSlotQuotas(conf, taskList)
+	admitted := make(map[*sproto.AllocateRequest]bool)
+	for _, req := range order {
+		if !taskList.IsScheduled(req.AllocationID) && quotas.Admit(req) {
+			admitted[req] = true
+		}
+	}
+
+	filtered := New()
+	for it := taskList.Iterator(); it.Next(); {
+		req := it.Value()
+		if allocated := taskList.Allocation(req.AllocationID); allocated != nil {
+			filtered.AddTask(req)
+			filtered.AddAllocationRaw(req.AllocationID, allocated)
+		} else if admitted[req] {
+			filtered.AddTask(req)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,71 @@
+package tasklist
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/testing/protocmp"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
+)
+
+func TestWithinQuotas(t *testing.T) {
+	now := time.Now()
+	newTask := func(id, user, workspace string, slots int) *sproto.AllocateRequest {
+		now = now.Add(time.Second)
+		return &sproto.AllocateRequest{
+			AllocationID:      model.AllocationID(id),
+			JobID:             model.JobID(id),
+			JobSubmissionTime: now,
+			RequestTime:       now,
+			User:              user,
+			Workspace:         workspace,
+			SlotsNeeded:       slots,
+		}
+	}
+
+	tasks := []*sproto.AllocateRequest{
+		newTask("alice-running", "alice", "research", 4),
+		newTask("alice-over-quota", "alice", "research", 2),
+		newTask("alice-within-quota", "alice", "research", 1),
+		newTask("bob-over-workspace-quota", "bob", "research", 4),
+		newTask("bob-within-quota", "bob", "prod", 4),
+		newTask("carol-zero-slot", "carol", "research", 0),
+		newTask("system", "", "", 8),
+	}
+	taskList := New()
+	for _, task := range tasks {
+		taskList.AddTask(task)
+	}
+	taskList.AddAllocationRaw(tasks[0].AllocationID, &sproto.ResourcesAllocated{
+		ID: tasks[0].AllocationID,
+	})
+
+	conf := &config.SlotQuotaConfig{
+		MaxSlotsPerUser: ptrs.Ptr(8),
+		Users:           map[string]int{"alice": 5},
+		Workspaces:      map[string]int{"research": 6},
+	}
+
+	filtered := WithinQuotas(taskList, conf, nil)
+	var ids []model.AllocationID
+	for it := filtered.Iterator(); it.Next(); {
+		ids = append(ids, it.Value().AllocationID)
+	}
+	assert.DeepEqual(t, ids, []model.AllocationID{
+		"alice-running", "alice-within-quota", "bob-within-quota", "carol-zero-slot", "system",
+	})
+	assert.Assert(t, filtered.IsScheduled("alice-running"))
+
+	assert.Equal(t, WithinQuotas(taskList, nil, nil), taskList)
+
+	assert.DeepEqual(t, NewSlotQuotas(conf, taskList).Usage(), []*jobv1.SlotQuotaUsage{
+		{User: ptrs.Ptr("alice"), SlotsUsed: 4, MaxSlots: 5},
+		{Workspace: ptrs.Ptr("research"), SlotsUsed: 4, MaxSlots: 6},
+	}, protocmp.Transform())
+}
@@ -2,6 +2,7 @@ package sproto
 
 import (
 	"fmt"
+	"maps"
 	"strings"
 
 	"github.com/determined-ai/determined/master/pkg/aproto"
@@ -36,14 +37,29 @@ type AgentSummary struct {
 // ScalingInfo describes the information that is needed for scaling.
 type ScalingInfo struct {
 	DesiredNewInstances int
+	// InstanceTypeDemands are the demands for each instance type of a provisioner that launches
+	// more than one instance type, keyed by the instance type name.
+	InstanceTypeDemands map[string]InstanceTypeDemand
 	Agents              map[string]AgentSummary
 }
 
-// Update updates its desired new instance number and the agent summaries.
-func (s *ScalingInfo) Update(desiredNewInstanceNum int, agents map[string]AgentSummary) bool {
+// InstanceTypeDemand describes the demand of the pending tasks for instances of an instance type.
+type InstanceTypeDemand struct {
+	DesiredNewInstances int
+	// FitsAllTasks is whether every pending task fits on instances of the instance type.
+	FitsAllTasks bool
+}
+
+// Update updates its desired new instance numbers and the agent summaries.
+func (s *ScalingInfo) Update(
+	desiredNewInstanceNum int,
+	instanceTypeDemands map[string]InstanceTypeDemand,
+	agents map[string]AgentSummary,
+) bool {
 	updated := false
 
-	if desiredNewInstanceNum != s.DesiredNewInstances {
+	if desiredNewInstanceNum != s.DesiredNewInstances ||
+		!maps.Equal(instanceTypeDemands, s.InstanceTypeDemands) {
 		updated = true
 	}
 
@@ -60,6 +76,7 @@ func (s *ScalingInfo) Update(desiredNewInstanceNum int, agents map[string]AgentS
 
 	if updated {
 		s.DesiredNewInstances = desiredNewInstanceNum
+		s.InstanceTypeDemands = instanceTypeDemands
 		s.Agents = agents
 	}
 
@@ -33,11 +33,18 @@ type (
 		IsUserVisible bool
 		State         SchedulingState
 		Name          string
+		// User and Workspace are the names of the owner and the workspace of the task, used to
+		// enforce slot quotas. They are empty for tasks started by the system.
+		User      string
+		Workspace string
 
 		// Resource configuration.
 		SlotsNeeded         int
 		ResourcePool        string
 		FittingRequirements FittingRequirements
+		// Images are the canonical references of the container images the task runs, by the type
+		// of device it runs on. Fitting prefers agents that already have the image cached.
+		Images map[device.Type]string
 
 		// Behavioral configuration.
 		Preemptible bool
@@ -45,6 +52,10 @@ type (
 		ProxyPorts  []*ProxyPortConfig
 		Restore     bool
 		ProxyTLS    bool
+		// MaxRuntime is the user-declared upper bound on how long the allocation runs. Schedulers
+		// plan around it and the allocation is terminated once it is exceeded. Nil means the
+		// runtime is unknown.
+		MaxRuntime *time.Duration
 
 		// Logging context of the allocation actor.
 		LogContext logger.Context
@@ -52,6 +63,11 @@ type (
 		BlockedNodes []string
 	}
 
+	// ImageMap is an image configuration with an image per device type.
+	ImageMap interface {
+		For(deviceType device.Type) string
+	}
+
 	// IdleTimeoutConfig configures how idle timeouts should behave.
 	IdleTimeoutConfig struct {
 		ServiceID       string
@@ -116,6 +132,18 @@ type (
 	}
 )
 
+// ImagesByDeviceType returns the canonical references of the images in the image configuration,
+// for use as AllocateRequest.Images.
+func ImagesByDeviceType(images ImageMap) map[device.Type]string {
+	result := make(map[device.Type]string)
+	for _, t := range []device.Type{device.CPU, device.CUDA, device.ROCM} {
+		if image := images.For(t); image != "" {
+			result[t] = aproto.CanonicalImage(image)
+		}
+	}
+	return result
+}
+
 // ResourcesEvent describes a change in status or state of an allocation's resources.
 type ResourcesEvent interface{ ResourcesEvent() }
 
@@ -248,6 +276,7 @@ type (
 		ResourcePool      string
 		Resources         ResourceList
 		JobSubmissionTime time.Time
+		StartTime         time.Time
 		Recovered         bool
 	}
 	// PendingPreemption notifies the task actor that it should release
@@ -305,6 +334,7 @@ func (ra ResourcesAllocated) Clone() *ResourcesAllocated {
 		ResourcePool:      ra.ResourcePool,
 		Resources:         maps.Clone(ra.Resources),
 		JobSubmissionTime: ra.JobSubmissionTime,
+		StartTime:         ra.StartTime,
 		Recovered:         ra.Recovered,
 	}
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/task/tasklogger"
 	"github.com/determined-ai/determined/master/internal/task/taskmodel"
 	"github.com/determined-ai/determined/master/internal/telemetry"
+	"github.com/determined-ai/determined/master/internal/webhooks"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	detLogger "github.com/determined-ai/determined/master/pkg/logger"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -127,6 +128,8 @@ type allocation struct {
 	// We send a kill when we terminate a task forcibly. we terminate forcibly when a container
 	// exits non zero. we don't need to send all these kills, so this exists.
 	killCooldown *time.Time
+	// Marks that we asked the allocation to gracefully terminate, so preemption is reported once.
+	preempted bool
 	// tracks if we have finished termination.
 	exited *AllocationExited
 
@@ -607,6 +610,27 @@ func (a *allocation) resourcesAllocated(msg *sproto.ResourcesAllocated) error {
 		})
 	}
 
+	if maxRuntime := a.req.MaxRuntime; maxRuntime != nil {
+		// Schedulers plan around the declared runtime from the time resources were allocated, so
+		// it is enforced from then, too.
+		start := msg.StartTime
+		if start.IsZero() {
+			start = time.Now()
+		}
+		a.wg.Go(func(ctx context.Context) {
+			t := time.NewTimer(time.Until(start.Add(*maxRuntime)))
+			defer t.Stop()
+
+			select {
+			case <-t.C:
+				a.syslog.Infof("terminating %s after its max_runtime of %s", a.req.Name, *maxRuntime)
+				a.Signal(TerminateAllocation, fmt.Sprintf("exceeded max_runtime of %s", *maxRuntime))
+			case <-ctx.Done():
+				return
+			}
+		})
+	}
+
 	if a.req.Restore {
 		for _, port := range a.model.Ports {
 			portregistry.RestorePort(port)
@@ -917,6 +941,12 @@ func (a *allocation) preempt(reason string) {
 	preemptible.Preempt(a.req.AllocationID.String(), func(ctx context.Context, err error) {
 		a.Signal(KillAllocation, err.Error())
 	})
+
+	if a.preempted {
+		return
+	}
+	a.preempted = true
+	webhooks.ReportAllocationPreempted(a.req.TaskID, a.req.AllocationID, reason)
 }
 
 func (a *allocation) kill(reason string) {
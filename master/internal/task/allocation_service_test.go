@@ -279,6 +279,60 @@ func TestPreemption(t *testing.T) {
 	}
 }
 
+func TestMaxRuntime(t *testing.T) {
+	t.Run("preemptible allocations are preempted", func(t *testing.T) {
+		db, _, id, q, exitFuture := requireStarted(t, func(ar *sproto.AllocateRequest) {
+			ar.Preemptible = true
+			ar.MaxRuntime = ptrs.Ptr(2 * time.Second)
+		})
+		defer requireKilled(t, db, id, q, exitFuture)
+
+		rID, _ := requireAssigned(t, db, id, q)
+		q.Put(&sproto.ResourcesStateChanged{
+			ResourcesID:      rID,
+			ResourcesState:   sproto.Running,
+			ResourcesStarted: &sproto.ResourcesStarted{},
+		})
+		requireState(t, db, id, model.AllocationStateRunning)
+		require.NoError(t, DefaultService.SetReady(context.Background(), id))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		preempted, err := DefaultService.WatchPreemption(ctx, id)
+		require.NoError(t, err)
+		require.True(t, preempted, "allocation was not preempted after its max runtime")
+
+		q.Put(&sproto.ResourcesStateChanged{
+			ResourcesID:      rID,
+			ResourcesState:   sproto.Terminated,
+			ResourcesStopped: &sproto.ResourcesStopped{},
+		})
+		requireTerminated(t, db, id, exitFuture)
+	})
+
+	t.Run("other allocations are killed", func(t *testing.T) {
+		db, _, id, q, exitFuture := requireStarted(t, func(ar *sproto.AllocateRequest) {
+			ar.Preemptible = false
+			ar.MaxRuntime = ptrs.Ptr(500 * time.Millisecond)
+		})
+		defer requireKilled(t, db, id, q, exitFuture)
+
+		rID, r := requireAssigned(t, db, id, q)
+		q.Put(&sproto.ResourcesStateChanged{
+			ResourcesID:      rID,
+			ResourcesState:   sproto.Running,
+			ResourcesStarted: &sproto.ResourcesStarted{},
+		})
+		requireState(t, db, id, model.AllocationStateRunning)
+
+		require.True(t, waitForCondition(5*time.Second, func() bool {
+			return exitFuture.Load() != nil
+		}), "allocation was not killed after its max runtime")
+		requireTerminated(t, db, id, exitFuture)
+		r.AssertCalled(t, "Kill", mock.Anything)
+	})
+}
+
 func TestSignalBeforeLaunch(t *testing.T) {
 	type args struct {
 		sig AllocationSignal
@@ -205,16 +205,7 @@ func (r *rendezvous) info() ([]cAddress, []string, []int32, error) {
 	}
 
 	sort.Slice(caddrs, func(i, j int) bool {
-		a := caddrs[i]
-		b := caddrs[j]
-		switch {
-		case a.ordinal == 0 && b.ordinal != 0:
-			return true
-		case a.ordinal != 0 && b.ordinal == 0:
-			return false
-		default:
-			return a.id < b.id
-		}
+		return caddrs[i].ordinal < caddrs[j].ordinal
 	})
 
 	var raddrs []string
@@ -230,7 +221,11 @@ func (r *rendezvous) info() ([]cAddress, []string, []int32, error) {
 		}
 
 		if len(addrs) == 1 {
-			raddrs = append(raddrs, addrs[0].HostIP)
+			raddr := addrs[0].HostIP
+			if addrs[0].HostName != "" {
+				raddr = addrs[0].HostName
+			}
+			raddrs = append(raddrs, raddr)
 			slots = append(slots, int32(caddr.slots))
 		} else {
 			err = multierror.Append(err, fmt.Errorf(
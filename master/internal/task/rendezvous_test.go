@@ -184,6 +184,35 @@ func TestRendezvousTimeout(t *testing.T) {
 		"some containers are taking a long time")
 }
 
+func TestRendezvousInfoOrder(t *testing.T) {
+	res := mocks.NewResources(t)
+	res.On("Summary").Return(sproto.ResourcesSummary{
+		AgentDevices: map[aproto.ID][]device.Device{},
+	})
+
+	t1 := model.AllocationID(uuid.New().String())
+	rIDs := []sproto.ResourcesID{"c", "a", "b"}
+	resources := resourcesList{}
+	for rank, rID := range rIDs {
+		resources[rID] = &taskmodel.ResourcesWithState{
+			Resources: res,
+			Rank:      rank,
+			Started:   &sproto.ResourcesStarted{Addresses: addressesFromContainerID(rID)},
+		}
+	}
+	// Addresses with a host name are reached through it instead of the host IP.
+	resources["b"].Started.Addresses[0].HostName = "det-1-1-1-2.det-1-1-1.default.svc"
+
+	r := newRendezvous(t1, resources, rendezvousTimeoutDuration)
+	_, raddrs, _, err := r.info()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, raddrs, []string{
+		"c.example.com",
+		"a.example.com",
+		"det-1-1-1-2.det-1-1-1.default.svc",
+	})
+}
+
 func addressesFromContainerID(rID sproto.ResourcesID) []cproto.Address {
 	return []cproto.Address{
 		{
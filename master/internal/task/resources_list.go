@@ -12,19 +12,9 @@ import (
 type resourcesList map[sproto.ResourcesID]*taskmodel.ResourcesWithState
 
 func (rs resourcesList) append(ars map[sproto.ResourcesID]sproto.Resources) error {
-	// Determined supports heterogeneous agent fits, so we order the resources by size,
-	// since it is nicest for the chief to be on the largest node.
-	var bySize []sproto.Resources
-	for _, r := range ars {
-		bySize = append(bySize, r)
-	}
-	sort.SliceStable(bySize, func(i, j int) bool {
-		return bySize[i].Summary().Slots() < bySize[j].Summary().Slots()
-	})
-
 	start := len(rs)
 	rank := 0
-	for _, r := range bySize {
+	for _, r := range rankOrder(ars) {
 		summary := r.Summary()
 		state := taskmodel.NewResourcesState(r, start+rank)
 		if err := state.Persist(); err != nil {
@@ -37,6 +27,29 @@ func (rs resourcesList) append(ars map[sproto.ResourcesID]sproto.Resources) erro
 	return nil
 }
 
+// rankOrder returns the resources in the order they are ranked in. Determined supports
+// heterogeneous agent fits, so we order the resources by size, since it is nicest for the chief
+// to be on the largest node. Resources of the same size are ordered by ID, shorter IDs first, so
+// that resources whose IDs only differ by a numeric suffix are ranked by it.
+func rankOrder(ars map[sproto.ResourcesID]sproto.Resources) []sproto.Resources {
+	bySize := make([]sproto.Resources, 0, len(ars))
+	for _, r := range ars {
+		bySize = append(bySize, r)
+	}
+	sort.Slice(bySize, func(i, j int) bool {
+		a, b := bySize[i].Summary(), bySize[j].Summary()
+		switch {
+		case a.Slots() != b.Slots():
+			return a.Slots() < b.Slots()
+		case len(a.ResourcesID) != len(b.ResourcesID):
+			return len(a.ResourcesID) < len(b.ResourcesID)
+		default:
+			return a.ResourcesID < b.ResourcesID
+		}
+	})
+	return bySize
+}
+
 func (rs resourcesList) first() *taskmodel.ResourcesWithState {
 	for _, r := range rs {
 		return r
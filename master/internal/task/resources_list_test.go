@@ -0,0 +1,40 @@
+package task
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/mocks"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+func TestRankOrder(t *testing.T) {
+	resources := func(id sproto.ResourcesID, slots int) sproto.Resources {
+		r := mocks.NewResources(t)
+		r.On("Summary").Return(sproto.ResourcesSummary{
+			ResourcesID:  id,
+			AgentDevices: map[aproto.ID][]device.Device{"agent": make([]device.Device, slots)},
+		})
+		return r
+	}
+
+	ars := map[sproto.ResourcesID]sproto.Resources{}
+	for _, r := range []sproto.Resources{
+		resources("abc-10", 1),
+		resources("abc-2", 1),
+		resources("abc-1", 1),
+		resources("abc-0", 2),
+		resources("abd-3", 1),
+	} {
+		ars[r.Summary().ResourcesID] = r
+	}
+
+	var ids []sproto.ResourcesID
+	for _, r := range rankOrder(ars) {
+		ids = append(ids, r.Summary().ResourcesID)
+	}
+	assert.DeepEqual(t, ids, []sproto.ResourcesID{"abc-1", "abc-2", "abd-3", "abc-10", "abc-0"})
+}
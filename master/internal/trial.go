@@ -22,6 +22,8 @@ import (
 	"github.com/determined-ai/determined/master/internal/task"
 
 	"github.com/determined-ai/determined/master/internal/task/tasklogger"
+	"github.com/determined-ai/determined/master/internal/webhooks"
+	"github.com/determined-ai/determined/master/internal/workspace"
 	"github.com/determined-ai/determined/master/pkg/logger"
 	"github.com/determined-ai/determined/master/pkg/mathx"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -383,6 +385,8 @@ func (t *trial) maybeAllocateTask() error {
 		return err
 	}
 
+	user, workspaceName := t.quotaOwner()
+
 	restoredAllocation, err := t.maybeRestoreAllocation()
 	if err != nil {
 		t.syslog.WithError(err).Warn("failed to restore trial allocation")
@@ -400,14 +404,18 @@ func (t *trial) maybeAllocateTask() error {
 			RequestTime:       time.Now().UTC(),
 			IsUserVisible:     true,
 			Name:              name,
+			User:              user,
+			Workspace:         workspaceName,
 			SlotsNeeded:       t.config.Resources().SlotsPerTrial(),
 			ResourcePool:      t.config.Resources().ResourcePool(),
 			FittingRequirements: sproto.FittingRequirements{
 				SingleAgent: false,
 			},
+			Images: sproto.ImagesByDeviceType(t.config.Environment().Image()),
 
 			Preemptible: true,
 			Restore:     true,
+			MaxRuntime:  (*time.Duration)(t.config.Resources().MaxRuntime()),
 			ProxyPorts: sproto.NewProxyPortConfig(
 				tasks.TrialSpecProxyPorts(t.taskSpec, t.config), t.taskID),
 
@@ -444,15 +452,19 @@ func (t *trial) maybeAllocateTask() error {
 		JobSubmissionTime: t.jobSubmissionTime,
 		IsUserVisible:     true,
 		Name:              name,
+		User:              user,
+		Workspace:         workspaceName,
 
 		SlotsNeeded:  t.config.Resources().SlotsPerTrial(),
 		ResourcePool: t.config.Resources().ResourcePool(),
 		FittingRequirements: sproto.FittingRequirements{
 			SingleAgent: false,
 		},
+		Images: sproto.ImagesByDeviceType(t.config.Environment().Image()),
 
 		Preemptible: true,
 		ProxyPorts:  sproto.NewProxyPortConfig(tasks.TrialSpecProxyPorts(t.taskSpec, t.config), t.taskID),
+		MaxRuntime:  (*time.Duration)(t.config.Resources().MaxRuntime()),
 
 		BlockedNodes: blockedNodes,
 	}
@@ -699,6 +711,7 @@ func (t *trial) transition(s model.StateWithReason) error {
 			if err := t.db.UpdateTrial(t.id, s.State); err != nil {
 				return fmt.Errorf("updating trial with end state (%s, %s): %w", s.State, s.InformationalReason, err)
 			}
+			webhooks.ReportTrialStateChanged(t.id, t.experimentID, s.State)
 		}
 		t.state = s.State
 	}
@@ -831,3 +844,24 @@ func (t *trial) checkResourcePoolRemainingCapacity() error {
 
 	return nil
 }
+
+// quotaOwner returns the names of the owner and the current workspace of the trial, which its
+// slot usage counts against. Lookup failures only leave the trial out of the quotas.
+func (t *trial) quotaOwner() (string, string) {
+	var user string
+	if t.taskSpec.Owner != nil {
+		user = t.taskSpec.Owner.Username
+	}
+
+	exp, err := db.ExperimentByID(context.TODO(), t.experimentID)
+	if err != nil {
+		t.syslog.WithError(err).Warn("failed to look up experiment for slot quotas")
+		return user, ""
+	}
+	w, err := workspace.WorkspaceByProjectID(context.TODO(), exp.ProjectID)
+	if err != nil {
+		t.syslog.WithError(err).Warn("failed to look up workspace for slot quotas")
+		return user, ""
+	}
+	return user, w.Name
+}
@@ -3,9 +3,10 @@ package webhooks
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
-	"net/url"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -14,16 +15,18 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
-	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/model"
 
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
-	"github.com/determined-ai/determined/proto/pkg/webhookv1"
 )
 
 // WebhooksAPIServer is an embedded api server struct.
 type WebhooksAPIServer struct{}
 
+const defaultDeliveriesLimit = 100
+
 // AuthorizeRequest checks if the user has CanEditWebhooks permissions.
 // TODO remove this eventually since authz replaces this
 // We can't yet since we use it else where.
@@ -40,14 +43,71 @@ func AuthorizeRequest(ctx context.Context) error {
 	return nil
 }
 
-// GetWebhooks returns all Webhooks.
+// authorizeWebhook checks if the user can edit a webhook. Cluster-wide webhooks require
+// CanEditWebhooks and workspace and project webhooks require CanEditWorkspaceWebhooks on their
+// workspace.
+func authorizeWebhook(ctx context.Context, curUser *model.User, w *Webhook) error {
+	ws, err := webhookWorkspace(ctx, w)
+	switch {
+	case errors.Is(err, sql.ErrNoRows) && w.WorkspaceID != nil:
+		return api.NotFoundErrs("workspace", fmt.Sprint(*w.WorkspaceID), true)
+	case errors.Is(err, sql.ErrNoRows) && w.ProjectID != nil:
+		return api.NotFoundErrs("project", fmt.Sprint(*w.ProjectID), true)
+	case err != nil:
+		return err
+	}
+
+	var authErr error
+	if ws == nil {
+		authErr = AuthZProvider.Get().CanEditWebhooks(ctx, curUser)
+	} else {
+		authErr = AuthZProvider.Get().CanEditWorkspaceWebhooks(ctx, curUser, ws)
+	}
+	if authErr != nil {
+		return status.Error(codes.PermissionDenied, authErr.Error())
+	}
+	return nil
+}
+
+// authorizeWebhookRequest gets a webhook and checks if the user can edit it. Only users that can
+// edit cluster-wide webhooks learn whether a webhook that they cannot see exists.
+func authorizeWebhookRequest(ctx context.Context, id WebhookID) (*Webhook, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get the user: %s", err)
+	}
+	w, err := GetWebhook(ctx, int(id))
+	if err != nil {
+		if authErr := AuthorizeRequest(ctx); authErr != nil {
+			return nil, authErr
+		}
+		return nil, err
+	}
+	if err := authorizeWebhook(ctx, curUser, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// GetWebhooks returns all Webhooks, or those of a workspace and its projects.
 func (a *WebhooksAPIServer) GetWebhooks(
 	ctx context.Context, req *apiv1.GetWebhooksRequest,
 ) (*apiv1.GetWebhooksResponse, error) {
-	if err := AuthorizeRequest(ctx); err != nil {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get the user: %s", err)
+	}
+	workspaceID := i32Ptr2iPtr(req.WorkspaceId)
+	if err := authorizeWebhook(ctx, curUser, &Webhook{WorkspaceID: workspaceID}); err != nil {
 		return nil, err
 	}
-	webhooks, err := GetWebhooks(ctx)
+
+	var webhooks Webhooks
+	if workspaceID != nil {
+		webhooks, err = GetWorkspaceWebhooks(ctx, *workspaceID)
+	} else {
+		webhooks, err = GetWebhooks(ctx)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -58,45 +118,20 @@ func (a *WebhooksAPIServer) GetWebhooks(
 func (a *WebhooksAPIServer) PostWebhook(
 	ctx context.Context, req *apiv1.PostWebhookRequest,
 ) (*apiv1.PostWebhookResponse, error) {
-	if err := AuthorizeRequest(ctx); err != nil {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get the user: %s", err)
+	}
+	w, err := WebhookFromProto(req.Webhook)
+	if err != nil {
 		return nil, err
 	}
-	if len(req.Webhook.Triggers) == 0 {
-		return nil, status.Errorf(
-			codes.InvalidArgument,
-			"at least one trigger required",
-		)
-	}
-	if _, err := url.ParseRequestURI(req.Webhook.Url); err != nil {
-		return nil, status.Errorf(
-			codes.InvalidArgument,
-			"valid url required",
-		)
-	}
-
-	for _, t := range req.Webhook.Triggers {
-		if t.TriggerType == webhookv1.TriggerType_TRIGGER_TYPE_TASK_LOG {
-			m := t.Condition.AsMap()
-			if len(m) != 1 {
-				return nil, status.Errorf(codes.InvalidArgument,
-					"webhook task log condition must have one key got %v", m)
-			}
-
-			v, ok := m[regexConditionKey]
-			if !ok {
-				return nil, status.Errorf(codes.InvalidArgument,
-					"webhook task log condition must have key '%s' got %v", regexConditionKey, m)
-			}
-
-			if _, typeOK := v.(string); !typeOK {
-				return nil, status.Errorf(codes.InvalidArgument,
-					"webhook task log condition must have key '%s' as string got %v",
-					regexConditionKey, m)
-			}
-		}
+	if err := w.validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := authorizeWebhook(ctx, curUser, &w); err != nil {
+		return nil, err
 	}
-
-	w := WebhookFromProto(req.Webhook)
 	if err := AddWebhook(ctx, &w); err != nil {
 		return nil, err
 	}
@@ -107,7 +142,10 @@ func (a *WebhooksAPIServer) PostWebhook(
 func (a *WebhooksAPIServer) DeleteWebhook(
 	ctx context.Context, req *apiv1.DeleteWebhookRequest,
 ) (*apiv1.DeleteWebhookResponse, error) {
-	if err := AuthorizeRequest(ctx); err != nil {
+	switch _, err := authorizeWebhookRequest(ctx, WebhookID(req.Id)); {
+	case errors.Is(err, sql.ErrNoRows):
+		return &apiv1.DeleteWebhookResponse{}, nil
+	case err != nil:
 		return nil, err
 	}
 	if err := DeleteWebhook(ctx, WebhookID(req.Id)); err != nil {
@@ -120,11 +158,7 @@ func (a *WebhooksAPIServer) DeleteWebhook(
 func (a *WebhooksAPIServer) TestWebhook(
 	ctx context.Context, req *apiv1.TestWebhookRequest,
 ) (*apiv1.TestWebhookResponse, error) {
-	if err := AuthorizeRequest(ctx); err != nil {
-		return nil, err
-	}
-
-	webhook, err := GetWebhook(ctx, int(req.Id))
+	webhook, err := authorizeWebhookRequest(ctx, WebhookID(req.Id))
 	if err != nil {
 		return nil, err
 	}
@@ -132,60 +166,25 @@ func (a *WebhooksAPIServer) TestWebhook(
 	eventID := uuid.New()
 	log.Infof("creating webhook payload for event %v", eventID)
 
-	var tReq *http.Request
-	switch webhook.WebhookType {
-	case WebhookTypeDefault:
-		t := time.Now().Unix()
-		p, perr := json.Marshal(EventPayload{
-			ID:        uuid.New(),
-			Timestamp: t,
-			Type:      TriggerTypeStateChange,
-			Condition: Condition{
-				State: "COMPLETED",
-			},
-			Data: EventData{
-				TestData: ptrs.Ptr("test"),
-			},
-		})
-		if perr != nil {
-			return nil, err
-		}
-
-		tr, rerr := generateWebhookRequest(ctx, webhook.URL, p, t)
-		if rerr != nil {
-			return nil, status.Errorf(codes.InvalidArgument,
-				"failed to create webhook request for event %v error : %v ", eventID, err)
-		}
-		tReq = tr
-	case WebhookTypeSlack:
-		slackMessage, serr := json.Marshal(SlackMessageBody{
-			Blocks: []SlackBlock{
-				{
-					Text: SlackField{
-						Text: "test",
-						Type: "plain_text",
-					},
-					Type: "section",
-				},
-			},
-		})
-		if serr != nil {
-			return nil, err
-		}
+	t, err := transportFor(webhook.WebhookType)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	p, err := t.test(ctx, webhook)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"failed to create webhook payload for event %v error: %v", eventID, err)
+	}
 
-		tr, rerr := http.NewRequestWithContext(
-			ctx,
-			http.MethodPost,
-			webhook.URL,
-			bytes.NewBuffer(slackMessage),
-		)
-		if rerr != nil {
-			return nil, status.Errorf(codes.InvalidArgument,
-				"failed to create webhook request for event %v error : %v ", eventID, err)
-		}
-		tReq = tr
-	default:
-		panic("Unknown webhook type")
+	var tReq *http.Request
+	if webhook.WebhookType == WebhookTypeSlack {
+		tReq, err = http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewBuffer(p))
+	} else {
+		tReq, err = generateWebhookRequest(ctx, webhook.URL, p, time.Now().Unix())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"failed to create webhook request for event %v error : %v ", eventID, err)
 	}
 
 	log.Infof("creating webhook request for event %v", eventID)
@@ -207,3 +206,67 @@ func (a *WebhooksAPIServer) TestWebhook(
 	}
 	return &apiv1.TestWebhookResponse{}, nil
 }
+
+// GetWebhookDeliveries returns the most recent delivery attempts of a Webhook.
+func (a *WebhooksAPIServer) GetWebhookDeliveries(
+	ctx context.Context, req *apiv1.GetWebhookDeliveriesRequest,
+) (*apiv1.GetWebhookDeliveriesResponse, error) {
+	if req.Limit < 0 || req.Offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit and offset must not be negative")
+	}
+	switch _, err := authorizeWebhookRequest(ctx, WebhookID(req.WebhookId)); {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, api.NotFoundErrs("webhook", fmt.Sprint(req.WebhookId), true)
+	case err != nil:
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultDeliveriesLimit
+	}
+	deliveries, err := GetWebhookDeliveries(ctx, WebhookID(req.WebhookId), limit, int(req.Offset))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetWebhookDeliveriesResponse{}
+	for _, d := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, d.Proto())
+	}
+	return resp, nil
+}
+
+// RedeliverWebhookEvent queues the event of a delivery attempt to be sent again.
+func (a *WebhooksAPIServer) RedeliverWebhookEvent(
+	ctx context.Context, req *apiv1.RedeliverWebhookEventRequest,
+) (*apiv1.RedeliverWebhookEventResponse, error) {
+	notFound := api.NotFoundErrs("webhook delivery", fmt.Sprint(req.DeliveryId), true)
+	d, err := GetDelivery(ctx, DeliveryID(req.DeliveryId))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if err := AuthorizeRequest(ctx); err != nil {
+			return nil, err
+		}
+		return nil, notFound
+	case err != nil:
+		return nil, err
+	}
+
+	// Deliveries of deleted webhooks can only be replayed by users that can edit any webhook.
+	if d.WebhookID == nil {
+		err = AuthorizeRequest(ctx)
+	} else {
+		_, err = authorizeWebhookRequest(ctx, *d.WebhookID)
+	}
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, notFound
+	case err != nil:
+		return nil, err
+	}
+
+	if err := RedeliverWebhookEvent(ctx, d); err != nil {
+		return nil, err
+	}
+	return &apiv1.RedeliverWebhookEventResponse{}, nil
+}
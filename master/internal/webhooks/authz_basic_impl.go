@@ -20,6 +20,17 @@ func (a *WebhookAuthZBasic) CanEditWebhooks(
 	return nil
 }
 
+// CanEditWorkspaceWebhooks returns an error if the user is not an admin or the owner of the
+// workspace.
+func (a *WebhookAuthZBasic) CanEditWorkspaceWebhooks(
+	ctx context.Context, curUser *model.User, workspace *model.Workspace,
+) (serverError error) {
+	if !curUser.Admin && curUser.ID != workspace.UserID {
+		return fmt.Errorf("only admins may edit webhooks of other user's workspaces")
+	}
+	return nil
+}
+
 func init() {
 	AuthZProvider.Register("basic", &WebhookAuthZBasic{})
 }
@@ -14,6 +14,14 @@ type WebhookAuthZ interface {
 	// DELETE /api/v1/webhooks/:webhook_id
 	// POST /api/v1/webhooks/test/:webhook_id
 	CanEditWebhooks(ctx context.Context, curUser *model.User) (serverError error)
+
+	// GET /webhooks?workspace_id=:workspace_id
+	// POST /webhooks for a workspace or project webhook
+	// DELETE /api/v1/webhooks/:webhook_id of a workspace or project webhook
+	// POST /api/v1/webhooks/test/:webhook_id of a workspace or project webhook
+	CanEditWorkspaceWebhooks(
+		ctx context.Context, curUser *model.User, workspace *model.Workspace,
+	) (serverError error)
 }
 
 // AuthZProvider is the authz registry for experiments.
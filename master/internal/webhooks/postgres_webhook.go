@@ -3,6 +3,7 @@ package webhooks
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"regexp"
@@ -214,6 +215,108 @@ func GetWebhooks(ctx context.Context) (Webhooks, error) {
 	return webhooks, nil
 }
 
+// GetWorkspaceWebhooks returns the Webhooks scoped to a workspace or to one of its projects.
+func GetWorkspaceWebhooks(ctx context.Context, workspaceID int) (Webhooks, error) {
+	webhooks := Webhooks{}
+	err := db.Bun().NewSelect().
+		Model(&webhooks).
+		Relation("Triggers").
+		Where("workspace_id = ?", workspaceID).
+		WhereOr("project_id IN (SELECT id FROM projects WHERE workspace_id = ?)", workspaceID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// DeleteWorkspaceWebhooks deletes the Webhooks scoped to a workspace or to one of its projects.
+func DeleteWorkspaceWebhooks(ctx context.Context, workspaceID int) error {
+	ws, err := GetWorkspaceWebhooks(ctx, workspaceID)
+	if err != nil {
+		return fmt.Errorf("getting webhooks of workspace %d: %w", workspaceID, err)
+	}
+	for _, w := range ws {
+		if err := DeleteWebhook(ctx, w.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteProjectWebhooks deletes the Webhooks scoped to a project.
+func DeleteProjectWebhooks(ctx context.Context, projectID int) error {
+	var ids []WebhookID
+	if err := db.Bun().NewSelect().Model((*Webhook)(nil)).Column("id").
+		Where("project_id = ?", projectID).
+		Scan(ctx, &ids); err != nil {
+		return fmt.Errorf("getting webhooks of project %d: %w", projectID, err)
+	}
+	for _, id := range ids {
+		if err := DeleteWebhook(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// webhookWorkspace returns the workspace that a webhook is scoped to, or nil if the webhook is
+// cluster-wide. Project webhooks belong to the workspace of their project.
+func webhookWorkspace(ctx context.Context, w *Webhook) (*model.Workspace, error) {
+	switch {
+	case w.WorkspaceID != nil:
+		var ws model.Workspace
+		if err := db.Bun().NewSelect().Model(&ws).
+			Where("id = ?", *w.WorkspaceID).
+			Scan(ctx); err != nil {
+			return nil, fmt.Errorf("getting workspace %d: %w", *w.WorkspaceID, err)
+		}
+		return &ws, nil
+	case w.ProjectID != nil:
+		ws, err := workspace.WorkspaceByProjectID(ctx, *w.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("getting workspace of project %d: %w", *w.ProjectID, err)
+		}
+		return ws, nil
+	default:
+		return nil, nil //nolint: nilnil
+	}
+}
+
+// experimentScope returns the workspace and project of an experiment.
+func experimentScope(ctx context.Context, experimentID int) (scope, error) {
+	var s scope
+	if err := db.Bun().NewRaw(`
+SELECT p.workspace_id, p.id
+FROM experiments e
+JOIN projects p ON e.project_id = p.id
+WHERE e.id = ?
+`, experimentID).Scan(ctx, &s.workspaceID, &s.projectID); err != nil {
+		return s, fmt.Errorf("getting workspace of experiment %d: %w", experimentID, err)
+	}
+	return s, nil
+}
+
+// taskScope returns the workspace and, for trials, the project of a task.
+func taskScope(ctx context.Context, task *model.Task) (scope, error) {
+	if task.TaskType == model.TaskTypeTrial {
+		trial, err := db.TrialByTaskID(ctx, task.TaskID)
+		if err != nil {
+			return scope{}, err
+		}
+		return experimentScope(ctx, trial.ExperimentID)
+	}
+
+	metadata, err := db.IdentifyTask(ctx, task.TaskID)
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		return scope{}, nil
+	case err != nil:
+		return scope{}, fmt.Errorf("getting workspace of task %s: %w", task.TaskID, err)
+	}
+	return scope{workspaceID: int(metadata.WorkspaceID)}, nil
+}
+
 // ReportExperimentStateChanged adds webhook events to the queue.
 // TODO(DET-8577): Remove unnecessary active config usage (remove the activeConfig parameter).
 func ReportExperimentStateChanged(
@@ -236,15 +339,33 @@ func ReportExperimentStateChanged(
 		return nil
 	}
 
+	// Only look up the experiment's workspace if some webhook needs it.
+	s := scope{projectID: e.ProjectID}
+	for _, t := range ts {
+		if !t.Webhook.scoped() {
+			continue
+		}
+		if err := db.Bun().NewSelect().Table("projects").Column("workspace_id").
+			Where("id = ?", e.ProjectID).
+			Scan(ctx, &s.workspaceID); err != nil {
+			return fmt.Errorf("getting workspace of project %d: %w", e.ProjectID, err)
+		}
+		break
+	}
+
 	var es []Event
 	for _, t := range ts {
-		p, err := generateEventPayload(
-			ctx, t.Webhook.WebhookType, e, activeConfig, e.State, TriggerTypeStateChange,
-		)
+		if !t.Webhook.inScope(s) {
+			continue
+		}
+		p, err := generateEventPayload(ctx, t.Webhook, e, activeConfig)
 		if err != nil {
 			return fmt.Errorf("error generating event payload: %w", err)
 		}
-		es = append(es, Event{Payload: p, URL: t.Webhook.URL})
+		es = append(es, Event{Payload: p, URL: t.Webhook.URL, WebhookID: &t.Webhook.ID})
+	}
+	if len(es) == 0 {
+		return nil
 	}
 	if _, err := db.Bun().NewInsert().Model(&es).Exec(ctx); err != nil {
 		return fmt.Errorf("report experiment state changed inserting event trigger: %w", err)
@@ -254,6 +375,194 @@ func ReportExperimentStateChanged(
 	return nil
 }
 
+// eventSubject is what a lifecycle event reports: the attributes trigger conditions are matched
+// against and the data sent in the event.
+type eventSubject struct {
+	condition Condition
+	// name is matched by regex conditions.
+	name string
+	// scope limits the event to cluster-wide webhooks and those of its workspace or project.
+	scope scope
+	data  EventData
+}
+
+// reportEvent adds an event to the queue for every webhook with a trigger of the given type whose
+// condition matches the event and whose scope includes it. The event is only loaded if there are
+// triggers of that type.
+func reportEvent(
+	ctx context.Context, triggerType TriggerType, load func() (*eventSubject, error),
+) error {
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Errorf("uncaught error in webhook report: %v", rec)
+		}
+	}()
+
+	var ts []*Trigger
+	switch err := db.Bun().NewSelect().Model(&ts).Relation("Webhook").
+		Where("trigger_type = ?", triggerType).
+		Scan(ctx); {
+	case err != nil:
+		return err
+	case len(ts) == 0:
+		return nil
+	}
+
+	subject, err := load()
+	if err != nil {
+		return fmt.Errorf("loading %s event: %w", triggerType, err)
+	}
+
+	var es []Event
+	for _, t := range ts {
+		if !t.Webhook.inScope(subject.scope) || !t.matches(subject.condition, subject.name) {
+			continue
+		}
+		tr, err := transportFor(t.Webhook.WebhookType)
+		if err != nil {
+			return err
+		}
+		p, err := tr.event(ctx, t.Webhook, EventPayload{
+			ID:        uuid.New(),
+			Type:      triggerType,
+			Timestamp: time.Now().Unix(),
+			Condition: subject.condition,
+			Data:      subject.data,
+		})
+		if err != nil {
+			return fmt.Errorf("error generating event payload: %w", err)
+		}
+		es = append(es, Event{Payload: p, URL: t.Webhook.URL, WebhookID: &t.Webhook.ID})
+	}
+	if len(es) == 0 {
+		return nil
+	}
+	if _, err := db.Bun().NewInsert().Model(&es).Exec(ctx); err != nil {
+		return fmt.Errorf("report %s inserting event trigger: %w", triggerType, err)
+	}
+
+	singletonShipper.Wake()
+	return nil
+}
+
+// ReportTrialStateChanged adds webhook events for a trial state change to the queue. Events are
+// added in the background, so trials don't wait on it while they transition.
+func ReportTrialStateChanged(trialID, experimentID int, state model.State) {
+	reportInBackground(TriggerTypeTrialStateChange, func(ctx context.Context) error {
+		return reportEvent(ctx, TriggerTypeTrialStateChange, func() (*eventSubject, error) {
+			s, err := experimentScope(ctx, experimentID)
+			if err != nil {
+				return nil, err
+			}
+			return &eventSubject{
+				condition: Condition{State: state},
+				scope:     s,
+				data: EventData{Trial: &TrialPayload{
+					ID:           trialID,
+					ExperimentID: experimentID,
+					State:        state,
+				}},
+			}, nil
+		})
+	})
+}
+
+// ReportCheckpointStateChanged adds webhook events for a checkpoint that completed or was
+// deleted to the queue.
+func ReportCheckpointStateChanged(
+	ctx context.Context, checkpointUUID uuid.UUID, state model.State,
+) error {
+	return reportEvent(ctx, TriggerTypeCheckpointStateChange, func() (*eventSubject, error) {
+		c := CheckpointPayload{UUID: checkpointUUID, State: state}
+		if err := db.Bun().NewSelect().Table("checkpoints_view").
+			Column("task_id", "trial_id", "experiment_id").
+			Where("uuid = ?", checkpointUUID).
+			Scan(ctx, &c.TaskID, &c.TrialID, &c.ExperimentID); err != nil {
+			return nil, fmt.Errorf("getting checkpoint %s: %w", checkpointUUID, err)
+		}
+		var s scope
+		if c.ExperimentID != nil {
+			var err error
+			if s, err = experimentScope(ctx, *c.ExperimentID); err != nil {
+				return nil, err
+			}
+		}
+		return &eventSubject{
+			condition: Condition{State: state},
+			scope:     s,
+			data:      EventData{Checkpoint: &c},
+		}, nil
+	})
+}
+
+// ReportModelVersionRegistered adds webhook events for a new model version to the queue.
+func ReportModelVersionRegistered(ctx context.Context, mv ModelVersionPayload) error {
+	return reportEvent(ctx, TriggerTypeModelVersionRegistered, func() (*eventSubject, error) {
+		var s scope
+		if err := db.Bun().NewSelect().Table("models").Column("workspace_id").
+			Where("id = ?", mv.ModelID).
+			Scan(ctx, &s.workspaceID); err != nil {
+			return nil, fmt.Errorf("getting workspace of model %d: %w", mv.ModelID, err)
+		}
+		return &eventSubject{
+			name:  mv.ModelName,
+			scope: s,
+			data:  EventData{ModelVersion: &mv},
+		}, nil
+	})
+}
+
+// ReportAllocationPreempted adds webhook events for an allocation being preempted to the queue.
+// Events are added in the background, so allocations don't wait on it while they preempt.
+func ReportAllocationPreempted(
+	taskID model.TaskID, allocationID model.AllocationID, reason string,
+) {
+	reportInBackground(TriggerTypeAllocationPreempted, func(ctx context.Context) error {
+		return reportEvent(ctx, TriggerTypeAllocationPreempted, func() (*eventSubject, error) {
+			task, err := db.TaskByID(ctx, taskID)
+			if err != nil {
+				return nil, err
+			}
+			s, err := taskScope(ctx, task)
+			if err != nil {
+				return nil, err
+			}
+			return &eventSubject{
+				condition: Condition{TaskType: task.TaskType},
+				scope:     s,
+				data: EventData{Allocation: &AllocationPayload{
+					AllocationID: allocationID,
+					TaskID:       taskID,
+					TaskType:     task.TaskType,
+					Reason:       reason,
+				}},
+			}, nil
+		})
+	})
+}
+
+// ReportTaskTerminated adds webhook events for a notebook, shell, command or tensorboard in the
+// given workspace terminating to the queue.
+func ReportTaskTerminated(
+	ctx context.Context,
+	taskID model.TaskID,
+	taskType model.TaskType,
+	workspaceID int,
+	exitStatus string,
+) error {
+	return reportEvent(ctx, TriggerTypeTaskTerminated, func() (*eventSubject, error) {
+		return &eventSubject{
+			condition: Condition{TaskType: taskType},
+			scope:     scope{workspaceID: workspaceID},
+			data: EventData{Task: &TaskPayload{
+				TaskID:     taskID,
+				TaskType:   taskType,
+				ExitStatus: exitStatus,
+			}},
+		}, nil
+	})
+}
+
 func addTaskLogEvent(ctx context.Context,
 	taskID model.TaskID, nodeName, triggeringLog string, trigger *Trigger,
 ) error {
@@ -269,8 +578,21 @@ func addTaskLogEvent(ctx context.Context,
 			"expected webhook trigger to have regex in condition instead got %v", trigger.Condition)
 	}
 
-	p, err := generateTaskLogPayload(
-		ctx, taskID, nodeName, regex, triggeringLog, trigger.Webhook.WebhookType)
+	if w := trigger.Webhook; w.scoped() {
+		task, err := db.TaskByID(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		s, err := taskScope(ctx, task)
+		if err != nil {
+			return err
+		}
+		if !w.inScope(s) {
+			return nil
+		}
+	}
+
+	p, err := generateTaskLogPayload(ctx, taskID, nodeName, regex, triggeringLog, trigger.Webhook)
 	if err != nil {
 		return fmt.Errorf("generating task logs event: %w", err)
 	}
@@ -291,8 +613,9 @@ func addTaskLogEvent(ctx context.Context,
 		}
 
 		if _, err := db.Bun().NewInsert().Model(&Event{
-			Payload: p,
-			URL:     trigger.Webhook.URL,
+			Payload:   p,
+			URL:       trigger.Webhook.URL,
+			WebhookID: &trigger.Webhook.ID,
 		}).Exec(ctx); err != nil {
 			return fmt.Errorf("inserting task logs event trigger: %w", err)
 		}
@@ -316,41 +639,17 @@ func generateTaskLogPayload(
 	nodeName,
 	regex,
 	triggeringLog string,
-	wt WebhookType,
+	w *Webhook,
 ) ([]byte, error) {
-	switch wt {
-	case WebhookTypeDefault:
-		p, err := json.Marshal(EventPayload{
-			ID:        uuid.New(),
-			Type:      TriggerTypeTaskLog,
-			Timestamp: time.Now().Unix(),
-			Condition: Condition{
-				Regex: regex,
-			},
-			Data: EventData{
-				TaskLog: &TaskLogPayload{
-					TaskID:        taskID,
-					NodeName:      nodeName,
-					TriggeringLog: triggeringLog,
-				},
-			},
-		})
-		if err != nil {
-			return nil, fmt.Errorf("marshaling json for log pattern payload: %w", err)
-		}
-
-		return p, nil
-
-	case WebhookTypeSlack:
-		p, err := generateLogPatternSlackPayload(ctx, taskID, nodeName, regex, triggeringLog)
-		if err != nil {
-			return nil, err
-		}
-		return p, nil
-
-	default:
-		return nil, fmt.Errorf("unknown webhook type %+v while generating log pattern payload", wt)
+	t, err := transportFor(w.WebhookType)
+	if err != nil {
+		return nil, fmt.Errorf("generating log pattern payload: %w", err)
+	}
+	p, err := t.taskLog(ctx, w, taskID, nodeName, regex, triggeringLog)
+	if err != nil {
+		return nil, fmt.Errorf("generating log pattern payload: %w", err)
 	}
+	return p, nil
 }
 
 func generateLogPatternSlackPayload(
@@ -413,38 +712,15 @@ func generateLogPatternSlackPayload(
 
 func generateEventPayload(
 	ctx context.Context,
-	wt WebhookType,
+	w *Webhook,
 	e model.Experiment,
 	activeConfig expconf.ExperimentConfig,
-	expState model.State,
-	tT TriggerType,
 ) ([]byte, error) {
-	switch wt {
-	case WebhookTypeDefault:
-		pJSON, err := json.Marshal(EventPayload{
-			ID:        uuid.New(),
-			Type:      tT,
-			Timestamp: time.Now().Unix(),
-			Condition: Condition{
-				State: expState,
-			},
-			Data: EventData{
-				Experiment: experimentToWebhookPayload(e, activeConfig),
-			},
-		})
-		if err != nil {
-			return nil, err
-		}
-		return pJSON, nil
-	case WebhookTypeSlack:
-		slackJSON, err := generateSlackPayload(ctx, e, activeConfig)
-		if err != nil {
-			return nil, err
-		}
-		return slackJSON, nil
-	default:
-		panic(fmt.Errorf("unknown webhook type: %+v", wt))
+	t, err := transportFor(w.WebhookType)
+	if err != nil {
+		return nil, err
 	}
+	return t.experimentStateChanged(ctx, w, e, activeConfig)
 }
 
 func generateSlackPayload(
@@ -573,6 +849,65 @@ func generateSlackPayload(
 	return message, nil
 }
 
+// addDelivery records an attempt to deliver an event.
+func addDelivery(ctx context.Context, d *Delivery) error {
+	if _, err := db.Bun().NewInsert().Model(d).Exec(ctx); err != nil {
+		return fmt.Errorf("inserting webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookDeliveries returns the most recent attempts to deliver events for a webhook, newest
+// first.
+func GetWebhookDeliveries(
+	ctx context.Context, webhookID WebhookID, limit, offset int,
+) ([]*Delivery, error) {
+	ds := []*Delivery{}
+	if err := db.Bun().NewSelect().Model(&ds).
+		Where("webhook_id = ?", webhookID).
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting deliveries of webhook %d: %w", webhookID, err)
+	}
+	return ds, nil
+}
+
+// GetDelivery returns a single delivery attempt.
+func GetDelivery(ctx context.Context, id DeliveryID) (*Delivery, error) {
+	var d Delivery
+	if err := db.Bun().NewSelect().Model(&d).Where("id = ?", id).Scan(ctx); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// RedeliverWebhookEvent queues the payload of a previous delivery attempt to be sent again.
+func RedeliverWebhookEvent(ctx context.Context, d *Delivery) error {
+	if _, err := db.Bun().NewInsert().Model(&Event{
+		Payload:   []byte(d.Payload),
+		URL:       d.URL,
+		WebhookID: d.WebhookID,
+	}).Exec(ctx); err != nil {
+		return fmt.Errorf("redelivering webhook delivery %d: %w", d.ID, err)
+	}
+
+	singletonShipper.Wake()
+	return nil
+}
+
+// deleteDeliveriesBefore deletes the delivery attempts made before the given time.
+func deleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := db.Bun().NewDelete().Model((*Delivery)(nil)).
+		Where("attempted_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("deleting webhook deliveries: %w", err)
+	}
+	return res.RowsAffected()
+}
+
 type eventBatch struct {
 	tx       *bun.Tx
 	events   []Event
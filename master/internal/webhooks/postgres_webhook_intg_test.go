@@ -4,10 +4,14 @@ package webhooks
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -17,6 +21,7 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/workspace"
 	"github.com/determined-ai/determined/master/pkg/etc"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/ptrs"
@@ -246,7 +251,7 @@ func testGenerateTaskLogPayloadTest(
 		}
 
 		payload, err := generateTaskLogPayload(
-			ctx, task.TaskID, "nodeA", "regexa", "trigA", webhookType)
+			ctx, task.TaskID, "nodeA", "regexa", "trigA", &Webhook{WebhookType: webhookType})
 		require.NoError(t, err)
 
 		if webhookType == WebhookTypeDefault {
@@ -383,6 +388,55 @@ func TestReportExperimentStateChanged(t *testing.T) {
 	})
 }
 
+func TestReportExperimentStateChangedScoped(t *testing.T) {
+	ctx := context.Background()
+	clearWebhooksTables(ctx, t)
+
+	singletonShipper = &shipper{wake: make(chan<- struct{})} // mock shipper
+
+	var config expconf.ExperimentConfig
+	config = schemas.WithDefaults(config)
+
+	user := db.RequireMockUser(t, pgDB)
+	exp := db.RequireMockExperiment(t, pgDB, user)
+	exp.State = model.CompletedState
+	ws, err := workspace.WorkspaceByProjectID(ctx, exp.ProjectID)
+	require.NoError(t, err)
+	otherWorkspace := &model.Workspace{Name: uuid.New().String(), UserID: user.ID}
+	_, err = db.Bun().NewInsert().Model(otherWorkspace).Exec(ctx)
+	require.NoError(t, err)
+
+	scopedWebhook := func(workspaceID, projectID *int) *Webhook {
+		w := mockWebhook()
+		w.WorkspaceID = workspaceID
+		w.ProjectID = projectID
+		w.Triggers = append(w.Triggers, &Trigger{
+			TriggerType: TriggerTypeStateChange,
+			Condition:   map[string]interface{}{"state": model.CompletedState},
+		})
+		require.NoError(t, AddWebhook(ctx, w))
+		return w
+	}
+	global := scopedWebhook(nil, nil)
+	inWorkspace := scopedWebhook(&ws.ID, nil)
+	inProject := scopedWebhook(nil, &exp.ProjectID)
+	otherScope := scopedWebhook(&otherWorkspace.ID, nil)
+
+	require.NoError(t, ReportExperimentStateChanged(ctx, *exp, config))
+	require.Equal(t, 1, countEventsForURL(ctx, t, global.URL))
+	require.Equal(t, 1, countEventsForURL(ctx, t, inWorkspace.URL))
+	require.Equal(t, 1, countEventsForURL(ctx, t, inProject.URL))
+	require.Equal(t, 0, countEventsForURL(ctx, t, otherScope.URL))
+
+	webhooks, err := GetWorkspaceWebhooks(ctx, ws.ID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []WebhookID{inWorkspace.ID, inProject.ID}, getWebhookIds(webhooks))
+
+	require.NoError(t, DeleteWorkspaceWebhooks(ctx, otherWorkspace.ID))
+	_, err = GetWebhook(ctx, int(otherScope.ID))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
 var (
 	testWebhookOne = Webhook{
 		ID:          1000,
@@ -567,3 +621,68 @@ func countEventsForURL(ctx context.Context, t *testing.T, url string) int {
 
 	return c
 }
+
+func TestWebhookDeliveries(t *testing.T) {
+	ctx := context.Background()
+	clearWebhooksTables(ctx, t)
+
+	singletonShipper = &shipper{wake: make(chan<- struct{}, 1)} // mock shipper
+
+	var status atomic.Int64
+	status.Store(http.StatusNotFound)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer receiver.Close()
+
+	w := &Webhook{
+		URL:         receiver.URL,
+		WebhookType: WebhookTypeDefault,
+		Triggers: Triggers{{
+			TriggerType: TriggerTypeStateChange,
+			Condition:   map[string]interface{}{"state": model.CompletedState},
+		}},
+	}
+	require.NoError(t, AddWebhook(ctx, w))
+	_, err := db.Bun().NewInsert().Model(&Event{
+		URL:       w.URL,
+		Payload:   []byte(`{"event_type": "TEST"}`),
+		WebhookID: &w.ID,
+	}).Exec(ctx)
+	require.NoError(t, err)
+
+	worker := newWorker(0)
+	n, err := worker.shipBatch(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	ds, err := GetWebhookDeliveries(ctx, w.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+	require.Equal(t, ptrs.Ptr(http.StatusNotFound), ds[0].StatusCode)
+	require.Equal(t, ptrs.Ptr("request returned 404"), ds[0].Error)
+	require.Equal(t, 1, ds[0].Attempt)
+	require.Equal(t, `{"event_type": "TEST"}`, ds[0].Payload)
+
+	t.Log("redeliver the failed event once the receiver is fixed")
+	status.Store(http.StatusOK)
+	require.NoError(t, RedeliverWebhookEvent(ctx, ds[0]))
+	n, err = worker.shipBatch(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	ds, err = GetWebhookDeliveries(ctx, w.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, ds, 2)
+	require.Equal(t, ptrs.Ptr(http.StatusOK), ds[0].StatusCode)
+	require.Nil(t, ds[0].Error)
+	require.Equal(t, ds[1].Payload, ds[0].Payload)
+
+	t.Log("prune deliveries past retention")
+	deleted, err := deleteDeliveriesBefore(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, int64(2))
+	ds, err = GetWebhookDeliveries(ctx, w.ID, 10, 0)
+	require.NoError(t, err)
+	require.Empty(t, ds)
+}
@@ -17,6 +17,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	conf "github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/pkg/syncx/queue"
 )
 
 const (
@@ -26,6 +27,10 @@ const (
 	backoffAttempts = 2
 	backoffInterval = time.Second
 	backoffMax      = time.Minute
+
+	deliveryPruneInterval = time.Hour
+
+	reportTimeout = time.Minute
 )
 
 var singletonShipper *shipper
@@ -48,6 +53,9 @@ type shipper struct {
 	wake   chan<- struct{}
 	wg     sync.WaitGroup
 	cancel context.CancelFunc
+	// reports are the reports of callers that must not wait on adding events, e.g., because they
+	// hold locks. They are added in order, and a nil report stops the reporter.
+	reports *queue.Queue[func()]
 }
 
 func newShipper() *shipper {
@@ -56,9 +64,10 @@ func newShipper() *shipper {
 	wake := make(chan struct{}, 1)
 	wake <- struct{}{} // Always attempt to process existing events.
 	s := &shipper{
-		log:    log.WithField("component", "webhook-sender"),
-		wake:   wake,
-		cancel: cancel,
+		log:     log.WithField("component", "webhook-sender"),
+		wake:    wake,
+		cancel:  cancel,
+		reports: queue.New[func()](),
 	}
 
 	for i := 0; i < maxWorkers; i++ {
@@ -71,6 +80,20 @@ func newShipper() *shipper {
 		}()
 	}
 
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.pruneDeliveries(ctx)
+	}()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for report := s.reports.Get(); report != nil; report = s.reports.Get() {
+			report()
+		}
+	}()
+
 	return s
 }
 
@@ -86,7 +109,49 @@ func (s *shipper) Wake() {
 	}
 }
 
+// pruneDeliveries periodically deletes delivery attempts older than the retention period.
+func (s *shipper) pruneDeliveries(ctx context.Context) {
+	t := time.NewTicker(deliveryPruneInterval)
+	defer t.Stop()
+	for {
+		retention := time.Duration(conf.GetMasterConfig().Webhooks.DeliveryRetention)
+		switch n, err := deleteDeliveriesBefore(ctx, time.Now().Add(-retention)); {
+		case err != nil && ctx.Err() == nil:
+			s.log.WithError(err).Warn("failed to prune webhook deliveries")
+		case n > 0:
+			s.log.Debugf("pruned %d webhook deliveries", n)
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reportInBackground adds the events of a report to the queue after the caller moves on. The report
+// is dropped if webhooks are not initialized.
+func reportInBackground(triggerType TriggerType, report func(ctx context.Context) error) {
+	s := singletonShipper
+	if s == nil || s.reports == nil {
+		log.Debugf("webhooks are not initialized, dropping %s event", triggerType)
+		return
+	}
+	s.reports.Put(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+		defer cancel()
+		if err := report(ctx); err != nil {
+			s.log.WithError(err).Errorf("failed to report %s webhook event", triggerType)
+		}
+	})
+}
+
 func (s *shipper) Close() {
+	if s.reports != nil {
+		// Reports don't use the shipper context, so the ones already made are still added.
+		s.reports.Put(nil)
+	}
 	s.cancel()
 	s.wg.Wait()
 }
@@ -154,8 +219,12 @@ func (w *worker) shipBatch(ctx context.Context) (int, error) {
 		wg.Add(1)
 		go func(e Event) {
 			defer wg.Done()
+			attempt := 0
 			if err := back.Retry(
-				func() error { return w.deliver(ctx, e) },
+				func() error {
+					attempt++
+					return w.deliver(ctx, e, attempt)
+				},
 				backoff(),
 			); err != nil {
 				w.log.WithError(err).Error("failed to deliver webhook")
@@ -180,16 +249,20 @@ func backoff() back.BackOff {
 	return back.WithMaxRetries(bf, backoffAttempts)
 }
 
-func (w *worker) deliver(ctx context.Context, e Event) error {
+func (w *worker) deliver(ctx context.Context, e Event, attempt int) error {
 	req, err := generateWebhookRequest(ctx, e.URL, e.Payload, time.Now().Unix())
 	if err != nil {
 		return err
 	}
 
+	start := time.Now()
 	resp, err := w.cl.Do(req)
 	if err != nil {
-		return fmt.Errorf("sending webhook request: %w", err)
+		err = fmt.Errorf("sending webhook request: %w", err)
+		w.record(ctx, e, attempt, nil, time.Since(start), err)
+		return err
 	}
+	latency := time.Since(start)
 	defer func() {
 		if err = resp.Body.Close(); err != nil {
 			w.log.WithError(err).Warn("failed to close response body")
@@ -198,11 +271,43 @@ func (w *worker) deliver(ctx context.Context, e Event) error {
 
 	switch {
 	case resp.StatusCode >= 500: //nolint: usestdlibvars
-		return fmt.Errorf("request returned %v: %w", resp.StatusCode, err)
+		err = fmt.Errorf("request returned %v", resp.StatusCode)
 	case resp.StatusCode >= 400: //nolint: usestdlibvars
-		return back.Permanent(fmt.Errorf("request returned %v: %w", resp.StatusCode, err))
-	default:
-		return nil
+		err = back.Permanent(fmt.Errorf("request returned %v", resp.StatusCode))
+	}
+	w.record(ctx, e, attempt, &resp.StatusCode, latency, err)
+	return err
+}
+
+// record saves a delivery attempt to the delivery history, unless it is disabled. Failing to
+// record an attempt does not fail the delivery.
+func (w *worker) record(
+	ctx context.Context,
+	e Event,
+	attempt int,
+	statusCode *int,
+	latency time.Duration,
+	deliveryErr error,
+) {
+	if conf.GetMasterConfig().Webhooks.DeliveryRetention <= 0 {
+		return
+	}
+
+	d := &Delivery{
+		EventID:    e.ID,
+		WebhookID:  e.WebhookID,
+		URL:        e.URL,
+		Payload:    string(e.Payload),
+		Attempt:    attempt,
+		StatusCode: statusCode,
+		LatencyMs:  int(latency.Milliseconds()),
+	}
+	if deliveryErr != nil {
+		msg := deliveryErr.Error()
+		d.Error = &msg
+	}
+	if err := addDelivery(ctx, d); err != nil {
+		w.log.WithError(err).Warn("failed to record webhook delivery")
 	}
 }
 
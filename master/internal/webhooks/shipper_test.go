@@ -212,6 +212,31 @@ func TestShipper(t *testing.T) {
 	}
 }
 
+func TestReportInBackground(t *testing.T) {
+	pgDB := db.MustResolveTestPostgres(t)
+	db.MustMigrateTestPostgres(t, pgDB, db.MigrationsFromDB)
+
+	singletonShipper = nil
+	reportInBackground(TriggerTypeTrialStateChange, func(ctx context.Context) error {
+		t.Error("report ran without a shipper")
+		return nil
+	})
+
+	singletonShipper = newShipper()
+	var reported []int
+	for i := 0; i < 10; i++ {
+		i := i
+		reportInBackground(TriggerTypeTrialStateChange, func(ctx context.Context) error {
+			_, ok := ctx.Deadline()
+			require.True(t, ok, "reports should have a deadline")
+			reported = append(reported, i)
+			return nil
+		})
+	}
+	singletonShipper.Close()
+	require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, reported, "reports should finish in order")
+}
+
 func scheduledWaitToDuration(factor int) time.Duration {
 	return 10 * time.Duration(factor) * time.Millisecond
 }
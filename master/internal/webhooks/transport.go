@@ -0,0 +1,431 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+
+	conf "github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+// transport renders the request bodies a type of webhook is sent. Rendered bodies are queued as
+// events and delivered by the shipper, so every transport shares its retries and request signing.
+type transport interface {
+	experimentStateChanged(
+		ctx context.Context, w *Webhook, e model.Experiment, activeConfig expconf.ExperimentConfig,
+	) ([]byte, error)
+	taskLog(
+		ctx context.Context, w *Webhook, taskID model.TaskID, nodeName, regex, triggeringLog string,
+	) ([]byte, error)
+	// event renders every other event, all of which are described by their EventPayload alone.
+	event(ctx context.Context, w *Webhook, p EventPayload) ([]byte, error)
+	test(ctx context.Context, w *Webhook) ([]byte, error)
+}
+
+var transports = map[WebhookType]transport{
+	WebhookTypeDefault:   eventTransport(renderDefault),
+	WebhookTypeSlack:     slackTransport{},
+	WebhookTypeTeams:     eventTransport(renderTeams),
+	WebhookTypePagerDuty: eventTransport(renderPagerDuty),
+	WebhookTypeTemplated: eventTransport(renderTemplated),
+}
+
+func transportFor(wt WebhookType) (transport, error) {
+	t, ok := transports[wt]
+	if !ok {
+		return nil, fmt.Errorf("unknown webhook type: %+v", wt)
+	}
+	return t, nil
+}
+
+// eventTransport is a transport that renders every event from its EventPayload, which is also the
+// body sent to DEFAULT webhooks.
+type eventTransport func(w *Webhook, p EventPayload) ([]byte, error)
+
+func (r eventTransport) experimentStateChanged(
+	_ context.Context, w *Webhook, e model.Experiment, activeConfig expconf.ExperimentConfig,
+) ([]byte, error) {
+	return r(w, EventPayload{
+		ID:        uuid.New(),
+		Type:      TriggerTypeStateChange,
+		Timestamp: time.Now().Unix(),
+		Condition: Condition{
+			State: e.State,
+		},
+		Data: EventData{
+			Experiment: experimentToWebhookPayload(e, activeConfig),
+		},
+	})
+}
+
+func (r eventTransport) taskLog(
+	_ context.Context, w *Webhook, taskID model.TaskID, nodeName, regex, triggeringLog string,
+) ([]byte, error) {
+	return r(w, EventPayload{
+		ID:        uuid.New(),
+		Type:      TriggerTypeTaskLog,
+		Timestamp: time.Now().Unix(),
+		Condition: Condition{
+			Regex: regex,
+		},
+		Data: EventData{
+			TaskLog: &TaskLogPayload{
+				TaskID:        taskID,
+				NodeName:      nodeName,
+				TriggeringLog: triggeringLog,
+			},
+		},
+	})
+}
+
+func (r eventTransport) event(_ context.Context, w *Webhook, p EventPayload) ([]byte, error) {
+	return r(w, p)
+}
+
+func (r eventTransport) test(_ context.Context, w *Webhook) ([]byte, error) {
+	return r(w, testEventPayload())
+}
+
+func testEventPayload() EventPayload {
+	return EventPayload{
+		ID:        uuid.New(),
+		Timestamp: time.Now().Unix(),
+		Type:      TriggerTypeStateChange,
+		Condition: Condition{
+			State: model.CompletedState,
+		},
+		Data: EventData{
+			TestData: ptrs.Ptr("test"),
+		},
+	}
+}
+
+func renderDefault(_ *Webhook, p EventPayload) ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// eventSummary returns a one line description of an event for transports that display text.
+func eventSummary(p EventPayload) string {
+	switch {
+	case p.Data.Experiment != nil:
+		e := p.Data.Experiment
+		return fmt.Sprintf("Experiment %s (#%d) is %s", e.Name, e.ID, e.State)
+	case p.Data.TaskLog != nil:
+		l := p.Data.TaskLog
+		return fmt.Sprintf("Task %s on node %s reported a log matching %q",
+			l.TaskID, l.NodeName, p.Condition.Regex)
+	case p.Data.Trial != nil:
+		t := p.Data.Trial
+		return fmt.Sprintf("Trial %d of experiment %d is %s", t.ID, t.ExperimentID, t.State)
+	case p.Data.Checkpoint != nil:
+		c := p.Data.Checkpoint
+		return fmt.Sprintf("Checkpoint %s is %s", c.UUID, c.State)
+	case p.Data.ModelVersion != nil:
+		mv := p.Data.ModelVersion
+		return fmt.Sprintf("Version %d of model %s was registered", mv.Version, mv.ModelName)
+	case p.Data.Allocation != nil:
+		a := p.Data.Allocation
+		return fmt.Sprintf("Allocation %s of %s %s was preempted", a.AllocationID,
+			strings.ToLower(string(a.TaskType)), a.TaskID)
+	case p.Data.Task != nil:
+		t := p.Data.Task
+		return fmt.Sprintf("%s %s terminated: %s", t.TaskType, t.TaskID, t.ExitStatus)
+	default:
+		return "Test event from Determined"
+	}
+}
+
+// eventURL returns a link to the web UI for an event, or "" if there is none.
+func eventURL(p EventPayload) string {
+	baseURL := conf.GetMasterConfig().Webhooks.BaseURL
+	switch {
+	case baseURL == "":
+		return ""
+	case p.Data.Experiment != nil:
+		return fmt.Sprintf("%s/det/experiments/%d/overview", baseURL, p.Data.Experiment.ID)
+	case p.Data.Trial != nil:
+		return fmt.Sprintf("%s/det/experiments/%d/trials/%d",
+			baseURL, p.Data.Trial.ExperimentID, p.Data.Trial.ID)
+	case p.Data.ModelVersion != nil:
+		return fmt.Sprintf("%s/det/models/%d/versions/%d",
+			baseURL, p.Data.ModelVersion.ModelID, p.Data.ModelVersion.Version)
+	default:
+		return ""
+	}
+}
+
+// TeamsMessage corresponds to a Microsoft Teams message carrying an Adaptive Card.
+type TeamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+// TeamsAttachment corresponds to an attachment of a Microsoft Teams message.
+type TeamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     TeamsCard `json:"content"`
+}
+
+// TeamsCard corresponds to an Adaptive Card.
+type TeamsCard struct {
+	Schema  string         `json:"$schema"`
+	Type    string         `json:"type"`
+	Version string         `json:"version"`
+	Body    []TeamsElement `json:"body"`
+	Actions []TeamsAction  `json:"actions,omitempty"`
+}
+
+// TeamsElement corresponds to an Adaptive Card element.
+type TeamsElement struct {
+	Type   string      `json:"type"`
+	Text   string      `json:"text,omitempty"`
+	Weight string      `json:"weight,omitempty"`
+	Color  string      `json:"color,omitempty"`
+	Wrap   bool        `json:"wrap,omitempty"`
+	Facts  []TeamsFact `json:"facts,omitempty"`
+}
+
+// TeamsFact corresponds to a fact in an Adaptive Card FactSet.
+type TeamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// TeamsAction corresponds to an Adaptive Card action.
+type TeamsAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+func renderTeams(_ *Webhook, p EventPayload) ([]byte, error) {
+	color := "Warning"
+	var facts []TeamsFact
+	switch {
+	case p.Data.Experiment != nil:
+		e := p.Data.Experiment
+		if e.State == model.CompletedState {
+			color = "Good"
+		} else {
+			color = "Attention"
+		}
+		facts = append(facts,
+			TeamsFact{Title: "Status", Value: string(e.State)},
+			TeamsFact{Title: "Duration", Value: (time.Duration(e.Duration) * time.Second).String()},
+		)
+		if e.WorkspaceName != "" {
+			facts = append(facts, TeamsFact{Title: "Workspace", Value: e.WorkspaceName})
+		}
+		if e.ProjectName != "" {
+			facts = append(facts, TeamsFact{Title: "Project", Value: e.ProjectName})
+		}
+	case p.Data.TaskLog != nil:
+		l := p.Data.TaskLog
+		facts = append(facts,
+			TeamsFact{Title: "Task", Value: string(l.TaskID)},
+			TeamsFact{Title: "Node", Value: l.NodeName},
+			TeamsFact{Title: "Regex", Value: p.Condition.Regex},
+			TeamsFact{Title: "Log", Value: l.TriggeringLog},
+		)
+	case p.Data.Trial != nil:
+		if p.Data.Trial.State == model.CompletedState {
+			color = "Good"
+		} else if p.Data.Trial.State == model.ErrorState {
+			color = "Attention"
+		}
+	case p.Data.Checkpoint != nil, p.Data.ModelVersion != nil:
+		color = "Good"
+	case p.Data.Allocation != nil, p.Data.Task != nil:
+	default:
+		color = "Default"
+	}
+
+	card := TeamsCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []TeamsElement{
+			{Type: "TextBlock", Text: eventSummary(p), Weight: "Bolder", Color: color, Wrap: true},
+		},
+	}
+	if len(facts) > 0 {
+		card.Body = append(card.Body, TeamsElement{Type: "FactSet", Facts: facts})
+	}
+	if u := eventURL(p); u != "" {
+		card.Actions = append(card.Actions, TeamsAction{
+			Type: "Action.OpenUrl", Title: "View in Determined", URL: u,
+		})
+	}
+
+	message, err := json.Marshal(TeamsMessage{
+		Type: "message",
+		Attachments: []TeamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content:     card,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating teams payload: %w", err)
+	}
+	return message, nil
+}
+
+// pagerDutyMaxSummaryLength is the longest summary the PagerDuty Events API accepts.
+const pagerDutyMaxSummaryLength = 1024
+
+// PagerDutyEvent corresponds to a PagerDuty Events API v2 event.
+type PagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	DedupKey    string           `json:"dedup_key,omitempty"`
+	Payload     PagerDutyPayload `json:"payload"`
+	Links       []PagerDutyLink  `json:"links,omitempty"`
+}
+
+// PagerDutyPayload corresponds to the payload of a PagerDuty Events API v2 event.
+type PagerDutyPayload struct {
+	Summary       string    `json:"summary"`
+	Source        string    `json:"source"`
+	Severity      string    `json:"severity"`
+	Timestamp     string    `json:"timestamp,omitempty"`
+	Class         string    `json:"class,omitempty"`
+	CustomDetails EventData `json:"custom_details"`
+}
+
+// PagerDutyLink corresponds to a link attached to a PagerDuty Events API v2 event.
+type PagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text,omitempty"`
+}
+
+func renderPagerDuty(w *Webhook, p EventPayload) ([]byte, error) {
+	if w.RoutingKey == nil {
+		return nil, fmt.Errorf("webhook %d has no routing key", w.ID)
+	}
+
+	severity := "info"
+	switch {
+	case p.Data.Experiment != nil && p.Data.Experiment.State != model.CompletedState,
+		p.Data.Trial != nil && p.Data.Trial.State == model.ErrorState:
+		severity = "error"
+	case p.Data.TaskLog != nil, p.Data.Allocation != nil:
+		severity = "warning"
+	}
+	summary := eventSummary(p)
+	if len(summary) > pagerDutyMaxSummaryLength {
+		summary = summary[:pagerDutyMaxSummaryLength]
+	}
+
+	event := PagerDutyEvent{
+		RoutingKey:  *w.RoutingKey,
+		EventAction: "trigger",
+		// Keying on the event ID makes retried deliveries of the same event idempotent.
+		DedupKey: p.ID.String(),
+		Payload: PagerDutyPayload{
+			Summary:       summary,
+			Source:        "determined",
+			Severity:      severity,
+			Timestamp:     time.Unix(p.Timestamp, 0).UTC().Format(time.RFC3339),
+			Class:         string(p.Type),
+			CustomDetails: p.Data,
+		},
+	}
+	if u := eventURL(p); u != "" {
+		event.Links = append(event.Links, PagerDutyLink{Href: u, Text: "View in Determined"})
+	}
+
+	message, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("creating pagerduty payload: %w", err)
+	}
+	return message, nil
+}
+
+// renderTemplated renders the webhook's template. The template is executed against the JSON
+// form of the EventPayload, so it refers to fields the same way as DEFAULT webhook payloads,
+// e.g. {{ .event_data.experiment.name }}. The json function quotes a value as JSON.
+func renderTemplated(w *Webhook, p EventPayload) ([]byte, error) {
+	if w.Template == nil {
+		return nil, fmt.Errorf("webhook %d has no template", w.ID)
+	}
+	tmpl, err := template.New("webhook").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			bs, err := json.Marshal(v)
+			return string(bs), err
+		},
+	}).Parse(*w.Template)
+	if err != nil {
+		return nil, fmt.Errorf("parsing webhook template: %w", err)
+	}
+
+	bs, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(bs, &data); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("executing webhook template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// slackTransport renders Slack Block Kit messages.
+type slackTransport struct{}
+
+func (slackTransport) experimentStateChanged(
+	ctx context.Context, _ *Webhook, e model.Experiment, activeConfig expconf.ExperimentConfig,
+) ([]byte, error) {
+	return generateSlackPayload(ctx, e, activeConfig)
+}
+
+func (slackTransport) taskLog(
+	ctx context.Context, _ *Webhook, taskID model.TaskID, nodeName, regex, triggeringLog string,
+) ([]byte, error) {
+	return generateLogPatternSlackPayload(ctx, taskID, nodeName, regex, triggeringLog)
+}
+
+func (slackTransport) event(_ context.Context, _ *Webhook, p EventPayload) ([]byte, error) {
+	msg := eventSummary(p)
+	if u := eventURL(p); u != "" {
+		msg = fmt.Sprintf("<%s | %s>", u, msg)
+	}
+	return json.Marshal(SlackMessageBody{
+		Blocks: []SlackBlock{
+			{
+				Type: "section",
+				Text: SlackField{
+					Type: "mrkdwn",
+					Text: msg,
+				},
+			},
+		},
+	})
+}
+
+func (slackTransport) test(context.Context, *Webhook) ([]byte, error) {
+	return json.Marshal(SlackMessageBody{
+		Blocks: []SlackBlock{
+			{
+				Text: SlackField{
+					Text: "test",
+					Type: "plain_text",
+				},
+				Type: "section",
+			},
+		},
+	})
+}
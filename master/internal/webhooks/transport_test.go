@@ -0,0 +1,284 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func testExperiment() (model.Experiment, expconf.ExperimentConfig) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Minute)
+	e := model.Experiment{ID: 7, State: model.ErrorState, StartTime: start, EndTime: &end}
+	activeConfig := schemas.WithDefaults(expconf.ExperimentConfig{
+		RawName:      expconf.Name{RawString: ptrs.Ptr("mnist")},
+		RawWorkspace: ptrs.Ptr("ws"),
+		RawProject:   ptrs.Ptr("proj"),
+	})
+	return e, activeConfig
+}
+
+func TestTeamsTransport(t *testing.T) {
+	ctx := context.Background()
+	w := &Webhook{WebhookType: WebhookTypeTeams}
+	tr, err := transportFor(w.WebhookType)
+	require.NoError(t, err)
+
+	e, activeConfig := testExperiment()
+	p, err := tr.experimentStateChanged(ctx, w, e, activeConfig)
+	require.NoError(t, err)
+
+	var msg TeamsMessage
+	require.NoError(t, json.Unmarshal(p, &msg))
+	require.Equal(t, "message", msg.Type)
+	require.Len(t, msg.Attachments, 1)
+	card := msg.Attachments[0].Content
+	require.Equal(t, "AdaptiveCard", card.Type)
+	require.Equal(t, TeamsElement{
+		Type:   "TextBlock",
+		Text:   "Experiment mnist (#7) is ERROR",
+		Weight: "Bolder",
+		Color:  "Attention",
+		Wrap:   true,
+	}, card.Body[0])
+	require.Equal(t, []TeamsFact{
+		{Title: "Status", Value: "ERROR"},
+		{Title: "Duration", Value: "1h30m0s"},
+		{Title: "Workspace", Value: "ws"},
+		{Title: "Project", Value: "proj"},
+	}, card.Body[1].Facts)
+}
+
+func TestPagerDutyTransport(t *testing.T) {
+	ctx := context.Background()
+	w := &Webhook{WebhookType: WebhookTypePagerDuty, RoutingKey: ptrs.Ptr("key")}
+	tr, err := transportFor(w.WebhookType)
+	require.NoError(t, err)
+
+	p, err := tr.taskLog(ctx, w, "task", "node", "OOM", "CUDA OOM")
+	require.NoError(t, err)
+
+	var event PagerDutyEvent
+	require.NoError(t, json.Unmarshal(p, &event))
+	require.Equal(t, "key", event.RoutingKey)
+	require.Equal(t, "trigger", event.EventAction)
+	require.NotEmpty(t, event.DedupKey)
+	require.Equal(t, `Task task on node node reported a log matching "OOM"`, event.Payload.Summary)
+	require.Equal(t, "warning", event.Payload.Severity)
+	require.Equal(t, string(TriggerTypeTaskLog), event.Payload.Class)
+	require.Equal(t, &TaskLogPayload{
+		TaskID: "task", NodeName: "node", TriggeringLog: "CUDA OOM",
+	}, event.Payload.CustomDetails.TaskLog)
+}
+
+func TestTemplatedTransport(t *testing.T) {
+	ctx := context.Background()
+	w := &Webhook{
+		WebhookType: WebhookTypeTemplated,
+		Template: ptrs.Ptr(`{"text": {{ json (printf "%s is %s" ` +
+			`.event_data.experiment.name .event_data.experiment.state) }}, ` +
+			`"id": {{ .event_data.experiment.id }}}`),
+	}
+	tr, err := transportFor(w.WebhookType)
+	require.NoError(t, err)
+
+	e, activeConfig := testExperiment()
+	p, err := tr.experimentStateChanged(ctx, w, e, activeConfig)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"text": "mnist is ERROR", "id": 7}`, string(p))
+}
+
+func TestWebhookValidate(t *testing.T) {
+	triggers := Triggers{{TriggerType: TriggerTypeStateChange, Condition: map[string]interface{}{
+		"state": "COMPLETED",
+	}}}
+	cases := []struct {
+		name    string
+		webhook Webhook
+		err     string
+	}{
+		{
+			name:    "valid default",
+			webhook: Webhook{WebhookType: WebhookTypeDefault, URL: "http://a", Triggers: triggers},
+		},
+		{
+			name:    "no triggers",
+			webhook: Webhook{WebhookType: WebhookTypeDefault, URL: "http://a"},
+			err:     "at least one trigger required",
+		},
+		{
+			name:    "unknown type",
+			webhook: Webhook{WebhookType: "EMAIL", URL: "http://a", Triggers: triggers},
+			err:     `unknown webhook type "EMAIL"`,
+		},
+		{
+			name:    "pagerduty without routing key",
+			webhook: Webhook{WebhookType: WebhookTypePagerDuty, URL: "http://a", Triggers: triggers},
+			err:     "webhook of type PAGERDUTY requires a routing_key",
+		},
+		{
+			name: "templated with invalid template",
+			webhook: Webhook{
+				WebhookType: WebhookTypeTemplated, URL: "http://a", Triggers: triggers,
+				Template: ptrs.Ptr("{{ .event_id "),
+			},
+			err: "parsing webhook template",
+		},
+		{
+			name: "workspace and project",
+			webhook: Webhook{
+				WebhookType: WebhookTypeDefault, URL: "http://a", Triggers: triggers,
+				WorkspaceID: ptrs.Ptr(1), ProjectID: ptrs.Ptr(1),
+			},
+			err: "scoped to a workspace or a project but not both",
+		},
+		{
+			name: "task log without regex",
+			webhook: Webhook{WebhookType: WebhookTypeTeams, URL: "http://a", Triggers: Triggers{
+				{TriggerType: TriggerTypeTaskLog, Condition: map[string]interface{}{"re": "x"}},
+			}},
+			err: "webhook task log condition must have key 'regex'",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.webhook.validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestTriggerValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		trigger Trigger
+		err     string
+	}{
+		{
+			name: "trial state change",
+			trigger: Trigger{TriggerType: TriggerTypeTrialStateChange, Condition: map[string]interface{}{
+				"state": "ERROR",
+			}},
+		},
+		{
+			name:    "trial state change without state",
+			trigger: Trigger{TriggerType: TriggerTypeTrialStateChange, Condition: map[string]interface{}{}},
+			err:     "must have key 'state'",
+		},
+		{
+			name: "checkpoint state change with unsupported state",
+			trigger: Trigger{
+				TriggerType: TriggerTypeCheckpointStateChange,
+				Condition:   map[string]interface{}{"state": "ACTIVE"},
+			},
+			err: "set to COMPLETED or DELETED",
+		},
+		{
+			name:    "model version registered without regex",
+			trigger: Trigger{TriggerType: TriggerTypeModelVersionRegistered},
+		},
+		{
+			name: "model version registered with invalid regex",
+			trigger: Trigger{
+				TriggerType: TriggerTypeModelVersionRegistered,
+				Condition:   map[string]interface{}{"regex": "("},
+			},
+			err: "compiling regex",
+		},
+		{
+			name: "task terminated with non-string task type",
+			trigger: Trigger{TriggerType: TriggerTypeTaskTerminated, Condition: map[string]interface{}{
+				"task_type": 1,
+			}},
+			err: "must have key 'task_type' as string",
+		},
+		{
+			name:    "unknown trigger type",
+			trigger: Trigger{TriggerType: "JOB_QUEUED"},
+			err:     `unknown trigger type "JOB_QUEUED"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.trigger.validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestTriggerMatches(t *testing.T) {
+	anyTask := &Trigger{
+		TriggerType: TriggerTypeAllocationPreempted,
+		Condition:   map[string]interface{}{},
+	}
+	require.True(t, anyTask.matches(Condition{TaskType: model.TaskTypeNotebook}, ""))
+
+	byTaskType := &Trigger{TriggerType: TriggerTypeTaskTerminated, Condition: map[string]interface{}{
+		"task_type": string(model.TaskTypeCommand),
+	}}
+	require.True(t, byTaskType.matches(Condition{TaskType: model.TaskTypeCommand}, ""))
+	require.False(t, byTaskType.matches(Condition{TaskType: model.TaskTypeShell}, ""))
+
+	byState := &Trigger{TriggerType: TriggerTypeTrialStateChange, Condition: map[string]interface{}{
+		"state": string(model.ErrorState),
+	}}
+	require.True(t, byState.matches(Condition{State: model.ErrorState}, ""))
+	require.False(t, byState.matches(Condition{State: model.CompletedState}, ""))
+
+	byName := &Trigger{
+		TriggerType: TriggerTypeModelVersionRegistered,
+		Condition:   map[string]interface{}{"regex": "^prod-"},
+	}
+	require.True(t, byName.matches(Condition{}, "prod-resnet"))
+	require.False(t, byName.matches(Condition{}, "dev-resnet"))
+}
+
+func TestDefaultTransportLifecycleEvent(t *testing.T) {
+	w := &Webhook{WebhookType: WebhookTypeDefault}
+	tr, err := transportFor(w.WebhookType)
+	require.NoError(t, err)
+
+	p, err := tr.event(context.Background(), w, EventPayload{
+		Type: TriggerTypeTaskTerminated,
+		Condition: Condition{
+			TaskType: model.TaskTypeCommand,
+		},
+		Data: EventData{Task: &TaskPayload{
+			TaskID: "cmd", TaskType: model.TaskTypeCommand, ExitStatus: "exited",
+		}},
+	})
+	require.NoError(t, err)
+
+	var got EventPayload
+	require.NoError(t, json.Unmarshal(p, &got))
+	require.Equal(t, TriggerTypeTaskTerminated, got.Type)
+	require.Equal(t, "cmd", string(got.Data.Task.TaskID))
+	require.Nil(t, got.Data.Experiment)
+}
+
+func TestWebhookInScope(t *testing.T) {
+	s := scope{workspaceID: 2, projectID: 3}
+	require.True(t, (&Webhook{}).inScope(s))
+	require.True(t, (&Webhook{}).inScope(scope{}))
+	require.True(t, (&Webhook{WorkspaceID: ptrs.Ptr(2)}).inScope(s))
+	require.False(t, (&Webhook{WorkspaceID: ptrs.Ptr(3)}).inScope(s))
+	require.False(t, (&Webhook{WorkspaceID: ptrs.Ptr(2)}).inScope(scope{}))
+	require.True(t, (&Webhook{ProjectID: ptrs.Ptr(3)}).inScope(s))
+	require.False(t, (&Webhook{ProjectID: ptrs.Ptr(2)}).inScope(s))
+}
@@ -2,11 +2,18 @@ package webhooks
 
 import (
 	"fmt"
+	"net/url"
+	"regexp"
+	"time"
 
 	"github.com/uptrace/bun"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 	"github.com/determined-ai/determined/proto/pkg/webhookv1"
 
@@ -29,20 +36,40 @@ func (ws Webhooks) Proto() []*webhookv1.Webhook {
 type Webhook struct {
 	bun.BaseModel `bun:"table:webhooks"`
 
-	ID          WebhookID   `bun:"id,pk,autoincrement"`
-	WebhookType WebhookType `bun:"webhook_type,notnull"`
-	URL         string      `bun:"url,notnull"`
-
-	Triggers Triggers `bun:"rel:has-many,join:id=webhook_id"`
+	ID          WebhookID   `bun:"id,pk,autoincrement" json:"id"`
+	WebhookType WebhookType `bun:"webhook_type,notnull" json:"webhook_type"`
+	URL         string      `bun:"url,notnull" json:"url"`
+	// Template is the text/template that TEMPLATED webhooks render their payloads from.
+	Template *string `bun:"template" json:"template,omitempty"`
+	// RoutingKey is the integration key that PAGERDUTY webhooks send events to.
+	RoutingKey *string `bun:"routing_key" json:"routing_key,omitempty"`
+	// WorkspaceID and ProjectID scope a webhook to the experiments and tasks of one workspace or
+	// project. Webhooks with neither set are cluster-wide and fire for every event.
+	WorkspaceID *int `bun:"workspace_id" json:"workspace_id,omitempty"`
+	ProjectID   *int `bun:"project_id" json:"project_id,omitempty"`
+
+	Triggers Triggers `bun:"rel:has-many,join:id=webhook_id" json:"triggers"`
 }
 
 // WebhookFromProto returns a model Webhook from a proto definition.
-func WebhookFromProto(w *webhookv1.Webhook) Webhook {
+func WebhookFromProto(w *webhookv1.Webhook) (Webhook, error) {
+	webhookType, err := WebhookTypeFromProto(w.WebhookType)
+	if err != nil {
+		return Webhook{}, err
+	}
+	triggers, err := TriggersFromProto(w.Triggers)
+	if err != nil {
+		return Webhook{}, err
+	}
 	return Webhook{
 		URL:         w.Url,
-		Triggers:    TriggersFromProto(w.Triggers),
-		WebhookType: WebhookTypeFromProto(w.WebhookType),
-	}
+		Triggers:    triggers,
+		WebhookType: webhookType,
+		Template:    w.Template,
+		RoutingKey:  w.RoutingKey,
+		WorkspaceID: i32Ptr2iPtr(w.WorkspaceId),
+		ProjectID:   i32Ptr2iPtr(w.ProjectId),
+	}, nil
 }
 
 // Proto converts a webhook to its protobuf representation.
@@ -52,9 +79,27 @@ func (w *Webhook) Proto() *webhookv1.Webhook {
 		Url:         w.URL,
 		Triggers:    w.Triggers.Proto(),
 		WebhookType: w.WebhookType.Proto(),
+		Template:    w.Template,
+		RoutingKey:  w.RoutingKey,
+		WorkspaceId: iPtr2i32Ptr(w.WorkspaceID),
+		ProjectId:   iPtr2i32Ptr(w.ProjectID),
 	}
 }
 
+func i32Ptr2iPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	return ptrs.Ptr(int(*v))
+}
+
+func iPtr2i32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	return ptrs.Ptr(int32(*v))
+}
+
 // WebhookID is the type for Webhook IDs.
 type WebhookID int
 
@@ -65,12 +110,16 @@ type WebhookType string
 type Triggers []*Trigger
 
 // TriggersFromProto returns a slice of model Triggers from a proto definition.
-func TriggersFromProto(ts []*webhookv1.Trigger) Triggers {
+func TriggersFromProto(ts []*webhookv1.Trigger) (Triggers, error) {
 	out := make(Triggers, len(ts))
 	for i, t := range ts {
-		out[i] = TriggerFromProto(t)
+		trigger, err := TriggerFromProto(t)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = trigger
 	}
-	return out
+	return out, nil
 }
 
 // Proto converts a slice of triggers to its protobuf representation.
@@ -86,12 +135,12 @@ func (ts Triggers) Proto() []*webhookv1.Trigger {
 type Trigger struct {
 	bun.BaseModel `bun:"table:webhook_triggers"`
 
-	ID          TriggerID              `bun:"id,pk,autoincrement"`
-	TriggerType TriggerType            `bun:"trigger_type,notnull"`
-	Condition   map[string]interface{} `bun:"condition,notnull"`
-	WebhookID   WebhookID              `bun:"webhook_id,notnull"`
+	ID          TriggerID              `bun:"id,pk,autoincrement" json:"id"`
+	TriggerType TriggerType            `bun:"trigger_type,notnull" json:"trigger_type"`
+	Condition   map[string]interface{} `bun:"condition,notnull" json:"condition"`
+	WebhookID   WebhookID              `bun:"webhook_id,notnull" json:"webhook_id"`
 
-	Webhook *Webhook `bun:"rel:belongs-to,join:webhook_id=id"`
+	Webhook *Webhook `bun:"rel:belongs-to,join:webhook_id=id" json:"-"`
 }
 
 // Used for deduping webhook events.
@@ -103,11 +152,15 @@ type webhookTaskLogTrigger struct {
 }
 
 // TriggerFromProto returns a Trigger from a proto definition.
-func TriggerFromProto(t *webhookv1.Trigger) *Trigger {
+func TriggerFromProto(t *webhookv1.Trigger) (*Trigger, error) {
+	triggerType, err := TriggerTypeFromProto(t.TriggerType)
+	if err != nil {
+		return nil, err
+	}
 	return &Trigger{
-		TriggerType: TriggerTypeFromProto(t.TriggerType),
+		TriggerType: triggerType,
 		Condition:   t.Condition.AsMap(),
-	}
+	}, nil
 }
 
 // Proto converts a Trigger to its protobuf representation.
@@ -135,6 +188,21 @@ const (
 
 	// TriggerTypeTaskLog represents a trigger for a task logs.
 	TriggerTypeTaskLog TriggerType = "TASK_LOG"
+
+	// TriggerTypeTrialStateChange represents a change in trial state.
+	TriggerTypeTrialStateChange TriggerType = "TRIAL_STATE_CHANGE"
+
+	// TriggerTypeCheckpointStateChange represents a checkpoint completing or being deleted.
+	TriggerTypeCheckpointStateChange TriggerType = "CHECKPOINT_STATE_CHANGE"
+
+	// TriggerTypeModelVersionRegistered represents a new version of a registered model.
+	TriggerTypeModelVersionRegistered TriggerType = "MODEL_VERSION_REGISTERED"
+
+	// TriggerTypeAllocationPreempted represents an allocation being preempted.
+	TriggerTypeAllocationPreempted TriggerType = "ALLOCATION_PREEMPTED"
+
+	// TriggerTypeTaskTerminated represents a notebook, shell, command or tensorboard exiting.
+	TriggerTypeTaskTerminated TriggerType = "TASK_TERMINATED"
 )
 
 const (
@@ -143,33 +211,190 @@ const (
 
 	// WebhookTypeSlack represents a slack webhook.
 	WebhookTypeSlack WebhookType = "SLACK"
+
+	// WebhookTypeTeams represents a Microsoft Teams webhook.
+	WebhookTypeTeams WebhookType = "TEAMS"
+
+	// WebhookTypePagerDuty represents a PagerDuty Events API v2 webhook.
+	WebhookTypePagerDuty WebhookType = "PAGERDUTY"
+
+	// WebhookTypeTemplated represents a webhook whose payload is rendered from a Go template.
+	WebhookTypeTemplated WebhookType = "TEMPLATED"
 )
 
+// validate checks that a webhook is well-formed before it is saved.
+func (w *Webhook) validate() error {
+	if len(w.Triggers) == 0 {
+		return fmt.Errorf("at least one trigger required")
+	}
+	if _, err := url.ParseRequestURI(w.URL); err != nil {
+		return fmt.Errorf("valid url required")
+	}
+	if w.WorkspaceID != nil && w.ProjectID != nil {
+		return fmt.Errorf("webhook can be scoped to a workspace or a project but not both")
+	}
+	for _, t := range w.Triggers {
+		if err := t.validate(); err != nil {
+			return err
+		}
+	}
+
+	switch w.WebhookType {
+	case WebhookTypeDefault, WebhookTypeSlack, WebhookTypeTeams:
+	case WebhookTypePagerDuty:
+		if w.RoutingKey == nil || *w.RoutingKey == "" {
+			return fmt.Errorf("webhook of type %s requires a routing_key", w.WebhookType)
+		}
+	case WebhookTypeTemplated:
+		if w.Template == nil || *w.Template == "" {
+			return fmt.Errorf("webhook of type %s requires a template", w.WebhookType)
+		}
+		// Render a test event so that templates that can never execute are rejected up front.
+		if _, err := renderTemplated(w, testEventPayload()); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown webhook type %q", w.WebhookType)
+	}
+	return nil
+}
+
+// scope is the workspace and project that an event happened in. Events that did not happen in a
+// workspace, such as those of tasks that were deleted, have a zero scope.
+type scope struct {
+	workspaceID int
+	projectID   int
+}
+
+// scoped returns whether the webhook belongs to a workspace or project.
+func (w *Webhook) scoped() bool {
+	return w.WorkspaceID != nil || w.ProjectID != nil
+}
+
+// inScope returns whether events in the given scope are sent to the webhook.
+func (w *Webhook) inScope(s scope) bool {
+	switch {
+	case w.WorkspaceID != nil:
+		return *w.WorkspaceID == s.workspaceID
+	case w.ProjectID != nil:
+		return *w.ProjectID == s.projectID
+	default:
+		return true
+	}
+}
+
+// validate checks that a trigger's condition matches its type.
+func (t *Trigger) validate() error {
+	switch t.TriggerType {
+	case TriggerTypeStateChange, TriggerTypeMetricThresholdExceeded:
+	case TriggerTypeTrialStateChange:
+		if _, ok := t.Condition[stateConditionKey].(string); !ok {
+			return fmt.Errorf("webhook %s condition must have key '%s' as string got %v",
+				t.TriggerType, stateConditionKey, t.Condition)
+		}
+	case TriggerTypeCheckpointStateChange:
+		state := t.Condition[stateConditionKey]
+		if state != string(model.CompletedState) && state != string(model.DeletedState) {
+			return fmt.Errorf("webhook %s condition must have key '%s' set to %s or %s got %v",
+				t.TriggerType, stateConditionKey, model.CompletedState, model.DeletedState,
+				t.Condition)
+		}
+	case TriggerTypeTaskLog:
+		if len(t.Condition) != 1 {
+			return fmt.Errorf("webhook task log condition must have one key got %v", t.Condition)
+		}
+
+		v, ok := t.Condition[regexConditionKey]
+		if !ok {
+			return fmt.Errorf("webhook task log condition must have key '%s' got %v",
+				regexConditionKey, t.Condition)
+		}
+
+		if _, typeOK := v.(string); !typeOK {
+			return fmt.Errorf("webhook task log condition must have key '%s' as string got %v",
+				regexConditionKey, t.Condition)
+		}
+	case TriggerTypeModelVersionRegistered:
+		if v, ok := t.Condition[regexConditionKey]; ok {
+			regex, typeOK := v.(string)
+			if !typeOK {
+				return fmt.Errorf("webhook %s condition must have key '%s' as string got %v",
+					t.TriggerType, regexConditionKey, t.Condition)
+			}
+			if _, err := regexp.Compile(regex); err != nil {
+				return fmt.Errorf("compiling regex %s: %w", regex, err)
+			}
+		}
+	case TriggerTypeAllocationPreempted, TriggerTypeTaskTerminated:
+		if v, ok := t.Condition[taskTypeConditionKey]; ok {
+			if _, typeOK := v.(string); !typeOK {
+				return fmt.Errorf("webhook %s condition must have key '%s' as string got %v",
+					t.TriggerType, taskTypeConditionKey, t.Condition)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown trigger type %q", t.TriggerType)
+	}
+	return nil
+}
+
+// matches returns whether an event satisfies the trigger's condition; name is matched by regex
+// conditions. Keys that are absent from the condition match any event.
+func (t *Trigger) matches(c Condition, name string) bool {
+	if state, ok := t.Condition[stateConditionKey]; ok && state != string(c.State) {
+		return false
+	}
+	if taskType, ok := t.Condition[taskTypeConditionKey]; ok && taskType != string(c.TaskType) {
+		return false
+	}
+	if regex, ok := t.Condition[regexConditionKey].(string); ok {
+		re, err := regexp.Compile(regex)
+		if err != nil || !re.MatchString(name) {
+			return false
+		}
+	}
+	return true
+}
+
 // WebhookTypeFromProto returns a WebhookType from a proto.
-func WebhookTypeFromProto(w webhookv1.WebhookType) WebhookType {
+func WebhookTypeFromProto(w webhookv1.WebhookType) (WebhookType, error) {
 	switch w {
 	case webhookv1.WebhookType_WEBHOOK_TYPE_DEFAULT:
-		return WebhookTypeDefault
+		return WebhookTypeDefault, nil
 	case webhookv1.WebhookType_WEBHOOK_TYPE_SLACK:
-		return WebhookTypeSlack
+		return WebhookTypeSlack, nil
+	case webhookv1.WebhookType_WEBHOOK_TYPE_TEAMS:
+		return WebhookTypeTeams, nil
+	case webhookv1.WebhookType_WEBHOOK_TYPE_PAGERDUTY:
+		return WebhookTypePagerDuty, nil
+	case webhookv1.WebhookType_WEBHOOK_TYPE_TEMPLATED:
+		return WebhookTypeTemplated, nil
 	default:
-		// TODO(???): prob don't panic
-		panic(fmt.Errorf("missing mapping for webhook type %s to SQL", w))
+		return "", status.Errorf(codes.InvalidArgument, "unknown webhook type %s", w)
 	}
 }
 
 // TriggerTypeFromProto returns a TriggerType from a proto.
-func TriggerTypeFromProto(t webhookv1.TriggerType) TriggerType {
+func TriggerTypeFromProto(t webhookv1.TriggerType) (TriggerType, error) {
 	switch t {
 	case webhookv1.TriggerType_TRIGGER_TYPE_METRIC_THRESHOLD_EXCEEDED:
-		return TriggerTypeMetricThresholdExceeded
+		return TriggerTypeMetricThresholdExceeded, nil
 	case webhookv1.TriggerType_TRIGGER_TYPE_EXPERIMENT_STATE_CHANGE:
-		return TriggerTypeStateChange
+		return TriggerTypeStateChange, nil
 	case webhookv1.TriggerType_TRIGGER_TYPE_TASK_LOG:
-		return TriggerTypeTaskLog
+		return TriggerTypeTaskLog, nil
+	case webhookv1.TriggerType_TRIGGER_TYPE_TRIAL_STATE_CHANGE:
+		return TriggerTypeTrialStateChange, nil
+	case webhookv1.TriggerType_TRIGGER_TYPE_CHECKPOINT_STATE_CHANGE:
+		return TriggerTypeCheckpointStateChange, nil
+	case webhookv1.TriggerType_TRIGGER_TYPE_MODEL_VERSION_REGISTERED:
+		return TriggerTypeModelVersionRegistered, nil
+	case webhookv1.TriggerType_TRIGGER_TYPE_ALLOCATION_PREEMPTED:
+		return TriggerTypeAllocationPreempted, nil
+	case webhookv1.TriggerType_TRIGGER_TYPE_TASK_TERMINATED:
+		return TriggerTypeTaskTerminated, nil
 	default:
-		// TODO(???): prob don't panic
-		panic(fmt.Errorf("missing mapping for trigger %s to SQL", t))
+		return "", status.Errorf(codes.InvalidArgument, "unknown trigger type %s", t)
 	}
 }
 
@@ -180,6 +405,12 @@ func (w WebhookType) Proto() webhookv1.WebhookType {
 		return webhookv1.WebhookType_WEBHOOK_TYPE_DEFAULT
 	case WebhookTypeSlack:
 		return webhookv1.WebhookType_WEBHOOK_TYPE_SLACK
+	case WebhookTypeTeams:
+		return webhookv1.WebhookType_WEBHOOK_TYPE_TEAMS
+	case WebhookTypePagerDuty:
+		return webhookv1.WebhookType_WEBHOOK_TYPE_PAGERDUTY
+	case WebhookTypeTemplated:
+		return webhookv1.WebhookType_WEBHOOK_TYPE_TEMPLATED
 	default:
 		return webhookv1.WebhookType_WEBHOOK_TYPE_UNSPECIFIED
 	}
@@ -194,6 +425,16 @@ func (t TriggerType) Proto() webhookv1.TriggerType {
 		return webhookv1.TriggerType_TRIGGER_TYPE_METRIC_THRESHOLD_EXCEEDED
 	case TriggerTypeTaskLog:
 		return webhookv1.TriggerType_TRIGGER_TYPE_TASK_LOG
+	case TriggerTypeTrialStateChange:
+		return webhookv1.TriggerType_TRIGGER_TYPE_TRIAL_STATE_CHANGE
+	case TriggerTypeCheckpointStateChange:
+		return webhookv1.TriggerType_TRIGGER_TYPE_CHECKPOINT_STATE_CHANGE
+	case TriggerTypeModelVersionRegistered:
+		return webhookv1.TriggerType_TRIGGER_TYPE_MODEL_VERSION_REGISTERED
+	case TriggerTypeAllocationPreempted:
+		return webhookv1.TriggerType_TRIGGER_TYPE_ALLOCATION_PREEMPTED
+	case TriggerTypeTaskTerminated:
+		return webhookv1.TriggerType_TRIGGER_TYPE_TASK_TERMINATED
 	default:
 		return webhookv1.TriggerType_TRIGGER_TYPE_UNSPECIFIED
 	}
@@ -230,6 +471,50 @@ type Event struct {
 	ID      WebhookEventID `bun:"id,pk,autoincrement"`
 	URL     string         `bun:"url,notnull"`
 	Payload []byte         `bun:"payload,notnull"`
+	// WebhookID is the webhook the event was sent for; nil if the webhook has been deleted.
+	WebhookID *WebhookID `bun:"webhook_id"`
+}
+
+// DeliveryID is the type for Delivery IDs.
+type DeliveryID int
+
+// Delivery corresponds to a row in the "webhook_deliveries" DB table: one attempt to deliver an
+// event.
+type Delivery struct {
+	bun.BaseModel `bun:"table:webhook_deliveries"`
+
+	ID DeliveryID `bun:"id,pk,autoincrement"`
+	// EventID groups the attempts to deliver the same queued event.
+	EventID    WebhookEventID `bun:"event_id,notnull"`
+	WebhookID  *WebhookID     `bun:"webhook_id"`
+	URL        string         `bun:"url,notnull"`
+	Payload    string         `bun:"payload,notnull"`
+	Attempt    int            `bun:"attempt,notnull"`
+	StatusCode *int           `bun:"status_code"`
+	LatencyMs  int            `bun:"latency_ms,notnull"`
+	Error      *string        `bun:"error"`
+
+	AttemptedAt time.Time `bun:"attempted_at,nullzero,default:current_timestamp"`
+}
+
+// Proto converts a delivery attempt to its protobuf representation.
+func (d *Delivery) Proto() *webhookv1.WebhookDelivery {
+	var webhookID *int32
+	if d.WebhookID != nil {
+		webhookID = ptrs.Ptr(int32(*d.WebhookID))
+	}
+	return &webhookv1.WebhookDelivery{
+		Id:          int32(d.ID),
+		EventId:     int32(d.EventID),
+		WebhookId:   webhookID,
+		Url:         d.URL,
+		Payload:     d.Payload,
+		Attempt:     int32(d.Attempt),
+		StatusCode:  iPtr2i32Ptr(d.StatusCode),
+		LatencyMs:   int32(d.LatencyMs),
+		Error:       d.Error,
+		AttemptedAt: timestamppb.New(d.AttemptedAt),
+	}
 }
 
 // SlackMessageBody corresponds to an entire message as a Slack Block.
@@ -266,12 +551,17 @@ type EventPayload struct {
 	Data      EventData   `json:"event_data"`
 }
 
-const regexConditionKey = "regex"
+const (
+	stateConditionKey    = "state"
+	regexConditionKey    = "regex"
+	taskTypeConditionKey = "task_type"
+)
 
 // Condition represents a trigger condition.
 type Condition struct {
-	State model.State `json:"state,omitempty"`
-	Regex string      `json:"regex,omitempty"`
+	State    model.State    `json:"state,omitempty"`
+	Regex    string         `json:"regex,omitempty"`
+	TaskType model.TaskType `json:"task_type,omitempty"`
 }
 
 // EventData represents the event_data for a webhook event.
@@ -279,6 +569,12 @@ type EventData struct {
 	TestData   *string            `json:"data,omitempty"`
 	Experiment *ExperimentPayload `json:"experiment,omitempty"`
 	TaskLog    *TaskLogPayload    `json:"task_log,omitempty"`
+
+	Trial        *TrialPayload        `json:"trial,omitempty"`
+	Checkpoint   *CheckpointPayload   `json:"checkpoint,omitempty"`
+	ModelVersion *ModelVersionPayload `json:"model_version,omitempty"`
+	Allocation   *AllocationPayload   `json:"allocation,omitempty"`
+	Task         *TaskPayload         `json:"task,omitempty"`
 }
 
 // ExperimentPayload is the webhook request representation of an experiment.
@@ -299,3 +595,43 @@ type TaskLogPayload struct {
 	NodeName      string       `json:"node_name"`
 	TriggeringLog string       `json:"triggering_log"`
 }
+
+// TrialPayload is the webhook request representation of a trial.
+type TrialPayload struct {
+	ID           int         `json:"id"`
+	ExperimentID int         `json:"experiment_id"`
+	State        model.State `json:"state"`
+}
+
+// CheckpointPayload is the webhook request representation of a checkpoint.
+type CheckpointPayload struct {
+	UUID         uuid.UUID     `json:"uuid"`
+	State        model.State   `json:"state"`
+	TaskID       *model.TaskID `json:"task_id,omitempty"`
+	TrialID      *int          `json:"trial_id,omitempty"`
+	ExperimentID *int          `json:"experiment_id,omitempty"`
+}
+
+// ModelVersionPayload is the webhook request representation of a model version.
+type ModelVersionPayload struct {
+	ModelID        int    `json:"model_id"`
+	ModelName      string `json:"model_name"`
+	Version        int    `json:"version"`
+	Name           string `json:"name"`
+	CheckpointUUID string `json:"checkpoint_uuid"`
+}
+
+// AllocationPayload is the webhook request representation of an allocation.
+type AllocationPayload struct {
+	AllocationID model.AllocationID `json:"allocation_id"`
+	TaskID       model.TaskID       `json:"task_id"`
+	TaskType     model.TaskType     `json:"task_type"`
+	Reason       string             `json:"reason"`
+}
+
+// TaskPayload is the webhook request representation of a notebook, shell, command or tensorboard.
+type TaskPayload struct {
+	TaskID     model.TaskID   `json:"task_id"`
+	TaskType   model.TaskType `json:"task_type"`
+	ExitStatus string         `json:"exit_status"`
+}
@@ -0,0 +1,68 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/webhookv1"
+)
+
+func TestWebhookTypeProto(t *testing.T) {
+	for _, w := range []WebhookType{
+		WebhookTypeDefault,
+		WebhookTypeSlack,
+		WebhookTypeTeams,
+		WebhookTypePagerDuty,
+		WebhookTypeTemplated,
+	} {
+		p := w.Proto()
+		require.NotEqual(t, webhookv1.WebhookType_WEBHOOK_TYPE_UNSPECIFIED, p, w)
+		back, err := WebhookTypeFromProto(p)
+		require.NoError(t, err)
+		require.Equal(t, w, back)
+	}
+
+	_, err := WebhookTypeFromProto(webhookv1.WebhookType_WEBHOOK_TYPE_UNSPECIFIED)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestTriggerTypeProto(t *testing.T) {
+	for _, tt := range []TriggerType{
+		TriggerTypeStateChange,
+		TriggerTypeMetricThresholdExceeded,
+		TriggerTypeTaskLog,
+		TriggerTypeTrialStateChange,
+		TriggerTypeCheckpointStateChange,
+		TriggerTypeModelVersionRegistered,
+		TriggerTypeAllocationPreempted,
+		TriggerTypeTaskTerminated,
+	} {
+		p := tt.Proto()
+		require.NotEqual(t, webhookv1.TriggerType_TRIGGER_TYPE_UNSPECIFIED, p, tt)
+		back, err := TriggerTypeFromProto(p)
+		require.NoError(t, err)
+		require.Equal(t, tt, back)
+	}
+
+	_, err := TriggerTypeFromProto(webhookv1.TriggerType_TRIGGER_TYPE_UNSPECIFIED)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestWebhookFromProto(t *testing.T) {
+	w, err := WebhookFromProto(&webhookv1.Webhook{
+		Url:         "https://events.pagerduty.com/v2/enqueue",
+		WebhookType: webhookv1.WebhookType_WEBHOOK_TYPE_PAGERDUTY,
+		RoutingKey:  ptrs.Ptr("key"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, WebhookTypePagerDuty, w.WebhookType)
+	require.Equal(t, "key", *w.RoutingKey)
+	require.Equal(t, "key", w.Proto().GetRoutingKey())
+
+	_, err = WebhookFromProto(&webhookv1.Webhook{WebhookType: webhookv1.WebhookType(100)})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
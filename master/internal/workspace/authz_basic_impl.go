@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/proto/pkg/projectv1"
 	"github.com/determined-ai/determined/proto/pkg/workspacev1"
@@ -36,6 +37,18 @@ func (a *WorkspaceAuthZBasic) CanModifyRPWorkspaceBindings(
 	return nil
 }
 
+// CanModifyRPReservations requires user to be an admin.
+func (a *WorkspaceAuthZBasic) CanModifyRPReservations(
+	ctx context.Context, curUser model.User, workspaceID int32,
+) error {
+	if !curUser.Admin {
+		return authz.PermissionDeniedError{}.WithPrefix(
+			"only admin privileged users can modify resource pool reservations",
+		)
+	}
+	return nil
+}
+
 // FilterWorkspaceProjects always returns the list provided and a nil error.
 func (a *WorkspaceAuthZBasic) FilterWorkspaceProjects(
 	ctx context.Context, curUser model.User, projects []*projectv1.Project,
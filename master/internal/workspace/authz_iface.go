@@ -26,6 +26,14 @@ type WorkspaceAuthZ interface {
 		ctx context.Context, curUser model.User, workspaceIDs []int32,
 	) error
 
+	// GET /api/v1/resource-pools/reservations
+	// POST /api/v1/resource-pools/reservations
+	// PATCH /api/v1/resource-pools/reservations/:reservation_id
+	// DELETE /api/v1/resource-pools/reservations/:reservation_id
+	CanModifyRPReservations(
+		ctx context.Context, curUser model.User, workspaceID int32,
+	) error
+
 	// GET /api/v1/workspaces/:workspace_id/projects
 	FilterWorkspaceProjects(
 		ctx context.Context, curUser model.User, projects []*projectv1.Project,
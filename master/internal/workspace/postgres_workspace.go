@@ -22,6 +22,16 @@ func WorkspaceByName(ctx context.Context, workspaceName string) (*model.Workspac
 	return &w, nil
 }
 
+// WorkspaceByID returns a workspace given its ID.
+func WorkspaceByID(ctx context.Context, id int) (*model.Workspace, error) {
+	var w model.Workspace
+	err := db.Bun().NewSelect().Model(&w).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
 // Exists returns if the workspace exists and is not archived.
 func Exists(ctx context.Context, id int) (bool, error) {
 	return db.Bun().NewSelect().Table("workspaces").
@@ -31,6 +31,8 @@ type MasterSetAgentOptions struct {
 	MasterInfo           MasterInfo
 	LoggingOptions       model.LoggingConfig
 	ContainersToReattach []ContainerReattach
+	// PrePullImages are the images the agent should pull ahead of tasks that use them.
+	PrePullImages []string
 }
 
 // StartContainer notifies the agent to start a container with the provided spec.
@@ -8,6 +8,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/stdcopy"
 
@@ -40,6 +41,9 @@ type MasterMessage struct {
 	ContainerStateChanged *ContainerStateChanged
 	ContainerLog          *ContainerLog
 	ContainerStatsRecord  *ContainerStatsRecord
+	DeviceHealthChanged   *DeviceHealthChanged
+	CachedImagesChanged   *CachedImagesChanged
+	AgentDrained          *AgentDrained
 }
 
 // ContainerReattach is a struct describing containers that can be reattached.
@@ -70,6 +74,49 @@ type AgentStarted struct {
 	Version              string
 	Devices              []device.Device
 	ContainersReattached []ContainerReattachAck
+	SlotResources        SlotResources
+}
+
+// SlotResources is the CPU and memory a container gets for each of its slots, unless its task
+// overrides them. Zero values leave the resource unlimited.
+type SlotResources struct {
+	CPU         float64
+	MemoryBytes int64
+}
+
+// DeviceHealthChanged notifies the master that the health of the agent's devices changed. It
+// carries the health of every device the agent checks, not only of the ones that changed.
+type DeviceHealthChanged struct {
+	Devices []DeviceHealth
+}
+
+// DeviceHealth is the result of checking the health of a device.
+type DeviceHealth struct {
+	ID      device.ID
+	Healthy bool
+	// Reason describes why the device is unhealthy.
+	Reason string
+}
+
+// AgentDrained notifies the master that the agent was drained locally, through its API. The agent
+// refuses new containers from then on, so the master should stop scheduling onto it.
+type AgentDrained struct{}
+
+// CachedImagesChanged notifies the master that the images cached on the agent changed. It carries
+// the canonical reference of every cached image, not only of the ones that changed.
+type CachedImagesChanged struct {
+	Images []string
+}
+
+// CanonicalImage returns the fully qualified form of an image reference, such as
+// "docker.io/library/ubuntu:latest" for "ubuntu", so that references to the same image compare
+// equal. References that can't be parsed are returned as is.
+func CanonicalImage(image string) string {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+	return reference.TagNameOnly(ref).String()
 }
 
 // ContainerStateChanged notifies the master that the agent transitioned the container state.
@@ -20,6 +20,9 @@ type Address struct {
 	// HostPort is the IP port from outside the container. This can be different
 	// than the ContainerPort because of network forwarding on the host machine.
 	HostPort int `json:"host_port"`
+	// HostName is a stable DNS name that resolves to the host, if it has one. Unlike the
+	// HostIP, it is known before the container starts.
+	HostName string `json:"host_name,omitempty"`
 }
 
 func (a Address) String() string {
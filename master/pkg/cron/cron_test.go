@@ -0,0 +1,71 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for _, spec := range []string{
+		"* * * * *", "0 9 * * mon-fri", "*/15 8-18 1,15 jan-jun 0", "30 2 * * 7", "5/10 * * * *",
+	} {
+		_, err := Parse(spec)
+		require.NoError(t, err, spec)
+	}
+	for _, spec := range []string{
+		"* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8",
+		"5-1 * * * *", "*/0 * * * *", "* * * * monday",
+	} {
+		_, err := Parse(spec)
+		require.Error(t, err, spec)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	s, err := Parse("0 9 * * mon-fri")
+	require.NoError(t, err)
+	// 2024-01-01 is a Monday.
+	require.True(t, s.Matches(time.Date(2024, 1, 1, 9, 0, 30, 0, time.UTC)))
+	require.False(t, s.Matches(time.Date(2024, 1, 1, 9, 1, 0, 0, time.UTC)))
+	require.False(t, s.Matches(time.Date(2024, 1, 6, 9, 0, 0, 0, time.UTC)))
+
+	// Sunday can be given as 0 or 7.
+	s, err = Parse("0 0 * * 7")
+	require.NoError(t, err)
+	require.True(t, s.Matches(time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)))
+
+	// When both day fields are restricted, either may match.
+	s, err = Parse("0 0 13 * fri")
+	require.NoError(t, err)
+	require.True(t, s.Matches(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)))
+	require.True(t, s.Matches(time.Date(2024, 1, 13, 0, 0, 0, 0, time.UTC)))
+	require.False(t, s.Matches(time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestPrev(t *testing.T) {
+	s, err := Parse("0 9 * * mon-fri")
+	require.NoError(t, err)
+
+	// Monday at 18:59 fired at 9:00 the same day.
+	prev, ok := s.Prev(time.Date(2024, 1, 1, 18, 59, 59, 0, time.UTC), 10*time.Hour)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), prev)
+
+	// Monday at 19:01 is out of a 10 hour window.
+	_, ok = s.Prev(time.Date(2024, 1, 1, 19, 1, 0, 0, time.UTC), 10*time.Hour)
+	require.False(t, ok)
+
+	// Monday at 8:00 last fired on Friday.
+	prev, ok = s.Prev(time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC), 7*24*time.Hour)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2023, 12, 29, 9, 0, 0, 0, time.UTC), prev)
+
+	// Times are in the location of the given time.
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	prev, ok = s.Prev(time.Date(2024, 1, 1, 16, 0, 0, 0, time.UTC).In(ny), 3*time.Hour)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC), prev.UTC())
+}
@@ -61,17 +61,30 @@ type SlotSummary struct {
 	Enabled   bool              `json:"enabled"`
 	Container *cproto.Container `json:"container"`
 	Draining  bool              `json:"draining"`
+	// UnhealthyReason is set when the agent reports the slot's device as unhealthy.
+	UnhealthyReason string `json:"unhealthy_reason,omitempty"`
+	// CPU and MemoryBytes are the CPUs and memory a container gets for the slot, unless its task
+	// overrides them. They are zero when unlimited.
+	CPU         float64 `json:"cpu,omitempty"`
+	MemoryBytes int64   `json:"memory_bytes,omitempty"`
 }
 
 // ToProto converts a SlotSummary to its protobuf representation.
 func (s SlotSummary) ToProto() *agentv1.Slot {
-	return &agentv1.Slot{
+	slot := &agentv1.Slot{
 		Id:        s.ID,
 		Device:    s.Device.Proto(),
 		Enabled:   s.Enabled,
 		Container: s.Container.ToProto(),
 		Draining:  s.Draining,
 	}
+	if s.CPU != 0 {
+		slot.CpuLimit = &s.CPU
+	}
+	if s.MemoryBytes != 0 {
+		slot.MemoryLimit = &s.MemoryBytes
+	}
+	return slot
 }
 
 // AgentStats stores the start/end status of instance.
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 )
 
 func TestConfigValidate(t *testing.T) {
@@ -58,6 +59,22 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid-max-runtime",
+			fields: fields{
+				Resources: ResourcesConfig{
+					Slots:      1,
+					Weight:     1,
+					MaxRuntime: ptrs.Ptr(Duration(0)),
+				},
+				Environment: environment,
+				Entrypoint: []string{
+					"test",
+				},
+				NotebookIdleType: NotebookIdleTypeActivity,
+			},
+			wantErr: true,
+		},
 	}
 	runTestCase := func(t *testing.T, tc testCase) {
 		t.Run(tc.name, func(t *testing.T) {
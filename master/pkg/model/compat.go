@@ -32,15 +32,17 @@ func (r ResourcesConfig) ToExpconf() expconf.ResourcesConfig {
 	}
 
 	return schemas.WithDefaults(expconf.ResourcesConfig{
-		RawSlots:          ptrs.Ptr(r.Slots),
-		RawMaxSlots:       r.MaxSlots,
-		RawSlotsPerTrial:  ptrs.Ptr(1),
-		RawWeight:         ptrs.Ptr(r.Weight),
-		RawNativeParallel: ptrs.Ptr(r.NativeParallel),
-		RawShmSize:        shm,
-		RawResourcePool:   ptrs.Ptr(r.ResourcePool),
-		RawPriority:       r.Priority,
-		RawDevices:        r.Devices.ToExpconf(),
+		RawSlots:           ptrs.Ptr(r.Slots),
+		RawMaxSlots:        r.MaxSlots,
+		RawSlotsPerTrial:   ptrs.Ptr(1),
+		RawWeight:          ptrs.Ptr(r.Weight),
+		RawNativeParallel:  ptrs.Ptr(r.NativeParallel),
+		RawShmSize:         shm,
+		RawResourcePool:    ptrs.Ptr(r.ResourcePool),
+		RawPriority:        r.Priority,
+		RawCPUPerSlot:      r.CPUPerSlot,
+		RawMemoryPerSlotGB: r.MemoryPerSlotGB,
+		RawDevices:         r.Devices.ToExpconf(),
 	})
 }
 
@@ -115,6 +117,10 @@ func (e Environment) ToExpconf() expconf.EnvironmentConfig {
 	image := e.Image.ToExpconf()
 	vars := e.EnvironmentVariables.ToExpconf()
 	proxyConf := e.ProxyPorts.ToExpconf()
+	var podSpecTemplate *string
+	if e.PodSpecTemplate != "" {
+		podSpecTemplate = &e.PodSpecTemplate
+	}
 
 	return schemas.WithDefaults(expconf.EnvironmentConfig{
 		RawImage:                &image,
@@ -124,6 +130,7 @@ func (e Environment) ToExpconf() expconf.EnvironmentConfig {
 		RawRegistryAuth:         e.RegistryAuth,
 		RawForcePullImage:       ptrs.Ptr(e.ForcePullImage),
 		RawPodSpec:              (*expconf.PodSpec)(e.PodSpec),
+		RawPodSpecTemplate:      podSpecTemplate,
 		RawAddCapabilities:      e.AddCapabilities,
 		RawDropCapabilities:     e.DropCapabilities,
 	})
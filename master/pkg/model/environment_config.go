@@ -34,6 +34,8 @@ type Environment struct {
 	RegistryAuth   *types.AuthConfig `json:"registry_auth,omitempty"`
 	ForcePullImage bool              `json:"force_pull_image"`
 	PodSpec        *k8sV1.Pod        `json:"pod_spec"`
+	// PodSpecTemplate names a stored pod spec template that is merged underneath PodSpec.
+	PodSpecTemplate string `json:"pod_spec_template,omitempty"`
 
 	AddCapabilities  []string `json:"add_capabilities"`
 	DropCapabilities []string `json:"drop_capabilities"`
@@ -90,6 +90,10 @@ type ResourcesConfig struct {
 	ShmSize        *StorageSize `json:"shm_size,omitempty"`
 	ResourcePool   string       `json:"resource_pool"`
 	Priority       *int         `json:"priority,omitempty"`
+	MaxRuntime     *Duration    `json:"max_runtime,omitempty"`
+
+	CPUPerSlot      *float64 `json:"cpu_per_slot,omitempty"`
+	MemoryPerSlotGB *float64 `json:"memory_per_slot_gb,omitempty"`
 
 	Devices DevicesConfig `json:"devices"`
 
@@ -166,6 +170,9 @@ func (r ResourcesConfig) Validate() []error {
 		check.GreaterThan(r.Weight, float64(0), "weight must be > 0"),
 	}
 	errs = append(errs, ValidatePrioritySetting(r.Priority)...)
+	if r.MaxRuntime != nil {
+		errs = append(errs, check.True(*r.MaxRuntime > 0, "max_runtime must be > 0"))
+	}
 	return errs
 }
 
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	k8sV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ParsePodSpecTemplate parses a pod spec template from JSON or YAML. Unlike the pod specs embedded
+// in experiment configs, unknown fields are rejected so that typos surface when the template is
+// stored instead of being silently dropped when a pod is created from it.
+func ParsePodSpecTemplate(data []byte) (*k8sV1.Pod, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing pod spec template")
+	}
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.DisallowUnknownFields()
+
+	var pod k8sV1.Pod
+	if err := dec.Decode(&pod); err != nil {
+		return nil, errors.Wrap(err, "pod spec template is not a valid Kubernetes pod")
+	}
+	if errs := ValidatePodSpec(&pod); len(errs) > 0 {
+		msgs := make([]string, 0, len(errs))
+		for _, err := range errs {
+			msgs = append(msgs, err.Error())
+		}
+		return nil, fmt.Errorf("invalid pod spec template: %s", strings.Join(msgs, "; "))
+	}
+	return &pod, nil
+}
+
+// ValidatePodSpec checks a pod spec for the fields Determined manages itself and for structural
+// errors that the Kubernetes API server would otherwise only report when the pod is created.
+func ValidatePodSpec(pod *k8sV1.Pod) []error {
+	if pod == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, err := range validatePodSpec(pod) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	volumes := make(map[string]bool)
+	for _, v := range pod.Spec.Volumes {
+		errs = append(errs, validateK8sName("volume", v.Name)...)
+		if volumes[v.Name] {
+			errs = append(errs, fmt.Errorf("volume %q is defined more than once", v.Name))
+		}
+		volumes[v.Name] = true
+	}
+
+	containers := make(map[string]bool)
+	validateContainers := func(kind string, cs []k8sV1.Container) {
+		for _, c := range cs {
+			errs = append(errs, validateK8sName(kind, c.Name)...)
+			if containers[c.Name] {
+				errs = append(errs, fmt.Errorf("%s %q is defined more than once", kind, c.Name))
+			}
+			containers[c.Name] = true
+			if c.Name != DeterminedK8ContainerName && c.Image == "" {
+				errs = append(errs, fmt.Errorf("%s %q must specify an image", kind, c.Name))
+			}
+			for _, m := range c.VolumeMounts {
+				if !volumes[m.Name] {
+					errs = append(errs, fmt.Errorf(
+						"%s %q mounts volume %q which is not defined", kind, c.Name, m.Name))
+				}
+			}
+		}
+	}
+	validateContainers("container", pod.Spec.Containers)
+	validateContainers("init container", pod.Spec.InitContainers)
+
+	for _, t := range pod.Spec.Tolerations {
+		switch t.Operator {
+		case k8sV1.TolerationOpExists:
+			if t.Value != "" {
+				errs = append(errs, fmt.Errorf(
+					"toleration for key %q must not set a value with operator Exists", t.Key))
+			}
+		case k8sV1.TolerationOpEqual, "":
+			if t.Key == "" {
+				errs = append(errs, errors.New("toleration with operator Equal must set a key"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf(
+				"toleration for key %q has unknown operator %q", t.Key, t.Operator))
+		}
+	}
+
+	for key := range pod.Spec.NodeSelector {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, fmt.Errorf("node selector key %q: %s", key, msg))
+		}
+	}
+	return errs
+}
+
+func validateK8sName(kind, name string) []error {
+	var errs []error
+	for _, msg := range validation.IsDNS1123Label(name) {
+		errs = append(errs, fmt.Errorf("%s name %q: %s", kind, name, msg))
+	}
+	return errs
+}
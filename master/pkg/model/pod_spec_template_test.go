@@ -0,0 +1,118 @@
+//nolint:exhaustruct
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	k8sV1 "k8s.io/api/core/v1"
+
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+const testPodSpecTemplate = `
+spec:
+  nodeSelector:
+    accelerator: a100
+  tolerations:
+    - key: nvidia.com/gpu
+      operator: Exists
+      effect: NoSchedule
+  volumes:
+    - name: datasets
+      hostPath:
+        path: /mnt/datasets
+  containers:
+    - name: determined-container
+      volumeMounts:
+        - name: datasets
+          mountPath: /datasets
+    - name: log-shipper
+      image: fluent/fluent-bit:2.2
+`
+
+func TestParsePodSpecTemplate(t *testing.T) {
+	pod, err := ParsePodSpecTemplate([]byte(testPodSpecTemplate))
+	require.NoError(t, err)
+	require.Equal(t, "a100", pod.Spec.NodeSelector["accelerator"])
+	require.Len(t, pod.Spec.Containers, 2)
+
+	_, err = ParsePodSpecTemplate([]byte("spec:\n  nodeSelectors:\n    accelerator: a100\n"))
+	require.ErrorContains(t, err, "unknown field")
+
+	_, err = ParsePodSpecTemplate([]byte("spec:\n  hostNetwork: true\n"))
+	require.ErrorContains(t, err, "host networking")
+}
+
+func TestValidatePodSpec(t *testing.T) {
+	cases := map[string]struct {
+		pod *k8sV1.Pod
+		err string
+	}{
+		"sidecar without image": {
+			pod: &k8sV1.Pod{Spec: k8sV1.PodSpec{Containers: []k8sV1.Container{{Name: "sidecar"}}}},
+			err: `container "sidecar" must specify an image`,
+		},
+		"invalid container name": {
+			pod: &k8sV1.Pod{Spec: k8sV1.PodSpec{Containers: []k8sV1.Container{
+				{Name: "Sidecar", Image: "busybox"},
+			}}},
+			err: `container name "Sidecar"`,
+		},
+		"duplicate volume": {
+			pod: &k8sV1.Pod{Spec: k8sV1.PodSpec{Volumes: []k8sV1.Volume{{Name: "a"}, {Name: "a"}}}},
+			err: `volume "a" is defined more than once`,
+		},
+		"mount of undefined volume": {
+			pod: &k8sV1.Pod{Spec: k8sV1.PodSpec{InitContainers: []k8sV1.Container{{
+				Name: "init", Image: "busybox", VolumeMounts: []k8sV1.VolumeMount{{Name: "missing"}},
+			}}}},
+			err: `init container "init" mounts volume "missing" which is not defined`,
+		},
+		"bad toleration operator": {
+			pod: &k8sV1.Pod{Spec: k8sV1.PodSpec{Tolerations: []k8sV1.Toleration{
+				{Key: "gpu", Operator: "Matches"},
+			}}},
+			err: `unknown operator "Matches"`,
+		},
+		"determined container image": {
+			pod: &k8sV1.Pod{Spec: k8sV1.PodSpec{Containers: []k8sV1.Container{
+				{Name: DeterminedK8ContainerName, Image: "custom"},
+			}}},
+			err: "container Image is not configurable",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			errs := ValidatePodSpec(tc.pod)
+			require.Len(t, errs, 1)
+			require.ErrorContains(t, errs[0], tc.err)
+		})
+	}
+}
+
+func TestPodSpecTemplateMerge(t *testing.T) {
+	tmpl, err := ParsePodSpecTemplate([]byte(testPodSpecTemplate))
+	require.NoError(t, err)
+
+	user := &k8sV1.Pod{Spec: k8sV1.PodSpec{
+		NodeSelector: map[string]string{"zone": "us-west"},
+		Containers: []k8sV1.Container{{
+			Name: DeterminedK8ContainerName,
+			Env:  []k8sV1.EnvVar{{Name: "DEBUG", Value: "1"}},
+		}},
+	}}
+	merged := (*k8sV1.Pod)(schemas.Merge((*expconf.PodSpec)(user), (*expconf.PodSpec)(tmpl)))
+
+	require.Empty(t, ValidatePodSpec(merged))
+	require.Equal(t, map[string]string{"accelerator": "a100", "zone": "us-west"}, merged.Spec.NodeSelector)
+	require.Len(t, merged.Spec.Tolerations, 1)
+	require.Len(t, merged.Spec.Containers, 2)
+	for _, c := range merged.Spec.Containers {
+		if c.Name == DeterminedK8ContainerName {
+			require.Len(t, c.VolumeMounts, 1)
+			require.Equal(t, []k8sV1.EnvVar{{Name: "DEBUG", Value: "1"}}, c.Env)
+		}
+	}
+}
@@ -63,10 +63,11 @@ type EnvironmentConfigV0 struct {
 	RawEnvironmentVariables *EnvironmentVariablesMapV0 `json:"environment_variables"`
 	RawProxyPorts           *ProxyPortsConfigV0        `json:"proxy_ports"`
 
-	RawPorts          map[string]int    `json:"ports"`
-	RawRegistryAuth   *types.AuthConfig `json:"registry_auth"`
-	RawForcePullImage *bool             `json:"force_pull_image"`
-	RawPodSpec        *PodSpec          `json:"pod_spec"`
+	RawPorts           map[string]int    `json:"ports"`
+	RawRegistryAuth    *types.AuthConfig `json:"registry_auth"`
+	RawForcePullImage  *bool             `json:"force_pull_image"`
+	RawPodSpec         *PodSpec          `json:"pod_spec"`
+	RawPodSpecTemplate *string           `json:"pod_spec_template"`
 
 	RawAddCapabilities  []string `json:"add_capabilities"`
 	RawDropCapabilities []string `json:"drop_capabilities"`
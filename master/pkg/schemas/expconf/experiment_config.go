@@ -210,13 +210,16 @@ type ResourcesConfigV0 struct {
 	// Slots is used by commands while trials use SlotsPerTrial.
 	RawSlots *int `json:"slots,omitempty"`
 
-	RawMaxSlots       *int     `json:"max_slots"`
-	RawSlotsPerTrial  *int     `json:"slots_per_trial"`
-	RawWeight         *float64 `json:"weight"`
-	RawNativeParallel *bool    `json:"native_parallel,omitempty"`
-	RawShmSize        *int     `json:"shm_size"`
-	RawResourcePool   *string  `json:"resource_pool"`
-	RawPriority       *int     `json:"priority"`
+	RawMaxSlots        *int      `json:"max_slots"`
+	RawSlotsPerTrial   *int      `json:"slots_per_trial"`
+	RawWeight          *float64  `json:"weight"`
+	RawNativeParallel  *bool     `json:"native_parallel,omitempty"`
+	RawShmSize         *int      `json:"shm_size"`
+	RawResourcePool    *string   `json:"resource_pool"`
+	RawPriority        *int      `json:"priority"`
+	RawMaxRuntime      *Duration `json:"max_runtime"`
+	RawCPUPerSlot      *float64  `json:"cpu_per_slot"`
+	RawMemoryPerSlotGB *float64  `json:"memory_per_slot_gb"`
 
 	RawDevices DevicesConfigV0 `json:"devices"`
 }
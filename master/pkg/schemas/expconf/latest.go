@@ -35,6 +35,7 @@ type (
 	LogActionExcludeNode      = LogActionExcludeNodeV0
 	LogHyperparameter         = LogHyperparameterV0
 	OptimizationsConfig       = OptimizationsConfigV0
+	PBTConfig                 = PBTConfigV0
 	PbsConfig                 = PbsConfigV0
 	ProfilingConfig           = ProfilingConfigV0
 	ProxyPort                 = ProxyPortV0
@@ -47,6 +48,7 @@ type (
 	SharedFSConfig            = SharedFSConfigV0
 	SingleConfig              = SingleConfigV0
 	SlurmConfig               = SlurmConfigV0
+	TPEConfig                 = TPEConfigV0
 )
 
 // These are EOL searchers, not to be used in new experiments.
@@ -18,6 +18,8 @@ type SearcherConfigV0 struct {
 	RawGridConfig         *GridConfigV0         `union:"name,grid" json:"-"`
 	RawAsyncHalvingConfig *AsyncHalvingConfigV0 `union:"name,async_halving" json:"-"`
 	RawAdaptiveASHAConfig *AdaptiveASHAConfigV0 `union:"name,adaptive_asha" json:"-"`
+	RawTPEConfig          *TPEConfigV0          `union:"name,tpe" json:"-"`
+	RawPBTConfig          *PBTConfigV0          `union:"name,pbt" json:"-"`
 	RawCustomConfig       *CustomConfigV0       `union:"name,custom" json:"-"`
 
 	// TODO(DET-8577): There should not be a need to parse EOL searchers if we get rid of parsing
@@ -65,6 +67,10 @@ func (s SearcherConfigV0) Unit() Unit {
 		return s.RawAsyncHalvingConfig.Unit()
 	case s.RawAdaptiveASHAConfig != nil:
 		return s.RawAdaptiveASHAConfig.Unit()
+	case s.RawTPEConfig != nil:
+		return s.RawTPEConfig.Unit()
+	case s.RawPBTConfig != nil:
+		return s.RawPBTConfig.Unit()
 	case s.RawCustomConfig != nil:
 		panic("custom searcher config does not provide Unit()")
 	case s.RawSyncHalvingConfig != nil:
@@ -92,6 +98,10 @@ func (s SearcherConfigV0) AsLegacy() LegacySearcher {
 		name = "async_halving"
 	case s.RawAdaptiveASHAConfig != nil:
 		name = "adaptive_asha"
+	case s.RawTPEConfig != nil:
+		name = "tpe"
+	case s.RawPBTConfig != nil:
+		name = "pbt"
 	case s.RawCustomConfig != nil:
 		name = "custom"
 	case s.RawSyncHalvingConfig != nil:
@@ -212,6 +222,40 @@ func (a AdaptiveASHAConfigV0) Unit() Unit {
 	return a.RawMaxLength.Unit
 }
 
+// TPEConfigV0 configures a Tree-structured Parzen Estimator search.
+//
+//go:generate ../gen.sh
+type TPEConfigV0 struct {
+	RawMaxLength           *LengthV0 `json:"max_length"`
+	RawMaxTrials           *int      `json:"max_trials"`
+	RawMaxConcurrentTrials *int      `json:"max_concurrent_trials"`
+	RawNumStartupTrials    *int      `json:"num_startup_trials"`
+	RawGamma               *float64  `json:"gamma"`
+	RawNumCandidates       *int      `json:"num_candidates"`
+}
+
+// Unit implements the model.InUnits interface.
+func (t TPEConfigV0) Unit() Unit {
+	return t.RawMaxLength.Unit
+}
+
+// PBTConfigV0 configures a Population Based Training search.
+//
+//go:generate ../gen.sh
+type PBTConfigV0 struct {
+	RawPopulationSize      *int      `json:"population_size"`
+	RawNumRounds           *int      `json:"num_rounds"`
+	RawLengthPerRound      *LengthV0 `json:"length_per_round"`
+	RawTruncateFraction    *float64  `json:"truncate_fraction"`
+	RawResampleProbability *float64  `json:"resample_probability"`
+	RawPerturbFactor       *float64  `json:"perturb_factor"`
+}
+
+// Unit implements the model.InUnits interface.
+func (p PBTConfigV0) Unit() Unit {
+	return p.RawLengthPerRound.Unit
+}
+
 // SyncHalvingConfigV0 is a legacy config.
 //
 //go:generate ../gen.sh
@@ -665,6 +665,13 @@ var (
                 "type": "string"
             }
         },
+        "pod_spec_template": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
         "pod_spec": {
             "type": [
                 "object",
@@ -979,6 +986,38 @@ var (
             "default": ""
         }
     },
+    "checks": {
+        "the pbt searcher requires checkpoint_policy to be set to all": {
+            "if": {
+                "$comment": "trials must checkpoint after every validation so they can be cloned",
+                "required": [
+                    "searcher"
+                ],
+                "properties": {
+                    "searcher": {
+                        "required": [
+                            "name"
+                        ],
+                        "properties": {
+                            "name": {
+                                "const": "pbt"
+                            }
+                        }
+                    }
+                }
+            },
+            "then": {
+                "required": [
+                    "checkpoint_policy"
+                ],
+                "properties": {
+                    "checkpoint_policy": {
+                        "const": "all"
+                    }
+                }
+            }
+        }
+    },
     "allOf": [
         {
             "if": {
@@ -1805,6 +1844,14 @@ var (
             ],
             "default": null
         },
+        "cpu_per_slot": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "exclusiveMinimum": 0,
+            "default": null
+        },
         "devices": {
             "type": [
                 "array",
@@ -1820,6 +1867,32 @@ var (
             ],
             "default": null
         },
+        "max_runtime": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "checks": {
+                "must be a valid duration, such as 30m or 4h": {
+                    "pattern": "^([0-9]*[.])?[0-9]+(ns|us|\u00b5s|ms|s|m|h)(([0-9]*[.])?[0-9]+(ns|us|\u00b5s|ms|s|m|h))*$"
+                },
+                "must be positive": {
+                    "not": {
+                        "type": "string",
+                        "pattern": "^(0*[.]?0+(ns|us|\u00b5s|ms|s|m|h))+$"
+                    }
+                }
+            },
+            "default": null
+        },
+        "memory_per_slot_gb": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "exclusiveMinimum": 0,
+            "default": null
+        },
         "native_parallel": {
             "type": [
                 "boolean",
@@ -2522,6 +2595,108 @@ var (
         ]
     }
 }
+`)
+	textPBTConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/searcher-pbt.json",
+    "title": "PBTConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "name"
+    ],
+    "eventuallyRequired": [
+        "population_size",
+        "num_rounds",
+        "length_per_round",
+        "metric"
+    ],
+    "properties": {
+        "name": {
+            "const": "pbt"
+        },
+        "population_size": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 1
+        },
+        "num_rounds": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 1
+        },
+        "length_per_round": {
+            "type": [
+                "object",
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/searcher-length.json"
+        },
+        "truncate_fraction": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "exclusiveMinimum": 0,
+            "maximum": 0.5,
+            "default": 0.25
+        },
+        "resample_probability": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "minimum": 0,
+            "maximum": 1,
+            "default": 0.25
+        },
+        "perturb_factor": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "minimum": 0,
+            "exclusiveMaximum": 1,
+            "default": 0.2
+        },
+        "metric": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "smaller_is_better": {
+            "type": [
+                "boolean",
+                "null"
+            ],
+            "default": true
+        },
+        "source_trial_id": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null
+        },
+        "source_checkpoint_uuid": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        }
+    }
+}
 `)
 	textRandomConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -2743,6 +2918,105 @@ var (
         }
     }
 }
+`)
+	textTPEConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/searcher-tpe.json",
+    "title": "TPEConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "name"
+    ],
+    "eventuallyRequired": [
+        "max_trials",
+        "max_length",
+        "metric"
+    ],
+    "properties": {
+        "name": {
+            "const": "tpe"
+        },
+        "max_concurrent_trials": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 0,
+            "default": 4
+        },
+        "max_trials": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 1
+        },
+        "max_length": {
+            "type": [
+                "object",
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/searcher-length.json"
+        },
+        "num_startup_trials": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 1,
+            "default": 10
+        },
+        "gamma": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "exclusiveMinimum": 0,
+            "exclusiveMaximum": 1,
+            "default": 0.25
+        },
+        "num_candidates": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 1,
+            "default": 24
+        },
+        "metric": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "smaller_is_better": {
+            "type": [
+                "boolean",
+                "null"
+            ],
+            "default": true
+        },
+        "source_trial_id": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null
+        },
+        "source_checkpoint_uuid": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        }
+    }
+}
 `)
 	textSearcherConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -2756,7 +3030,7 @@ var (
     },
     "then": {
         "union": {
-            "defaultMessage": "is not an object where object[\"name\"] is one of 'single', 'random', 'grid', 'custom', or 'adaptive_asha'",
+            "defaultMessage": "is not an object where object[\"name\"] is one of 'single', 'random', 'grid', 'custom', 'adaptive_asha', 'tpe', or 'pbt'",
             "items": [
                 {
                     "unionKey": "const:name=single",
@@ -2778,6 +3052,14 @@ var (
                     "unionKey": "const:name=adaptive_asha",
                     "$ref": "http://determined.ai/schemas/expconf/v0/searcher-adaptive-asha.json"
                 },
+                {
+                    "unionKey": "const:name=tpe",
+                    "$ref": "http://determined.ai/schemas/expconf/v0/searcher-tpe.json"
+                },
+                {
+                    "unionKey": "const:name=pbt",
+                    "$ref": "http://determined.ai/schemas/expconf/v0/searcher-pbt.json"
+                },
                 {
                     "unionKey": "const:name=async_halving",
                     "$ref": "http://determined.ai/schemas/expconf/v0/searcher-async-halving.json"
@@ -2808,14 +3090,23 @@ var (
     "properties": {
         "bracket_rungs": true,
         "divisor": true,
+        "gamma": true,
+        "length_per_round": true,
         "max_concurrent_trials": true,
         "max_length": true,
         "max_rungs": true,
         "max_trials": true,
         "mode": true,
         "name": true,
+        "num_candidates": true,
+        "num_rounds": true,
         "num_rungs": true,
+        "num_startup_trials": true,
+        "perturb_factor": true,
+        "population_size": true,
+        "resample_probability": true,
         "stop_once": true,
+        "truncate_fraction": true,
         "metric": {
             "type": [
                 "string",
@@ -3234,12 +3525,16 @@ var (
 
 	schemaSearcherLengthV0 interface{}
 
+	schemaPBTConfigV0 interface{}
+
 	schemaRandomConfigV0 interface{}
 
 	schemaSingleConfigV0 interface{}
 
 	schemaSyncHalvingConfigV0 interface{}
 
+	schemaTPEConfigV0 interface{}
+
 	schemaSearcherConfigV0 interface{}
 
 	schemaSecurityConfigV0 interface{}
@@ -4185,6 +4480,26 @@ func ParsedSearcherLengthV0() interface{} {
 	return schemaSearcherLengthV0
 }
 
+func ParsedPBTConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaPBTConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaPBTConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaPBTConfigV0 != nil {
+		return schemaPBTConfigV0
+	}
+	err := json.Unmarshal(textPBTConfigV0, &schemaPBTConfigV0)
+	if err != nil {
+		panic("invalid embedded json for PBTConfigV0")
+	}
+	return schemaPBTConfigV0
+}
+
 func ParsedRandomConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaRandomConfigV0 != nil {
@@ -4245,6 +4560,26 @@ func ParsedSyncHalvingConfigV0() interface{} {
 	return schemaSyncHalvingConfigV0
 }
 
+func ParsedTPEConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaTPEConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaTPEConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaTPEConfigV0 != nil {
+		return schemaTPEConfigV0
+	}
+	err := json.Unmarshal(textTPEConfigV0, &schemaTPEConfigV0)
+	if err != nil {
+		panic("invalid embedded json for TPEConfigV0")
+	}
+	return schemaTPEConfigV0
+}
+
 func ParsedSearcherConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaSearcherConfigV0 != nil {
@@ -4532,12 +4867,16 @@ func schemaBytesMap() map[string][]byte {
 	cachedSchemaBytesMap[url] = textGridConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/searcher-length.json"
 	cachedSchemaBytesMap[url] = textSearcherLengthV0
+	url = "http://determined.ai/schemas/expconf/v0/searcher-pbt.json"
+	cachedSchemaBytesMap[url] = textPBTConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/searcher-random.json"
 	cachedSchemaBytesMap[url] = textRandomConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/searcher-single.json"
 	cachedSchemaBytesMap[url] = textSingleConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/searcher-sync-halving.json"
 	cachedSchemaBytesMap[url] = textSyncHalvingConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/searcher-tpe.json"
+	cachedSchemaBytesMap[url] = textTPEConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/searcher.json"
 	cachedSchemaBytesMap[url] = textSearcherConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/security.json"
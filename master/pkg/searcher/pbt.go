@@ -0,0 +1,250 @@
+package searcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/determined-ai/determined/master/pkg/mathx"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/nprand"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+type (
+	// pbtSearchState stores the state for pbt. Metrics holds the results of the current round,
+	// keyed by trial; a round is finished once every member of the population has reported.
+	// TrialRoundsCompleted counts rounds per trial since trials cloned from another trial start
+	// training from zero, so their ValidateAfter lengths are relative to when they were created.
+	pbtSearchState struct {
+		RoundsCompleted      int                              `json:"rounds_completed"`
+		Metrics              map[model.RequestID]float64      `json:"metrics"`
+		TrialRoundsCompleted map[model.RequestID]int          `json:"trial_rounds_completed"`
+		TrialParams          map[model.RequestID]HParamSample `json:"trial_params"`
+		EarlyExitTrials      map[model.RequestID]bool         `json:"early_exit_trials"`
+		SearchMethodType     SearchMethodType                 `json:"search_method_type"`
+	}
+
+	// pbtSearch implements Population Based Training (Jaderberg et al., 2017). A fixed-size
+	// population of trials is trained in rounds of length_per_round. At the end of each round, the
+	// worst truncate_fraction of the population is closed and replaced by clones of the best
+	// truncate_fraction: each clone starts from the checkpoint of its parent, with the parent's
+	// hyperparameters perturbed or resampled.
+	pbtSearch struct {
+		defaultSearchMethod
+		expconf.PBTConfig
+		SmallerIsBetter bool
+		pbtSearchState
+	}
+)
+
+// pbtExitedMetricValue ranks trials that exited early below every trial that reported a metric.
+const pbtExitedMetricValue = math.MaxFloat64
+
+func newPBTSearch(config expconf.PBTConfig, smallerIsBetter bool) SearchMethod {
+	return &pbtSearch{
+		PBTConfig:       config,
+		SmallerIsBetter: smallerIsBetter,
+		pbtSearchState: pbtSearchState{
+			Metrics:              make(map[model.RequestID]float64),
+			TrialRoundsCompleted: make(map[model.RequestID]int),
+			TrialParams:          make(map[model.RequestID]HParamSample),
+			EarlyExitTrials:      make(map[model.RequestID]bool),
+			SearchMethodType:     PBTSearch,
+		},
+	}
+}
+
+func (s *pbtSearch) initialOperations(ctx context) ([]Operation, error) {
+	var ops []Operation
+	for trial := 0; trial < s.PopulationSize(); trial++ {
+		create := NewCreate(ctx.rand, sampleAll(ctx.hparams, ctx.rand), model.TrialWorkloadSequencerType)
+		ops = append(ops, s.startTrial(create)...)
+	}
+	return ops, nil
+}
+
+func (s *pbtSearch) startTrial(create Create) []Operation {
+	s.TrialParams[create.RequestID] = create.Hparams
+	s.TrialRoundsCompleted[create.RequestID] = 0
+	return []Operation{create, NewValidateAfter(create.RequestID, s.LengthPerRound().Units)}
+}
+
+func (s *pbtSearch) validationCompleted(
+	ctx context, requestID model.RequestID, metric interface{}, op ValidateAfter,
+) ([]Operation, error) {
+	value, ok := metric.(float64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected metric type for PBT built-in search method %v", metric)
+	}
+	if !s.SmallerIsBetter {
+		value *= -1
+	}
+	s.Metrics[requestID] = value
+	s.TrialRoundsCompleted[requestID]++
+	return s.maybeFinishRound(ctx), nil
+}
+
+// trialExitedEarly counts the trial as the worst member of the population for the current round,
+// which guarantees that its slot is handed to a clone of a better trial.
+func (s *pbtSearch) trialExitedEarly(
+	ctx context, requestID model.RequestID, exitedReason model.ExitedReason,
+) ([]Operation, error) {
+	if _, ok := s.TrialParams[requestID]; !ok || s.EarlyExitTrials[requestID] {
+		// The trial is no longer part of the population, e.g. it was replaced last round.
+		return nil, nil
+	}
+	s.EarlyExitTrials[requestID] = true
+	if _, ok := s.Metrics[requestID]; ok {
+		// The trial already reported for this round and will simply not be continued.
+		s.Metrics[requestID] = pbtExitedMetricValue
+		return nil, nil
+	}
+	s.Metrics[requestID] = pbtExitedMetricValue
+	return s.maybeFinishRound(ctx), nil
+}
+
+func (s *pbtSearch) maybeFinishRound(ctx context) []Operation {
+	if len(s.Metrics) < s.PopulationSize() {
+		return nil
+	}
+
+	var ops []Operation
+	s.RoundsCompleted++
+	defer func() { s.Metrics = make(map[model.RequestID]float64) }()
+
+	ranked := make([]model.RequestID, 0, len(s.Metrics))
+	for requestID := range s.Metrics {
+		ranked = append(ranked, requestID)
+	}
+	// Break ties on request ID so that the search is reproducible despite map ordering.
+	sort.Slice(ranked, func(i, j int) bool {
+		mi, mj := s.Metrics[ranked[i]], s.Metrics[ranked[j]]
+		if mi != mj {
+			return mi < mj
+		}
+		return ranked[i].Before(ranked[j])
+	})
+
+	if s.RoundsCompleted >= s.NumRounds() {
+		for _, requestID := range ranked {
+			if !s.EarlyExitTrials[requestID] {
+				ops = append(ops, NewClose(requestID))
+			}
+			delete(s.TrialParams, requestID)
+		}
+		return ops
+	}
+
+	// Every trial that exited early must be replaced, even if that means replacing more than the
+	// configured fraction of the population.
+	numTruncate := int(s.TruncateFraction() * float64(s.PopulationSize()))
+	numExited := 0
+	for _, requestID := range ranked {
+		if s.EarlyExitTrials[requestID] {
+			numExited++
+		}
+	}
+	numTruncate = mathx.Max(numTruncate, numExited)
+	numSurvivors := len(ranked) - numTruncate
+	parents := ranked[:numSurvivors]
+
+	for i, requestID := range ranked[numSurvivors:] {
+		if !s.EarlyExitTrials[requestID] {
+			ops = append(ops, NewClose(requestID))
+		}
+		delete(s.TrialParams, requestID)
+
+		if len(parents) == 0 {
+			// No trial survived the round, so there is nothing to clone; start over at random.
+			create := NewCreate(ctx.rand, sampleAll(ctx.hparams, ctx.rand), model.TrialWorkloadSequencerType)
+			ops = append(ops, s.startTrial(create)...)
+			continue
+		}
+		// Clone the best trials in order, so the best trial gets the first replaced slot.
+		parent := parents[i%mathx.Min(len(parents), numTruncate)]
+		hparams := s.explore(ctx, s.TrialParams[parent])
+		create := NewCreateFromCheckpoint(ctx.rand, hparams, parent, model.TrialWorkloadSequencerType)
+		ops = append(ops, s.startTrial(create)...)
+	}
+
+	for _, requestID := range parents {
+		rounds := uint64(s.TrialRoundsCompleted[requestID] + 1)
+		ops = append(ops, NewValidateAfter(requestID, s.LengthPerRound().Units*rounds))
+	}
+	return ops
+}
+
+// explore returns a copy of the given hyperparameters in which each value is either resampled from
+// the hyperparameter space or perturbed by a factor of 1 +/- perturb_factor.
+func (s *pbtSearch) explore(ctx context, params HParamSample) HParamSample {
+	results := make(HParamSample)
+	ctx.hparams.Each(func(name string, param expconf.Hyperparameter) {
+		results[name] = s.exploreOne(ctx.rand, param, params[name])
+	})
+	return results
+}
+
+func (s *pbtSearch) exploreOne(
+	rand *nprand.State, h expconf.Hyperparameter, value interface{},
+) interface{} {
+	if h.RawNestedHyperparameter == nil && rand.UnitInterval() < s.ResampleProbability() {
+		return sampleOne(h, rand)
+	}
+	scale := 1 - s.PerturbFactor()
+	if rand.UnitInterval() < 0.5 {
+		scale = 1 + s.PerturbFactor()
+	}
+	f, isNumeric := toFloat64(value)
+
+	switch {
+	case h.RawIntHyperparameter != nil && isNumeric:
+		p := h.RawIntHyperparameter
+		return mathx.Clamp(p.Minval(), int(math.Round(f*scale)), p.Maxval())
+	case h.RawDoubleHyperparameter != nil && isNumeric:
+		p := h.RawDoubleHyperparameter
+		return mathx.Clamp(p.Minval(), f*scale, p.Maxval())
+	case h.RawLogHyperparameter != nil && isNumeric:
+		// Perturb in exponent space, where the hyperparameter is sampled.
+		p := h.RawLogHyperparameter
+		exponent := mathx.Clamp(p.Minval(), math.Log(f)/math.Log(p.Base())*scale, p.Maxval())
+		return math.Pow(p.Base(), exponent)
+	case h.RawNestedHyperparameter != nil:
+		nested, _ := value.(map[string]interface{})
+		if sample, ok := value.(HParamSample); ok {
+			nested = sample
+		}
+		p := make(map[string]interface{})
+		for key, val := range *h.RawNestedHyperparameter {
+			p[key] = s.exploreOne(rand, val, nested[key])
+		}
+		return p
+	case value == nil:
+		// The hyperparameter was added after the parent was sampled (e.g. on a restored
+		// experiment); there is nothing to perturb.
+		return sampleOne(h, rand)
+	default:
+		// Constants and categoricals have no notion of a nearby value, so they only change when
+		// resampled.
+		return value
+	}
+}
+
+func (s *pbtSearch) progress(
+	map[model.RequestID]PartialUnits, map[model.RequestID]bool,
+) float64 {
+	reported := float64(len(s.Metrics)) / float64(s.PopulationSize())
+	return (float64(s.RoundsCompleted) + reported) / float64(s.NumRounds())
+}
+
+func (s *pbtSearch) Snapshot() (json.RawMessage, error) {
+	return json.Marshal(s.pbtSearchState)
+}
+
+func (s *pbtSearch) Restore(state json.RawMessage) error {
+	if state == nil {
+		return nil
+	}
+	return json.Unmarshal(state, &s.pbtSearchState)
+}
@@ -0,0 +1,184 @@
+//nolint:exhaustruct
+package searcher
+
+import (
+	"math"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/nprand"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func pbtTestConfig(populationSize, numRounds int) expconf.PBTConfig {
+	return schemas.WithDefaults(expconf.PBTConfig{
+		RawPopulationSize:   ptrs.Ptr(populationSize),
+		RawNumRounds:        ptrs.Ptr(numRounds),
+		RawLengthPerRound:   ptrs.Ptr(expconf.NewLengthInBatches(100)),
+		RawTruncateFraction: ptrs.Ptr(0.5),
+	})
+}
+
+func TestPBTSearcherBatches(t *testing.T) {
+	// After the first round, half of the population continues and the other half is replaced by
+	// clones that train for the final round only.
+	expected := [][]ValidateAfter{
+		toOps("100B"), toOps("100B"),
+		toOps("100B"), toOps("100B"),
+		toOps("100B 200B"), toOps("100B 200B"),
+	}
+	search := newPBTSearch(pbtTestConfig(4, 2), true)
+	checkSimulation(t, search, nil, ConstantValidation, expected)
+}
+
+func TestPBTSearcherReproducibility(t *testing.T) {
+	conf := pbtTestConfig(8, 4)
+	gen := func() SearchMethod { return newPBTSearch(conf, true) }
+	checkReproducibility(t, gen, tpeTestHyperparameters(), defaultMetric)
+}
+
+func TestPBTSearchMethod(t *testing.T) {
+	testCases := []valueSimulationTestCase{
+		{
+			name: "test pbt search method",
+			expectedTrials: []predefinedTrial{
+				newPredefinedTrial(toOps("100B 200B 300B"), nil, []float64{.1, .1, .1}),
+				newConstantPredefinedTrial(toOps("100B"), .5),
+				newConstantPredefinedTrial(toOps("100B"), .3),
+				newConstantPredefinedTrial(toOps("100B"), .2),
+			},
+			hparams: tpeTestHyperparameters(),
+			config: expconf.SearcherConfig{
+				RawPBTConfig: &expconf.PBTConfig{
+					RawPopulationSize:   ptrs.Ptr(2),
+					RawNumRounds:        ptrs.Ptr(3),
+					RawLengthPerRound:   ptrs.Ptr(expconf.NewLengthInBatches(100)),
+					RawTruncateFraction: ptrs.Ptr(0.5),
+				},
+			},
+		},
+		{
+			name: "test pbt search method with larger is better",
+			expectedTrials: []predefinedTrial{
+				newConstantPredefinedTrial(toOps("100B"), .1),
+				newPredefinedTrial(toOps("100B 200B"), nil, []float64{.5, .5}),
+				newConstantPredefinedTrial(toOps("100B"), .3),
+			},
+			hparams: tpeTestHyperparameters(),
+			config: expconf.SearcherConfig{
+				RawSmallerIsBetter: ptrs.Ptr(false),
+				RawPBTConfig: &expconf.PBTConfig{
+					RawPopulationSize:   ptrs.Ptr(2),
+					RawNumRounds:        ptrs.Ptr(2),
+					RawLengthPerRound:   ptrs.Ptr(expconf.NewLengthInBatches(100)),
+					RawTruncateFraction: ptrs.Ptr(0.5),
+				},
+			},
+		},
+		{
+			name: "test pbt search method with early exit",
+			expectedTrials: []predefinedTrial{
+				newEarlyExitPredefinedTrial(toOps("100B"), .1),
+				newPredefinedTrial(toOps("100B 200B"), nil, []float64{.5, .5}),
+				newConstantPredefinedTrial(toOps("100B"), .3),
+			},
+			hparams: tpeTestHyperparameters(),
+			config: expconf.SearcherConfig{
+				RawPBTConfig: &expconf.PBTConfig{
+					RawPopulationSize:   ptrs.Ptr(2),
+					RawNumRounds:        ptrs.Ptr(2),
+					RawLengthPerRound:   ptrs.Ptr(expconf.NewLengthInBatches(100)),
+					RawTruncateFraction: ptrs.Ptr(0.5),
+				},
+			},
+		},
+	}
+
+	runValueSimulationTestCases(t, testCases)
+}
+
+func TestPBTSearcherClonesFromCheckpoint(t *testing.T) {
+	hparams := tpeTestHyperparameters()
+	method := newPBTSearch(pbtTestConfig(4, 2), true).(*pbtSearch)
+	ctx := context{rand: nprand.New(0), hparams: hparams}
+
+	ops, err := method.initialOperations(ctx)
+	assert.NilError(t, err)
+	var population []model.RequestID
+	for _, op := range ops {
+		if create, ok := op.(Create); ok {
+			assert.Assert(t, create.Checkpoint == nil)
+			population = append(population, create.RequestID)
+		}
+	}
+	assert.Equal(t, len(population), 4)
+
+	for i, requestID := range population {
+		ops, err = method.validationCompleted(
+			ctx, requestID, float64(i), NewValidateAfter(requestID, 100))
+		assert.NilError(t, err)
+	}
+
+	// The two best trials continue and the two worst are replaced by their clones.
+	var closed []model.RequestID
+	var parents []model.RequestID
+	for _, op := range ops {
+		switch op := op.(type) {
+		case Close:
+			closed = append(closed, op.RequestID)
+		case Create:
+			assert.Assert(t, op.Checkpoint != nil)
+			parents = append(parents, op.Checkpoint.RequestID)
+		case ValidateAfter:
+			if op.RequestID == population[0] || op.RequestID == population[1] {
+				assert.Equal(t, op.Length, uint64(200))
+			} else {
+				assert.Equal(t, op.Length, uint64(100))
+			}
+		}
+	}
+	assert.DeepEqual(t, closed, population[2:])
+	assert.DeepEqual(t, parents, population[:2])
+}
+
+func TestPBTExplore(t *testing.T) {
+	hparams := tpeTestHyperparameters()
+	parent := HParamSample{
+		"x":      0.5,
+		"n":      5,
+		"lr":     1e-3,
+		"nested": map[string]interface{}{"opt": "adam"},
+	}
+	ctx := context{rand: nprand.New(0), hparams: hparams}
+
+	perturb := newPBTSearch(schemas.WithDefaults(expconf.PBTConfig{
+		RawResampleProbability: ptrs.Ptr(0.0),
+		RawPerturbFactor:       ptrs.Ptr(0.2),
+	}), true).(*pbtSearch)
+	for i := 0; i < 20; i++ {
+		child := perturb.explore(ctx, parent)
+		x := child["x"].(float64)
+		assert.Assert(t, math.Abs(x-0.4) < 1e-9 || math.Abs(x-0.6) < 1e-9, "x=%f", x)
+		n := child["n"].(int)
+		assert.Assert(t, n == 4 || n == 6, "n=%d", n)
+		exponent := math.Log10(child["lr"].(float64))
+		assert.Assert(t, math.Abs(exponent+2.4) < 1e-9 || math.Abs(exponent+3.6) < 1e-9,
+			"lr exponent=%f", exponent)
+		assert.DeepEqual(t, child["nested"], map[string]interface{}{"opt": "adam"})
+	}
+
+	resample := newPBTSearch(schemas.WithDefaults(expconf.PBTConfig{
+		RawResampleProbability: ptrs.Ptr(1.0),
+	}), true).(*pbtSearch)
+	for i := 0; i < 20; i++ {
+		child := resample.explore(ctx, parent)
+		x := child["x"].(float64)
+		assert.Assert(t, x >= 0 && x <= 1, "x=%f", x)
+		n := child["n"].(int)
+		assert.Assert(t, n >= 1 && n <= 8, "n=%d", n)
+	}
+}
@@ -65,6 +65,10 @@ const (
 	ASHASearch SearchMethodType = "asha"
 	// AdaptiveASHASearch is the SearchMethodType for an adaptive ASHA searcher.
 	AdaptiveASHASearch SearchMethodType = "adaptive_asha"
+	// TPESearch is the SearchMethodType for a Tree-structured Parzen Estimator searcher.
+	TPESearch SearchMethodType = "tpe"
+	// PBTSearch is the SearchMethodType for a Population Based Training searcher.
+	PBTSearch SearchMethodType = "pbt"
 	// CustomSearch is the SearchMethodType for a custom searcher.
 	CustomSearch SearchMethodType = "custom_search"
 )
@@ -85,6 +89,10 @@ func NewSearchMethod(c expconf.SearcherConfig) SearchMethod {
 		return newAsyncHalvingSearch(*c.RawAsyncHalvingConfig, c.SmallerIsBetter())
 	case c.RawAdaptiveASHAConfig != nil:
 		return newAdaptiveASHASearch(*c.RawAdaptiveASHAConfig, c.SmallerIsBetter())
+	case c.RawTPEConfig != nil:
+		return newTPESearch(*c.RawTPEConfig, c.SmallerIsBetter())
+	case c.RawPBTConfig != nil:
+		return newPBTSearch(*c.RawPBTConfig, c.SmallerIsBetter())
 	case c.RawCustomConfig != nil:
 		return newCustomSearch(*c.RawCustomConfig)
 	default:
@@ -0,0 +1,426 @@
+package searcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/determined-ai/determined/master/pkg/mathx"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/nprand"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+type (
+	// tpeObservation records the hyperparameters a trial was trained with and the searcher metric
+	// it reported when it finished training.
+	tpeObservation struct {
+		Hparams HParamSample `json:"hparams"`
+		Metric  float64      `json:"metric"`
+	}
+
+	// tpeSearchState stores the state for tpe. Observations is the history the density estimators
+	// are built from, so it must survive a master restart; TrialHparams tracks the hyperparameters
+	// of trials that have been requested but have not yet reported a validation metric.
+	tpeSearchState struct {
+		CreatedTrials    int                              `json:"created_trials"`
+		PendingTrials    int                              `json:"pending_trials"`
+		TrialHparams     map[model.RequestID]HParamSample `json:"trial_hparams"`
+		Observations     []tpeObservation                 `json:"observations"`
+		SearchMethodType SearchMethodType                 `json:"search_method_type"`
+	}
+
+	// tpeSearch implements the Tree-structured Parzen Estimator (Bergstra et al., 2011). The first
+	// num_startup_trials configurations are sampled at random. After that, completed trials are
+	// split at the gamma quantile of the searcher metric into "good" and "bad" sets, a Parzen
+	// density is fit to each set per hyperparameter, and the next configuration is the candidate
+	// (drawn from the good density) that maximizes the ratio of good to bad density.
+	tpeSearch struct {
+		defaultSearchMethod
+		expconf.TPEConfig
+		SmallerIsBetter bool
+		tpeSearchState
+	}
+)
+
+func newTPESearch(config expconf.TPEConfig, smallerIsBetter bool) SearchMethod {
+	return &tpeSearch{
+		TPEConfig:       config,
+		SmallerIsBetter: smallerIsBetter,
+		tpeSearchState: tpeSearchState{
+			TrialHparams:     make(map[model.RequestID]HParamSample),
+			SearchMethodType: TPESearch,
+		},
+	}
+}
+
+func (s *tpeSearch) initialOperations(ctx context) ([]Operation, error) {
+	var ops []Operation
+	initialTrials := s.MaxTrials()
+	if s.MaxConcurrentTrials() > 0 {
+		initialTrials = mathx.Min(s.MaxTrials(), s.MaxConcurrentTrials())
+	}
+	for trial := 0; trial < initialTrials; trial++ {
+		ops = append(ops, s.createTrial(ctx)...)
+	}
+	return ops, nil
+}
+
+func (s *tpeSearch) createTrial(ctx context) []Operation {
+	create := NewCreate(ctx.rand, s.suggest(ctx), model.TrialWorkloadSequencerType)
+	s.TrialHparams[create.RequestID] = create.Hparams
+	s.CreatedTrials++
+	s.PendingTrials++
+	return []Operation{
+		create,
+		NewValidateAfter(create.RequestID, s.MaxLength().Units),
+		NewClose(create.RequestID),
+	}
+}
+
+func (s *tpeSearch) validationCompleted(
+	ctx context, requestID model.RequestID, metric interface{}, op ValidateAfter,
+) ([]Operation, error) {
+	value, ok := metric.(float64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected metric type for TPE built-in search method %v", metric)
+	}
+	hparams, ok := s.TrialHparams[requestID]
+	if !ok {
+		return nil, fmt.Errorf("no hyperparameters recorded for trial %s", requestID)
+	}
+	delete(s.TrialHparams, requestID)
+	if math.IsNaN(value) {
+		return nil, nil
+	}
+	s.Observations = append(s.Observations, tpeObservation{Hparams: hparams, Metric: value})
+	return nil, nil
+}
+
+func (s *tpeSearch) progress(
+	trialProgress map[model.RequestID]PartialUnits,
+	trialsClosed map[model.RequestID]bool,
+) float64 {
+	if s.MaxConcurrentTrials() > 0 && s.PendingTrials > s.MaxConcurrentTrials() {
+		panic("pending trials is greater than max_concurrent_trials")
+	}
+	// Progress is calculated the same way as for random search, since both train every trial for
+	// max_length units and replace InvalidHP trials with a new configuration.
+	unitsCompleted := 0.
+	for k, v := range trialProgress {
+		if trialsClosed[k] {
+			unitsCompleted += float64(s.MaxLength().Units)
+		} else {
+			unitsCompleted += float64(v)
+		}
+	}
+	unitsExpected := s.MaxLength().Units * uint64(s.MaxTrials())
+	return unitsCompleted / float64(unitsExpected)
+}
+
+// trialExitedEarly drops the trial from the model, since it never reported a metric, and arranges
+// for InvalidHP trials to be replaced by a new suggestion once they are closed.
+func (s *tpeSearch) trialExitedEarly(
+	ctx context, requestID model.RequestID, exitedReason model.ExitedReason,
+) ([]Operation, error) {
+	delete(s.TrialHparams, requestID)
+	if exitedReason == model.InvalidHP || exitedReason == model.InitInvalidHP {
+		s.CreatedTrials--
+	}
+	return nil, nil
+}
+
+func (s *tpeSearch) trialClosed(ctx context, requestID model.RequestID) ([]Operation, error) {
+	s.PendingTrials--
+	if s.CreatedTrials < s.MaxTrials() {
+		return s.createTrial(ctx), nil
+	}
+	return nil, nil
+}
+
+func (s *tpeSearch) Snapshot() (json.RawMessage, error) {
+	return json.Marshal(s.tpeSearchState)
+}
+
+func (s *tpeSearch) Restore(state json.RawMessage) error {
+	if state == nil {
+		return nil
+	}
+	return json.Unmarshal(state, &s.tpeSearchState)
+}
+
+// suggest returns the hyperparameters for the next trial.
+func (s *tpeSearch) suggest(ctx context) HParamSample {
+	if len(s.Observations) < s.NumStartupTrials() {
+		return sampleAll(ctx.hparams, ctx.rand)
+	}
+
+	sorted := make([]tpeObservation, len(s.Observations))
+	copy(sorted, s.Observations)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if s.SmallerIsBetter {
+			return sorted[i].Metric < sorted[j].Metric
+		}
+		return sorted[i].Metric > sorted[j].Metric
+	})
+	numGood := mathx.Max(1, int(math.Ceil(s.Gamma()*float64(len(sorted)))))
+
+	results := make(HParamSample)
+	ctx.hparams.Each(func(name string, param expconf.Hyperparameter) {
+		results[name] = s.suggestOne(ctx.rand, []string{name}, param, sorted[:numGood], sorted[numGood:])
+	})
+	return results
+}
+
+func (s *tpeSearch) suggestOne(
+	rand *nprand.State, route []string, h expconf.Hyperparameter, good, bad []tpeObservation,
+) interface{} {
+	switch {
+	case h.RawConstHyperparameter != nil:
+		return h.RawConstHyperparameter.Val()
+	case h.RawIntHyperparameter != nil:
+		p := h.RawIntHyperparameter
+		// Widen the range by half a unit on each side so the endpoints are as likely as any other
+		// integer once the continuous suggestion is rounded.
+		low, high := float64(p.Minval())-0.5, float64(p.Maxval())+0.5
+		x := s.suggestContinuous(rand, low, high, observedValues(route, good, identity),
+			observedValues(route, bad, identity))
+		return mathx.Clamp(p.Minval(), int(math.Round(x)), p.Maxval())
+	case h.RawDoubleHyperparameter != nil:
+		p := h.RawDoubleHyperparameter
+		return s.suggestContinuous(rand, p.Minval(), p.Maxval(), observedValues(route, good, identity),
+			observedValues(route, bad, identity))
+	case h.RawLogHyperparameter != nil:
+		// Log hyperparameters are modeled in exponent space, which is where they are sampled.
+		p := h.RawLogHyperparameter
+		toExponent := func(x float64) float64 { return math.Log(x) / math.Log(p.Base()) }
+		x := s.suggestContinuous(rand, p.Minval(), p.Maxval(), observedValues(route, good, toExponent),
+			observedValues(route, bad, toExponent))
+		return math.Pow(p.Base(), x)
+	case h.RawCategoricalHyperparameter != nil:
+		p := h.RawCategoricalHyperparameter
+		return p.Vals()[s.suggestCategorical(rand, p.Vals(), route, good, bad)]
+	case h.RawNestedHyperparameter != nil:
+		p := make(map[string]interface{})
+		for key, val := range *h.RawNestedHyperparameter {
+			p[key] = s.suggestOne(rand, append(append([]string{}, route...), key), val, good, bad)
+		}
+		return p
+	default:
+		panic(fmt.Sprintf("unexpected hyperparameter type: %+v", h))
+	}
+}
+
+// suggestContinuous draws candidates from the density fit to the good observations and returns the
+// one that maximizes l(x) / g(x), i.e. the expected improvement under the TPE model.
+func (s *tpeSearch) suggestContinuous(
+	rand *nprand.State, low, high float64, good, bad []float64,
+) float64 {
+	l := newParzenEstimator(good, low, high)
+	g := newParzenEstimator(bad, low, high)
+
+	best, bestScore := 0.0, math.Inf(-1)
+	for i := 0; i < s.NumCandidates(); i++ {
+		x := l.sample(rand)
+		if score := l.logPDF(x) - g.logPDF(x); score > bestScore {
+			best, bestScore = x, score
+		}
+	}
+	return best
+}
+
+// suggestCategorical is the categorical analog of suggestContinuous. Each density is the count of
+// observations per category plus a uniform prior of one, and it returns the index of the chosen
+// category.
+func (s *tpeSearch) suggestCategorical(
+	rand *nprand.State, vals []interface{}, route []string, good, bad []tpeObservation,
+) int {
+	weights := func(obs []tpeObservation) []float64 {
+		w := make([]float64, len(vals))
+		total := float64(len(vals))
+		for i := range w {
+			w[i] = 1
+		}
+		for _, o := range obs {
+			v, ok := lookupSample(o.Hparams, route)
+			if !ok {
+				continue
+			}
+			if idx := categoricalIndex(vals, v); idx >= 0 {
+				w[idx]++
+				total++
+			}
+		}
+		for i := range w {
+			w[i] /= total
+		}
+		return w
+	}
+	l, g := weights(good), weights(bad)
+
+	best, bestScore := 0, math.Inf(-1)
+	for i := 0; i < s.NumCandidates(); i++ {
+		idx := sampleWeighted(rand, l)
+		if score := math.Log(l[idx]) - math.Log(g[idx]); score > bestScore {
+			best, bestScore = idx, score
+		}
+	}
+	return best
+}
+
+// parzenEstimator is a mixture of Gaussians truncated to [low, high], with one component per
+// observation plus a wide prior component centered on the range.
+type parzenEstimator struct {
+	mus, sigmas []float64
+	low, high   float64
+}
+
+func newParzenEstimator(observations []float64, low, high float64) parzenEstimator {
+	width := high - low
+	mus := append([]float64{(low + high) / 2}, observations...)
+	sort.Float64s(mus)
+
+	// Each component's bandwidth is the distance to its farthest neighbor, clipped so that it is
+	// neither degenerate nor wider than the whole range, following the hyperopt implementation.
+	minSigma := width / math.Min(100, float64(len(mus)))
+	sigmas := make([]float64, len(mus))
+	for i, mu := range mus {
+		left, right := mu-low, high-mu
+		if i > 0 {
+			left = mu - mus[i-1]
+		}
+		if i < len(mus)-1 {
+			right = mus[i+1] - mu
+		}
+		sigmas[i] = math.Max(minSigma, math.Min(width, math.Max(left, right)))
+	}
+	return parzenEstimator{mus: mus, sigmas: sigmas, low: low, high: high}
+}
+
+func (p parzenEstimator) sample(rand *nprand.State) float64 {
+	i := rand.Intn(len(p.mus))
+	// Rejection-sample the truncated normal, falling back to clamping if the component has almost
+	// no mass inside the range.
+	for attempt := 0; attempt < 100; attempt++ {
+		if x := p.mus[i] + p.sigmas[i]*normal(rand); x >= p.low && x <= p.high {
+			return x
+		}
+	}
+	return math.Max(p.low, math.Min(p.high, p.mus[i]))
+}
+
+func (p parzenEstimator) logPDF(x float64) float64 {
+	terms := make([]float64, len(p.mus))
+	for i := range p.mus {
+		mu, sigma := p.mus[i], p.sigmas[i]
+		mass := normalCDF((p.high-mu)/sigma) - normalCDF((p.low-mu)/sigma)
+		z := (x - mu) / sigma
+		terms[i] = -0.5*z*z - math.Log(sigma*math.Sqrt(2*math.Pi)*math.Max(mass, 1e-12))
+	}
+	return logSumExp(terms) - math.Log(float64(len(p.mus)))
+}
+
+func normal(rand *nprand.State) float64 {
+	// Box-Muller transform; 1 - UnitInterval() is in (0, 1] so the log is always finite.
+	u1, u2 := 1-rand.UnitInterval(), rand.UnitInterval()
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+}
+
+func normalCDF(z float64) float64 {
+	return 0.5 * (1 + math.Erf(z/math.Sqrt2))
+}
+
+func logSumExp(xs []float64) float64 {
+	maxX := math.Inf(-1)
+	for _, x := range xs {
+		maxX = math.Max(maxX, x)
+	}
+	if math.IsInf(maxX, -1) {
+		return maxX
+	}
+	sum := 0.
+	for _, x := range xs {
+		sum += math.Exp(x - maxX)
+	}
+	return maxX + math.Log(sum)
+}
+
+func sampleWeighted(rand *nprand.State, weights []float64) int {
+	u := rand.UnitInterval()
+	for i, w := range weights {
+		if u < w {
+			return i
+		}
+		u -= w
+	}
+	return len(weights) - 1
+}
+
+func identity(x float64) float64 { return x }
+
+// observedValues extracts the numeric value at route from each observation, transformed into the
+// space the hyperparameter is modeled in.
+func observedValues(
+	route []string, obs []tpeObservation, transform func(float64) float64,
+) []float64 {
+	var values []float64
+	for _, o := range obs {
+		v, ok := lookupSample(o.Hparams, route)
+		if !ok {
+			continue
+		}
+		if f, ok := toFloat64(v); ok {
+			values = append(values, transform(f))
+		}
+	}
+	return values
+}
+
+// lookupSample finds the value at route in a possibly nested sample. Nested samples may be either
+// HParamSamples or plain maps, depending on whether they were restored from a snapshot.
+func lookupSample(sample HParamSample, route []string) (interface{}, bool) {
+	var cur interface{} = map[string]interface{}(sample)
+	for _, key := range route {
+		switch m := cur.(type) {
+		case HParamSample:
+			cur = m[key]
+		case map[string]interface{}:
+			cur = m[key]
+		default:
+			return nil, false
+		}
+	}
+	return cur, cur != nil
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// categoricalIndex returns the index of v in vals, comparing JSON encodings so that values that
+// round-tripped through a snapshot (e.g. ints that became float64s) still match.
+func categoricalIndex(vals []interface{}, v interface{}) int {
+	target, err := json.Marshal(v)
+	if err != nil {
+		return -1
+	}
+	for i, val := range vals {
+		if b, err := json.Marshal(val); err == nil && string(b) == string(target) {
+			return i
+		}
+	}
+	return -1
+}
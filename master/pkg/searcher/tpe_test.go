@@ -0,0 +1,178 @@
+//nolint:exhaustruct
+package searcher
+
+import (
+	"math"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/nprand"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func tpeTestHyperparameters() expconf.Hyperparameters {
+	return schemas.WithDefaults(expconf.Hyperparameters{
+		"x": expconf.Hyperparameter{
+			RawDoubleHyperparameter: &expconf.DoubleHyperparameter{RawMinval: 0, RawMaxval: 1},
+		},
+		"n": expconf.Hyperparameter{
+			RawIntHyperparameter: &expconf.IntHyperparameter{RawMinval: 1, RawMaxval: 8},
+		},
+		"lr": expconf.Hyperparameter{
+			RawLogHyperparameter: &expconf.LogHyperparameter{
+				RawMinval: -5, RawMaxval: -1, RawBase: 10,
+			},
+		},
+		"nested": expconf.Hyperparameter{
+			RawNestedHyperparameter: &map[string]expconf.Hyperparameter{
+				"opt": {
+					RawCategoricalHyperparameter: &expconf.CategoricalHyperparameter{
+						RawVals: []interface{}{"sgd", "adam", 3},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestTPESearcherRecords(t *testing.T) {
+	actual := expconf.TPEConfig{
+		RawMaxTrials: ptrs.Ptr(6), RawMaxLength: ptrs.Ptr(expconf.NewLengthInRecords(19200)),
+		RawNumStartupTrials: ptrs.Ptr(2),
+	}
+	actual = schemas.WithDefaults(actual)
+	expected := [][]ValidateAfter{
+		toOps("19200R"),
+		toOps("19200R"),
+		toOps("19200R"),
+		toOps("19200R"),
+		toOps("19200R"),
+		toOps("19200R"),
+	}
+	search := newTPESearch(actual, true)
+	checkSimulation(t, search, tpeTestHyperparameters(), RandomValidation, expected)
+}
+
+func TestTPESearcherReproducibility(t *testing.T) {
+	conf := expconf.TPEConfig{
+		RawMaxTrials: ptrs.Ptr(12), RawMaxLength: ptrs.Ptr(expconf.NewLengthInBatches(300)),
+		RawNumStartupTrials: ptrs.Ptr(3),
+	}
+	conf = schemas.WithDefaults(conf)
+	gen := func() SearchMethod { return newTPESearch(conf, true) }
+	checkReproducibility(t, gen, tpeTestHyperparameters(), defaultMetric)
+}
+
+func TestTPESearchMethod(t *testing.T) {
+	testCases := []valueSimulationTestCase{
+		{
+			name: "test tpe search method",
+			expectedTrials: []predefinedTrial{
+				newConstantPredefinedTrial(toOps("500B"), .5),
+				newConstantPredefinedTrial(toOps("500B"), .4),
+				newConstantPredefinedTrial(toOps("500B"), .3),
+				newConstantPredefinedTrial(toOps("500B"), .2),
+				newEarlyExitPredefinedTrial(toOps("500B"), .1),
+			},
+			hparams: tpeTestHyperparameters(),
+			config: expconf.SearcherConfig{
+				RawTPEConfig: &expconf.TPEConfig{
+					RawMaxLength:           ptrs.Ptr(expconf.NewLengthInBatches(500)),
+					RawMaxTrials:           ptrs.Ptr(5),
+					RawMaxConcurrentTrials: ptrs.Ptr(2),
+					RawNumStartupTrials:    ptrs.Ptr(2),
+				},
+			},
+		},
+	}
+
+	runValueSimulationTestCases(t, testCases)
+}
+
+func TestTPESearcherConverges(t *testing.T) {
+	hparams := schemas.WithDefaults(expconf.Hyperparameters{
+		"x": expconf.Hyperparameter{
+			RawDoubleHyperparameter: &expconf.DoubleHyperparameter{RawMinval: 0, RawMaxval: 1},
+		},
+		"opt": expconf.Hyperparameter{
+			RawCategoricalHyperparameter: &expconf.CategoricalHyperparameter{
+				RawVals: []interface{}{"sgd", "adam", "rmsprop"},
+			},
+		},
+	})
+	objective := func(sample HParamSample) float64 {
+		loss := math.Pow(sample["x"].(float64)-0.8, 2)
+		if sample["opt"] != "adam" {
+			loss++
+		}
+		return loss
+	}
+
+	conf := schemas.WithDefaults(expconf.TPEConfig{
+		RawMaxTrials:        ptrs.Ptr(60),
+		RawMaxLength:        ptrs.Ptr(expconf.NewLengthInBatches(1)),
+		RawNumStartupTrials: ptrs.Ptr(10),
+	})
+	method := newTPESearch(conf, true).(*tpeSearch)
+	ctx := context{rand: nprand.New(0), hparams: hparams}
+
+	var best float64
+	for i := 0; i < conf.MaxTrials(); i++ {
+		sample := method.suggest(ctx)
+		requestID := model.NewRequestID(ctx.rand)
+		method.TrialHparams[requestID] = sample
+		_, err := method.validationCompleted(ctx, requestID, objective(sample), ValidateAfter{})
+		assert.NilError(t, err)
+		if i >= conf.MaxTrials()-10 {
+			best += objective(sample) / 10
+		}
+	}
+	// Uniform random search would average a loss of ~0.76 on this objective; the model-based
+	// suggestions should be concentrated near x=0.8 with opt=adam.
+	assert.Assert(t, best < 0.2, "mean loss of final suggestions was %f", best)
+}
+
+func TestTPESearcherRestoredObservations(t *testing.T) {
+	hparams := tpeTestHyperparameters()
+	conf := schemas.WithDefaults(expconf.TPEConfig{
+		RawMaxTrials:        ptrs.Ptr(10),
+		RawMaxLength:        ptrs.Ptr(expconf.NewLengthInBatches(1)),
+		RawNumStartupTrials: ptrs.Ptr(4),
+	})
+	method := newTPESearch(conf, false).(*tpeSearch)
+	ctx := context{rand: nprand.New(0), hparams: hparams}
+	for i := 0; i < conf.NumStartupTrials(); i++ {
+		requestID := model.NewRequestID(ctx.rand)
+		method.TrialHparams[requestID] = method.suggest(ctx)
+		_, err := method.validationCompleted(ctx, requestID, float64(i), ValidateAfter{})
+		assert.NilError(t, err)
+	}
+
+	// Restoring turns ints into float64s and nested samples into plain maps; the density
+	// estimators must still find every observed value.
+	state, err := method.Snapshot()
+	assert.NilError(t, err)
+	restored := newTPESearch(conf, false).(*tpeSearch)
+	assert.NilError(t, restored.Restore(state))
+	for _, route := range [][]string{{"x"}, {"n"}, {"lr"}} {
+		assert.Equal(t, len(observedValues(route, restored.Observations, identity)),
+			conf.NumStartupTrials())
+	}
+	vals := hparams["nested"].RawNestedHyperparameter
+	opts := (*vals)["opt"].RawCategoricalHyperparameter.Vals()
+	for _, o := range restored.Observations {
+		v, ok := lookupSample(o.Hparams, []string{"nested", "opt"})
+		assert.Assert(t, ok)
+		assert.Assert(t, categoricalIndex(opts, v) >= 0, "%v not in %v", v, opts)
+	}
+
+	sample := restored.suggest(ctx)
+	n := sample["n"].(int)
+	assert.Assert(t, n >= 1 && n <= 8, "int suggestion %d out of range", n)
+	lr := sample["lr"].(float64)
+	assert.Assert(t, lr >= 1e-5 && lr <= 1e-1, "log suggestion %f out of range", lr)
+}
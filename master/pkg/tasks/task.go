@@ -290,9 +290,9 @@ func (t *TaskSpec) ToDockerSpec() cproto.Spec {
 				CapAdd:          env.AddCapabilities(),
 				CapDrop:         env.DropCapabilities(),
 
-				Resources: docker.Resources{
+				Resources: slotResources(resources, len(t.Devices), docker.Resources{
 					Devices: devices,
-				},
+				}),
 			},
 			Archives:   append(runArchives, rootArchives...),
 			DeviceType: deviceType,
@@ -303,6 +303,20 @@ func (t *TaskSpec) ToDockerSpec() cproto.Spec {
 	return spec
 }
 
+// slotResources limits the CPUs and memory of a container with the given number of slots to the
+// per-slot resources of the task, if set. The agent applies its own defaults otherwise.
+func slotResources(
+	config expconf.ResourcesConfig, slots int, resources docker.Resources,
+) docker.Resources {
+	if cpu := config.CPUPerSlot(); cpu != nil {
+		resources.NanoCPUs = int64(*cpu * float64(slots) * 1e9)
+	}
+	if memory := config.MemoryPerSlotGB(); memory != nil {
+		resources.Memory = int64(*memory * float64(slots) * (1 << 30))
+	}
+	return resources
+}
+
 // workDirArchive ensures that the workdir is created and owned by the user.
 func workDirArchive(
 	aug *model.AgentUserGroup, workDir string, createWorkDir bool,
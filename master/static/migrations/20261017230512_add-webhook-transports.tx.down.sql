@@ -0,0 +1,16 @@
+DELETE FROM webhooks WHERE webhook_type NOT IN ('DEFAULT', 'SLACK');
+
+ALTER TABLE webhooks DROP COLUMN template;
+ALTER TABLE webhooks DROP COLUMN routing_key;
+
+ALTER TYPE webhook_type RENAME TO _webhook_type;
+
+CREATE TYPE webhook_type AS ENUM (
+  'DEFAULT',
+  'SLACK'
+);
+
+ALTER TABLE webhooks ALTER COLUMN webhook_type
+    SET DATA TYPE webhook_type USING (webhook_type::text::webhook_type);
+
+DROP TYPE public._webhook_type;
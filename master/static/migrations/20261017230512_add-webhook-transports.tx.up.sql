@@ -0,0 +1,17 @@
+ALTER TYPE webhook_type RENAME TO _webhook_type;
+
+CREATE TYPE webhook_type AS ENUM (
+  'DEFAULT',
+  'SLACK',
+  'TEAMS',
+  'PAGERDUTY',
+  'TEMPLATED'
+);
+
+ALTER TABLE webhooks ALTER COLUMN webhook_type
+    SET DATA TYPE webhook_type USING (webhook_type::text::webhook_type);
+
+DROP TYPE public._webhook_type;
+
+ALTER TABLE webhooks ADD COLUMN template text;
+ALTER TABLE webhooks ADD COLUMN routing_key text;
@@ -0,0 +1 @@
+DROP TABLE rp_reservations;
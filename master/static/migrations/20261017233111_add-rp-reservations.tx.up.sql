@@ -0,0 +1,13 @@
+CREATE TABLE rp_reservations (
+  id SERIAL PRIMARY KEY,
+  pool_name text NOT NULL,
+  workspace_id integer NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+  slots integer NOT NULL CHECK (slots > 0),
+  start_time timestamptz NOT NULL,
+  end_time timestamptz NOT NULL,
+  description text NOT NULL DEFAULT '',
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT rp_reservations_window CHECK (end_time > start_time)
+);
+
+CREATE INDEX ix_rp_reservations_pool_name_end_time ON rp_reservations USING btree (pool_name, end_time);
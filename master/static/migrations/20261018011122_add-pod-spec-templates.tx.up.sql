@@ -0,0 +1,10 @@
+CREATE TABLE pod_spec_templates (
+  id SERIAL PRIMARY KEY,
+  name text NOT NULL,
+  pool_name text NOT NULL DEFAULT '',
+  pod_spec jsonb NOT NULL,
+  description text NOT NULL DEFAULT '',
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT uq_pod_spec_templates_name_pool_name UNIQUE (name, pool_name)
+);
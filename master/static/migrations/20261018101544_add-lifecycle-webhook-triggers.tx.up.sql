@@ -0,0 +1,17 @@
+ALTER TYPE trigger_type RENAME TO _trigger_type;
+
+CREATE TYPE trigger_type AS ENUM (
+  'EXPERIMENT_STATE_CHANGE',
+  'METRIC_THRESHOLD_EXCEEDED',
+  'TASK_LOG',
+  'TRIAL_STATE_CHANGE',
+  'CHECKPOINT_STATE_CHANGE',
+  'MODEL_VERSION_REGISTERED',
+  'ALLOCATION_PREEMPTED',
+  'TASK_TERMINATED'
+);
+
+ALTER TABLE webhook_triggers ALTER COLUMN trigger_type
+    SET DATA TYPE trigger_type USING (trigger_type::text::trigger_type);
+
+DROP TYPE public._trigger_type;
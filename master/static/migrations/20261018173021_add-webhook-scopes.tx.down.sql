@@ -0,0 +1,4 @@
+DELETE FROM webhooks WHERE workspace_id IS NOT NULL OR project_id IS NOT NULL;
+
+ALTER TABLE webhooks DROP COLUMN workspace_id;
+ALTER TABLE webhooks DROP COLUMN project_id;
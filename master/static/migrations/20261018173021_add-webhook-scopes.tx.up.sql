@@ -0,0 +1,7 @@
+ALTER TABLE webhooks
+  ADD COLUMN workspace_id integer REFERENCES workspaces(id) ON DELETE CASCADE,
+  ADD COLUMN project_id integer REFERENCES projects(id) ON DELETE CASCADE,
+  ADD CONSTRAINT webhooks_single_scope CHECK (workspace_id IS NULL OR project_id IS NULL);
+
+CREATE INDEX ix_webhooks_workspace_id ON webhooks USING btree (workspace_id);
+CREATE INDEX ix_webhooks_project_id ON webhooks USING btree (project_id);
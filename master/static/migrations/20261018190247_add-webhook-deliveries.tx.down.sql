@@ -0,0 +1,3 @@
+DROP TABLE webhook_deliveries;
+
+ALTER TABLE webhook_events_queue DROP COLUMN webhook_id;
@@ -0,0 +1,18 @@
+ALTER TABLE webhook_events_queue
+  ADD COLUMN webhook_id integer REFERENCES webhooks(id) ON DELETE SET NULL;
+
+CREATE TABLE webhook_deliveries (
+  id SERIAL PRIMARY KEY,
+  event_id integer NOT NULL,
+  webhook_id integer REFERENCES webhooks(id) ON DELETE CASCADE,
+  url text NOT NULL,
+  payload text NOT NULL,
+  attempt integer NOT NULL,
+  status_code integer,
+  latency_ms integer NOT NULL,
+  error text,
+  attempted_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ix_webhook_deliveries_webhook_id ON webhook_deliveries USING btree (webhook_id);
+CREATE INDEX ix_webhook_deliveries_attempted_at ON webhook_deliveries USING btree (attempted_at);